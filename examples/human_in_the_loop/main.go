@@ -4,6 +4,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -70,43 +71,53 @@ func GenerateOutputNode(_ context.Context, s ApprovalState) graph.NodeResult[App
 	}
 }
 
+// ApprovalDecision is the human reviewer's answer, delivered with Engine.Resume.
+type ApprovalDecision struct {
+	Approved bool
+	Comment  string
+}
+
 // ApprovalGateNode pauses execution until approval is received.
-func ApprovalGateNode(_ context.Context, s ApprovalState) graph.NodeResult[ApprovalState] {
+func ApprovalGateNode(ctx context.Context, s ApprovalState) graph.NodeResult[ApprovalState] {
 	// Check if approval has been provided.
-	if s.Approved == nil {
+	input, ok := graph.ResumeInput(ctx)
+	if !ok {
 		fmt.Printf("\n⏸️  Workflow paused - awaiting human approval\n")
-		fmt.Printf("Generated Output:\n%s\n\n", s.GeneratedOutput)
 
-		// Pause: Return Stop to halt execution.
-		// Workflow will resume when state is updated with approval.
+		// Pause: Interrupt persists the run and hands the output to the caller.
+		// The run continues from here when Engine.Resume delivers a decision.
 		return graph.NodeResult[ApprovalState]{
-			Route: graph.Stop(),
+			Route: graph.Interrupt(s.GeneratedOutput),
 		}
 	}
+	decision := input.(ApprovalDecision)
 
 	// Approval decision has been made.
-	if *s.Approved {
+	if decision.Approved {
 		fmt.Printf("\n✅ Approved by human reviewer\n")
-		if s.ApprovalComment != "" {
-			fmt.Printf("Comment: %s\n", s.ApprovalComment)
+		if decision.Comment != "" {
+			fmt.Printf("Comment: %s\n", decision.Comment)
 		}
 		return graph.NodeResult[ApprovalState]{
+			Delta: ApprovalState{
+				Approved:        &decision.Approved,
+				ApprovalComment: decision.Comment,
+			},
 			Route: graph.Goto("finalize"),
 		}
 	}
 
 	fmt.Printf("\n❌ Rejected by human reviewer\n")
-	if s.ApprovalComment != "" {
-		fmt.Printf("Reason: %s\n", s.ApprovalComment)
+	if decision.Comment != "" {
+		fmt.Printf("Reason: %s\n", decision.Comment)
 	}
 
 	// Check if we should retry.
 	if s.Attempts < 3 {
 		fmt.Printf("Regenerating output (attempt %d/3)...\n", s.Attempts+1)
-		// Reset approval state and regenerate.
 		return graph.NodeResult[ApprovalState]{
 			Delta: ApprovalState{
-				Approved: nil, // Reset approval status
+				ApprovalComment: decision.Comment,
 			},
 			Route: graph.Goto("generate"),
 		}
@@ -114,6 +125,10 @@ func ApprovalGateNode(_ context.Context, s ApprovalState) graph.NodeResult[Appro
 
 	fmt.Printf("Max attempts reached. Workflow cancelled.\n")
 	return graph.NodeResult[ApprovalState]{
+		Delta: ApprovalState{
+			Approved:        &decision.Approved,
+			ApprovalComment: decision.Comment,
+		},
 		Route: graph.Stop(),
 	}
 }
@@ -130,7 +145,7 @@ func FinalizeNode(_ context.Context, s ApprovalState) graph.NodeResult[ApprovalS
 }
 
 // setupEngine creates and configures the workflow engine.
-func setupEngine() (*graph.Engine[ApprovalState], store.Store[ApprovalState]) {
+func setupEngine() *graph.Engine[ApprovalState] {
 	st := store.NewMemStore[ApprovalState]()
 	emitter := emit.NewLogEmitter(os.Stdout, false)
 
@@ -157,11 +172,11 @@ func setupEngine() (*graph.Engine[ApprovalState], store.Store[ApprovalState]) {
 		log.Fatalf("failed to set start node: %v", err)
 	}
 
-	return engine, st
+	return engine
 }
 
 // getApprovalFromUser prompts for user input.
-func getApprovalFromUser() (bool, string) {
+func getApprovalFromUser() ApprovalDecision {
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Print("\n👤 Approve this output? (y/n): ")
@@ -174,7 +189,7 @@ func getApprovalFromUser() (bool, string) {
 	comment, _ := reader.ReadString('\n')
 	comment = strings.TrimSpace(comment)
 
-	return approved, comment
+	return ApprovalDecision{Approved: approved, Comment: comment}
 }
 
 // demonstrateApprovalWorkflow shows interactive approval workflow.
@@ -184,7 +199,7 @@ func demonstrateApprovalWorkflow() {
 	fmt.Println("=" + strings.Repeat("=", 70))
 
 	ctx := context.Background()
-	engine, st := setupEngine()
+	engine := setupEngine()
 	runID := "approval-demo-001"
 
 	// Initial state.
@@ -195,55 +210,31 @@ func demonstrateApprovalWorkflow() {
 	// Start workflow.
 	fmt.Println("\n🚀 Starting workflow...")
 	final, err := engine.Run(ctx, runID, initialState)
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
-	}
 
-	// Check if paused for approval.
-	if final.Approved == nil {
+	// Each interrupt asks the reviewer; a rejection regenerates and asks again.
+	var interrupt *graph.InterruptError
+	for errors.As(err, &interrupt) {
 		fmt.Println("\n📋 Workflow paused at approval gate")
+		fmt.Printf("Generated Output:\n%s\n", interrupt.Payload)
 
 		// Get human approval.
-		approved, comment := getApprovalFromUser()
-
-		// Load the latest checkpoint.
-		// LoadCheckpointV2 expects (runID, stepID) but we need the latest step.
-		// For simplicity, we'll load the state directly and create a minimal checkpoint.
-		latestState, latestStep, err := st.LoadLatest(ctx, runID)
-		if err != nil {
-			fmt.Printf("Error loading latest state: %v\n", err)
-			return
-		}
-
-		// Update state with approval decision.
-		latestState.Approved = &approved
-		latestState.ApprovalComment = comment
-
-		// Create a checkpoint to resume from.
-		checkpoint := store.CheckpointV2[ApprovalState]{
-			RunID:  runID,
-			StepID: latestStep,
-			State:  latestState,
-		}
+		decision := getApprovalFromUser()
 
 		// Resume workflow.
 		fmt.Println("\n▶️  Resuming workflow...")
-		final, err = engine.RunWithCheckpoint(ctx, checkpoint)
-		if err != nil {
-			fmt.Printf("Error resuming: %v\n", err)
-			return
-		}
+		final, err = engine.Resume(ctx, interrupt.RunID, decision)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
 	}
 
 	// Show final result.
 	fmt.Println("\n" + strings.Repeat("=", 70))
 	if final.Approved != nil && *final.Approved {
 		fmt.Println("✅ Workflow completed successfully - Output approved and finalized")
-	} else if final.Approved != nil && !*final.Approved {
-		fmt.Println("❌ Workflow terminated - Output rejected after max attempts")
 	} else {
-		fmt.Println("⏸️  Workflow paused - Awaiting approval")
+		fmt.Println("❌ Workflow terminated - Output rejected after max attempts")
 	}
 	fmt.Println(strings.Repeat("=", 70))
 }
@@ -255,7 +246,7 @@ func demonstrateAutomaticResume() {
 	fmt.Println(strings.Repeat("=", 70))
 
 	ctx := context.Background()
-	engine, st := setupEngine()
+	engine := setupEngine()
 	runID := "auto-resume-001"
 
 	// Start workflow.
@@ -264,49 +255,28 @@ func demonstrateAutomaticResume() {
 		Request: "Generate quarterly report summary",
 	}
 
-	final, err := engine.Run(ctx, runID, initialState)
-	if err != nil {
+	_, err := engine.Run(ctx, runID, initialState)
+	if !errors.Is(err, graph.ErrInterrupted) {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	// Workflow paused - simulate saving for later.
-	if final.Approved == nil {
-		fmt.Println("\n💾 Checkpoint saved. Workflow can be resumed later...")
-		fmt.Println("(In production, approval could come from web UI, API, etc.)")
+	// Workflow paused - the checkpoint is already saved in the store.
+	fmt.Println("\n💾 Checkpoint saved. Workflow can be resumed later...")
+	fmt.Println("(In production, approval could come from web UI, API, etc.)")
 
-		// Simulate time passing.
-		fmt.Println("\n⏰ [Simulating: approval received via external system]")
-		time.Sleep(1 * time.Second)
+	// Simulate time passing.
+	fmt.Println("\n⏰ [Simulating: approval received via external system]")
+	time.Sleep(1 * time.Second)
 
-		// Load the latest state.
-		latestState, latestStep, err := st.LoadLatest(ctx, runID)
-		if err != nil {
-			fmt.Printf("Error loading latest state: %v\n", err)
-			return
-		}
-
-		// Apply approval (simulating external approval system).
-		approved := true
-		latestState.Approved = &approved
-		latestState.ApprovalComment = "Approved via external system"
-
-		// Create checkpoint for resumption.
-		checkpoint := store.CheckpointV2[ApprovalState]{
-			RunID:  runID,
-			StepID: latestStep,
-			State:  latestState,
-		}
-
-		fmt.Println("\n▶️  Resuming workflow from checkpoint...")
-		final, err = engine.RunWithCheckpoint(ctx, checkpoint)
-		if err != nil {
-			fmt.Printf("Error resuming: %v\n", err)
-			return
-		}
-
-		fmt.Println("\n✅ Workflow resumed and completed successfully")
+	// Apply approval (simulating external approval system).
+	fmt.Println("\n▶️  Resuming workflow from checkpoint...")
+	if _, err := engine.Resume(ctx, runID, ApprovalDecision{Approved: true, Comment: "Approved via external system"}); err != nil {
+		fmt.Printf("Error resuming: %v\n", err)
+		return
 	}
+
+	fmt.Println("\n✅ Workflow resumed and completed successfully")
 }
 
 // demonstrateRejectionAndRetry shows rejection with regeneration.
@@ -316,7 +286,7 @@ func demonstrateRejectionAndRetry() {
 	fmt.Println(strings.Repeat("=", 70))
 
 	ctx := context.Background()
-	engine, st := setupEngine()
+	engine := setupEngine()
 	runID := "rejection-demo-001"
 
 	// Start workflow.
@@ -325,74 +295,32 @@ func demonstrateRejectionAndRetry() {
 		Request: "Draft customer apology email",
 	}
 
-	final, err := engine.Run(ctx, runID, initialState)
-	if err != nil {
+	_, err := engine.Run(ctx, runID, initialState)
+	if !errors.Is(err, graph.ErrInterrupted) {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	// Simulate rejection.
-	if final.Approved == nil {
-		fmt.Println("\n📋 Simulating rejection by reviewer...")
-
-		// Load latest state.
-		latestState, latestStep, err := st.LoadLatest(ctx, runID)
-		if err != nil {
-			fmt.Printf("Error loading latest state: %v\n", err)
-			return
-		}
-
-		// Reject with feedback.
-		approved := false
-		latestState.Approved = &approved
-		latestState.ApprovalComment = "Tone is too formal - needs to be more personal"
-
-		// Create checkpoint for resumption.
-		checkpoint := store.CheckpointV2[ApprovalState]{
-			RunID:  runID,
-			StepID: latestStep,
-			State:  latestState,
-		}
-
-		// Resume - will regenerate.
-		fmt.Println("\n▶️  Resuming workflow...")
-		final, err = engine.RunWithCheckpoint(ctx, checkpoint)
-		if err != nil {
-			fmt.Printf("Error resuming: %v\n", err)
-			return
-		}
+	// Simulate rejection - the run regenerates and pauses at the gate again.
+	fmt.Println("\n📋 Simulating rejection by reviewer...")
+	fmt.Println("\n▶️  Resuming workflow...")
+	_, err = engine.Resume(ctx, runID, ApprovalDecision{
+		Approved: false,
+		Comment:  "Tone is too formal - needs to be more personal",
+	})
+	if !errors.Is(err, graph.ErrInterrupted) {
+		fmt.Printf("Error resuming: %v\n", err)
+		return
+	}
 
-		// Now at approval gate again after regeneration.
-		if final.Approved == nil {
-			fmt.Println("\n📋 New output generated - awaiting approval again...")
-
-			// Load latest state again.
-			latestState, latestStep, err = st.LoadLatest(ctx, runID)
-			if err != nil {
-				fmt.Printf("Error loading latest state: %v\n", err)
-				return
-			}
-
-			// Approve the revised version.
-			approved = true
-			latestState.Approved = &approved
-			latestState.ApprovalComment = "Much better - approved!"
-
-			checkpoint = store.CheckpointV2[ApprovalState]{
-				RunID:  runID,
-				StepID: latestStep,
-				State:  latestState,
-			}
-
-			final, err = engine.RunWithCheckpoint(ctx, checkpoint)
-			if err != nil {
-				fmt.Printf("Error resuming: %v\n", err)
-				return
-			}
-
-			fmt.Println("\n✅ Revised output approved and finalized")
-		}
+	// Approve the revised version.
+	fmt.Println("\n📋 New output generated - awaiting approval again...")
+	if _, err := engine.Resume(ctx, runID, ApprovalDecision{Approved: true, Comment: "Much better - approved!"}); err != nil {
+		fmt.Printf("Error resuming: %v\n", err)
+		return
 	}
+
+	fmt.Println("\n✅ Revised output approved and finalized")
 }
 
 func main() {
@@ -0,0 +1,156 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// cachedResult is a node result as stored in a store.ResultCache.
+type cachedResult[S any] struct {
+	Delta S    `json:"delta"`
+	Route Next `json:"route"`
+}
+
+// nodeCache is the cache entry a work item's result is looked up and saved
+// under.
+type nodeCache struct {
+	cache store.ResultCache
+	key   string
+	ttl   time.Duration
+}
+
+// cacheFor returns the cache entry of item under its node's CachePolicy, or
+// nil if its result is not cached. Items resuming an interrupted node are
+// never cached, since their result depends on the Resume input. Items started
+// by a Sends route are also keyed by their input.
+//
+// Returns an EngineError with code CACHE_UNSUPPORTED if the policy has no
+// Cache and the engine's store does not implement store.ResultCache.
+func (e *Engine[S]) cacheFor(runID string, policy *NodePolicy, item WorkItem[S]) (*nodeCache, error) {
+	if policy == nil || policy.CachePolicy == nil || item.Interrupted {
+		return nil, nil
+	}
+	cachePol := policy.CachePolicy
+
+	cache := cachePol.Cache
+	if cache == nil {
+		cache, _ = e.store.(store.ResultCache)
+	}
+	if cache == nil {
+		return nil, &EngineError{
+			Message: "node " + item.NodeID + " has a CachePolicy but no cache: set CachePolicy.Cache or use a store implementing store.ResultCache",
+			Code:    "CACHE_UNSUPPORTED",
+		}
+	}
+
+	var key string
+	if cachePol.Key != nil {
+		key = cachePol.Key(item.State)
+	} else {
+		stateJSON, err := json.Marshal(item.State)
+		if err != nil {
+			e.emitCacheFailed(runID, item, "key", err)
+			return nil, nil
+		}
+		sum := sha256.Sum256(stateJSON)
+		key = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	if key == "" {
+		return nil, nil
+	}
+
+	// Branches of a Sends route share their state, so their input is part
+	// of the key
+	if item.Input != nil {
+		inputJSON, err := canonicalJSON(item.Input)
+		if err != nil {
+			e.emitCacheFailed(runID, item, "key", err)
+			return nil, nil
+		}
+		sum := sha256.Sum256(inputJSON)
+		key += ":input:sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return &nodeCache{cache: cache, key: item.NodeID + ":" + key, ttl: cachePol.TTL}, nil
+}
+
+// loadCached looks item's result up in its cache, emitting node_cache_hit or
+// node_cache_miss and counting the lookup. A result that cannot be loaded is
+// a miss.
+func (e *Engine[S]) loadCached(ctx context.Context, runID string, item WorkItem[S], cache *nodeCache) (NodeResult[S], bool) {
+	var result NodeResult[S]
+	hit := false
+
+	data, err := cache.cache.LoadCachedResult(ctx, cache.key)
+	if err == nil {
+		var cached cachedResult[S]
+		if err = json.Unmarshal(data, &cached); err == nil {
+			result, hit = NodeResult[S]{Delta: cached.Delta, Route: cached.Route}, true
+		}
+	}
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		e.emitCacheFailed(runID, item, "load", err)
+	}
+
+	msg, outcome := "node_cache_miss", "miss"
+	if hit {
+		msg, outcome = "node_cache_hit", "hit"
+	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    msg,
+		Meta: map[string]interface{}{
+			"cache_key": cache.key,
+		},
+	})
+	if e.metrics != nil {
+		e.metrics.IncrementCacheLookups(item.NodeID, outcome)
+	}
+	return result, hit
+}
+
+// saveCached caches the successful result of item. Results routing with
+// Sends, whose inputs would not decode to their original types, and
+// interrupting results are not cached.
+func (e *Engine[S]) saveCached(ctx context.Context, runID string, item WorkItem[S], cache *nodeCache, result NodeResult[S]) {
+	if len(result.Route.Sends) > 0 || result.Route.Interrupt {
+		return
+	}
+
+	data, err := json.Marshal(cachedResult[S]{Delta: result.Delta, Route: result.Route})
+	if err == nil {
+		var expiresAt time.Time
+		if cache.ttl > 0 {
+			expiresAt = time.Now().Add(cache.ttl)
+		}
+		err = cache.cache.SaveCachedResult(ctx, cache.key, data, expiresAt)
+	}
+	if err != nil {
+		e.emitCacheFailed(runID, item, "save", err)
+	}
+}
+
+// emitCacheFailed emits a node_cache_failed event for a cache operation that
+// failed. The node executes as if its result were not cached.
+func (e *Engine[S]) emitCacheFailed(runID string, item WorkItem[S], operation string, err error) {
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    "node_cache_failed",
+		Meta: map[string]interface{}{
+			"operation": operation,
+			"error":     err.Error(),
+		},
+	})
+}
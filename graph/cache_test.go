@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestEngine_CachePolicy verifies nodes with a CachePolicy reuse the results
+// cached for their input state.
+func TestEngine_CachePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Topic   string
+		Results []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Results = append(prev.Results, delta.Results...)
+		return prev
+	}
+
+	// newEngine builds a single "embed" node under cachePol, which counts its
+	// executions in calls and appends "embed:<topic>" to the results.
+	newEngine := func(st store.Store[TestState], emitter *mockEmitter, opts Options, cachePol *CachePolicy, calls *atomic.Int32) *Engine[TestState] {
+		engine := New(reducer, st, emitter, opts)
+		embed := NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+			calls.Add(1)
+			return NodeResult[TestState]{Delta: TestState{Results: []string{"embed:" + s.Topic}}, Route: Stop()}
+		})
+		if err := engine.Add("embed", policyNode[TestState]{Node: embed, policy: NodePolicy{CachePolicy: cachePol}}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("embed"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range []struct {
+		name string
+		opts Options
+	}{
+		{name: "sequential", opts: Options{MaxSteps: 20}},
+		{name: "concurrent", opts: Options{MaxSteps: 20, MaxConcurrentNodes: 4}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("hit skips the node", func(t *testing.T) {
+				var calls atomic.Int32
+				emitter := &mockEmitter{}
+				metrics := NewPrometheusMetrics(prometheus.NewRegistry())
+				opts := mode.opts
+				opts.Metrics = metrics
+				engine := newEngine(store.NewMemStore[TestState](), emitter, opts, &CachePolicy{}, &calls)
+
+				for i, topic := range []string{"cats", "cats", "dogs"} {
+					final, err := engine.Run(ctx, fmt.Sprintf("run-%03d", i+1), TestState{Topic: topic})
+					if err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+					if !reflect.DeepEqual(final.Results, []string{"embed:" + topic}) {
+						t.Errorf("results %v, want the %s result", final.Results, topic)
+					}
+				}
+				if got := calls.Load(); got != 2 {
+					t.Errorf("embed executed %d times, want 2", got)
+				}
+
+				var lookups []string
+				for _, event := range emitter.events {
+					if event.Msg == "node_cache_hit" || event.Msg == "node_cache_miss" {
+						lookups = append(lookups, event.Msg)
+					}
+				}
+				if !reflect.DeepEqual(lookups, []string{"node_cache_miss", "node_cache_hit", "node_cache_miss"}) {
+					t.Errorf("cache events = %v, want miss, hit, miss", lookups)
+				}
+				if got := testutil.ToFloat64(metrics.cacheLookups.WithLabelValues("embed", "hit")); got != 1 {
+					t.Errorf("node_cache_lookups_total{result=hit} = %v, want 1", got)
+				}
+				if got := testutil.ToFloat64(metrics.cacheLookups.WithLabelValues("embed", "miss")); got != 2 {
+					t.Errorf("node_cache_lookups_total{result=miss} = %v, want 2", got)
+				}
+			})
+
+			t.Run("expired results execute again", func(t *testing.T) {
+				var calls atomic.Int32
+				engine := newEngine(store.NewMemStore[TestState](), &mockEmitter{}, mode.opts, &CachePolicy{TTL: 10 * time.Millisecond}, &calls)
+				for _, runID := range []string{"run-001", "run-002"} {
+					if _, err := engine.Run(ctx, runID, TestState{Topic: "cats"}); err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				if _, err := engine.Run(ctx, "run-003", TestState{Topic: "cats"}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if got := calls.Load(); got != 2 {
+					t.Errorf("embed executed %d times, want 2", got)
+				}
+			})
+
+			t.Run("key function", func(t *testing.T) {
+				var calls atomic.Int32
+				// Cache by topic alone; an empty topic is not cached
+				key := func(state any) string { return state.(TestState).Topic }
+				engine := newEngine(store.NewMemStore[TestState](), &mockEmitter{}, mode.opts, &CachePolicy{Key: key}, &calls)
+				runs := []TestState{
+					{Topic: "cats"},
+					{Topic: "cats", Results: []string{"earlier"}},
+					{},
+					{},
+				}
+				for i, initial := range runs {
+					if _, err := engine.Run(ctx, fmt.Sprintf("run-%03d", i+1), initial); err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+				}
+				if got := calls.Load(); got != 3 {
+					t.Errorf("embed executed %d times, want 3", got)
+				}
+			})
+
+			t.Run("sends are keyed by input", func(t *testing.T) {
+				for _, cachePol := range []*CachePolicy{
+					{},
+					{Key: func(any) string { return "same" }},
+				} {
+					var calls atomic.Int32
+					engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, mode.opts)
+					fan := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+						return NodeResult[TestState]{Route: SendEach("sum", []string{"a", "b", "c"})}
+					})
+					sum := NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+						calls.Add(1)
+						input, _ := SendInputAs[string](ctx)
+						return NodeResult[TestState]{Delta: TestState{Results: []string{"sum(" + input + ")"}}, Route: Stop()}
+					})
+					if err := engine.Add("fan", fan); err != nil {
+						t.Fatalf("Add failed: %v", err)
+					}
+					if err := engine.Add("sum", policyNode[TestState]{Node: sum, policy: NodePolicy{CachePolicy: cachePol}}); err != nil {
+						t.Fatalf("Add failed: %v", err)
+					}
+					if err := engine.StartAt("fan"); err != nil {
+						t.Fatalf("StartAt failed: %v", err)
+					}
+
+					for _, runID := range []string{"run-001", "run-002"} {
+						final, err := engine.Run(ctx, runID, TestState{})
+						if err != nil {
+							t.Fatalf("Run failed: %v", err)
+						}
+						sort.Strings(final.Results)
+						if want := []string{"sum(a)", "sum(b)", "sum(c)"}; !reflect.DeepEqual(final.Results, want) {
+							t.Errorf("results %v, want %v", final.Results, want)
+						}
+					}
+					if got := calls.Load(); got != 3 {
+						t.Errorf("sum executed %d times, want 3", got)
+					}
+				}
+			})
+		})
+	}
+
+	t.Run("cache shared through SQLite", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.db")
+		var calls atomic.Int32
+		for _, runID := range []string{"run-001", "run-002"} {
+			// Each engine opens the cache as a process restarting would
+			cache, err := store.NewSQLiteStore[TestState](path)
+			if err != nil {
+				t.Fatalf("NewSQLiteStore failed: %v", err)
+			}
+			engine := newEngine(store.NewMemStore[TestState](), &mockEmitter{}, Options{MaxSteps: 20}, &CachePolicy{Cache: cache}, &calls)
+			final, err := engine.Run(ctx, runID, TestState{Topic: "cats"})
+			_ = cache.Close()
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if !reflect.DeepEqual(final.Results, []string{"embed:cats"}) {
+				t.Errorf("results %v, want the cached result", final.Results)
+			}
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("embed executed %d times, want 1", got)
+		}
+	})
+
+	t.Run("failed results are not cached", func(t *testing.T) {
+		var calls atomic.Int32
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{MaxSteps: 20})
+		flaky := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			if calls.Add(1) == 1 {
+				return NodeResult[TestState]{Err: errors.New("model unavailable")}
+			}
+			return NodeResult[TestState]{Delta: TestState{Results: []string{"embed"}}, Route: Stop()}
+		})
+		if err := engine.Add("embed", policyNode[TestState]{Node: flaky, policy: NodePolicy{CachePolicy: &CachePolicy{}}}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("embed"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		if _, err := engine.Run(ctx, "run-001", TestState{}); err == nil {
+			t.Fatal("expected the first run to fail")
+		}
+		for _, runID := range []string{"run-002", "run-003"} {
+			if _, err := engine.Run(ctx, runID, TestState{}); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("embed executed %d times, want 2", got)
+		}
+	})
+
+	t.Run("store without a cache", func(t *testing.T) {
+		var calls atomic.Int32
+		// Embedding the interface hides MemStore's ResultCache methods
+		st := struct{ store.Store[TestState] }{store.NewMemStore[TestState]()}
+		engine := newEngine(st, &mockEmitter{}, Options{MaxSteps: 20}, &CachePolicy{}, &calls)
+		var engineErr *EngineError
+		if _, err := engine.Run(ctx, "run-001", TestState{}); !errors.As(err, &engineErr) || engineErr.Code != "CACHE_UNSUPPORTED" {
+			t.Errorf("expected CACHE_UNSUPPORTED, got %v", err)
+		}
+		if calls.Load() != 0 {
+			t.Error("expected the node not to execute")
+		}
+	})
+}
@@ -76,7 +76,7 @@ type Checkpoint[S any] struct {
 	// Computed from RunID to ensure consistent random values across replays.
 	RNGSeed int64 `json:"rng_seed"`
 
-	// RecordedIOs contains all captured external interactions up to this checkpoint.
+	// RecordedIOs contains the external interactions captured since the run's previous checkpoint.
 	// Indexed by (NodeID, Attempt) for lookup during replay.
 	RecordedIOs []RecordedIO `json:"recorded_ios"`
 
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -1144,3 +1145,215 @@ func TestCompletionDetectionStress(t *testing.T) {
 		t.Errorf("CRITICAL: %d delayed terminations detected (polling inefficiency)", delayed)
 	}
 }
+
+// ============================================================================
+// Concurrent Runs on a Shared Engine
+// ============================================================================
+
+// recordableNode declares Recordable I/O for a node.
+type recordableNode[S any] struct {
+	Node[S]
+}
+
+func (recordableNode[S]) Effects() SideEffectPolicy {
+	return SideEffectPolicy{Recordable: true}
+}
+
+// TestEngine_ConcurrentRuns verifies that runs executing in parallel on one
+// engine keep their frontier, step counter, RNG and recorded I/O to
+// themselves: each produces what it produces when run alone. Run it with
+// -race to detect shared run state.
+func TestEngine_ConcurrentRuns(t *testing.T) {
+	const numRuns = 16
+	// Runs that corrupt each other's queue may never finish
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	type TestState struct {
+		Topic   string
+		Results []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Results = append(prev.Results, delta.Results...)
+		return prev
+	}
+
+	// newEngine builds a compiled engine where a draws from the run's RNG
+	// and records a lookup of the topic before fanning out: a -> {x, y}
+	newEngine := func(st store.Store[TestState], mode int) *Engine[TestState] {
+		engine := New(reducer, st, emit.NewNullEmitter(), Options{MaxSteps: 20, MaxConcurrentNodes: mode})
+		a := NodeFunc[TestState](func(ctx context.Context, s TestState) NodeResult[TestState] {
+			draw := ctx.Value(RNGKey).(*rand.Rand).Intn(1_000_000)
+			out, err := Recorded(ctx, "lookup", s.Topic, func() (string, error) {
+				return "io:" + s.Topic, nil
+			})
+			if err != nil {
+				return NodeResult[TestState]{Err: err}
+			}
+			return NodeResult[TestState]{
+				Delta: TestState{Results: []string{fmt.Sprintf("a:%s:%d:%s", s.Topic, draw, out)}},
+				Route: Many([]string{"x", "y"}),
+			}
+		})
+		leaf := func(id string) Node[TestState] {
+			return NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Delta: TestState{Results: []string{id + ":" + s.Topic}}, Route: Stop()}
+			})
+		}
+		_ = engine.Add("a", recordableNode[TestState]{Node: a})
+		_ = engine.Add("x", leaf("x"))
+		_ = engine.Add("y", leaf("y"))
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if _, err := engine.Compile(); err != nil {
+			t.Fatalf("Compile failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range []struct {
+		name string
+		mode int
+	}{
+		{name: "sequential", mode: 0},
+		{name: "concurrent", mode: 4},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			// Each run alone, one after another
+			want := make([]TestState, numRuns)
+			serial := newEngine(store.NewMemStore[TestState](), mode.mode)
+			for i := range want {
+				final, err := serial.Run(ctx, fmt.Sprintf("run-%02d", i), TestState{Topic: fmt.Sprintf("topic-%02d", i)})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				want[i] = final
+			}
+
+			// All runs at once on a shared engine
+			st := store.NewMemStore[TestState]()
+			engine := newEngine(st, mode.mode)
+			got := make([]TestState, numRuns)
+			errs := make([]error, numRuns)
+			var wg sync.WaitGroup
+			for i := range got {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					got[i], errs[i] = engine.Run(ctx, fmt.Sprintf("run-%02d", i), TestState{Topic: fmt.Sprintf("topic-%02d", i)})
+				}(i)
+			}
+			wg.Wait()
+
+			for i := range got {
+				runID := fmt.Sprintf("run-%02d", i)
+				if errs[i] != nil {
+					t.Errorf("%s failed: %v", runID, errs[i])
+					continue
+				}
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Errorf("%s = %+v, want %+v", runID, got[i], want[i])
+				}
+
+				// The final checkpoint holds the run's own steps and I/O
+				checkpoint, err := engine.latestCheckpoint(ctx, runID)
+				if err != nil {
+					t.Fatalf("latestCheckpoint failed: %v", err)
+				}
+				recorded, err := decodeRecordedIOs(checkpoint.RecordedIOs)
+				if err != nil {
+					t.Fatalf("decodeRecordedIOs failed: %v", err)
+				}
+				if checkpoint.StepID != 3 || len(recorded) != 1 || string(recorded[0].Request) != fmt.Sprintf("%q", fmt.Sprintf("topic-%02d", i)) {
+					t.Errorf("%s checkpoint at step %d with I/O %+v, want step 3 and its own lookup", runID, checkpoint.StepID, recorded)
+				}
+			}
+			if active := engine.ActiveRuns(); len(active) != 0 {
+				t.Errorf("ActiveRuns after completion = %+v, want none", active)
+			}
+		})
+	}
+}
+
+// TestEngine_Priority verifies saturated workers execute higher-priority
+// nodes first without changing the order their deltas merge in.
+func TestEngine_Priority(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Urgent bool
+		Merged []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Merged = append(prev.Merged, delta.Merged...)
+		return prev
+	}
+
+	// newEngine builds fan -> {answer, enrich1, enrich2} on a single worker,
+	// recording the order the branches execute in. answer takes the
+	// priority pol gives it.
+	newEngine := func(pol NodePolicy, executed *[]string) *Engine[TestState] {
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{MaxSteps: 20, MaxConcurrentNodes: 1})
+		var mu sync.Mutex
+		node := func(id string, route Next) Node[TestState] {
+			return NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				mu.Lock()
+				*executed = append(*executed, id)
+				mu.Unlock()
+				return NodeResult[TestState]{Delta: TestState{Merged: []string{id}}, Route: route}
+			})
+		}
+		_ = engine.Add("fan", node("fan", Many([]string{"answer", "enrich1", "enrich2"})))
+		_ = engine.Add("answer", policyNode[TestState]{Node: node("answer", Stop()), policy: pol})
+		_ = engine.Add("enrich1", node("enrich1", Stop()))
+		_ = engine.Add("enrich2", node("enrich2", Stop()))
+		if err := engine.StartAt("fan"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	var baseline []string
+	unprioritized := newEngine(NodePolicy{}, &baseline)
+	want, err := unprioritized.Run(ctx, "run-001", TestState{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if baseline[1] == "answer" {
+		t.Fatalf("execution order = %v; answer already runs first by OrderKey", baseline)
+	}
+
+	urgent := func(state any) int {
+		if state.(TestState).Urgent {
+			return 10
+		}
+		return 0
+	}
+	for _, tc := range []struct {
+		name   string
+		pol    NodePolicy
+		urgent bool
+		first  string
+	}{
+		{name: "static", pol: NodePolicy{Priority: 10}, first: "answer"},
+		{name: "from state", pol: NodePolicy{PriorityFunc: urgent}, urgent: true, first: "answer"},
+		{name: "from state, not urgent", pol: NodePolicy{Priority: 10, PriorityFunc: urgent}, first: baseline[1]},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var executed []string
+			engine := newEngine(tc.pol, &executed)
+			final, err := engine.Run(ctx, "run-001", TestState{Urgent: tc.urgent})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if executed[1] != tc.first {
+				t.Errorf("execution order = %v, want %s right after fan", executed, tc.first)
+			}
+			// Deltas still merge in OrderKey order
+			if !reflect.DeepEqual(final.Merged, want.Merged) {
+				t.Errorf("merged %v, want %v", final.Merged, want.Merged)
+			}
+		})
+	}
+}
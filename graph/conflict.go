@@ -0,0 +1,359 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// ConflictError is returned when concurrent branches write different values to
+// the same state field under the ConflictFail or ConflictCRDT policy.
+type ConflictError struct {
+	// RunID is the run in which the conflict was detected.
+	RunID string
+
+	// NodeID is the join node where the branches were merged, or empty when
+	// they were merged into the run's state.
+	NodeID string
+
+	// Fields lists the conflicting state fields, in declaration order.
+	Fields []string
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	where := "run state"
+	if e.NodeID != "" {
+		where = "join node " + e.NodeID
+	}
+	return fmt.Sprintf("%s: run %s, %s: fields %s", ErrMergeConflict.Error(), e.RunID, where, strings.Join(e.Fields, ", "))
+}
+
+// Is reports whether target is ErrMergeConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrMergeConflict
+}
+
+// conflictField is a state field that concurrent branches can conflict on.
+type conflictField struct {
+	index int
+	name  string
+}
+
+// fieldWrite is a non-zero value a node wrote to a state field.
+type fieldWrite struct {
+	slots []ForkSlot // Branch the write was made on
+	step  int
+	value reflect.Value
+}
+
+// fieldHistory holds the writes to a field merged into the run state that
+// later writes can still conflict with.
+type fieldHistory struct {
+	writes     []fieldWrite
+	conflicted bool // A conflict was detected since the last resolve
+	fresh      bool // The conflict involves branches not reported yet
+}
+
+// conflictTracker detects and resolves conflicting field writes for one run
+// according to the engine's ConflictPolicy. It is safe for concurrent use.
+type conflictTracker[S any] struct {
+	e      *Engine[S]
+	runID  string
+	policy ConflictPolicy
+	fields []conflictField
+
+	mu       sync.Mutex
+	history  []fieldHistory
+	reported map[string]bool // Conflicting branch pairs already reported, per field
+}
+
+// detectsConflicts reports whether the engine detects conflicts, which every
+// policy but ConflictNone does.
+func (e *Engine[S]) detectsConflicts() bool {
+	return e.opts.ConflictPolicy != ConflictNone
+}
+
+// newConflictTracker returns the run's conflict tracker, or nil if conflicts
+// are not detected (see detectsConflicts) or S has no fields that can
+// conflict.
+func (e *Engine[S]) newConflictTracker(runID string) *conflictTracker[S] {
+	if !e.detectsConflicts() {
+		return nil
+	}
+	fields := conflictFields(reflect.TypeOf((*S)(nil)).Elem())
+	if len(fields) == 0 {
+		return nil
+	}
+	return &conflictTracker[S]{
+		e:        e,
+		runID:    runID,
+		policy:   e.opts.ConflictPolicy,
+		fields:   fields,
+		history:  make([]fieldHistory, len(fields)),
+		reported: make(map[string]bool),
+	}
+}
+
+// conflictFields returns the exported fields of struct type t that can
+// conflict: those without a reduce strategy other than replace that are not
+// CRDTs.
+func conflictFields(t reflect.Type) []conflictField {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []conflictField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || mergeMethod(field.Type) >= 0 {
+			continue
+		}
+		if strategy, ok := field.Tag.Lookup("reduce"); ok && strategy != ReduceReplace {
+			continue
+		}
+		fields = append(fields, conflictField{index: i, name: field.Name})
+	}
+	return fields
+}
+
+// observe records the fields delta writes to the run state and detects
+// conflicts with earlier writes. slots and step identify the work item that
+// produced delta; deltas must be observed in step order.
+func (t *conflictTracker[S]) observe(slots []ForkSlot, step int, delta S) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	value := reflect.ValueOf(delta)
+	for i, field := range t.fields {
+		written := value.Field(field.index)
+		if written.IsZero() {
+			continue
+		}
+		w := fieldWrite{slots: slots, step: step, value: written}
+		h := &t.history[i]
+
+		// A write outside any fan-out follows every earlier write, so none of
+		// them can conflict with later ones
+		if len(slots) == 0 {
+			h.writes = []fieldWrite{w}
+			continue
+		}
+		for _, earlier := range h.writes {
+			if key, ok := conflictKey(earlier, w); ok {
+				h.conflicted = true
+				if !t.reported[field.name+"|"+key] {
+					t.reported[field.name+"|"+key] = true
+					h.fresh = true
+				}
+			}
+		}
+		h.writes = append(h.writes, w)
+	}
+}
+
+// resolve applies the policy to the conflicts observed since the last call.
+// state is the run state the observed deltas were merged into.
+func (t *conflictTracker[S]) resolve(state S) (S, error) {
+	if t == nil {
+		return state, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resolved := reflect.ValueOf(&state).Elem()
+	var conflicted, fresh []string
+	for i, field := range t.fields {
+		h := &t.history[i]
+		if !h.conflicted {
+			continue
+		}
+		conflicted = append(conflicted, field.name)
+		if h.fresh {
+			fresh = append(fresh, field.name)
+		}
+		h.conflicted, h.fresh = false, false
+
+		if t.policy == LastWriterWins {
+			if winner, ok := lastWriter(h.writes); ok {
+				resolved.Field(field.index).Set(winner.value)
+			}
+		}
+	}
+
+	return t.finish("", state, conflicted, fresh)
+}
+
+// resolveJoin checks the branches merged at join node nodeID for conflicts and
+// applies the policy to their merged state. Each arrival's value for a field
+// is the last one it wrote since the fan-out.
+func (t *conflictTracker[S]) resolveJoin(nodeID string, arrivals []joinArrival[S], state S) (S, error) {
+	if t == nil {
+		return state, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resolved := reflect.ValueOf(&state).Elem()
+	var conflicted, fresh []string
+	for _, field := range t.fields {
+		var writes []fieldWrite
+		for _, a := range arrivals {
+			deltas := branchDeltasOf(a)
+			for j := len(deltas) - 1; j >= 0; j-- {
+				if written := reflect.ValueOf(deltas[j]).Field(field.index); !written.IsZero() {
+					writes = append(writes, fieldWrite{slots: a.item.ForkSlots, step: a.item.StepID, value: written})
+					break
+				}
+			}
+		}
+
+		isConflicted, isFresh := false, false
+		for j := range writes {
+			for k := j + 1; k < len(writes); k++ {
+				if key, ok := conflictKey(writes[j], writes[k]); ok {
+					isConflicted = true
+					if !t.reported[field.name+"|"+key] {
+						t.reported[field.name+"|"+key] = true
+						isFresh = true
+					}
+				}
+			}
+		}
+		if !isConflicted {
+			continue
+		}
+		conflicted = append(conflicted, field.name)
+		if isFresh {
+			fresh = append(fresh, field.name)
+		}
+		if t.policy == LastWriterWins {
+			if winner, ok := lastWriter(writes); ok {
+				resolved.Field(field.index).Set(winner.value)
+			}
+		}
+	}
+
+	return t.finish(nodeID, state, conflicted, fresh)
+}
+
+// finish reports the fresh conflicts and returns the policy's outcome for the
+// conflicted fields. Callers must hold t.mu.
+func (t *conflictTracker[S]) finish(nodeID string, state S, conflicted, fresh []string) (S, error) {
+	if len(fresh) > 0 {
+		t.report(nodeID, fresh)
+	}
+	if len(conflicted) > 0 && t.policy != LastWriterWins {
+		var zero S
+		return zero, &ConflictError{RunID: t.runID, NodeID: nodeID, Fields: conflicted}
+	}
+	return state, nil
+}
+
+// report emits a merge_conflict event and updates the merge conflict metric.
+func (t *conflictTracker[S]) report(nodeID string, fields []string) {
+	t.e.publish(emit.Event{
+		RunID:  t.runID,
+		NodeID: nodeID,
+		Msg:    "merge_conflict",
+		Meta: map[string]interface{}{
+			"fields": fields,
+			"policy": t.policy.String(),
+		},
+	})
+	if t.e.metrics != nil {
+		t.e.metrics.IncrementMergeConflicts(t.runID, t.policy.String(), fields...)
+	}
+}
+
+// conflictKey reports whether writes a and b conflict: they were made on
+// concurrent branches and hold different values. The key identifies the pair
+// of branches.
+func conflictKey(a, b fieldWrite) (string, bool) {
+	level, concurrent := divergence(a.slots, b.slots)
+	if !concurrent || reflect.DeepEqual(a.value.Interface(), b.value.Interface()) {
+		return "", false
+	}
+	x, y := a.slots[level].Branch, b.slots[level].Branch
+	return fmt.Sprintf("%016x|%016x", min(x, y), max(x, y)), true
+}
+
+// divergence reports whether two writes were made on concurrent branches of
+// the same fan-out, and the fork level of that fan-out. Writes on the same
+// branch, or where one branch encloses the other, were made in sequence.
+func divergence(a, b []ForkSlot) (int, bool) {
+	for level := 0; level < len(a) && level < len(b); level++ {
+		if a[level] == b[level] {
+			continue
+		}
+		// Branches of different fan-outs at the same level ran one after the
+		// other, for example in two iterations of a loop
+		return level, a[level].Fanout == b[level].Fanout
+	}
+	return 0, false
+}
+
+// lastWriter returns the write that stands among writes: of two concurrent
+// writes the one on the branch with the higher OrderKey at the fan-out where
+// they diverge, and of two sequential writes the later one. ok is false if
+// there are no writes.
+func lastWriter(writes []fieldWrite) (fieldWrite, bool) {
+	if len(writes) == 0 {
+		return fieldWrite{}, false
+	}
+	winner := writes[0]
+	for _, w := range writes[1:] {
+		level, concurrent := divergence(winner.slots, w.slots)
+		if concurrent && w.slots[level].Branch > winner.slots[level].Branch ||
+			!concurrent && w.step > winner.step {
+			winner = w
+		}
+	}
+	return winner, true
+}
+
+// crdtReducer wraps reducer so that every CRDT field of S (a type with a
+// Merge method, such as those in graph/crdt) is merged with its Merge method,
+// overriding whatever reducer did with the field. It returns reducer unchanged
+// if S has no CRDT fields.
+func crdtReducer[S any](reducer Reducer[S]) Reducer[S] {
+	t := reflect.TypeOf((*S)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return reducer
+	}
+
+	type crdtField struct {
+		index  int
+		method int
+	}
+	var fields []crdtField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if method := mergeMethod(field.Type); field.IsExported() && method >= 0 {
+			fields = append(fields, crdtField{index: i, method: method})
+		}
+	}
+	if len(fields) == 0 {
+		return reducer
+	}
+
+	return func(prev, delta S) S {
+		out := reducer(prev, delta)
+		merged := reflect.ValueOf(&out).Elem()
+		prevValue, deltaValue := reflect.ValueOf(prev), reflect.ValueOf(delta)
+		for _, f := range fields {
+			current := prevValue.Field(f.index)
+			if written := deltaValue.Field(f.index); !written.IsZero() {
+				current = current.Method(f.method).Call([]reflect.Value{written})[0]
+			}
+			merged.Field(f.index).Set(current)
+		}
+		return out
+	}
+}
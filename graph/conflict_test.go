@@ -0,0 +1,354 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// ConflictTestState has a field that can conflict, a field merged by its
+// reduce tag and a CRDT field.
+type ConflictTestState struct {
+	Summary string
+	Notes   []string `reduce:"append"`
+	Views   conflictTestCounter
+}
+
+// conflictTestCounter is a minimal grow-only counter CRDT keyed by node
+// execution. The graph/crdt package cannot be imported here.
+type conflictTestCounter struct {
+	Counts map[string]int
+}
+
+func (c conflictTestCounter) Increment(ctx context.Context) conflictTestCounter {
+	replica := fmt.Sprintf("%v@%v", ctx.Value(NodeIDKey), ctx.Value(OrderKeyKey))
+	return c.Merge(conflictTestCounter{Counts: map[string]int{replica: c.Counts[replica] + 1}})
+}
+
+func (c conflictTestCounter) Merge(other conflictTestCounter) conflictTestCounter {
+	merged := conflictTestCounter{Counts: make(map[string]int, len(c.Counts)+len(other.Counts))}
+	for _, counts := range []map[string]int{c.Counts, other.Counts} {
+		for replica, n := range counts {
+			merged.Counts[replica] = max(merged.Counts[replica], n)
+		}
+	}
+	return merged
+}
+
+func (c conflictTestCounter) Value() int {
+	total := 0
+	for _, n := range c.Counts {
+		total += n
+	}
+	return total
+}
+
+// newConflictTestEngine builds an engine whose "split" node fans out to "a" and
+// "b", which write Summary and route to "done". With join set, "done" joins a
+// and b; otherwise each branch runs it.
+func newConflictTestEngine(t *testing.T, mode int, policy ConflictPolicy, join bool, a, b string) (*Engine[ConflictTestState], *mockEmitter, *ConflictTestState) {
+	t.Helper()
+
+	reducer, err := NewReducer[ConflictTestState]()
+	if err != nil {
+		t.Fatalf("NewReducer failed: %v", err)
+	}
+	emitter := &mockEmitter{}
+	engine := New(reducer, store.NewMemStore[ConflictTestState](), emitter, Options{
+		MaxSteps:           20,
+		MaxConcurrentNodes: mode,
+		ConflictPolicy:     policy,
+	})
+
+	var mu sync.Mutex
+	joined := &ConflictTestState{}
+	branch := func(summary string) NodeFunc[ConflictTestState] {
+		return func(ctx context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+			return NodeResult[ConflictTestState]{
+				Delta: ConflictTestState{
+					Summary: summary,
+					Notes:   []string{summary},
+					Views:   s.Views.Increment(ctx),
+				},
+				Route: Goto("done"),
+			}
+		}
+	}
+
+	nodes := map[string]NodeFunc[ConflictTestState]{
+		"split": func(_ context.Context, _ ConflictTestState) NodeResult[ConflictTestState] {
+			return NodeResult[ConflictTestState]{Route: Many([]string{"a", "b"})}
+		},
+		"a": branch(a),
+		"b": branch(b),
+		"done": func(_ context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+			mu.Lock()
+			*joined = s
+			mu.Unlock()
+			return NodeResult[ConflictTestState]{Route: Stop()}
+		},
+	}
+	for id, node := range nodes {
+		if err := engine.Add(id, node); err != nil {
+			t.Fatalf("Add(%s) failed: %v", id, err)
+		}
+	}
+	if join {
+		if err := engine.Join("done", "a", "b"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+	}
+	if err := engine.StartAt("split"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+	return engine, emitter, joined
+}
+
+// conflictEvents returns the merge_conflict events emitted.
+func conflictEvents(emitter *mockEmitter) []emit.Event {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+
+	var events []emit.Event
+	for _, event := range emitter.events {
+		if event.Msg == "merge_conflict" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// TestEngine_ConflictPolicy verifies conflict detection and resolution for
+// concurrent writes of the same field.
+func TestEngine_ConflictPolicy(t *testing.T) {
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			for _, join := range []bool{true, false} {
+				where := "run state"
+				if join {
+					where = "join"
+				}
+
+				t.Run("fail stops the run at a "+where, func(t *testing.T) {
+					engine, emitter, _ := newConflictTestEngine(t, mode.maxConcurrentNodes, ConflictFail, join, "left", "right")
+
+					_, err := engine.Run(context.Background(), "conflict-fail", ConflictTestState{})
+					if !errors.Is(err, ErrMergeConflict) {
+						t.Fatalf("expected ErrMergeConflict, got %v", err)
+					}
+					var conflict *ConflictError
+					if !errors.As(err, &conflict) || fmt.Sprint(conflict.Fields) != "[Summary]" {
+						t.Fatalf("expected ConflictError on [Summary], got %v", err)
+					}
+					// Sequential mode merges branch deltas into the run state
+					// before routing, so it detects the conflict there first
+					if join && mode.maxConcurrentNodes > 0 && conflict.NodeID != "done" {
+						t.Errorf("NodeID = %q, want done", conflict.NodeID)
+					}
+
+					events := conflictEvents(emitter)
+					if len(events) != 1 {
+						t.Fatalf("got %d merge_conflict events, want 1", len(events))
+					}
+					if events[0].Meta["policy"] != "fail" || fmt.Sprint(events[0].Meta["fields"]) != "[Summary]" {
+						t.Errorf("merge_conflict meta = %v", events[0].Meta)
+					}
+				})
+
+				t.Run("last writer wins at a "+where, func(t *testing.T) {
+					engine, emitter, joined := newConflictTestEngine(t, mode.maxConcurrentNodes, LastWriterWins, join, "left", "right")
+
+					// The branch with the higher OrderKey wins
+					split := WorkItem[ConflictTestState]{NodeID: "split"}
+					want := "left"
+					if successorOrderKey(split, 1) > successorOrderKey(split, 0) {
+						want = "right"
+					}
+
+					final, err := engine.Run(context.Background(), "conflict-lww", ConflictTestState{})
+					if err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+					if final.Summary != want {
+						t.Errorf("final Summary = %q, want %q", final.Summary, want)
+					}
+					if join && joined.Summary != want {
+						t.Errorf("join saw Summary %q, want %q", joined.Summary, want)
+					}
+					if len(final.Notes) != 2 || final.Views.Value() != 2 {
+						t.Errorf("merged fields lost updates: Notes %v, Views %d", final.Notes, final.Views.Value())
+					}
+					if events := conflictEvents(emitter); len(events) != 1 {
+						t.Errorf("got %d merge_conflict events, want 1", len(events))
+					}
+				})
+			}
+
+			t.Run("equal values do not conflict", func(t *testing.T) {
+				engine, emitter, _ := newConflictTestEngine(t, mode.maxConcurrentNodes, ConflictFail, true, "same", "same")
+
+				final, err := engine.Run(context.Background(), "conflict-equal", ConflictTestState{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if final.Summary != "same" || len(conflictEvents(emitter)) != 0 {
+					t.Errorf("final Summary = %q, events %d", final.Summary, len(conflictEvents(emitter)))
+				}
+			})
+
+			t.Run("no policy merges with the reducer only", func(t *testing.T) {
+				// The zero ConflictPolicy is ConflictNone
+				var unset ConflictPolicy
+				engine, emitter, _ := newConflictTestEngine(t, mode.maxConcurrentNodes, unset, true, "left", "right")
+
+				if _, err := engine.Run(context.Background(), "conflict-none", ConflictTestState{}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if events := conflictEvents(emitter); len(events) != 0 {
+					t.Errorf("got %d merge_conflict events, want 0", len(events))
+				}
+			})
+
+			t.Run("writes in sequence do not conflict", func(t *testing.T) {
+				reducer, err := NewReducer[ConflictTestState]()
+				if err != nil {
+					t.Fatalf("NewReducer failed: %v", err)
+				}
+				engine := New(reducer, store.NewMemStore[ConflictTestState](), &mockEmitter{}, Options{
+					MaxSteps:           30,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				}, WithConflictPolicy(ConflictFail))
+
+				// Each iteration fans out again; only branch "a" writes, and
+				// "b" stays on the same path across iterations
+				addNode := func(id string, node NodeFunc[ConflictTestState]) {
+					if err := engine.Add(id, node); err != nil {
+						t.Fatalf("Add(%s) failed: %v", id, err)
+					}
+				}
+				addNode("split", func(_ context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+					if len(s.Notes) >= 2 {
+						return NodeResult[ConflictTestState]{Route: Goto("finish")}
+					}
+					return NodeResult[ConflictTestState]{Route: Many([]string{"a", "b"})}
+				})
+				addNode("a", func(_ context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+					round := fmt.Sprintf("round-%d", len(s.Notes))
+					return NodeResult[ConflictTestState]{
+						Delta: ConflictTestState{Summary: round, Notes: []string{round}},
+						Route: Goto("join"),
+					}
+				})
+				addNode("b", func(_ context.Context, _ ConflictTestState) NodeResult[ConflictTestState] {
+					return NodeResult[ConflictTestState]{Route: Goto("join")}
+				})
+				addNode("join", func(_ context.Context, _ ConflictTestState) NodeResult[ConflictTestState] {
+					return NodeResult[ConflictTestState]{Route: Goto("split")}
+				})
+				addNode("finish", func(_ context.Context, _ ConflictTestState) NodeResult[ConflictTestState] {
+					return NodeResult[ConflictTestState]{Delta: ConflictTestState{Summary: "final"}, Route: Stop()}
+				})
+				if err := engine.Join("join", "a", "b"); err != nil {
+					t.Fatalf("Join failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				final, err := engine.Run(context.Background(), "conflict-loop", ConflictTestState{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if final.Summary != "final" {
+					t.Errorf("final Summary = %q, want final", final.Summary)
+				}
+			})
+
+			t.Run("crdt merges CRDT fields whatever the reducer does", func(t *testing.T) {
+				// The reducer only knows about Notes
+				reducer := func(prev, delta ConflictTestState) ConflictTestState {
+					prev.Notes = append(prev.Notes, delta.Notes...)
+					return prev
+				}
+				engine := New(reducer, store.NewMemStore[ConflictTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+					ConflictPolicy:     ConflictCRDT,
+				})
+
+				var seen ConflictTestState
+				increment := func(ctx context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+					return NodeResult[ConflictTestState]{
+						Delta: ConflictTestState{Views: s.Views.Increment(ctx)},
+						Route: Goto("done"),
+					}
+				}
+				nodes := map[string]NodeFunc[ConflictTestState]{
+					"split": func(_ context.Context, _ ConflictTestState) NodeResult[ConflictTestState] {
+						return NodeResult[ConflictTestState]{Route: SendEach("count", []int{1, 2, 3})}
+					},
+					"count": increment,
+					"done": func(_ context.Context, s ConflictTestState) NodeResult[ConflictTestState] {
+						seen = s
+						return NodeResult[ConflictTestState]{Route: Stop()}
+					},
+				}
+				for id, node := range nodes {
+					if err := engine.Add(id, node); err != nil {
+						t.Fatalf("Add(%s) failed: %v", id, err)
+					}
+				}
+				if err := engine.Join("done", "count"); err != nil {
+					t.Fatalf("Join failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				final, err := engine.Run(context.Background(), "conflict-crdt", ConflictTestState{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if seen.Views.Value() != 3 || final.Views.Value() != 3 {
+					t.Errorf("Views = %d at join, %d final, want 3", seen.Views.Value(), final.Views.Value())
+				}
+			})
+		})
+	}
+}
+
+// TestConflictPolicy_Values verifies the policies' values and names, and that
+// unknown policies are rejected.
+func TestConflictPolicy_Values(t *testing.T) {
+	policies := []struct {
+		policy ConflictPolicy
+		value  int
+		name   string
+	}{
+		{ConflictNone, 0, "none"},
+		{ConflictFail, 1, "fail"},
+		{LastWriterWins, 2, "last_writer_wins"},
+		{ConflictCRDT, 3, "crdt"},
+	}
+	for _, tt := range policies {
+		if int(tt.policy) != tt.value || tt.policy.String() != tt.name {
+			t.Errorf("policy %s = %d, want %s = %d", tt.policy, int(tt.policy), tt.name, tt.value)
+		}
+		cfg := &engineConfig{}
+		if err := WithConflictPolicy(tt.policy)(cfg); err != nil || cfg.opts.ConflictPolicy != tt.policy {
+			t.Errorf("WithConflictPolicy(%s) = %v, policy %s", tt.policy, err, cfg.opts.ConflictPolicy)
+		}
+	}
+
+	err := WithConflictPolicy(ConflictPolicy(99))(&engineConfig{})
+	var engineErr *EngineError
+	if !errors.As(err, &engineErr) || engineErr.Code != "UNSUPPORTED_CONFLICT_POLICY" {
+		t.Errorf("expected UNSUPPORTED_CONFLICT_POLICY error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Package crdt provides conflict-free replicated data types for LangGraph-Go
+// state fields.
+//
+// Concurrent branches of a fan-out each work on their own copy of the state.
+// A CRDT field records its updates so that the copies can be merged in any
+// order with the same result, so branches may update it concurrently without
+// a merge conflict:
+//
+//   - GCounter: a grow-only counter
+//   - ORSet: an observed-remove set
+//   - LWWRegister: a last-writer-wins register
+//
+// Each type is a value with a Merge method. graph.NewReducer merges fields of
+// these types with Merge by default, and under the graph.ConflictCRDT policy
+// the engine merges them with Merge whatever the reducer does. Conflict
+// detection never reports them.
+//
+// Updates take a replica ID that must differ between concurrent branches; use
+// Replica to derive one from the node's context:
+//
+//	type State struct {
+//	    Views crdt.GCounter
+//	    Tags  crdt.ORSet[string]
+//	}
+//
+//	func (n *TagNode) Run(ctx context.Context, s State) graph.NodeResult[State] {
+//	    replica := crdt.Replica(ctx)
+//	    return graph.NodeResult[State]{Delta: State{
+//	        Views: s.Views.Increment(replica, 1),
+//	        Tags:  s.Tags.Add(replica, "reviewed"),
+//	    }}
+//	}
+//
+// All types are immutable: updates and merges return new values and never
+// modify the receiver, and all types marshal to JSON for checkpoints.
+package crdt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// Replica returns a replica ID for the node running with ctx. It combines the
+// node ID with the work item's OrderKey, so concurrent branches, including
+// several branches running the same node, get different IDs, and a replayed
+// run gets the same ones.
+//
+// Outside a node, Replica returns "local".
+func Replica(ctx context.Context) string {
+	nodeID, ok := ctx.Value(graph.NodeIDKey).(string)
+	if !ok {
+		return "local"
+	}
+	orderKey, _ := ctx.Value(graph.OrderKeyKey).(uint64)
+	return fmt.Sprintf("%s@%016x", nodeID, orderKey)
+}
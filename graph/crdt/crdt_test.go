@@ -0,0 +1,100 @@
+package crdt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph"
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestReplica verifies replica IDs derived from a node's context.
+func TestReplica(t *testing.T) {
+	if got := Replica(context.Background()); got != "local" {
+		t.Errorf("Replica() = %q outside a node, want local", got)
+	}
+
+	ctx := context.WithValue(context.Background(), graph.NodeIDKey, "tag")
+	if Replica(context.WithValue(ctx, graph.OrderKeyKey, uint64(1))) == Replica(context.WithValue(ctx, graph.OrderKeyKey, uint64(2))) {
+		t.Error("branches of the same node share a replica ID")
+	}
+}
+
+// engineState is updated concurrently by the branches of a fan-out.
+type engineState struct {
+	Views  GCounter
+	Tags   ORSet[string]
+	Status LWWRegister[string]
+}
+
+// TestCRDTs_ConcurrentBranches verifies CRDT fields merge every branch's
+// updates without conflicts in both execution modes.
+func TestCRDTs_ConcurrentBranches(t *testing.T) {
+	for _, maxConcurrent := range []int{0, 4} {
+		t.Run(fmt.Sprintf("max concurrent %d", maxConcurrent), func(t *testing.T) {
+			reducer, err := graph.NewReducer[engineState]()
+			if err != nil {
+				t.Fatalf("NewReducer failed: %v", err)
+			}
+			engine := graph.New(reducer, store.NewMemStore[engineState](), emit.NewNullEmitter(), graph.Options{
+				MaxSteps:           20,
+				MaxConcurrentNodes: maxConcurrent,
+				ConflictPolicy:     graph.ConflictCRDT,
+			})
+
+			nodes := map[string]graph.NodeFunc[engineState]{
+				"split": func(ctx context.Context, s engineState) graph.NodeResult[engineState] {
+					return graph.NodeResult[engineState]{
+						Delta: engineState{Tags: s.Tags.Add(Replica(ctx), "stale")},
+						Route: graph.SendEach("tag", []string{"a", "b", "c"}),
+					}
+				},
+				"tag": func(ctx context.Context, s engineState) graph.NodeResult[engineState] {
+					tag, _ := graph.SendInputAs[string](ctx)
+					tags := s.Tags.Add(Replica(ctx), tag)
+					if tag == "a" {
+						tags = tags.Remove("stale")
+					}
+					return graph.NodeResult[engineState]{
+						Delta: engineState{
+							Views:  s.Views.Increment(Replica(ctx), 1),
+							Tags:   tags,
+							Status: s.Status.Assign(ctx, "tagged "+tag),
+						},
+						Route: graph.Goto("done"),
+					}
+				},
+				"done": func(_ context.Context, _ engineState) graph.NodeResult[engineState] {
+					return graph.NodeResult[engineState]{Route: graph.Stop()}
+				},
+			}
+			for id, node := range nodes {
+				if err := engine.Add(id, node); err != nil {
+					t.Fatalf("Add(%s) failed: %v", id, err)
+				}
+			}
+			if err := engine.Join("done", "tag"); err != nil {
+				t.Fatalf("Join failed: %v", err)
+			}
+			if err := engine.StartAt("split"); err != nil {
+				t.Fatalf("StartAt failed: %v", err)
+			}
+
+			final, err := engine.Run(context.Background(), "crdt-branches", engineState{})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if final.Views.Value() != 3 {
+				t.Errorf("Views = %d, want 3", final.Views.Value())
+			}
+			if final.Tags.Len() != 3 || final.Tags.Contains("stale") {
+				t.Errorf("Tags = %v, want a, b and c", final.Tags.Elements())
+			}
+			if final.Status.Get() == "" {
+				t.Error("Status register lost every write")
+			}
+		})
+	}
+}
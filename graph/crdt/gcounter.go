@@ -0,0 +1,60 @@
+package crdt
+
+import "sort"
+
+// GCounter is a grow-only counter. Each replica counts its own increments and
+// the value is their sum; merging keeps the highest count seen per replica.
+//
+// The zero value is an empty counter ready to use.
+type GCounter struct {
+	// Counts holds each replica's increments.
+	Counts map[string]uint64 `json:"counts,omitempty"`
+}
+
+// Increment returns a copy of the counter with n added to replica's count.
+func (c GCounter) Increment(replica string, n uint64) GCounter {
+	next := c.clone()
+	next.Counts[replica] += n
+	return next
+}
+
+// Value returns the counter's total.
+func (c GCounter) Value() uint64 {
+	var total uint64
+	for _, count := range c.Counts {
+		total += count
+	}
+	return total
+}
+
+// Merge returns the union of both counters, keeping the highest count of each
+// replica.
+func (c GCounter) Merge(other GCounter) GCounter {
+	next := c.clone()
+	for replica, count := range other.Counts {
+		if count > next.Counts[replica] {
+			next.Counts[replica] = count
+		}
+	}
+	return next
+}
+
+// Replicas returns the IDs of the replicas that incremented the counter, in
+// sorted order.
+func (c GCounter) Replicas() []string {
+	replicas := make([]string, 0, len(c.Counts))
+	for replica := range c.Counts {
+		replicas = append(replicas, replica)
+	}
+	sort.Strings(replicas)
+	return replicas
+}
+
+// clone returns a copy with its own, non-nil Counts map.
+func (c GCounter) clone() GCounter {
+	counts := make(map[string]uint64, len(c.Counts)+1)
+	for replica, count := range c.Counts {
+		counts[replica] = count
+	}
+	return GCounter{Counts: counts}
+}
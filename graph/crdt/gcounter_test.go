@@ -0,0 +1,62 @@
+package crdt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestGCounter verifies increments, merging and serialization.
+func TestGCounter(t *testing.T) {
+	t.Run("zero value counts from zero", func(t *testing.T) {
+		var c GCounter
+		if c.Value() != 0 {
+			t.Errorf("Value() = %d, want 0", c.Value())
+		}
+		if got := c.Increment("a", 2).Increment("a", 3).Value(); got != 5 {
+			t.Errorf("Value() = %d, want 5", got)
+		}
+	})
+
+	t.Run("increment does not modify the receiver", func(t *testing.T) {
+		base := GCounter{}.Increment("a", 1)
+		_ = base.Increment("a", 1)
+		if base.Value() != 1 {
+			t.Errorf("receiver modified: Value() = %d, want 1", base.Value())
+		}
+	})
+
+	t.Run("merge keeps every replica's increments once", func(t *testing.T) {
+		base := GCounter{}.Increment("root", 1)
+		left := base.Increment("left", 2)
+		right := base.Increment("right", 3).Increment("root", 1)
+
+		merged := left.Merge(right)
+		if merged.Value() != 7 {
+			t.Errorf("Value() = %d, want 7", merged.Value())
+		}
+		if other := right.Merge(left); other.Value() != merged.Value() {
+			t.Errorf("merge is not commutative: %d != %d", other.Value(), merged.Value())
+		}
+		if again := merged.Merge(left); again.Value() != merged.Value() {
+			t.Errorf("merge is not idempotent: %d != %d", again.Value(), merged.Value())
+		}
+		if got := merged.Replicas(); len(got) != 3 || got[0] != "left" || got[2] != "root" {
+			t.Errorf("Replicas() = %v, want [left right root]", got)
+		}
+	})
+
+	t.Run("round-trips through JSON", func(t *testing.T) {
+		c := GCounter{}.Increment("a", 4)
+		data, err := json.Marshal(c)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var decoded GCounter
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if decoded.Value() != 4 {
+			t.Errorf("decoded Value() = %d, want 4", decoded.Value())
+		}
+	})
+}
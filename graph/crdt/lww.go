@@ -0,0 +1,49 @@
+package crdt
+
+import (
+	"context"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// LWWRegister is a last-writer-wins register: a single value where the write
+// with the highest timestamp wins a merge, and ties are broken by replica ID.
+//
+// Timestamps are logical and must be deterministic for replay; Assign uses
+// the node's step number.
+type LWWRegister[T any] struct {
+	// Value is the register's current value.
+	Value T `json:"value"`
+
+	// Timestamp orders writes; higher wins.
+	Timestamp uint64 `json:"timestamp,omitempty"`
+
+	// Replica identifies the writer and breaks timestamp ties.
+	Replica string `json:"replica,omitempty"`
+}
+
+// Set returns a register holding value written by replica at timestamp.
+func (r LWWRegister[T]) Set(value T, timestamp uint64, replica string) LWWRegister[T] {
+	return LWWRegister[T]{Value: value, Timestamp: timestamp, Replica: replica}
+}
+
+// Assign returns a register holding value, written by the node running with
+// ctx. The timestamp is the node's step number plus one, so the write wins
+// over the register's zero value and over writes from earlier steps.
+func (r LWWRegister[T]) Assign(ctx context.Context, value T) LWWRegister[T] {
+	step, _ := ctx.Value(graph.StepIDKey).(int)
+	return r.Set(value, uint64(max(step, 0))+1, Replica(ctx)) // #nosec G115 -- step is non-negative
+}
+
+// Get returns the register's value.
+func (r LWWRegister[T]) Get() T {
+	return r.Value
+}
+
+// Merge returns whichever register was written last.
+func (r LWWRegister[T]) Merge(other LWWRegister[T]) LWWRegister[T] {
+	if other.Timestamp > r.Timestamp || (other.Timestamp == r.Timestamp && other.Replica > r.Replica) {
+		return other
+	}
+	return r
+}
@@ -0,0 +1,41 @@
+package crdt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// TestLWWRegister verifies the latest write wins merges.
+func TestLWWRegister(t *testing.T) {
+	t.Run("higher timestamp wins", func(t *testing.T) {
+		older := LWWRegister[string]{}.Set("old", 1, "b")
+		newer := LWWRegister[string]{}.Set("new", 2, "a")
+		if got := older.Merge(newer).Get(); got != "new" {
+			t.Errorf("Get() = %q, want new", got)
+		}
+		if got := newer.Merge(older).Get(); got != "new" {
+			t.Errorf("Get() = %q, want new", got)
+		}
+	})
+
+	t.Run("replica breaks timestamp ties", func(t *testing.T) {
+		a := LWWRegister[int]{}.Set(1, 3, "a")
+		b := LWWRegister[int]{}.Set(2, 3, "b")
+		if a.Merge(b).Get() != 2 || b.Merge(a).Get() != 2 {
+			t.Errorf("tie not broken by replica: %d, %d", a.Merge(b).Get(), b.Merge(a).Get())
+		}
+	})
+
+	t.Run("assign uses the node's step and replica", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), graph.StepIDKey, 4)
+		ctx = context.WithValue(ctx, graph.NodeIDKey, "review")
+		ctx = context.WithValue(ctx, graph.OrderKeyKey, uint64(0xab))
+
+		r := LWWRegister[string]{}.Assign(ctx, "done")
+		if r.Timestamp != 5 || r.Replica != "review@00000000000000ab" || r.Get() != "done" {
+			t.Errorf("Assign() = %+v", r)
+		}
+	})
+}
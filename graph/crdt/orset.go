@@ -0,0 +1,105 @@
+package crdt
+
+import (
+	"sort"
+	"strconv"
+)
+
+// ORSet is an observed-remove set. Every Add is recorded under a unique tag,
+// and Remove discards only the tags it has observed, so an element added on
+// one branch while another branch removes it stays in the merged set.
+//
+// The zero value is an empty set ready to use. Elements must be
+// JSON-serializable for the set to be checkpointed.
+type ORSet[T comparable] struct {
+	// Adds maps each add tag to its element.
+	Adds map[string]T `json:"adds,omitempty"`
+
+	// Removed holds the tags of removed adds.
+	Removed map[string]bool `json:"removed,omitempty"`
+}
+
+// Add returns a copy of the set with elem added by replica.
+func (s ORSet[T]) Add(replica string, elem T) ORSet[T] {
+	next := s.clone()
+	// The replica prefix keeps tags from concurrent branches apart
+	next.Adds[replica+":"+strconv.Itoa(len(s.Adds))] = elem
+	return next
+}
+
+// Remove returns a copy of the set without elem. Adds of elem made on
+// concurrent branches that this set has not seen survive a merge.
+func (s ORSet[T]) Remove(elem T) ORSet[T] {
+	next := s.clone()
+	for tag, value := range next.Adds {
+		if value == elem {
+			next.Removed[tag] = true
+		}
+	}
+	return next
+}
+
+// Contains reports whether elem is in the set.
+func (s ORSet[T]) Contains(elem T) bool {
+	for tag, value := range s.Adds {
+		if value == elem && !s.Removed[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// Elements returns the elements in the set, ordered by the tag of their first
+// add so the order is deterministic.
+func (s ORSet[T]) Elements() []T {
+	tags := make([]string, 0, len(s.Adds))
+	for tag := range s.Adds {
+		if !s.Removed[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	seen := make(map[T]bool, len(tags))
+	elems := make([]T, 0, len(tags))
+	for _, tag := range tags {
+		elem := s.Adds[tag]
+		if !seen[elem] {
+			seen[elem] = true
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// Len returns the number of elements in the set.
+func (s ORSet[T]) Len() int {
+	return len(s.Elements())
+}
+
+// Merge returns the union of both sets' adds and removes.
+func (s ORSet[T]) Merge(other ORSet[T]) ORSet[T] {
+	next := s.clone()
+	for tag, elem := range other.Adds {
+		next.Adds[tag] = elem
+	}
+	for tag := range other.Removed {
+		next.Removed[tag] = true
+	}
+	return next
+}
+
+// clone returns a copy with its own, non-nil maps.
+func (s ORSet[T]) clone() ORSet[T] {
+	next := ORSet[T]{
+		Adds:    make(map[string]T, len(s.Adds)+1),
+		Removed: make(map[string]bool, len(s.Removed)),
+	}
+	for tag, elem := range s.Adds {
+		next.Adds[tag] = elem
+	}
+	for tag := range s.Removed {
+		next.Removed[tag] = true
+	}
+	return next
+}
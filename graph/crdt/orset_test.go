@@ -0,0 +1,50 @@
+package crdt
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestORSet verifies adds, removes and merging of concurrent updates.
+func TestORSet(t *testing.T) {
+	t.Run("add and remove", func(t *testing.T) {
+		s := ORSet[string]{}.Add("a", "x").Add("a", "y").Add("a", "x")
+		if s.Len() != 2 || !s.Contains("x") {
+			t.Fatalf("Elements() = %v, want [x y]", s.Elements())
+		}
+		s = s.Remove("x")
+		if s.Contains("x") || fmt.Sprint(s.Elements()) != "[y]" {
+			t.Errorf("Elements() = %v, want [y]", s.Elements())
+		}
+	})
+
+	t.Run("concurrent add survives a remove", func(t *testing.T) {
+		base := ORSet[string]{}.Add("root", "x")
+		removed := base.Remove("x")
+		readded := base.Add("right", "x")
+
+		if merged := removed.Merge(readded); !merged.Contains("x") {
+			t.Errorf("merged set lost a concurrent add: %v", merged.Elements())
+		}
+		if merged := readded.Merge(removed); !merged.Contains("x") {
+			t.Errorf("merge is not commutative: %v", merged.Elements())
+		}
+	})
+
+	t.Run("concurrent adds on different replicas are kept", func(t *testing.T) {
+		base := ORSet[int]{}
+		merged := base.Add("left", 1).Merge(base.Add("right", 2))
+		if merged.Len() != 2 {
+			t.Errorf("Elements() = %v, want both adds", merged.Elements())
+		}
+	})
+
+	t.Run("updates do not modify the receiver", func(t *testing.T) {
+		base := ORSet[string]{}.Add("a", "x")
+		_ = base.Add("a", "y")
+		_ = base.Remove("x")
+		if fmt.Sprint(base.Elements()) != "[x]" {
+			t.Errorf("receiver modified: %v", base.Elements())
+		}
+	})
+}
@@ -0,0 +1,421 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+const (
+	// defaultWorkLease is how long a worker holds a work item when
+	// Options.LeaseDuration is not set.
+	defaultWorkLease = 30 * time.Second
+
+	// defaultWorkPollInterval is how often the queue is polled when
+	// Options.WorkPollInterval is not set.
+	defaultWorkPollInterval = 50 * time.Millisecond
+)
+
+// queuedItem is a work item as pushed to the work queue, with what the
+// worker needs to record and replay the node's I/O as the run would.
+type queuedItem[S any] struct {
+	WorkItem[S]
+
+	// Replay and StrictReplay are the recording mode of the run.
+	Replay       bool `json:"replay,omitempty"`
+	StrictReplay bool `json:"strict_replay,omitempty"`
+
+	// Recordings are the run's unserved recordings of the node, served to
+	// its Recorded calls in replay mode.
+	Recordings []RecordedIO `json:"recordings,omitempty"`
+}
+
+// workResult is the outcome of a work item executed by Engine.Work, as
+// committed to the work queue.
+type workResult[S any] struct {
+	Delta S    `json:"delta"`
+	Route Next `json:"route"`
+
+	// Error and Code describe the error the node failed with. Code and
+	// EngineMessage are those of the EngineError in the error, if it had one.
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+	EngineMessage string `json:"engine_message,omitempty"`
+
+	// Sentinels names the remoteSentinels the error matches, and NodeError
+	// is the NodeError in it, so the coordinator can rebuild the error.
+	Sentinels []string         `json:"sentinels,omitempty"`
+	NodeError *remoteNodeError `json:"node_error,omitempty"`
+
+	// Served holds the positions of the queued item's Recordings the node
+	// replayed, and Recordings the I/O it recorded live.
+	Served     []int        `json:"served,omitempty"`
+	Recordings []RecordedIO `json:"recordings,omitempty"`
+}
+
+// remoteNodeError is a NodeError as committed to the work queue.
+type remoteNodeError struct {
+	Message  string `json:"message"`
+	Code     string `json:"code,omitempty"`
+	NodeID   string `json:"node_id,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+}
+
+// remoteSentinels are the sentinel errors errors.Is finds in the errors of
+// queued nodes as it does in a local run, by the name they travel under.
+var remoteSentinels = []struct {
+	name string
+	err  error
+}{
+	{"context.Canceled", context.Canceled},
+	{"context.DeadlineExceeded", context.DeadlineExceeded},
+	{"ErrMaxAttemptsExceeded", ErrMaxAttemptsExceeded},
+	{"ErrReplayMismatch", ErrReplayMismatch},
+	{"ErrBackpressure", ErrBackpressure},
+	{"ErrBackpressureTimeout", ErrBackpressureTimeout},
+	{"ErrMergeConflict", ErrMergeConflict},
+	{"ErrMaxStepsExceeded", ErrMaxStepsExceeded},
+	{"ErrNoProgress", ErrNoProgress},
+	{"ErrIdempotencyViolation", ErrIdempotencyViolation},
+	{"ErrInvalidRetryPolicy", ErrInvalidRetryPolicy},
+	{"ErrInterrupted", ErrInterrupted},
+	{"ErrCancelled", ErrCancelled},
+}
+
+// remoteError is the error a node failed with on a worker, as rebuilt by
+// the coordinator: it has the original message and unwraps to the sentinels,
+// NodeError and EngineError the original error held.
+type remoteError struct {
+	message string
+	causes  []error
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the rebuilt causes for errors.Is and errors.As.
+func (e *remoteError) Unwrap() []error {
+	return e.causes
+}
+
+// failedWork returns the work result of a node that failed with err.
+func failedWork[S any](err error) workResult[S] {
+	failed := workResult[S]{Error: err.Error()}
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) {
+		failed.Code, failed.EngineMessage = engineErr.Code, engineErr.Message
+	}
+	var nodeErr *NodeError
+	if errors.As(err, &nodeErr) {
+		failed.NodeError = &remoteNodeError{Message: nodeErr.Message, Code: nodeErr.Code, NodeID: nodeErr.NodeID, Attempts: nodeErr.Attempts}
+	}
+	for _, sentinel := range remoteSentinels {
+		if errors.Is(err, sentinel.err) {
+			failed.Sentinels = append(failed.Sentinels, sentinel.name)
+		}
+	}
+	return failed
+}
+
+// err rebuilds the error of a failed work result. Errors without a sentinel,
+// NodeError or EngineError wrap an EngineError with code REMOTE_NODE_ERROR.
+func (r workResult[S]) err() error {
+	rebuilt := &remoteError{message: r.Error}
+	for _, name := range r.Sentinels {
+		for _, sentinel := range remoteSentinels {
+			if sentinel.name == name {
+				rebuilt.causes = append(rebuilt.causes, sentinel.err)
+			}
+		}
+	}
+	if r.NodeError != nil {
+		rebuilt.causes = append(rebuilt.causes, &NodeError{
+			Message:  r.NodeError.Message,
+			Code:     r.NodeError.Code,
+			NodeID:   r.NodeError.NodeID,
+			Attempts: r.NodeError.Attempts,
+		})
+	}
+	if r.Code != "" {
+		rebuilt.causes = append(rebuilt.causes, &EngineError{Message: r.EngineMessage, Code: r.Code})
+	}
+	if len(rebuilt.causes) == 0 {
+		rebuilt.causes = append(rebuilt.causes, &EngineError{Message: r.Error, Code: "REMOTE_NODE_ERROR"})
+	}
+	return rebuilt
+}
+
+// Work executes work items from Options.WorkQueue until ctx is cancelled,
+// making this process a worker for the concurrent runs of any process sharing
+// the queue. Up to MaxConcurrentNodes items (at least one) execute at a time.
+//
+// The engine must have the same nodes as the engines running the workflows.
+// Each leased item is renewed every third of Options.LeaseDuration (30s if
+// unset) while its node executes; if the worker stops, its items are
+// released or expire and another worker executes them. Failed queue
+// operations are reported in work_failed events and retried.
+//
+// Returns ctx.Err() once ctx is cancelled and the executing items have
+// stopped, or an EngineError with code WORK_QUEUE_REQUIRED if
+// Options.WorkQueue is not set.
+//
+// Example:
+//
+//	// In each worker process, with the same graph as the coordinator
+//	engine := graph.New(reducer, st, emitter, graph.WithWorkQueue(st), graph.WithMaxConcurrent(4))
+//	// ... add nodes and edges ...
+//	err := engine.Work(ctx)
+func (e *Engine[S]) Work(ctx context.Context) error {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	queue := e.opts.WorkQueue
+	if queue == nil {
+		return &EngineError{
+			Message: "Options.WorkQueue is required to work on queued items",
+			Code:    "WORK_QUEUE_REQUIRED",
+		}
+	}
+
+	workers := max(e.opts.MaxConcurrentNodes, 1)
+	owner := e.leaseOwner()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker holds its own leases
+			e.workLoop(ctx, queue, fmt.Sprintf("%s#%d", owner, i))
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// workLoop leases and executes work items as owner until ctx is cancelled.
+func (e *Engine[S]) workLoop(ctx context.Context, queue store.WorkQueue, owner string) {
+	for ctx.Err() == nil {
+		work, err := queue.LeaseWork(ctx, owner, time.Now().Add(e.workLease()))
+		if err == nil {
+			e.executeWork(ctx, queue, owner, work)
+			continue
+		}
+		if !errors.Is(err, store.ErrNotFound) && ctx.Err() == nil {
+			e.publishWorkFailed("", "lease", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(e.workPollInterval()):
+		}
+	}
+}
+
+// executeWork executes a leased work item and commits its result. The lease
+// is renewed while the node executes; an item whose lease is lost is left to
+// its new owner, and an item interrupted by ctx is released.
+func (e *Engine[S]) executeWork(ctx context.Context, queue store.WorkQueue, owner string, work store.WorkRecord) {
+	lease := e.workLease()
+	workCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() {
+		ticker := time.NewTicker(max(lease/3, time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-workCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := queue.RenewWork(workCtx, work.ID, owner, time.Now().Add(lease))
+			if errors.Is(err, store.ErrLeaseLost) {
+				cancel(err)
+				return
+			}
+			if err != nil && workCtx.Err() == nil {
+				e.publishWorkFailed(work.RunID, "renew", err)
+			}
+		}
+	}()
+
+	result := e.executeWorkItem(workCtx, work)
+	if errors.Is(context.Cause(workCtx), store.ErrLeaseLost) {
+		return
+	}
+	// The queue must still be updated once the worker is stopping
+	saveCtx := context.WithoutCancel(ctx)
+	if ctx.Err() != nil {
+		if err := queue.RenewWork(saveCtx, work.ID, owner, time.Now()); err != nil && !errors.Is(err, store.ErrLeaseLost) {
+			e.publishWorkFailed(work.RunID, "release", err)
+		}
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		data, _ = json.Marshal(failedWork[S](&EngineError{Message: "failed to encode work result: " + err.Error(), Code: "WORK_ENCODE_ERROR"}))
+	}
+	if err := queue.CompleteWork(saveCtx, work.ID, owner, data); err != nil && !errors.Is(err, store.ErrLeaseLost) {
+		e.publishWorkFailed(work.RunID, "complete", err)
+	}
+}
+
+// executeWorkItem runs the node of a queued work item as runFrontier would,
+// with the item's deterministic RNG, run metadata and recorder in its
+// context.
+func (e *Engine[S]) executeWorkItem(ctx context.Context, work store.WorkRecord) workResult[S] {
+	var queued queuedItem[S]
+	if err := json.Unmarshal(work.Item, &queued); err != nil {
+		return failedWork[S](&EngineError{Message: "failed to decode work item: " + err.Error(), Code: "WORK_DECODE_ERROR"})
+	}
+	item := queued.WorkItem
+
+	e.mu.RLock()
+	nodeImpl, exists := e.nodes[item.NodeID]
+	e.mu.RUnlock()
+	if !exists {
+		return failedWork[S](&EngineError{Message: "node not found on worker: " + item.NodeID, Code: "NODE_NOT_FOUND"})
+	}
+
+	itemRNG := rand.New(rand.NewSource(runSeed(work.RunID) ^ int64(item.OrderKey))) // #nosec G404,G115 -- deterministic RNG for replay, as in runFrontier
+	nodeCtx := context.WithValue(ctx, RNGKey, itemRNG)
+	nodeCtx = e.nodeContext(nodeCtx, work.RunID, item)
+	rec := newIORecorder(queued.Recordings, queued.Replay, queued.StrictReplay)
+	nodeCtx = context.WithValue(nodeCtx, recorderKey, rec)
+
+	var out workResult[S]
+	result, err := e.runNode(nodeCtx, work.RunID, item, nodeImpl)
+	if err != nil {
+		out = failedWork[S](err)
+	} else {
+		out.Delta, out.Route = result.Delta, result.Route
+	}
+	// Failed attempts are recorded too, as they are in a local run
+	out.Served, out.Recordings = rec.split(len(queued.Recordings))
+	return out
+}
+
+// runQueued executes a work item of a concurrent run through
+// Options.WorkQueue: it pushes the item under its idempotency key and waits
+// for a worker to commit the result. An item already queued by an earlier
+// attempt of the run is not pushed again, and its committed result is reused.
+// The worker replays the run's recordings of the node, and the I/O it
+// records is added to the run's recorder for its checkpoints. A failure is
+// returned as a remoteError, which errors.Is and errors.As see as the local
+// error.
+func (e *Engine[S]) runQueued(ctx context.Context, runID string, item WorkItem[S]) (NodeResult[S], error) {
+	var zero NodeResult[S]
+	queue := e.opts.WorkQueue
+
+	id, err := computeIdempotencyKey(runID, item.StepID, []WorkItem[S]{item}, item.State)
+	if err != nil {
+		return zero, &EngineError{
+			Message: "failed to compute idempotency key: " + err.Error(),
+			Code:    "IDEMPOTENCY_KEY_ERROR",
+		}
+	}
+	queued := queuedItem[S]{WorkItem: item}
+	var indices []int
+	rec, _ := ctx.Value(recorderKey).(*ioRecorder)
+	if rec != nil {
+		queued.Replay, queued.StrictReplay = rec.replay, rec.strict
+		queued.Recordings, indices = rec.unserved(item.NodeID)
+	}
+	data, err := json.Marshal(queued)
+	if err != nil {
+		return zero, &EngineError{
+			Message: "failed to encode work item: " + err.Error(),
+			Code:    "WORK_ENCODE_ERROR",
+		}
+	}
+	if err := queue.PushWork(ctx, store.WorkRecord{ID: id, RunID: runID, NodeID: item.NodeID, Item: data}); err != nil {
+		return zero, &EngineError{
+			Message: "failed to queue work item: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	ticker := time.NewTicker(e.workPollInterval())
+	defer ticker.Stop()
+	for {
+		work, err := queue.LoadWork(ctx, id)
+		if err != nil && ctx.Err() == nil {
+			return zero, &EngineError{
+				Message: "failed to load work item: " + err.Error(),
+				Code:    "STORE_ERROR",
+			}
+		}
+		if err == nil && work.Status == store.WorkDone {
+			var result workResult[S]
+			if err := json.Unmarshal(work.Result, &result); err != nil {
+				return zero, &EngineError{
+					Message: "failed to decode work result: " + err.Error(),
+					Code:    "WORK_DECODE_ERROR",
+				}
+			}
+			if rec != nil {
+				rec.merge(indices, result.Served, result.Recordings)
+			}
+			if result.Error != "" {
+				return zero, result.err()
+			}
+			return NodeResult[S]{Delta: result.Delta, Route: result.Route}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeItem runs the node of a concurrent run's work item, through the
+// work queue if Options.WorkQueue is set. The item resuming an interrupted
+// node always runs here, since the Resume input lives in ctx.
+func (e *Engine[S]) executeItem(ctx context.Context, runID string, item WorkItem[S], nodeImpl Node[S]) (NodeResult[S], error) {
+	if e.opts.WorkQueue == nil || item.Interrupted {
+		return e.runNode(ctx, runID, item, nodeImpl)
+	}
+	return e.runQueued(ctx, runID, item)
+}
+
+// workLease returns how long workers hold a work item.
+func (e *Engine[S]) workLease() time.Duration {
+	if e.opts.LeaseDuration > 0 {
+		return e.opts.LeaseDuration
+	}
+	return defaultWorkLease
+}
+
+// workPollInterval returns how often the work queue is polled.
+func (e *Engine[S]) workPollInterval() time.Duration {
+	if e.opts.WorkPollInterval > 0 {
+		return e.opts.WorkPollInterval
+	}
+	return defaultWorkPollInterval
+}
+
+// publishWorkFailed emits a work_failed event for a queue operation that
+// failed on a worker.
+func (e *Engine[S]) publishWorkFailed(runID, operation string, err error) {
+	e.publish(emit.Event{
+		RunID: runID,
+		Msg:   "work_failed",
+		Meta: map[string]interface{}{
+			"operation": operation,
+			"error":     err.Error(),
+		},
+	})
+}
@@ -0,0 +1,403 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// queueState is the state of the work queue tests. It is declared at package
+// level so TestWorkerProcess can decode the runs TestEngine_WorkQueueProcesses
+// stores.
+type queueState struct {
+	Executed []string
+}
+
+func queueReducer(prev, delta queueState) queueState {
+	prev.Executed = append(prev.Executed, delta.Executed...)
+	return prev
+}
+
+// newDistributedTestEngine builds fan -> {w1, w2, w3, w4}, where each node
+// appends "<id>@<where>" to Executed, sleeping for pause first. It is shared
+// by the coordinator and the worker processes, which must build the same
+// graph.
+func newDistributedTestEngine(t *testing.T, st store.Store[queueState], opts Options, where string, pause time.Duration) *Engine[queueState] {
+	t.Helper()
+	engine := New(queueReducer, st, &mockEmitter{}, opts)
+	node := func(id string, route Next) Node[queueState] {
+		return NodeFunc[queueState](func(ctx context.Context, _ queueState) NodeResult[queueState] {
+			select {
+			case <-time.After(pause):
+			case <-ctx.Done():
+				return NodeResult[queueState]{Err: ctx.Err()}
+			}
+			return NodeResult[queueState]{Delta: queueState{Executed: []string{id + "@" + where}}, Route: route}
+		})
+	}
+	workers := []string{"w1", "w2", "w3", "w4"}
+	if err := engine.Add("fan", node("fan", Many(workers))); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	for _, id := range workers {
+		if err := engine.Add(id, node(id, Stop())); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := engine.StartAt("fan"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+	return engine
+}
+
+// TestEngine_WorkQueue verifies the nodes of a concurrent run execute on the
+// workers sharing its work queue.
+func TestEngine_WorkQueue(t *testing.T) {
+	ctx := context.Background()
+	opts := Options{MaxSteps: 20, MaxConcurrentNodes: 4, WorkPollInterval: time.Millisecond}
+
+	t.Run("nodes execute on workers", func(t *testing.T) {
+		st := store.NewMemStore[queueState]()
+		opts := opts
+		opts.WorkQueue = st
+		coordinator := newDistributedTestEngine(t, st, opts, "coordinator", 0)
+		worker := newDistributedTestEngine(t, st, opts, "worker", 0)
+
+		workCtx, stop := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() { done <- worker.Work(workCtx) }()
+
+		final, err := coordinator.Run(ctx, "run-001", queueState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		sort.Strings(final.Executed)
+		want := []string{"fan@worker", "w1@worker", "w2@worker", "w3@worker", "w4@worker"}
+		if strings.Join(final.Executed, ",") != strings.Join(want, ",") {
+			t.Errorf("executed %v, want %v", final.Executed, want)
+		}
+
+		stop()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Work returned %v, want context.Canceled", err)
+		}
+		// The run's work is dropped once it completes
+		if work, err := st.LeaseWork(ctx, "check", time.Now().Add(time.Minute)); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("LeaseWork = %+v, %v; want an empty queue", work, err)
+		}
+	})
+
+	t.Run("node errors are returned to the run", func(t *testing.T) {
+		st := store.NewMemStore[queueState]()
+		opts := opts
+		opts.WorkQueue = st
+		coordinator := newDistributedTestEngine(t, st, opts, "coordinator", 0)
+		worker := New(queueReducer, st, &mockEmitter{}, opts)
+		failing := NodeFunc[queueState](func(_ context.Context, _ queueState) NodeResult[queueState] {
+			return NodeResult[queueState]{Err: errors.New("model unavailable")}
+		})
+		if err := worker.Add("fan", failing); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		workCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		go func() { _ = worker.Work(workCtx) }()
+
+		var engineErr *EngineError
+		_, err := coordinator.Run(ctx, "run-001", queueState{})
+		if !errors.As(err, &engineErr) || engineErr.Code != "REMOTE_NODE_ERROR" || !strings.Contains(engineErr.Message, "model unavailable") {
+			t.Fatalf("Run returned %v, want REMOTE_NODE_ERROR", err)
+		}
+	})
+
+	t.Run("node errors match local runs", func(t *testing.T) {
+		retryPol := &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Retryable: func(error) bool { return true }}
+		for _, failure := range []struct {
+			name   string
+			policy NodePolicy
+			err    func(ctx context.Context) error
+		}{
+			{
+				name:   "retries exhausted",
+				policy: NodePolicy{RetryPolicy: retryPol},
+				err: func(context.Context) error {
+					return &NodeError{Message: "rate limited", Code: "RATE_LIMITED", NodeID: "fan"}
+				},
+			},
+			{
+				name: "deadline",
+				err: func(context.Context) error {
+					return fmt.Errorf("lookup failed: %w", context.DeadlineExceeded)
+				},
+			},
+			{
+				name:   "timeout",
+				policy: NodePolicy{Timeout: 10 * time.Millisecond},
+				err: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+		} {
+			t.Run(failure.name, func(t *testing.T) {
+				st := store.NewMemStore[queueState]()
+				// newEngine builds a single failing fan node
+				newEngine := func(opts Options) *Engine[queueState] {
+					engine := New(queueReducer, st, &mockEmitter{}, opts)
+					fan := NodeFunc[queueState](func(ctx context.Context, _ queueState) NodeResult[queueState] {
+						return NodeResult[queueState]{Err: failure.err(ctx)}
+					})
+					if err := engine.Add("fan", policyNode[queueState]{Node: fan, policy: failure.policy}); err != nil {
+						t.Fatalf("Add failed: %v", err)
+					}
+					if err := engine.StartAt("fan"); err != nil {
+						t.Fatalf("StartAt failed: %v", err)
+					}
+					return engine
+				}
+				// describe reports what errors.Is and errors.As find in err
+				describe := func(err error) string {
+					var nodeErr *NodeError
+					var engineErr *EngineError
+					return fmt.Sprintf("%q max_attempts=%v deadline=%v node_error=%v engine_error=%v",
+						err, errors.Is(err, ErrMaxAttemptsExceeded), errors.Is(err, context.DeadlineExceeded),
+						errors.As(err, &nodeErr) && nodeErr.Code == "RATE_LIMITED" && nodeErr.NodeID == "fan",
+						errors.As(err, &engineErr) && engineErr.Code == "NODE_TIMEOUT")
+				}
+
+				_, local := newEngine(opts).Run(ctx, "run-001", queueState{})
+				if local == nil {
+					t.Fatal("expected the local run to fail")
+				}
+
+				queued := opts
+				queued.WorkQueue = st
+				workCtx, stop := context.WithCancel(ctx)
+				defer stop()
+				go func() { _ = newEngine(queued).Work(workCtx) }()
+				_, remote := newEngine(queued).Run(ctx, "run-002", queueState{})
+
+				if describe(remote) != describe(local) {
+					t.Errorf("queued run returned %s, want %s", describe(remote), describe(local))
+				}
+			})
+		}
+	})
+
+	t.Run("released items are executed by another worker", func(t *testing.T) {
+		st := store.NewMemStore[queueState]()
+		opts := opts
+		opts.WorkQueue = st
+		coordinator := newDistributedTestEngine(t, st, opts, "coordinator", 0)
+		slow := New(queueReducer, st, &mockEmitter{}, opts)
+		started := make(chan struct{})
+		blocking := NodeFunc[queueState](func(ctx context.Context, _ queueState) NodeResult[queueState] {
+			close(started)
+			<-ctx.Done()
+			return NodeResult[queueState]{Err: ctx.Err()}
+		})
+		if err := slow.Add("fan", blocking); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+
+		slowCtx, stopSlow := context.WithCancel(ctx)
+		slowDone := make(chan error)
+		go func() { slowDone <- slow.Work(slowCtx) }()
+
+		result := make(chan error)
+		go func() {
+			_, err := coordinator.Run(ctx, "run-001", queueState{})
+			result <- err
+		}()
+
+		// A worker stopping while it executes fan releases the item
+		<-started
+		stopSlow()
+		<-slowDone
+
+		worker := newDistributedTestEngine(t, st, opts, "worker", 0)
+		workCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		go func() { _ = worker.Work(workCtx) }()
+
+		if err := <-result; err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	})
+
+	t.Run("recorded I/O is replayed", func(t *testing.T) {
+		st := store.NewMemStore[string]()
+		opts := opts
+		opts.WorkQueue = st
+		reducer := func(prev, delta string) string {
+			if prev == "" {
+				return delta
+			}
+			return prev + "," + delta
+		}
+		// newEngine builds fanout -> {lookup0, lookup1} calling service
+		newEngine := func(opts Options, service *lookupService) *Engine[string] {
+			engine := New(reducer, st, &mockEmitter{}, opts)
+			fanout := NodeFunc[string](func(_ context.Context, _ string) NodeResult[string] {
+				return NodeResult[string]{Route: Many([]string{"lookup0", "lookup1"})}
+			})
+			if err := engine.Add("fanout", fanout); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+			for i, query := range []string{"a", "b"} {
+				if err := engine.Add(fmt.Sprintf("lookup%d", i), &lookupNode{service: service, query: query, recordable: true}); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+			}
+			if err := engine.StartAt("fanout"); err != nil {
+				t.Fatalf("StartAt failed: %v", err)
+			}
+			return engine
+		}
+		// work runs a worker calling service until the returned func is called
+		work := func(service *lookupService) func() {
+			workCtx, stop := context.WithCancel(ctx)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_ = newEngine(opts, service).Work(workCtx)
+			}()
+			return func() { stop(); <-done }
+		}
+
+		unused := &lookupService{answer: "coordinator"}
+		stop := work(&lookupService{answer: "live"})
+		recorded, err := newEngine(opts, unused).Run(ctx, "run-001", "")
+		stop()
+		if err != nil {
+			t.Fatalf("record Run failed: %v", err)
+		}
+
+		// The worker replays in the run's mode, whatever its own options
+		replayService := &lookupService{answer: "changed"}
+		stop = work(replayService)
+		replayOpts := opts
+		replayOpts.ReplayMode, replayOpts.StrictReplay = true, true
+		replayed, err := newEngine(replayOpts, unused).Run(ctx, "run-001", "")
+		stop()
+		if err != nil {
+			t.Fatalf("replay Run failed: %v", err)
+		}
+
+		if replayed != recorded || len(recorded) != len("live:a,live:b") {
+			t.Errorf("replayed %q, recorded %q, want both answers from the live service", replayed, recorded)
+		}
+		if calls := replayService.callCount() + unused.callCount(); calls != 0 {
+			t.Errorf("replay made %d live calls, want 0", calls)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var engineErr *EngineError
+		engine := New(queueReducer, store.NewMemStore[queueState](), &mockEmitter{}, Options{})
+		if err := engine.Work(ctx); !errors.As(err, &engineErr) || engineErr.Code != "WORK_QUEUE_REQUIRED" {
+			t.Errorf("expected WORK_QUEUE_REQUIRED, got %v", err)
+		}
+		var nilEngine *Engine[queueState]
+		if err := nilEngine.Work(ctx); !errors.As(err, &engineErr) || engineErr.Code != "NIL_ENGINE" {
+			t.Errorf("expected NIL_ENGINE, got %v", err)
+		}
+	})
+}
+
+// workerProcessEnv names the SQLite database a re-executed test binary
+// works on as a worker process, see TestWorkerProcess.
+const workerProcessEnv = "LANGGRAPH_TEST_WORKER_DB"
+
+// TestWorkerProcess is the worker process started by
+// TestEngine_WorkQueueProcesses. It works on the database named by
+// workerProcessEnv until it is killed, and is skipped otherwise.
+func TestWorkerProcess(t *testing.T) {
+	path := os.Getenv(workerProcessEnv)
+	if path == "" {
+		t.Skip("only runs as a worker process")
+	}
+	st, err := store.NewSQLiteStore[queueState](path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	opts := Options{MaxSteps: 20, MaxConcurrentNodes: 1, WorkQueue: st, WorkPollInterval: 10 * time.Millisecond}
+	worker := newDistributedTestEngine(t, st, opts, fmt.Sprint(os.Getpid()), 300*time.Millisecond)
+	if err := os.WriteFile(path+fmt.Sprintf(".%d.ready", os.Getpid()), nil, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	_ = worker.Work(context.Background())
+}
+
+// TestEngine_WorkQueueProcesses runs a workflow whose nodes execute in two
+// worker processes sharing a SQLite database with the coordinator.
+func TestEngine_WorkQueueProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts worker processes")
+	}
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "work.db")
+	st, err := store.NewSQLiteStore[queueState](path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	for i := 0; i < 2; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWorkerProcess$") // #nosec G204 -- re-executes the test binary
+		cmd.Env = append(os.Environ(), workerProcessEnv+"="+path)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("starting worker process failed: %v", err)
+		}
+		defer func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}()
+	}
+
+	// Wait for both workers to poll the queue
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		ready, _ := filepath.Glob(path + ".*.ready")
+		if len(ready) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker processes did not start, %d ready", len(ready))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	opts := Options{MaxSteps: 20, MaxConcurrentNodes: 4, WorkQueue: st, WorkPollInterval: 10 * time.Millisecond}
+	coordinator := newDistributedTestEngine(t, st, opts, "coordinator", 0)
+	runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	final, err := coordinator.Run(runCtx, "run-001", queueState{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	pids := map[string]bool{}
+	for _, entry := range final.Executed {
+		id, pid, _ := strings.Cut(entry, "@")
+		if pid == "coordinator" || pid == fmt.Sprint(os.Getpid()) {
+			t.Errorf("%s executed in the coordinator", id)
+		}
+		pids[pid] = true
+	}
+	if len(final.Executed) != 5 || len(pids) != 2 {
+		t.Errorf("executed %v, want 5 nodes executed by 2 worker processes", final.Executed)
+	}
+}
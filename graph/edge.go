@@ -25,6 +25,10 @@ type Edge[S any] struct {
 	// If nil, the edge is unconditional (always traverse).
 	// If non-nil, the edge is only traversed when When(state) returns true.
 	When Predicate[S]
+
+	// Label is an optional description of the edge, such as the condition its
+	// predicate checks. It is shown when rendering the graph (see Topology).
+	Label string
 }
 
 // Predicate is a function that evaluates state to determine if an edge should be traversed.
@@ -6,7 +6,6 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"math/rand"
 	"sort"
 	"sync"
@@ -57,8 +56,19 @@ const (
 	// Type: *rand.Rand (from math/rand package)
 	RNGKey contextKey = "langgraph.rng"
 
-	// RecordedIOsKey is the context key for storing recorded I/O during replay.
+	// RecordedIOsKey is the context key for the recorded I/O a run starts from.
+	// Type: []RecordedIO. In replay mode these are served to Recorded calls; they
+	// are not saved again in the run's new checkpoints. Set by Run,
+	// RunWithCheckpoint and Resume (in replay mode) and ReplayRun unless already present.
 	RecordedIOsKey contextKey = "langgraph.recordedIOs"
+
+	// ResumeInputKey is the context key for the input passed to Engine.Resume.
+	// It is only set for the node that interrupted the run; use ResumeInput to read it.
+	ResumeInputKey contextKey = "langgraph.resume_input"
+
+	// SendInputKey is the context key for the branch input of a node started by
+	// a Sends route. Use SendInput or SendInputAs to read it.
+	SendInputKey contextKey = "langgraph.send_input"
 )
 
 // initRNG creates a deterministic random number generator seeded from the runID.
@@ -98,18 +108,21 @@ const (
 // Returns:
 //   - *rand.Rand: A seeded random number generator for this run
 func initRNG(runID string) *rand.Rand {
-	// Hash the runID to generate a deterministic seed
+	// Create a new rand.Rand with the deterministic seed
+	// Note: Using math/rand (not crypto/rand) intentionally for deterministic replay
+	source := rand.NewSource(runSeed(runID)) // #nosec G404 -- deterministic RNG for replay, not security
+	return rand.New(source)                  // #nosec G404 -- deterministic RNG for replay, not security
+}
+
+// runSeed derives the deterministic RNG seed for a run from its runID.
+//
+// The seed is the first 8 bytes of the SHA-256 hash of runID. Per-work-item
+// RNGs XOR this seed with the item's OrderKey.
+func runSeed(runID string) int64 {
 	hasher := sha256.New()
 	hasher.Write([]byte(runID))
 	hashBytes := hasher.Sum(nil)
-
-	// Extract first 8 bytes as int64 seed
-	seed := int64(binary.BigEndian.Uint64(hashBytes[:8])) // #nosec G115 -- conversion for deterministic seeding
-
-	// Create a new rand.Rand with the deterministic seed
-	// Note: Using math/rand (not crypto/rand) intentionally for deterministic replay
-	source := rand.NewSource(seed) // #nosec G404 -- deterministic RNG for replay, not security
-	return rand.New(source)        // #nosec G404 -- deterministic RNG for replay, not security
+	return int64(binary.BigEndian.Uint64(hashBytes[:8])) // #nosec G115 -- conversion for deterministic seeding
 }
 
 // Reducer is a function that merges a partial state update (delta) into the previous state.
@@ -148,6 +161,11 @@ type Reducer[S any] func(prev, delta S) S
 //
 // Type parameter S is the state type shared across the workflow.
 //
+// An Engine is safe for concurrent use: runs with different run IDs can
+// execute on one engine in parallel, each with its own frontier, step
+// counter, RNG and recorded I/O. Compile the graph before sharing the engine
+// so it cannot change while runs execute.
+//
 // Example:
 //
 //	reducer := func(prev, delta MyState) MyState {
@@ -179,9 +197,15 @@ type Engine[S any] struct {
 	// edges defines conditional transitions between nodes
 	edges []Edge[S]
 
+	// joins maps fan-in node IDs to the predecessors they wait for (see Join)
+	joins map[string][]string
+
 	// startNode is the entry point for workflow execution
 	startNode string
 
+	// compiled is set by a successful Compile and rejects further graph changes
+	compiled bool
+
 	// store persists workflow state and checkpoints
 	store store.Store[S]
 
@@ -201,11 +225,28 @@ type Engine[S any] struct {
 	// opts contains execution configuration
 	opts Options
 
-	// frontier manages the execution frontier queue for concurrent node execution.
-	// It is initialized in Run() with capacity from Options.QueueDepth and handles
-	// work item scheduling with deterministic ordering via OrderKey.
-	// Nil when MaxConcurrentNodes = 0 (sequential execution mode).
-	frontier *Frontier[S]
+	// streams maps run IDs to the streamSink of an open Stream call (see Stream)
+	streams sync.Map
+
+	// parents maps the run IDs of nested runs to the function re-emitting
+	// their events in the parent graph (see Subgraph)
+	parents sync.Map
+
+	// runs maps the IDs of executing runs to their *activeRun, which holds
+	// the state scoped to the run (see Status)
+	runs sync.Map
+
+	// pools limits the nodes executing at once per resource tag across all
+	// runs (see Options.ResourcePools). Nil without pools.
+	pools *resourcePools
+
+	// errorEdges route failed nodes to their handlers, in the order they
+	// were added (see OnError)
+	errorEdges []errorEdge
+
+	// errorState turns a routed failure into the delta merged before the
+	// handler runs (see OnErrorState). Nil merges a zero delta.
+	errorState func(failure *NodeError) S
 }
 
 // Options configures Engine execution behavior.
@@ -233,9 +274,11 @@ type Options struct {
 	// When MaxSteps is exceeded, Run() returns EngineError with code "MAX_STEPS_EXCEEDED".
 	MaxSteps int
 
-	// Retries specifies how many times to retry a node on transient errors.
-	// If 0, nodes are not retried.
-	// Transient errors are identified by checking if error implements Retryable interface.
+	// Retries specifies how many times to retry a node that fails, in both
+	// execution modes. If 0, nodes are not retried. Every error is retried,
+	// with exponential backoff from a 100ms base delay. A node's
+	// NodePolicy.RetryPolicy takes precedence.
+	//
 	// Deprecated: Use NodePolicy.RetryPolicy for per-node retry configuration.
 	Retries int
 
@@ -283,8 +326,9 @@ type Options struct {
 	// ReplayMode enables deterministic replay using recorded I/O.
 	// Default: false (record mode - captures I/O for later replay).
 	//
-	// When true, nodes with SideEffectPolicy.Recordable=true will use recorded
-	// responses instead of executing live I/O. This enables:
+	// When true, calls made through Recorded by nodes with
+	// SideEffectPolicy.Recordable=true return recorded responses instead of
+	// executing live I/O. This enables:
 	//   - Debugging: Replay production executions locally
 	//   - Testing: Verify workflow logic without external dependencies
 	//   - Auditing: Reconstruct exact execution flow from checkpoints
@@ -306,8 +350,9 @@ type Options struct {
 	// If nil, metrics are not collected.
 	//
 	// Create with NewPrometheusMetrics(registry) to enable production monitoring.
-	// All 6 metrics (inflight_nodes, queue_depth, step_latency_ms, retries_total,
-	// merge_conflicts_total, backpressure_events_total) are automatically updated.
+	// The execution metrics (inflight_nodes, queue_depth, step_latency_ms,
+	// retries_total, merge_conflicts_total, backpressure_events_total) are
+	// automatically updated, and pruned_records_total by Prune and PruneEvery.
 	//
 	// Example:
 	//   registry := prometheus.NewRegistry()
@@ -325,6 +370,82 @@ type Options struct {
 	//   tracker := NewCostTracker("run-123", "USD")
 	//   engine := New(reducer, store, emitter, Options{CostTracker: tracker})
 	CostTracker *CostTracker
+
+	// ConflictPolicy selects how concurrent branches writing the same state
+	// field are handled. Default: ConflictNone, which does not detect
+	// conflicts.
+	//
+	// See ConflictPolicy for how conflicts are detected.
+	ConflictPolicy ConflictPolicy
+
+	// CheckpointOnCancel saves a CheckpointV2 labeled "cancel" when a run is
+	// stopped with Engine.Cancel, holding the state after its last completed
+	// step and the work that had not completed. Default: false.
+	//
+	// Pass the checkpoint to RunWithCheckpoint to continue the run later.
+	CheckpointOnCancel bool
+
+	// CheckpointEveryStep saves a CheckpointV2 when a run starts and after
+	// each of its steps, so Fork can branch off any of them with the exact
+	// work the run had pending. Sequential runs are checkpointed between
+	// rounds, concurrent runs after every node. Default: false.
+	//
+	// Each checkpoint is a store write and emits a checkpoint_saved event.
+	CheckpointEveryStep bool
+
+	// LeaseDuration enables crash recovery when the store implements
+	// store.RunTracker. Each run is recorded as running while it executes and
+	// holds a lease on its record that is renewed every LeaseDuration/3; its
+	// outcome is recorded when it returns. A run whose lease expired was
+	// abandoned and is resumed by RecoverIncomplete. Default: 0 (disabled).
+	//
+	// Leased runs are checkpointed when they start and after each step, as
+	// under CheckpointEveryStep, so that RecoverIncomplete continues an
+	// abandoned run from its last completed step instead of executing its
+	// nodes, with their side effects, again.
+	//
+	// Choose a duration well above the store's write latency: a run whose
+	// lease expires while it executes can be claimed by another process, and
+	// is then cancelled with a LEASE_LOST error.
+	LeaseDuration time.Duration
+
+	// LeaseOwner identifies this process in run leases. Processes sharing a
+	// store must use distinct owners. Default: "hostname:pid".
+	LeaseOwner string
+
+	// WorkQueue distributes the node executions of concurrent runs
+	// (MaxConcurrentNodes > 0) to worker processes calling Engine.Work on a
+	// queue backed by the same store. The process calling Run still routes,
+	// merges and checkpoints the run; each work item is pushed under its
+	// idempotency key and its result read back once a worker committed it.
+	// Default: nil (nodes execute in this process).
+	//
+	// Workers hold items for LeaseDuration (30s if unset), so the items of a
+	// crashed worker are executed again by another one.
+	WorkQueue store.WorkQueue
+
+	// WorkPollInterval is how often the work queue is polled for new items
+	// and committed results. Default: 50ms.
+	WorkPollInterval time.Duration
+
+	// ResourcePools limits how many nodes tagged with each resource execute
+	// at once across all runs of the engine, for example {"openai": 3,
+	// "db": 1}. Nodes declare their tags in NodePolicy.Resources; tags
+	// without a pool are not limited. A limit below 1 is treated as 1.
+	// Default: nil (only MaxConcurrentNodes limits concurrency).
+	//
+	// In concurrent mode, workers skip queued items whose pools are full and
+	// execute the next admissible item in OrderKey order instead, so nodes
+	// outside a saturated pool are not held up by it.
+	ResourcePools map[string]int
+
+	// PriorityAging is the number of steps after which a queued work item
+	// gains one priority level over items queued at later steps, so that
+	// low-priority items are not starved by a stream of higher-priority
+	// ones (see NodePolicy.Priority). Age is counted in StepIDs, not time,
+	// so the dispatch order stays the same on replay.
+	// Default: 0 (no aging; priority, then OrderKey).
+	PriorityAging int
 }
 
 // New creates a new Engine with the given configuration.
@@ -392,15 +513,22 @@ func New[S any](reducer Reducer[S], st store.Store[S], emitter emit.Emitter, opt
 		}
 	}
 
+	// CRDT fields merge with their Merge method under ConflictCRDT
+	if cfg.opts.ConflictPolicy == ConflictCRDT && reducer != nil {
+		reducer = crdtReducer(reducer)
+	}
+
 	return &Engine[S]{
 		reducer:     reducer,
 		nodes:       make(map[string]Node[S]),
 		edges:       make([]Edge[S], 0),
+		joins:       make(map[string][]string),
 		store:       st,
 		emitter:     emitter,
 		metrics:     cfg.opts.Metrics,     // T044: Optional metrics
 		costTracker: cfg.opts.CostTracker, // T045: Optional cost tracking
 		opts:        cfg.opts,
+		pools:       newResourcePools(cfg.opts.ResourcePools),
 	}
 }
 
@@ -417,6 +545,7 @@ func New[S any](reducer Reducer[S], st store.Store[S], emitter emit.Emitter, opt
 //   - nodeID is empty
 //   - node is nil
 //   - a node with this ID already exists
+//   - the graph has been compiled (see Compile)
 //
 // Example:
 //
@@ -443,6 +572,9 @@ func (e *Engine[S]) Add(nodeID string, node Node[S]) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.compiled {
+		return compiledError("add node " + nodeID)
+	}
 	if _, exists := e.nodes[nodeID]; exists {
 		return &EngineError{
 			Message: "duplicate node ID: " + nodeID,
@@ -465,6 +597,7 @@ func (e *Engine[S]) Add(nodeID string, node Node[S]) error {
 // Returns error if:
 //   - nodeID is empty
 //   - node with this ID doesn't exist
+//   - the graph has been compiled (see Compile)
 //
 // Example:
 //
@@ -482,6 +615,9 @@ func (e *Engine[S]) StartAt(nodeID string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.compiled {
+		return compiledError("change start node")
+	}
 	if _, exists := e.nodes[nodeID]; !exists {
 		return &EngineError{
 			Message: "start node does not exist: " + nodeID,
@@ -509,9 +645,11 @@ func (e *Engine[S]) StartAt(nodeID string) error {
 //
 // Returns error if:
 //   - from or to is empty
+//   - the graph has been compiled (see Compile)
 //
 // Note: Node existence is not validated (lazy validation) to allow
-// flexible graph construction order.
+// flexible graph construction order. Use Validate or Compile to check
+// edges before Run.
 //
 // Example:
 //
@@ -523,6 +661,20 @@ func (e *Engine[S]) StartAt(nodeID string) error {
 //	    return s.Score > 0.8
 //	})
 func (e *Engine[S]) Connect(from, to string, predicate Predicate[S]) error {
+	return e.ConnectLabeled(from, to, "", predicate)
+}
+
+// ConnectLabeled creates an edge like Connect and gives it a label.
+//
+// Labels describe the edge, typically the condition its predicate checks, and
+// are shown when rendering the graph with Topology. They do not affect routing.
+//
+// Example:
+//
+//	engine.ConnectLabeled("review", "publish", "score > 0.8", func(s MyState) bool {
+//	    return s.Score > 0.8
+//	})
+func (e *Engine[S]) ConnectLabeled(from, to, label string, predicate Predicate[S]) error {
 	// Prevent panic when called on nil Engine
 	if e == nil {
 		return &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
@@ -537,10 +689,15 @@ func (e *Engine[S]) Connect(from, to string, predicate Predicate[S]) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.compiled {
+		return compiledError("connect " + from + " -> " + to)
+	}
+
 	edge := Edge[S]{
-		From: from,
-		To:   to,
-		When: predicate,
+		From:  from,
+		To:    to,
+		When:  predicate,
+		Label: label,
 	}
 
 	e.edges = append(e.edges, edge)
@@ -577,6 +734,10 @@ func (e *Engine[S]) Connect(from, to string, predicate Predicate[S]) error {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Final state: %+v\n", final)
+//
+// Thread-safety: This method is safe for concurrent use with different runIDs.
+// Starting a runID that is already executing returns an EngineError with code
+// RUN_IN_PROGRESS.
 func (e *Engine[S]) Run(ctx context.Context, runID string, initial S) (S, error) {
 	var zero S
 
@@ -598,7 +759,14 @@ func (e *Engine[S]) Run(ctx context.Context, runID string, initial S) (S, error)
 			Code:    "MISSING_STORE",
 		}
 	}
-	if e.startNode == "" {
+
+	// Read the start node under the lock, since the graph may change until Compile
+	e.mu.RLock()
+	startNode := e.startNode
+	_, exists := e.nodes[startNode]
+	e.mu.RUnlock()
+
+	if startNode == "" {
 		return zero, &EngineError{
 			Message: "start node not set (call StartAt before Run)",
 			Code:    "NO_START_NODE",
@@ -606,17 +774,20 @@ func (e *Engine[S]) Run(ctx context.Context, runID string, initial S) (S, error)
 	}
 
 	// Validate start node exists
-	e.mu.RLock()
-	_, exists := e.nodes[e.startNode]
-	e.mu.RUnlock()
-
 	if !exists {
 		return zero, &EngineError{
-			Message: "start node does not exist: " + e.startNode,
+			Message: "start node does not exist: " + startNode,
 			Code:    "NODE_NOT_FOUND",
 		}
 	}
 
+	// Register the run so it can be inspected and cancelled while it executes
+	ctx, run, unregister, err := e.register(ctx, runID, 0, nil)
+	if err != nil {
+		return zero, err
+	}
+	defer unregister()
+
 	// Enforce RunWallClockBudget if configured (T075)
 	// This creates a derived context with timeout that applies to the entire workflow execution.
 	// When the budget is exceeded, all running nodes receive context cancellation.
@@ -633,208 +804,35 @@ func (e *Engine[S]) Run(ctx context.Context, runID string, initial S) (S, error)
 	rng := initRNG(runID)
 	ctx = context.WithValue(ctx, RNGKey, rng)
 
-	// Initialize Frontier for concurrent execution if MaxConcurrentNodes > 0 (T034)
-	if e.opts.MaxConcurrentNodes > 0 {
-		queueDepth := e.opts.QueueDepth
-		if queueDepth == 0 {
-			queueDepth = 1024 // Default queue depth
+	// Replay serves Recorded calls from the run's checkpoints
+	if e.opts.ReplayMode && ctx.Value(RecordedIOsKey) == nil {
+		recorded, err := e.loadRecordedIOs(ctx, runID, -1)
+		if err != nil {
+			return run.settle(zero, err)
 		}
-		e.frontier = NewFrontier[S](ctx, queueDepth, runID, e.opts.Metrics, e.emitter)
-
-		// Use concurrent execution path (T035)
-		return e.runConcurrent(ctx, runID, initial)
+		ctx = context.WithValue(ctx, RecordedIOsKey, recorded)
 	}
 
-	// Initialize execution state (sequential execution path)
-	currentState := initial
-	currentNode := e.startNode
-	step := 0
-
-	// Execution loop
-	for {
-		step++
-
-		// Check MaxSteps limit (T060)
-		if e.opts.MaxSteps > 0 && step > e.opts.MaxSteps {
-			return zero, &EngineError{
-				Message: "workflow exceeded MaxSteps limit",
-				Code:    "MAX_STEPS_EXCEEDED",
-			}
-		}
-
-		// Check context cancellation
-		select {
-		case <-ctx.Done():
-			return zero, ctx.Err()
-		default:
-		}
-
-		// Get current node implementation
-		e.mu.RLock()
-		nodeImpl, exists := e.nodes[currentNode]
-		e.mu.RUnlock()
-
-		if !exists {
-			return zero, &EngineError{
-				Message: "node not found during execution: " + currentNode,
-				Code:    "NODE_NOT_FOUND",
-			}
-		}
-
-		// Emit node_start event (T153)
-		e.emitNodeStart(runID, currentNode, step-1) // step is incremented at start of loop, but events use 0-based indexing
-
-		// Get node policy for retry and timeout configuration (US1, US2)
-		var policy *NodePolicy
-		if policyProvider, ok := nodeImpl.(interface{ Policy() NodePolicy }); ok {
-			p := policyProvider.Policy()
-			policy = &p
-		}
-
-		// Execute node with retry support for sequential execution (US1: T005-T009)
-		var result NodeResult[S]
-		maxRetries := e.opts.Retries // Number of retry attempts (0 = no retries)
-
-		for attempt := 0; attempt <= maxRetries; attempt++ {
-			// Add retry attempt to context for nodes to access
-			attemptCtx := context.WithValue(ctx, AttemptKey, attempt)
-
-			// Execute node with timeout enforcement (US2: T017, T018)
-			var timeoutErr error
-			result, timeoutErr = executeNodeWithTimeout(attemptCtx, nodeImpl, currentNode, currentState, policy, e.opts.DefaultNodeTimeout)
-			if timeoutErr != nil {
-				// Timeout occurred - treat as node error
-				result.Err = timeoutErr
-			}
-
-			// If node succeeded, break out of retry loop
-			if result.Err == nil {
-				break
-			}
-
-			// Node failed - check if we should retry
-			if attempt < maxRetries {
-				// Get RNG from attemptCtx for deterministic backoff jitter
-				var rng *rand.Rand
-				if rngVal := attemptCtx.Value(RNGKey); rngVal != nil {
-					if r, ok := rngVal.(*rand.Rand); ok {
-						rng = r
-					}
-				}
-
-				// Compute exponential backoff with jitter (deterministic)
-				const maxBackoff = 30 * time.Second // Cap backoff to prevent overflow
-				baseDelay := 100 * time.Millisecond
-
-				// Safe exponential calculation with bounds checking
-				shift := attempt
-				if shift > 10 {
-					shift = 10 // Cap shift to prevent overflow (2^10 = 1024)
-				}
-				backoff := baseDelay * time.Duration(1<<uint(shift)) // #nosec G115 -- shift capped at 10, safe conversion
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-
-				// Add deterministic jitter if RNG available
-				var jitter time.Duration
-				if rng != nil {
-					// Deterministic jitter for replay consistency
-					jitter = time.Duration(rng.Intn(50)) * time.Millisecond
-				}
-				delay := backoff + jitter
-
-				// Apply backoff delay with context cancellation support
-				select {
-				case <-time.After(delay):
-					// Backoff completed, continue to retry
-				case <-ctx.Done():
-					// Context canceled during backoff
-					return zero, ctx.Err()
-				}
-
-				// Continue to next retry attempt
-				continue
-			}
-
-			// Max retries exceeded - emit final error and return
-			e.emitError(runID, currentNode, step-1, result.Err)
-			return zero, result.Err
-		}
-
-		// Merge state update (only on success)
-		currentState = e.reducer(currentState, result.Delta)
-
-		// Persist state after node execution (T058)
-		if err := e.store.SaveStep(ctx, runID, step, currentNode, currentState); err != nil {
-			return zero, &EngineError{
-				Message: "failed to save step: " + err.Error(),
-				Code:    "STORE_ERROR",
-			}
-		}
-
-		// Emit node_end event with delta (T155)
-		e.emitNodeEnd(runID, currentNode, step-1, result.Delta)
-
-		// Determine next node from routing decision
-		if result.Route.Terminal {
-			// Emit routing_decision event for Stop (T157)
-			e.emitRoutingDecision(runID, currentNode, step-1, map[string]interface{}{
-				"terminal": true,
-			})
-			// Workflow complete
-			return currentState, nil
-		}
-
-		// Handle parallel execution (fan-out) - T104-T108
-		if len(result.Route.Many) > 0 {
-			// Emit routing_decision event for parallel execution (T157)
-			e.emitRoutingDecision(runID, currentNode, step-1, map[string]interface{}{
-				"parallel": true,
-				"branches": result.Route.Many,
-			})
-
-			// Execute branches in parallel with isolated state copies
-			parallelState, err := e.executeParallel(ctx, result.Route.Many, currentState)
-			if err != nil {
-				return zero, err
-			}
-			currentState = parallelState
-
-			// Parallel execution completes the workflow (all branches end with Stop())
-			return currentState, nil
-		}
-
-		if result.Route.To != "" {
-			// Emit routing_decision event for Goto (T157)
-			e.emitRoutingDecision(runID, currentNode, step-1, map[string]interface{}{
-				"next_node": result.Route.To,
-			})
-
-			// Single next node (Goto)
-			currentNode = result.Route.To
-			continue
-		}
-
-		// If no explicit route, fall back to edge-based routing (T079)
-		nextNode := e.evaluateEdges(currentNode, currentState)
-		if nextNode == "" {
-			// No matching edge found - workflow cannot continue
-			return zero, &EngineError{
-				Message: "no valid route from node: " + currentNode,
-				Code:    "NO_ROUTE",
-			}
+	// Checkpoint the start of the run so it can be forked from step 0, so
+	// RecoverIncomplete can restart a leased run that never got further, and
+	// so ReplayRun can re-execute a run that recorded I/O
+	entry := entryItem(startNode, initial)
+	if e.checkpointsSteps(run) || e.recordsIO() {
+		if err := e.saveCheckpoint(ctx, runID, 0, initial, []WorkItem[S]{entry}, nil, ""); err != nil {
+			return run.settle(zero, err)
 		}
+	}
 
-		// Emit routing_decision event for edge-based routing (T157)
-		e.emitRoutingDecision(runID, currentNode, step-1, map[string]interface{}{
-			"next_node": nextNode,
-			"via_edge":  true,
-		})
+	// Initialize Frontier for concurrent execution if MaxConcurrentNodes > 0 (T034)
+	if e.opts.MaxConcurrentNodes > 0 {
+		run.frontier = e.newFrontier(ctx, runID)
 
-		currentNode = nextNode
-		continue
+		// Use concurrent execution path (T035)
+		return run.settle(e.runConcurrent(ctx, run, initial, entry))
 	}
+
+	// Sequential execution path
+	return run.settle(e.runSequential(ctx, run, initial, []WorkItem[S]{entry}, 0, e.newJoinBarriers(runID)))
 }
 
 // evaluateEdges finds the first matching edge from the given node based on predicates (T079, T081).
@@ -870,55 +868,241 @@ func (e *Engine[S]) evaluateEdges(fromNode string, state S) string {
 	return ""
 }
 
+// routeSuccessors resolves a node's routing decision into the work items that
+// run next, emitting the routing_decision event.
+//
+// Routing precedence matches both execution modes:
+//  1. Stop() ends the path and yields no successors
+//  2. Many() yields one branch per target, each with a deep copy of nextState
+//  3. Sends() yields one branch per Send, each with a deep copy of nextState
+//     and its own input
+//  4. Goto() yields its target
+//  5. Otherwise the first matching edge, evaluated against nextState
+//
+// nextState is the state successors observe: the node's input state with its
+// delta applied. Returns a NO_ROUTE error when no route or edge matches.
+func (e *Engine[S]) routeSuccessors(runID string, item WorkItem[S], result NodeResult[S], nextState S) ([]WorkItem[S], error) {
+	if result.Route.Terminal {
+		e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
+			"terminal": true,
+		})
+		return nil, nil
+	}
+
+	// Fan-out routing (Next.Many)
+	if len(result.Route.Many) > 0 {
+		e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
+			"parallel": true,
+			"branches": result.Route.Many,
+		})
+
+		fanout := fanoutKey(item)
+		branches := make([]WorkItem[S], 0, len(result.Route.Many))
+		for edgeIdx, branchID := range result.Route.Many {
+			// Deep copy state for branch isolation
+			branchState, err := deepCopyState(nextState)
+			if err != nil {
+				return nil, err
+			}
+
+			orderKey := successorOrderKey(item, edgeIdx)
+			branches = append(branches, WorkItem[S]{
+				StepID:       item.StepID + 1,
+				OrderKey:     orderKey,
+				NodeID:       branchID,
+				State:        branchState,
+				Attempt:      0,
+				ParentNodeID: item.NodeID,
+				EdgeIndex:    edgeIdx,
+				Forks:        forkBranch(item.Forks, result.Delta),
+				ForkSlots:    forkSlotsBranch(item, ForkSlot{Fanout: fanout, Branch: orderKey}),
+			})
+		}
+		return branches, nil
+	}
+
+	// Map-reduce fan-out (Next.Sends)
+	if len(result.Route.Sends) > 0 {
+		targets := make([]string, len(result.Route.Sends))
+		for i, send := range result.Route.Sends {
+			targets[i] = send.NodeID
+		}
+		e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
+			"parallel": true,
+			"send":     true,
+			"branches": targets,
+		})
+
+		fanout := fanoutKey(item)
+		count := len(result.Route.Sends)
+		branches := make([]WorkItem[S], 0, count)
+		for idx, send := range result.Route.Sends {
+			// Deep copy state for branch isolation
+			branchState, err := deepCopyState(nextState)
+			if err != nil {
+				return nil, err
+			}
+
+			orderKey := sendOrderKey(item, idx)
+			branches = append(branches, WorkItem[S]{
+				StepID:       item.StepID + 1,
+				OrderKey:     orderKey,
+				NodeID:       send.NodeID,
+				State:        branchState,
+				Attempt:      0,
+				ParentNodeID: item.NodeID,
+				EdgeIndex:    idx,
+				Forks:        forkBranch(item.Forks, result.Delta),
+				ForkSlots:    forkSlotsBranch(item, ForkSlot{Fanout: fanout, Branch: orderKey, Count: count}),
+				Input:        send.Input,
+			})
+		}
+		return branches, nil
+	}
+
+	// Single next node (Goto), falling back to edge-based routing
+	nextNode := result.Route.To
+	viaEdge := false
+	if nextNode == "" {
+		nextNode = e.evaluateEdges(item.NodeID, nextState)
+		viaEdge = true
+	}
+	if nextNode == "" {
+		return nil, &EngineError{
+			Message: "no valid route from node: " + item.NodeID,
+			Code:    "NO_ROUTE",
+		}
+	}
+
+	decision := map[string]interface{}{
+		"next_node": nextNode,
+	}
+	if viaEdge {
+		decision["via_edge"] = true
+	}
+	e.emitRoutingDecision(runID, item.NodeID, item.StepID, decision)
+
+	return []WorkItem[S]{{
+		StepID:       item.StepID + 1,
+		OrderKey:     successorOrderKey(item, 0),
+		NodeID:       nextNode,
+		State:        nextState,
+		Attempt:      0,
+		ParentNodeID: item.NodeID,
+		EdgeIndex:    0,
+		Forks:        forkContinue(item.Forks, result.Delta),
+		ForkSlots:    item.ForkSlots,
+	}}, nil
+}
+
 // nodeResult represents the outcome of a single node execution in concurrent mode.
 // Used internally by runConcurrent for collecting and merging results.
 type nodeResult[S any] struct {
-	nodeID   string
-	delta    S
-	route    Next
-	orderKey uint64
-	err      error
+	nodeID    string
+	delta     S
+	route     Next
+	orderKey  uint64
+	step      int
+	slots     []ForkSlot     // Fork slots of the executed item, for conflict detection
+	interrupt *pausedNode[S] // Set when the node paused the run with Interrupt
+	flight    int            // ID of the item in the run's registry entry
+	err       error
 }
 
 // runConcurrent executes the workflow using concurrent node execution with the Frontier scheduler (T035).
 //
+// It seeds the run's frontier with entry and delegates to runFrontier, which
+// owns the worker pool, routing, and deterministic delta merging.
+//
+// Returns final state after workflow completes or error if execution fails.
+func (e *Engine[S]) runConcurrent(ctx context.Context, run *activeRun[S], initial S, entry WorkItem[S]) (S, error) {
+	var zero S
+
+	// Enqueue initial work item
+	if err := run.frontier.Enqueue(ctx, entry); err != nil {
+		return zero, err
+	}
+
+	return e.runFrontier(ctx, run, initial, 0, e.newJoinBarriers(run.runID))
+}
+
+// entryItem creates the work item that begins a run at nodeID.
+func entryItem[S any](nodeID string, state S) WorkItem[S] {
+	return WorkItem[S]{
+		StepID:       0,
+		OrderKey:     computeOrderKey("__start__", 0),
+		NodeID:       nodeID,
+		State:        state,
+		Attempt:      0,
+		ParentNodeID: "__start__",
+		EdgeIndex:    0,
+	}
+}
+
+// runFrontier drains the run's frontier with a pool of concurrent workers.
+//
 // This method implements a worker pool pattern where:
 //  1. Up to MaxConcurrentNodes goroutines execute nodes concurrently
 //  2. Workers dequeue WorkItems from the Frontier (priority queue by OrderKey)
 //  3. After executing a node, workers create new WorkItems for next hops
-//  4. State deltas are collected and merged deterministically by OrderKey
-//  5. WaitGroup tracks active workers for graceful shutdown
+//  4. Routes into join nodes are held at a barrier until all predecessors arrive
+//  5. State deltas are collected and merged deterministically by OrderKey
+//  6. WaitGroup tracks active workers for graceful shutdown
 //
 // The concurrent execution maintains deterministic replay through:
 //   - OrderKey-based work item prioritization (deterministic across runs)
 //   - Ordered delta merging (sort by OrderKey before applying reducer)
 //   - Deep state copies for fan-out branches (isolation)
 //
-// Returns final state after workflow completes or error if execution fails.
-func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S) (S, error) {
+// Nodes that return Interrupt stop their path without contributing a delta.
+// Once the remaining work has settled the run pauses (see pauseRun) and the
+// merged state so far is returned together with the InterruptError.
+//
+// A run stopped with Cancel returns a CancelledError once its workers have
+// stopped. Items whose successors were all dispatched count as completed; the
+// others return to the frontier of the cancel checkpoint (see
+// Options.CheckpointOnCancel).
+//
+// The run's frontier must already contain the work items to execute. startStep is the
+// step counter value to resume from (0 for fresh runs), initial is the state
+// the collected deltas are merged into, and barriers holds the run's join
+// arrivals.
+func (e *Engine[S]) runFrontier(ctx context.Context, run *activeRun[S], initial S, startStep int, barriers *joinBarriers[S]) (S, error) {
 	var zero S
+	runID := run.runID
+	frontier := run.frontier
+
+	// Report the queued work items in the run's Status
+	run.track(frontier.Len)
+
+	// Collect the run's recorded I/O for its checkpoints
+	ctx = e.withRecorder(ctx)
+
+	// Drop the run's queued work once it returns. A run abandoned with its
+	// process keeps the results committed so far, which RecoverIncomplete
+	// reuses when it pushes the same items again.
+	if queue := e.opts.WorkQueue; queue != nil {
+		defer func() {
+			if run.parent.Err() == nil {
+				_ = queue.DeleteWork(context.WithoutCancel(ctx), runID)
+			}
+		}()
+	}
 
 	// WaitGroup tracks active workers
 	var wg sync.WaitGroup
 
-	// Enqueue initial work item
-	initialItem := WorkItem[S]{
-		StepID:       0,
-		OrderKey:     computeOrderKey("__start__", 0),
-		NodeID:       e.startNode,
-		State:        initial,
-		Attempt:      0,
-		ParentNodeID: "__start__",
-		EdgeIndex:    0,
-	}
-
-	if err := e.frontier.Enqueue(ctx, initialItem); err != nil {
-		return zero, err
-	}
-
 	// Track step counter and collected deltas
 	var stepCounter atomic.Int32
+	stepCounter.Store(int32(startStep)) // #nosec G115 -- startStep is bounded by MaxSteps
 	collectedResults := make([]nodeResult[S], 0, e.opts.MaxSteps)
+	var paused []pausedNode[S]
+
+	// Track the run's outstanding work to checkpoint it after every step
+	var steps *stepLog[S]
+	if e.checkpointsSteps(run) {
+		steps = newStepLog(frontier.items(), startStep)
+	}
 
 	// Determine number of worker goroutines (up to MaxConcurrentNodes)
 	const defaultMaxWorkers = 8
@@ -939,10 +1123,7 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 	// We compute the base seed the same way as initRNG() does, then use it to
 	// derive unique seeds for each worker. This ensures deterministic replay
 	// while preventing concurrent access to shared RNG state.
-	hasher := sha256.New()
-	hasher.Write([]byte(runID))
-	hashBytes := hasher.Sum(nil)
-	baseSeed := int64(binary.BigEndian.Uint64(hashBytes[:8])) // #nosec G115 -- conversion for deterministic seeding
+	baseSeed := runSeed(runID)
 
 	// T046: Track inflight nodes for metrics
 	var inflightCounter atomic.Int32
@@ -954,7 +1135,7 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 	// BUG-004 fix (T026): Helper function to check and signal completion atomically
 	// Returns true if this call detected completion (frontier empty + no inflight work)
 	checkCompletion := func() bool {
-		if e.frontier.Len() == 0 && inflightCounter.Load() == 0 {
+		if frontier.Len() == 0 && inflightCounter.Load() == 0 {
 			// Atomically check and set completion flag
 			// Only the first worker to see completion will return true
 			if completionDetected.CompareAndSwap(false, true) {
@@ -979,7 +1160,7 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 					return
 				case <-ticker.C:
 					// Update queue depth and inflight nodes metrics
-					queueDepth := e.frontier.Len()
+					queueDepth := frontier.Len()
 					inflight := int(inflightCounter.Load())
 					e.metrics.UpdateQueueDepth(queueDepth)
 					e.metrics.UpdateInflightNodes(inflight)
@@ -988,6 +1169,38 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 		}()
 	}
 
+	// admit returns the successor work items to enqueue, diverting routes
+	// into join nodes through their barrier. Only the arrival that completes
+	// a barrier admits the join node itself.
+	admit := func(successors []WorkItem[S], delta S) ([]WorkItem[S], error) {
+		ready := make([]WorkItem[S], 0, len(successors))
+		for _, next := range successors {
+			if barriers.has(next.NodeID) {
+				joined, complete, err := barriers.arrive(next, delta)
+				if err != nil {
+					return nil, err
+				}
+				if !complete {
+					continue
+				}
+				e.emitJoinReady(runID, joined)
+				next = joined
+			}
+			ready = append(ready, next)
+		}
+		return ready, nil
+	}
+
+	// Items whose resource pools are full are skipped until a slot frees up,
+	// so they do not hold up the items behind them
+	dequeue := frontier.Dequeue
+	if e.pools != nil {
+		admit := e.poolAdmission()
+		dequeue = func(ctx context.Context) (WorkItem[S], error) {
+			return frontier.dequeueAdmitted(ctx, admit, e.pools.changed)
+		}
+	}
+
 	for i := 0; i < maxWorkers; i++ {
 		wg.Add(1)
 		go func(_ int) {
@@ -995,7 +1208,7 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 
 			for {
 				// Dequeue next work item with worker context for proper cancellation
-				item, err := e.frontier.Dequeue(workerCtx)
+				item, err := dequeue(workerCtx)
 				if err != nil {
 					// BUG-004 fix (T027): Check for completion after dequeue failure
 					// This handles the case where the frontier is empty and no work is inflight
@@ -1004,12 +1217,19 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 					return
 				}
 
+				// Items that fail or are cancelled stay in flight in the
+				// registry, so a cancelled run checkpoints them again
+				flight := run.start(item)
+
 				// T046: Track node execution for inflight metrics (using func to ensure decrement)
 				func() {
 					inflightCounter.Add(1)
 					defer inflightCounter.Add(-1)
+					// Admission took the slots of the item's resource pools
+					defer e.pools.release(e.resourcesOf(item.NodeID))
 
 					currentStep := stepCounter.Add(1)
+					run.setStep(int(currentStep))
 
 					// Check MaxSteps limit
 					if e.opts.MaxSteps > 0 && int(currentStep) > e.opts.MaxSteps {
@@ -1042,13 +1262,6 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 					// Emit node_start event
 					e.emitNodeStart(runID, item.NodeID, item.StepID)
 
-					// Get node policy to check for retry configuration (T087)
-					var policy *NodePolicy
-					if policyProvider, ok := nodeImpl.(interface{ Policy() NodePolicy }); ok {
-						p := policyProvider.Policy()
-						policy = &p
-					}
-
 					// Create work-item-specific RNG seeded from OrderKey for deterministic replay
 					// This ensures the same logical work item (same OrderKey) always uses the same RNG,
 					// regardless of which physical worker goroutine executes it.
@@ -1056,123 +1269,45 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 					itemSeed := baseSeed ^ int64(item.OrderKey)   // #nosec G115 -- XOR for deterministic seeding
 					itemRNG := rand.New(rand.NewSource(itemSeed)) // #nosec G404 -- deterministic RNG for replay, not security
 
-					// Create node context with work-item-specific RNG and attempt number
+					// Create node context with work-item-specific RNG
 					nodeCtx := context.WithValue(workerCtx, RNGKey, itemRNG)
-					nodeCtx = context.WithValue(nodeCtx, AttemptKey, item.Attempt)
-
-					// T046: Track node execution start time for latency metrics
-					startTime := time.Now()
-
-					// Execute node (with replay mode support - T052)
-					// When Options.ReplayMode=true, recorded I/O should be used instead of live execution.
-					// Full replay implementation requires:
-					//   1. Loading checkpoint with RecordedIOs at Run() start
-					//   2. Looking up recorded response via lookupRecordedIO(nodeID, attempt)
-					//   3. Deserializing recorded response into NodeResult
-					//   4. Optionally verifying hash with verifyReplayHash() if StrictReplay=true
-					// For now, execute normally - replay integration will be completed in T056-T057
-					result := nodeImpl.Run(nodeCtx, item.State)
-
-					// T046: Record step latency metric
-					latency := time.Since(startTime)
-					status := "success"
-					if result.Err != nil {
-						status = "error"
-					}
-					if e.metrics != nil {
-						e.metrics.RecordStepLatency(runID, item.NodeID, latency, status)
-					}
+					nodeCtx = e.nodeContext(nodeCtx, runID, item)
 
-					// Handle node error with retry support (T087-T090)
-					if result.Err != nil {
-						e.emitError(runID, item.NodeID, item.StepID, result.Err)
-
-						// Helper to send error result and cancel.
+					// Execute node with timeout and retry support shared with
+					// sequential mode (T087-T090); retries run inline on this worker,
+					// or on the worker process that leased the item from WorkQueue.
+					result, err := e.executeItem(nodeCtx, runID, item, nodeImpl)
+					if err != nil {
 						// Errors are rare and critical - we MUST deliver them to the caller.
 						// Blocking is safe because results channel buffer is maxWorkers*2.
-						sendErrorAndCancel := func(err error) {
-							select {
-							case results <- nodeResult[S]{err: err}:
-								// Error sent successfully
-							case <-ctx.Done():
-								// Parent context canceled before send completed
-								// This is acceptable - workflow is being torn down
-							}
-							cancel()
+						select {
+						case results <- nodeResult[S]{err: err}:
+							// Error sent successfully
+						case <-ctx.Done():
+							// Parent context canceled before send completed
+							// This is acceptable - workflow is being torn down
 						}
+						cancel()
+						return
+					}
 
-						// Check if error is retryable and we haven't exceeded max attempts
-						if policy != nil && policy.RetryPolicy != nil {
-							retryPol := policy.RetryPolicy
-
-							// Validate retry policy configuration (defensive check)
-							// In production, policies should be validated at configuration time
-							if err := retryPol.Validate(); err != nil {
-								// Wrap validation error with context
-								sendErrorAndCancel(fmt.Errorf("retry policy validation failed for node %s: %w", item.NodeID, err))
-								return
-							}
-
-							// Check if error is retryable using predicate (T084)
-							isRetryable := retryPol.Retryable != nil && retryPol.Retryable(result.Err)
-
-							// Calculate remaining retry attempts (T089)
-							// item.Attempt is 0-based, so attempt 0 = first execution
-							// MaxAttempts includes the initial attempt, so MaxAttempts=3 means:
-							// attempt 0 (initial), attempt 1 (retry 1), attempt 2 (retry 2)
-							remainingRetries := retryPol.MaxAttempts - item.Attempt - 1
-
-							if isRetryable && remainingRetries > 0 {
-								// T046: Increment retry metrics
-								if e.metrics != nil {
-									e.metrics.IncrementRetries(runID, item.NodeID, "error")
-								}
-
-								// Compute backoff delay (T086, T090)
-								// Use item-specific RNG for retry backoff calculation for deterministic replay
-								var rng *rand.Rand
-								if rngVal := nodeCtx.Value(RNGKey); rngVal != nil {
-									rng = rngVal.(*rand.Rand)
-								}
-								delay := computeBackoff(item.Attempt, retryPol.BaseDelay, retryPol.MaxDelay, rng)
-
-								// Apply backoff delay before re-enqueueing (T090)
-								time.Sleep(delay)
-
-								// Create retry work item with incremented attempt (T088)
-								retryItem := WorkItem[S]{
-									StepID:       item.StepID,      // Same step ID (retry, not new step)
-									OrderKey:     item.OrderKey,    // Preserve order key for determinism
-									NodeID:       item.NodeID,      // Same node
-									State:        item.State,       // Same input state
-									Attempt:      item.Attempt + 1, // Increment attempt counter (T088)
-									ParentNodeID: item.ParentNodeID,
-									EdgeIndex:    item.EdgeIndex,
-								}
-
-								// Re-enqueue for retry
-								if err := e.frontier.Enqueue(workerCtx, retryItem); err != nil {
-									// If enqueue fails, treat as non-retryable
-									sendErrorAndCancel(result.Err)
-									return
-								}
-
-								// Retry enqueued successfully - exit anonymous function
-								// This returns from func(), not from the goroutine.
-								// The outer for loop continues and dequeues the next work item.
-								return
-							}
-
-							// Error is non-retryable or max attempts exceeded
-							if remainingRetries <= 0 {
-								// Max attempts exceeded (T089)
-								sendErrorAndCancel(ErrMaxAttemptsExceeded)
-								return
-							}
+					// Interrupted paths wait for Resume; their delta is discarded
+					if result.Route.Interrupt {
+						e.emitInterrupt(runID, item, result.Route.InterruptPayload)
+						interrupted := nodeResult[S]{
+							nodeID:    item.NodeID,
+							orderKey:  item.OrderKey,
+							interrupt: &pausedNode[S]{item: item, payload: result.Route.InterruptPayload},
+							flight:    flight,
 						}
-
-						// Non-retryable error or no retry policy
-						sendErrorAndCancel(result.Err)
+						results <- interrupted
+						_, snapshot, _ := steps.complete(item, interrupted, nil, barriers)
+						if err := e.saveStepCheckpoint(ctx, runID, initial, snapshot); err != nil {
+							results <- nodeResult[S]{err: err}
+							cancel()
+							return
+						}
+						run.finish(flight)
 						return
 					}
 
@@ -1180,117 +1315,49 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 					e.emitNodeEnd(runID, item.NodeID, item.StepID, result.Delta)
 
 					// Send result to collection channel
-					results <- nodeResult[S]{
+					completed := nodeResult[S]{
 						nodeID:   item.NodeID,
 						delta:    result.Delta,
 						route:    result.Route,
 						orderKey: item.OrderKey,
+						step:     item.StepID,
+						slots:    item.ForkSlots,
+						flight:   flight,
 						err:      nil,
 					}
+					results <- completed
 
-					// Handle routing (T036)
-					if result.Route.Terminal {
-						// Terminal node - stop workflow
-						e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-							"terminal": true,
-						})
-						// Don't enqueue more work, but continue to allow other parallel branches to complete
+					// Successors observe this node's delta applied to its input state.
+					nextState := e.reducer(item.State, result.Delta)
+					e.publishState(runID, item, nextState)
+
+					successors, err := e.routeSuccessors(runID, item, result, nextState)
+					if err != nil {
+						results <- nodeResult[S]{err: err}
+						cancel()
 						return
 					}
-
-					// Fan-out routing (Next.Many)
-					if len(result.Route.Many) > 0 {
-						e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-							"parallel": true,
-							"branches": result.Route.Many,
-						})
-
-						for edgeIdx, branchID := range result.Route.Many {
-							// Deep copy state for branch isolation
-							branchState, err := deepCopyState(item.State)
-							if err != nil {
-								results <- nodeResult[S]{err: err}
-								cancel()
-								return
-							}
-
-							// Create work item for branch
-							branchItem := WorkItem[S]{
-								StepID:       item.StepID + 1,
-								OrderKey:     computeOrderKey(item.NodeID, edgeIdx),
-								NodeID:       branchID,
-								State:        branchState,
-								Attempt:      0,
-								ParentNodeID: item.NodeID,
-								EdgeIndex:    edgeIdx,
-							}
-
-							if err := e.frontier.Enqueue(workerCtx, branchItem); err != nil {
-								results <- nodeResult[S]{err: err}
-								cancel()
-								return
-							}
-						}
+					ready, snapshot, err := steps.complete(item, completed, func() ([]WorkItem[S], error) {
+						return admit(successors, result.Delta)
+					}, barriers)
+					if err != nil {
+						results <- nodeResult[S]{err: err}
+						cancel()
 						return
 					}
-
-					// Single next node (Goto)
-					if result.Route.To != "" {
-						e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-							"next_node": result.Route.To,
-						})
-
-						nextItem := WorkItem[S]{
-							StepID:       item.StepID + 1,
-							OrderKey:     computeOrderKey(item.NodeID, 0),
-							NodeID:       result.Route.To,
-							State:        item.State,
-							Attempt:      0,
-							ParentNodeID: item.NodeID,
-							EdgeIndex:    0,
-						}
-
-						if err := e.frontier.Enqueue(workerCtx, nextItem); err != nil {
+					for _, next := range ready {
+						if err := frontier.Enqueue(workerCtx, next); err != nil {
 							results <- nodeResult[S]{err: err}
 							cancel()
 							return
 						}
-						return
-					}
-
-					// Edge-based routing fallback
-					nextNode := e.evaluateEdges(item.NodeID, item.State)
-					if nextNode == "" {
-						results <- nodeResult[S]{
-							err: &EngineError{
-								Message: "no valid route from node: " + item.NodeID,
-								Code:    "NO_ROUTE",
-							},
-						}
-						cancel()
-						return
-					}
-
-					e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-						"next_node": nextNode,
-						"via_edge":  true,
-					})
-
-					nextItem := WorkItem[S]{
-						StepID:       item.StepID + 1,
-						OrderKey:     computeOrderKey(item.NodeID, 0),
-						NodeID:       nextNode,
-						State:        item.State,
-						Attempt:      0,
-						ParentNodeID: item.NodeID,
-						EdgeIndex:    0,
 					}
-
-					if err := e.frontier.Enqueue(workerCtx, nextItem); err != nil {
+					if err := e.saveStepCheckpoint(ctx, runID, initial, snapshot); err != nil {
 						results <- nodeResult[S]{err: err}
 						cancel()
 						return
 					}
+					run.finish(flight)
 				}() // T046: End inflight tracking func
 
 				// BUG-004 fix (T028): Check for completion after node execution completes
@@ -1315,37 +1382,230 @@ func (e *Engine[S]) runConcurrent(ctx context.Context, runID string, initial S)
 
 	for result := range results {
 		if result.err != nil {
+			// A cancelled run waits for its workers to checkpoint what they completed
+			if cancelled(ctx) != nil {
+				continue
+			}
 			return zero, result.err
 		}
+		if result.interrupt != nil {
+			paused = append(paused, *result.interrupt)
+			continue
+		}
 		collectedResults = append(collectedResults, result)
 	}
 
+	if cause := cancelled(ctx); cause != nil {
+		return zero, e.stopFrontier(ctx, runID, cause, run, initial, int(stepCounter.Load()), collectedResults, paused, barriers)
+	}
+
+	// Workers also exit when the caller cancels; a cancelled run must not be
+	// reported as a successful completion with partial results.
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
 	// Merge deltas deterministically by OrderKey (T038)
 	finalState := e.mergeDeltas(initial, collectedResults)
+	finalState, err := e.resolveConflicts(barriers.conflicts, finalState, collectedResults)
+	if err != nil {
+		return zero, err
+	}
+
+	// Paused paths keep their join arrivals waiting until Resume
+	if len(paused) > 0 {
+		return finalState, e.pauseRun(ctx, runID, int(stepCounter.Load()), finalState, paused, barriers)
+	}
+
+	// Routes that reached a join node without all of its predecessors can
+	// never make progress.
+	if err := barriers.pendingErr(); err != nil {
+		return zero, err
+	}
 
 	// Save checkpoint after merging all deltas (T049)
 	// Use the final step count for checkpoint ID
-	finalStepID := int(stepCounter.Load())
-	emptyFrontier := []WorkItem[S]{} // Frontier is empty at workflow completion
-	noRecordedIOs := []RecordedIO{}  // TODO: Track recorded IOs in later phases
-
-	if err := e.saveCheckpoint(ctx, runID, finalStepID, finalState, emptyFrontier, noRecordedIOs, ""); err != nil {
-		// Log checkpoint error but don't fail the workflow
-		// The workflow completed successfully even if checkpoint save failed
-		if e.emitter != nil {
-			e.emitter.Emit(emit.Event{
-				RunID:  runID,
-				Step:   finalStepID,
-				NodeID: "",
-				Msg:    "checkpoint_save_failed",
-				Meta: map[string]interface{}{
-					"error": err.Error(),
-				},
-			})
+	e.saveFinalCheckpoint(ctx, runID, int(stepCounter.Load()), finalState)
+
+	return finalState, nil
+}
+
+// stopFrontier ends a concurrent run stopped with Cancel once its workers
+// have stopped. The deltas of the items that completed are merged into
+// initial; the items still in flight, the queued ones, the paused paths and
+// the waiting join arrivals form the frontier passed to stopCancelled.
+// stepID is the run's step counter, which counts the steps started.
+func (e *Engine[S]) stopFrontier(ctx context.Context, runID string, cause *CancelledError, run *activeRun[S], initial S, stepID int, results []nodeResult[S], paused []pausedNode[S], barriers *joinBarriers[S]) error {
+	unfinished := run.unfinished()
+	completed := make([]nodeResult[S], 0, len(results))
+	for _, result := range results {
+		if _, ok := unfinished[result.flight]; !ok {
+			completed = append(completed, result)
 		}
 	}
 
-	return finalState, nil
+	state := e.mergeDeltas(initial, completed)
+	state, err := e.resolveConflicts(barriers.conflicts, state, completed)
+	if err != nil {
+		return err
+	}
+
+	frontier := run.frontier.drain()
+	for _, item := range unfinished {
+		item.Attempt = 0
+		frontier = append(frontier, item)
+	}
+	for _, p := range paused {
+		item := p.item
+		item.Attempt = 0
+		frontier = append(frontier, item)
+	}
+	frontier = append(frontier, barriers.arrivals()...)
+	sort.SliceStable(frontier, func(i, j int) bool {
+		return frontier[i].OrderKey < frontier[j].OrderKey
+	})
+
+	// Steps that did not complete run again from the checkpoint
+	return e.stopCancelled(ctx, runID, cause, stepID-len(unfinished), state, frontier)
+}
+
+// stepLog tracks the outstanding work of a concurrent run so that it can be
+// checkpointed after each of its steps (see checkpointsSteps).
+// Workers take and enqueue items while a step completes, so the frontier of
+// each checkpoint is derived from the items the steps consumed and produced
+// rather than read from the queue. A nil stepLog records nothing.
+type stepLog[S any] struct {
+	mu      sync.Mutex
+	step    int
+	pending []WorkItem[S]   // Items queued or executing
+	paused  []pausedNode[S] // Paths paused so far, which run again when restored
+	results []nodeResult[S] // Steps completed so far
+}
+
+// stepSnapshot is a concurrent run after one of its steps: the results
+// merged into its state and the work left.
+type stepSnapshot[S any] struct {
+	step     int
+	results  []nodeResult[S]
+	frontier []WorkItem[S]
+}
+
+// newStepLog returns the log of a run whose frontier holds items, continuing
+// from step.
+func newStepLog[S any](items []WorkItem[S], step int) *stepLog[S] {
+	return &stepLog[S]{step: step, pending: items}
+}
+
+// complete records the step of item, which ended with result, and returns
+// the successors admit returns along with a snapshot of the run after the
+// step. admit is nil for interrupted items. admit runs under the log's lock,
+// so that steps completing together reach barriers and the snapshot one
+// after the other.
+func (l *stepLog[S]) complete(item WorkItem[S], result nodeResult[S], admit func() ([]WorkItem[S], error), barriers *joinBarriers[S]) ([]WorkItem[S], *stepSnapshot[S], error) {
+	if l == nil {
+		if admit == nil {
+			return nil, nil, nil
+		}
+		ready, err := admit()
+		return ready, nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var ready []WorkItem[S]
+	if admit != nil {
+		var err error
+		if ready, err = admit(); err != nil {
+			return nil, nil, err
+		}
+	}
+	for i, pending := range l.pending {
+		if pending.NodeID == item.NodeID && pending.OrderKey == item.OrderKey {
+			l.pending = append(l.pending[:i:i], l.pending[i+1:]...)
+			break
+		}
+	}
+	l.pending = append(l.pending, ready...)
+	if result.interrupt != nil {
+		l.paused = append(l.paused, *result.interrupt)
+	} else {
+		l.results = append(l.results, result)
+	}
+	l.step++
+
+	snapshot := &stepSnapshot[S]{
+		step:     l.step,
+		results:  append([]nodeResult[S](nil), l.results...),
+		frontier: roundFrontier(l.pending, l.paused, barriers),
+	}
+	sort.SliceStable(snapshot.frontier, func(i, j int) bool {
+		return snapshot.frontier[i].OrderKey < snapshot.frontier[j].OrderKey
+	})
+	return ready, snapshot, nil
+}
+
+// saveStepCheckpoint saves the checkpoint of a concurrent run after one of
+// its steps, holding the deltas of the steps completed so far merged by
+// OrderKey. Conflicting writes are resolved when the run completes.
+// snapshot is nil when steps are not checkpointed.
+func (e *Engine[S]) saveStepCheckpoint(ctx context.Context, runID string, initial S, snapshot *stepSnapshot[S]) error {
+	if snapshot == nil {
+		return nil
+	}
+	state := e.mergeDeltas(initial, snapshot.results)
+	return e.saveCheckpoint(ctx, runID, snapshot.step, state, snapshot.frontier, runRecorder(ctx), "")
+}
+
+// newFrontier creates the frontier of a concurrent run, with capacity
+// Options.QueueDepth, ordering items by their nodes' priorities.
+func (e *Engine[S]) newFrontier(ctx context.Context, runID string) *Frontier[S] {
+	queueDepth := e.opts.QueueDepth
+	if queueDepth == 0 {
+		queueDepth = 1024 // Default queue depth
+	}
+	frontier := NewFrontier[S](ctx, queueDepth, runID, e.opts.Metrics, e.emitter)
+	frontier.heap.aging = e.opts.PriorityAging
+	frontier.priority = e.itemPriority
+	return frontier
+}
+
+// itemPriority returns the scheduling priority of a work item: its node's
+// NodePolicy.PriorityFunc applied to the item's state, or else its
+// NodePolicy.Priority.
+func (e *Engine[S]) itemPriority(item WorkItem[S]) int {
+	e.mu.RLock()
+	nodeImpl := e.nodes[item.NodeID]
+	e.mu.RUnlock()
+	policy := nodePolicy(nodeImpl)
+	if policy == nil {
+		return 0
+	}
+	if policy.PriorityFunc != nil {
+		return policy.PriorityFunc(item.State)
+	}
+	return policy.Priority
+}
+
+// saveFinalCheckpoint saves the checkpoint of a completed run, holding its
+// final state, an empty frontier and the I/O recorded during the run.
+//
+// A failed save does not fail the run, which has already completed; a
+// checkpoint_save_failed event is emitted instead.
+func (e *Engine[S]) saveFinalCheckpoint(ctx context.Context, runID string, stepID int, state S) {
+	emptyFrontier := []WorkItem[S]{} // Frontier is empty at workflow completion
+
+	if err := e.saveCheckpoint(ctx, runID, stepID, state, emptyFrontier, runRecorder(ctx), ""); err != nil {
+		e.publish(emit.Event{
+			RunID:  runID,
+			Step:   stepID,
+			NodeID: "",
+			Msg:    "checkpoint_save_failed",
+			Meta: map[string]interface{}{
+				"error": err.Error(),
+			},
+		})
+	}
 }
 
 // mergeDeltas merges collected node deltas into final state using deterministic ordering (T038).
@@ -1372,113 +1632,22 @@ func (e *Engine[S]) mergeDeltas(initial S, results []nodeResult[S]) S {
 	return finalState
 }
 
-// executeParallel executes multiple branches in parallel with isolated state copies (T104-T108).
-//
-// Each branch:
-//  1. Receives a deep copy of the current state (T104)
-//  2. Executes in its own goroutine (T106)
-//  3. Returns its delta state update
-//
-// After all branches complete:
-//  1. Deltas are merged deterministically using the reducer (T109-T110)
-//  2. Merge order is lexicographic by nodeID for determinism (T111-T112)
-//  3. Errors from any branch are collected (T113-T114)
-//
-// Uses sync.WaitGroup for coordination (T108).
-func (e *Engine[S]) executeParallel(ctx context.Context, branches []string, state S) (S, error) {
-	var zero S
-
-	type branchResult struct {
-		nodeID string
-		delta  S
-		err    error
-	}
-
-	// Channel for collecting results from all branches
-	results := make(chan branchResult, len(branches))
-
-	// WaitGroup for synchronization (T108)
-	var wg sync.WaitGroup
-
-	// Launch goroutines for each branch (T106)
-	for _, branchID := range branches {
-		wg.Add(1)
-
-		go func(nodeID string) {
-			defer wg.Done()
-
-			// Deep copy state for isolation (T104)
-			branchState, err := deepCopy(state)
-			if err != nil {
-				results <- branchResult{nodeID: nodeID, err: err}
-				return
-			}
-
-			// Execute the branch node
-			e.mu.RLock()
-			node, exists := e.nodes[nodeID]
-			e.mu.RUnlock()
-
-			if !exists {
-				results <- branchResult{
-					nodeID: nodeID,
-					err: &EngineError{
-						Message: "parallel branch node not found: " + nodeID,
-						Code:    "NODE_NOT_FOUND",
-					},
-				}
-				return
-			}
-
-			// Execute node with isolated state copy
-			result := node.Run(ctx, branchState)
-
-			if result.Err != nil {
-				results <- branchResult{nodeID: nodeID, err: result.Err}
-				return
-			}
-
-			// Return the delta from this branch
-			results <- branchResult{nodeID: nodeID, delta: result.Delta}
-		}(branchID)
-	}
-
-	// Wait for all branches to complete
-	wg.Wait()
-	close(results)
-
-	// Collect all results
-	branchResults := make([]branchResult, 0, len(branches))
-	for result := range results {
-		branchResults = append(branchResults, result)
-	}
-
-	// Check for errors (T113-T114)
-	var errors []error
-	for _, result := range branchResults {
-		if result.err != nil {
-			errors = append(errors, result.err)
-		}
+// resolveConflicts observes the collected deltas in step order and applies the
+// engine's ConflictPolicy to conflicting writes of concurrent branches.
+// conflicts is nil when conflicts are not detected.
+func (e *Engine[S]) resolveConflicts(conflicts *conflictTracker[S], state S, results []nodeResult[S]) (S, error) {
+	if conflicts == nil {
+		return state, nil
 	}
 
-	if len(errors) > 0 {
-		// Return first error (could aggregate in future - T115-T116)
-		return zero, errors[0]
-	}
-
-	// Sort results by nodeID for deterministic merge order (T111-T112)
-	// Lexicographic ordering ensures consistent results regardless of goroutine completion order
-	sort.Slice(branchResults, func(i, j int) bool {
-		return branchResults[i].nodeID < branchResults[j].nodeID
+	ordered := append([]nodeResult[S](nil), results...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].step < ordered[j].step
 	})
-
-	// Merge all branch deltas into final state using reducer (T109-T110)
-	finalState := state
-	for _, result := range branchResults {
-		finalState = e.reducer(finalState, result.delta)
+	for _, result := range ordered {
+		conflicts.observe(result.slots, result.step, result.delta)
 	}
-
-	return finalState, nil
+	return conflicts.resolve(state)
 }
 
 // SaveCheckpoint creates a named checkpoint for the most recent state of a run.
@@ -1533,17 +1702,15 @@ func (e *Engine[S]) SaveCheckpoint(ctx context.Context, runID string, cpID strin
 	}
 
 	// Emit checkpoint event
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   latestStep,
-			NodeID: "",
-			Msg:    "checkpoint saved: " + cpID,
-			Meta: map[string]interface{}{
-				"checkpoint_id": cpID,
-			},
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   latestStep,
+		NodeID: "",
+		Msg:    "checkpoint saved: " + cpID,
+		Meta: map[string]interface{}{
+			"checkpoint_id": cpID,
+		},
+	})
 
 	return nil
 }
@@ -1594,18 +1761,16 @@ func (e *Engine[S]) ResumeFromCheckpoint(ctx context.Context, cpID string, newRu
 	}
 
 	// Emit resume event
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  newRunID,
-			Step:   0,
-			NodeID: startNode,
-			Msg:    "resuming from checkpoint: " + cpID,
-			Meta: map[string]interface{}{
-				"checkpoint_id":   cpID,
-				"checkpoint_step": checkpointStep,
-			},
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  newRunID,
+		Step:   0,
+		NodeID: startNode,
+		Msg:    "resuming from checkpoint: " + cpID,
+		Meta: map[string]interface{}{
+			"checkpoint_id":   cpID,
+			"checkpoint_step": checkpointStep,
+		},
+	})
 
 	// Validate configuration (same as Run)
 	if e.reducer == nil {
@@ -1639,163 +1804,62 @@ func (e *Engine[S]) ResumeFromCheckpoint(ctx context.Context, cpID string, newRu
 		}
 	}
 
-	// Initialize execution state with checkpoint state
-	currentState := checkpointState
-	currentNode := startNode
-	step := 0
-
-	// Execution loop (same as Run but starting from checkpoint state)
-	for {
-		step++
-
-		// Check MaxSteps limit
-		if e.opts.MaxSteps > 0 && step > e.opts.MaxSteps {
-			return zero, &EngineError{
-				Message: "workflow exceeded MaxSteps limit",
-				Code:    "MAX_STEPS_EXCEEDED",
-			}
-		}
-
-		// Check context cancellation
-		select {
-		case <-ctx.Done():
-			return zero, ctx.Err()
-		default:
-		}
-
-		// Get current node implementation
-		e.mu.RLock()
-		nodeImpl, exists := e.nodes[currentNode]
-		e.mu.RUnlock()
-
-		if !exists {
-			return zero, &EngineError{
-				Message: "node not found during execution: " + currentNode,
-				Code:    "NODE_NOT_FOUND",
-			}
-		}
-
-		// Emit node_start event (T153)
-		e.emitNodeStart(newRunID, currentNode, step-1) // step is incremented at start of loop, but events use 0-based indexing
-
-		// Execute node
-		result := nodeImpl.Run(ctx, currentState)
-
-		// Handle node error (T159)
-		if result.Err != nil {
-			e.emitError(newRunID, currentNode, step-1, result.Err)
-			return zero, result.Err
-		}
-
-		// Merge state update
-		currentState = e.reducer(currentState, result.Delta)
-
-		// Persist state after node execution
-		if err := e.store.SaveStep(ctx, newRunID, step, currentNode, currentState); err != nil {
-			return zero, &EngineError{
-				Message: "failed to save step: " + err.Error(),
-				Code:    "STORE_ERROR",
-			}
-		}
-
-		// Emit node_end event with delta (T155)
-		e.emitNodeEnd(newRunID, currentNode, step-1, result.Delta)
-
-		// Determine next node from routing decision
-		if result.Route.Terminal {
-			// Emit routing_decision event for Stop (T157)
-			e.emitRoutingDecision(newRunID, currentNode, step-1, map[string]interface{}{
-				"terminal": true,
-			})
-			// Workflow complete
-			return currentState, nil
-		}
-
-		if result.Route.To != "" {
-			// Emit routing_decision event for Goto (T157)
-			e.emitRoutingDecision(newRunID, currentNode, step-1, map[string]interface{}{
-				"next_node": result.Route.To,
-			})
-
-			// Single next node (Goto)
-			currentNode = result.Route.To
-			continue
-		}
-
-		// If no explicit route, fall back to edge-based routing
-		nextNode := e.evaluateEdges(currentNode, currentState)
-		if nextNode == "" {
-			// No matching edge found - workflow cannot continue
-			return zero, &EngineError{
-				Message: "no valid route from node: " + currentNode,
-				Code:    "NO_ROUTE",
-			}
-		}
-
-		// Emit routing_decision event for edge-based routing (T157)
-		e.emitRoutingDecision(newRunID, currentNode, step-1, map[string]interface{}{
-			"next_node": nextNode,
-			"via_edge":  true,
-		})
-
-		currentNode = nextNode
-		continue
+	// Register the run so it can be inspected and cancelled while it executes
+	ctx, run, unregister, err := e.register(ctx, newRunID, 0, nil)
+	if err != nil {
+		return zero, err
 	}
+	defer unregister()
+
+	// Execute from the checkpoint state (same as Run's sequential path)
+	return run.settle(e.runSequential(ctx, run, checkpointState, []WorkItem[S]{entryItem(startNode, checkpointState)}, 0, e.newJoinBarriers(newRunID)))
 }
 
 // emitNodeStart emits a node_start event if emitter is configured (T153).
 func (e *Engine[S]) emitNodeStart(runID, nodeID string, step int) {
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   step,
-			NodeID: nodeID,
-			Msg:    "node_start",
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   step,
+		NodeID: nodeID,
+		Msg:    "node_start",
+	})
 }
 
 // emitNodeEnd emits a node_end event with delta metadata if emitter is configured (T155).
 func (e *Engine[S]) emitNodeEnd(runID, nodeID string, step int, delta S) {
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   step,
-			NodeID: nodeID,
-			Msg:    "node_end",
-			Meta: map[string]interface{}{
-				"delta": delta,
-			},
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   step,
+		NodeID: nodeID,
+		Msg:    "node_end",
+		Meta: map[string]interface{}{
+			"delta": delta,
+		},
+	})
 }
 
 // emitError emits an error event if emitter is configured (T159).
 func (e *Engine[S]) emitError(runID, nodeID string, step int, err error) {
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   step,
-			NodeID: nodeID,
-			Msg:    "error",
-			Meta: map[string]interface{}{
-				"error": err.Error(),
-			},
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   step,
+		NodeID: nodeID,
+		Msg:    "error",
+		Meta: map[string]interface{}{
+			"error": err.Error(),
+		},
+	})
 }
 
 // emitRoutingDecision emits a routing_decision event if emitter is configured (T157).
 func (e *Engine[S]) emitRoutingDecision(runID, nodeID string, step int, meta map[string]interface{}) {
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   step,
-			NodeID: nodeID,
-			Msg:    "routing_decision",
-			Meta:   meta,
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   step,
+		NodeID: nodeID,
+		Msg:    "routing_decision",
+		Meta:   meta,
+	})
 }
 
 // saveCheckpoint atomically commits a checkpoint to the store with idempotency protection (T048).
@@ -1817,7 +1881,7 @@ func (e *Engine[S]) emitRoutingDecision(runID, nodeID string, step int, meta map
 //   - stepID: Sequential step number (monotonically increasing)
 //   - state: Current accumulated state after applying all deltas
 //   - frontier: Work items ready to execute at this checkpoint
-//   - recordedIOs: External I/O operations captured for replay
+//   - rec: Recorder of the run, whose recordings not yet saved are stored in this checkpoint (nil for none)
 //   - label: Optional user-defined label (empty string for automatic checkpoints)
 //
 // Returns error if:
@@ -1830,7 +1894,7 @@ func (e *Engine[S]) emitRoutingDecision(runID, nodeID string, step int, meta map
 // was not committed and the step should be retried.
 //
 // Thread-safety: This method is safe for concurrent use by multiple goroutines.
-func (e *Engine[S]) saveCheckpoint(ctx context.Context, runID string, stepID int, state S, frontier []WorkItem[S], recordedIOs []RecordedIO, label string) error {
+func (e *Engine[S]) saveCheckpoint(ctx context.Context, runID string, stepID int, state S, frontier []WorkItem[S], rec *ioRecorder, label string) error {
 	// Compute idempotency key from execution context (T046)
 	idempotencyKey, err := computeIdempotencyKey(runID, stepID, frontier, state)
 	if err != nil {
@@ -1839,6 +1903,11 @@ func (e *Engine[S]) saveCheckpoint(ctx context.Context, runID string, stepID int
 			Code:    "IDEMPOTENCY_KEY_ERROR",
 		}
 	}
+	if label != "" {
+		// A labeled checkpoint may repeat the unlabeled one of its step, which
+		// it must replace rather than be skipped as a duplicate
+		idempotencyKey += ":" + label
+	}
 
 	// Check if this checkpoint was already committed (idempotency check)
 	exists, err := e.store.CheckIdempotency(ctx, idempotencyKey)
@@ -1855,6 +1924,9 @@ func (e *Engine[S]) saveCheckpoint(ctx context.Context, runID string, stepID int
 		return nil
 	}
 
+	// Each recording is stored once, in the first checkpoint saved after it
+	recordedIOs, recordedEnd := rec.unsaved()
+
 	// Create checkpoint struct (T047 - relies on automatic JSON marshaling)
 	// Use store.CheckpointV2 type which contains full execution context
 	checkpoint := store.CheckpointV2[S]{
@@ -1884,22 +1956,21 @@ func (e *Engine[S]) saveCheckpoint(ctx context.Context, runID string, stepID int
 			Code:    "CHECKPOINT_SAVE_FAILED",
 		}
 	}
+	rec.markSaved(recordedEnd)
 
 	// Emit checkpoint event for observability
-	if e.emitter != nil {
-		e.emitter.Emit(emit.Event{
-			RunID:  runID,
-			Step:   stepID,
-			NodeID: "",
-			Msg:    "checkpoint_saved",
-			Meta: map[string]interface{}{
-				"idempotency_key": idempotencyKey,
-				"frontier_size":   len(frontier),
-				"recorded_ios":    len(recordedIOs),
-				"label":           label,
-			},
-		})
-	}
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   stepID,
+		NodeID: "",
+		Msg:    "checkpoint_saved",
+		Meta: map[string]interface{}{
+			"idempotency_key": idempotencyKey,
+			"frontier_size":   len(frontier),
+			"recorded_ios":    len(recordedIOs),
+			"label":           label,
+		},
+	})
 
 	return nil
 }
@@ -1984,24 +2055,18 @@ func (e *Engine[S]) RunWithCheckpoint(ctx context.Context, checkpoint store.Chec
 	ctx = context.WithValue(ctx, RNGKey, rng)
 
 	// Restore frontier from checkpoint
-	// The Frontier field is stored as interface{} to avoid circular dependency
-	// Convert it back to []WorkItem[S]
-	var frontierItems []WorkItem[S]
-	if checkpoint.Frontier != nil {
-		// Marshal and unmarshal to convert from interface{} to []WorkItem[S]
-		frontierJSON, err := json.Marshal(checkpoint.Frontier)
+	frontierItems, err := decodeFrontier[S](checkpoint.Frontier)
+	if err != nil {
+		return zero, err
+	}
+
+	// Replay serves Recorded calls from the I/O recorded after the checkpoint
+	if e.opts.ReplayMode && ctx.Value(RecordedIOsKey) == nil {
+		recorded, err := e.loadRecordedIOs(ctx, checkpoint.RunID, checkpoint.StepID)
 		if err != nil {
-			return zero, &EngineError{
-				Message: "failed to marshal checkpoint frontier: " + err.Error(),
-				Code:    "CHECKPOINT_RESTORE_ERROR",
-			}
-		}
-		if err := json.Unmarshal(frontierJSON, &frontierItems); err != nil {
-			return zero, &EngineError{
-				Message: "failed to unmarshal checkpoint frontier: " + err.Error(),
-				Code:    "CHECKPOINT_RESTORE_ERROR",
-			}
+			return zero, err
 		}
+		ctx = context.WithValue(ctx, RecordedIOsKey, recorded)
 	}
 
 	// If frontier is empty, workflow was already complete at checkpoint
@@ -2010,361 +2075,104 @@ func (e *Engine[S]) RunWithCheckpoint(ctx context.Context, checkpoint store.Chec
 		return checkpoint.State, nil
 	}
 
-	// Check if concurrent execution is enabled
-	if e.opts.MaxConcurrentNodes > 0 {
-		// Initialize Frontier for concurrent execution
-		queueDepth := e.opts.QueueDepth
-		if queueDepth == 0 {
-			queueDepth = 1024 // Default queue depth
-		}
-		e.frontier = NewFrontier[S](ctx, queueDepth, checkpoint.RunID, e.opts.Metrics, e.emitter)
-
-		// Enqueue all work items from checkpoint
-		for _, item := range frontierItems {
-			if err := e.frontier.Enqueue(ctx, item); err != nil {
-				return zero, &EngineError{
-					Message: "failed to enqueue checkpoint work item: " + err.Error(),
-					Code:    "CHECKPOINT_RESTORE_ERROR",
-				}
-			}
-		}
-
-		// Use concurrent execution path
-		// The checkpoint state is already in the work items, so we pass it as initial
-		return e.runConcurrentFromCheckpoint(ctx, checkpoint.RunID, checkpoint.State, checkpoint.StepID)
-	}
+	return e.continueRun(ctx, checkpoint.RunID, checkpoint.State, frontierItems, checkpoint.StepID, nil)
+}
 
-	// Sequential execution from checkpoint
-	// For sequential mode, process first work item from frontier
-	if len(frontierItems) == 0 {
-		return checkpoint.State, nil
+// decodeFrontier converts a checkpoint frontier back into work items.
+// The Frontier field is stored as interface{} to avoid a circular dependency,
+// so it is round-tripped through JSON into []WorkItem[S].
+func decodeFrontier[S any](frontier interface{}) ([]WorkItem[S], error) {
+	var items []WorkItem[S]
+	if frontier == nil {
+		return items, nil
 	}
 
-	// Take first work item as current node
-	firstItem := frontierItems[0]
-	currentState := checkpoint.State
-	currentNode := firstItem.NodeID
-	step := checkpoint.StepID
-
-	// Execution loop (same as Run)
-	for {
-		step++
-
-		// Check MaxSteps limit
-		if e.opts.MaxSteps > 0 && step > e.opts.MaxSteps {
-			return zero, &EngineError{
-				Message: "workflow exceeded MaxSteps limit",
-				Code:    "MAX_STEPS_EXCEEDED",
-			}
-		}
-
-		// Check context cancellation
-		select {
-		case <-ctx.Done():
-			return zero, ctx.Err()
-		default:
-		}
-
-		// Get current node implementation
-		e.mu.RLock()
-		nodeImpl, exists := e.nodes[currentNode]
-		e.mu.RUnlock()
-
-		if !exists {
-			return zero, &EngineError{
-				Message: "node not found during execution: " + currentNode,
-				Code:    "NODE_NOT_FOUND",
-			}
-		}
-
-		// Emit node_start event
-		e.emitNodeStart(checkpoint.RunID, currentNode, step-1)
-
-		// Execute node
-		result := nodeImpl.Run(ctx, currentState)
-
-		// Handle node error
-		if result.Err != nil {
-			e.emitError(checkpoint.RunID, currentNode, step-1, result.Err)
-			return zero, result.Err
-		}
-
-		// Merge state update
-		currentState = e.reducer(currentState, result.Delta)
-
-		// Persist state after node execution
-		if err := e.store.SaveStep(ctx, checkpoint.RunID, step, currentNode, currentState); err != nil {
-			return zero, &EngineError{
-				Message: "failed to save step: " + err.Error(),
-				Code:    "STORE_ERROR",
-			}
-		}
-
-		// Emit node_end event with delta
-		e.emitNodeEnd(checkpoint.RunID, currentNode, step-1, result.Delta)
-
-		// Determine next node from routing decision
-		if result.Route.Terminal {
-			e.emitRoutingDecision(checkpoint.RunID, currentNode, step-1, map[string]interface{}{
-				"terminal": true,
-			})
-			return currentState, nil
-		}
-
-		if result.Route.To != "" {
-			e.emitRoutingDecision(checkpoint.RunID, currentNode, step-1, map[string]interface{}{
-				"next_node": result.Route.To,
-			})
-			currentNode = result.Route.To
-			continue
+	frontierJSON, err := json.Marshal(frontier)
+	if err != nil {
+		return nil, &EngineError{
+			Message: "failed to marshal checkpoint frontier: " + err.Error(),
+			Code:    "CHECKPOINT_RESTORE_ERROR",
 		}
-
-		// Edge-based routing fallback
-		nextNode := e.evaluateEdges(currentNode, currentState)
-		if nextNode == "" {
-			return zero, &EngineError{
-				Message: "no valid route from node: " + currentNode,
-				Code:    "NO_ROUTE",
-			}
+	}
+	if err := json.Unmarshal(frontierJSON, &items); err != nil {
+		return nil, &EngineError{
+			Message: "failed to unmarshal checkpoint frontier: " + err.Error(),
+			Code:    "CHECKPOINT_RESTORE_ERROR",
 		}
-
-		e.emitRoutingDecision(checkpoint.RunID, currentNode, step-1, map[string]interface{}{
-			"next_node": nextNode,
-			"via_edge":  true,
-		})
-
-		currentNode = nextNode
-		continue
 	}
+	return items, nil
 }
 
-// runConcurrentFromCheckpoint resumes concurrent execution from a checkpoint.
-// This is a helper method used by RunWithCheckpoint for concurrent execution mode.
-func (e *Engine[S]) runConcurrentFromCheckpoint(ctx context.Context, runID string, initialState S, startStepID int) (S, error) {
+// continueRun executes a run from restored frontier items in the engine's
+// execution mode. Items waiting at a join barrier are re-registered, and the
+// remaining items are executed starting after step stepID. claim is passed
+// to register.
+func (e *Engine[S]) continueRun(ctx context.Context, runID string, state S, items []WorkItem[S], stepID int, claim func(context.Context) error) (S, error) {
 	var zero S
 
-	// Result channel for collecting node execution outcomes
-	results := make(chan nodeResult[S], e.opts.MaxConcurrentNodes)
-
-	// WaitGroup tracks active workers
-	var wg sync.WaitGroup
-
-	// Track step counter starting from checkpoint
-	var stepCounter atomic.Int32
-	stepCounter.Store(int32(startStepID)) // #nosec G115 -- startStepID is bounded by MaxSteps (default 100)
-	collectedResults := make([]nodeResult[S], 0, e.opts.MaxSteps)
-
-	// Spawn worker goroutines
-	maxWorkers := e.opts.MaxConcurrentNodes
-	if maxWorkers == 0 {
-		maxWorkers = 8
+	barriers := e.newJoinBarriers(runID)
+	runnable, err := barriers.restore(items)
+	if err != nil {
+		return zero, err
+	}
+	if len(runnable) == 0 {
+		// Only join arrivals remain; they can never be released
+		if err := barriers.pendingErr(); err != nil {
+			return zero, err
+		}
+		return state, nil
 	}
 
-	workerCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	for i := 0; i < maxWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-
-			for {
-				// Dequeue next work item
-				item, err := e.frontier.Dequeue(ctx)
-				if err != nil {
-					return
-				}
-
-				currentStep := stepCounter.Add(1)
-
-				// Check MaxSteps limit
-				if e.opts.MaxSteps > 0 && int(currentStep) > e.opts.MaxSteps {
-					results <- nodeResult[S]{
-						err: &EngineError{
-							Message: "workflow exceeded MaxSteps limit",
-							Code:    "MAX_STEPS_EXCEEDED",
-						},
-					}
-					cancel()
-					return
-				}
-
-				// Get node implementation
-				e.mu.RLock()
-				nodeImpl, exists := e.nodes[item.NodeID]
-				e.mu.RUnlock()
-
-				if !exists {
-					results <- nodeResult[S]{
-						err: &EngineError{
-							Message: "node not found during execution: " + item.NodeID,
-							Code:    "NODE_NOT_FOUND",
-						},
-					}
-					cancel()
-					return
-				}
-
-				// Emit node_start event
-				e.emitNodeStart(runID, item.NodeID, item.StepID)
-
-				// Execute node
-				result := nodeImpl.Run(workerCtx, item.State)
-
-				// Handle node error
-				if result.Err != nil {
-					e.emitError(runID, item.NodeID, item.StepID, result.Err)
-					results <- nodeResult[S]{err: result.Err}
-					cancel()
-					return
-				}
-
-				// Emit node_end event
-				e.emitNodeEnd(runID, item.NodeID, item.StepID, result.Delta)
-
-				// Send result to collection channel
-				results <- nodeResult[S]{
-					nodeID:   item.NodeID,
-					delta:    result.Delta,
-					route:    result.Route,
-					orderKey: item.OrderKey,
-					err:      nil,
-				}
-
-				// Handle routing
-				if result.Route.Terminal {
-					e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-						"terminal": true,
-					})
-					return
-				}
-
-				// Fan-out routing
-				if len(result.Route.Many) > 0 {
-					e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-						"parallel": true,
-						"branches": result.Route.Many,
-					})
-
-					for edgeIdx, branchID := range result.Route.Many {
-						branchState, err := deepCopyState(item.State)
-						if err != nil {
-							results <- nodeResult[S]{err: err}
-							cancel()
-							return
-						}
-
-						branchItem := WorkItem[S]{
-							StepID:       item.StepID + 1,
-							OrderKey:     computeOrderKey(item.NodeID, edgeIdx),
-							NodeID:       branchID,
-							State:        branchState,
-							Attempt:      0,
-							ParentNodeID: item.NodeID,
-							EdgeIndex:    edgeIdx,
-						}
-
-						if err := e.frontier.Enqueue(workerCtx, branchItem); err != nil {
-							results <- nodeResult[S]{err: err}
-							cancel()
-							return
-						}
-					}
-					return
-				}
-
-				// Single next node
-				if result.Route.To != "" {
-					e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-						"next_node": result.Route.To,
-					})
-
-					nextItem := WorkItem[S]{
-						StepID:       item.StepID + 1,
-						OrderKey:     computeOrderKey(item.NodeID, 0),
-						NodeID:       result.Route.To,
-						State:        item.State,
-						Attempt:      0,
-						ParentNodeID: item.NodeID,
-						EdgeIndex:    0,
-					}
-
-					if err := e.frontier.Enqueue(workerCtx, nextItem); err != nil {
-						results <- nodeResult[S]{err: err}
-						cancel()
-						return
-					}
-					continue
-				}
+	// Register the run so it can be inspected and cancelled while it executes
+	ctx, run, unregister, err := e.register(ctx, runID, stepID, claim)
+	if err != nil {
+		return zero, err
+	}
+	defer unregister()
+	run.restored = true
 
-				// Edge-based routing
-				nextNode := e.evaluateEdges(item.NodeID, item.State)
-				if nextNode == "" {
-					results <- nodeResult[S]{
-						err: &EngineError{
-							Message: "no valid route from node: " + item.NodeID,
-							Code:    "NO_ROUTE",
-						},
-					}
-					cancel()
-					return
-				}
+	// Check if concurrent execution is enabled
+	if e.opts.MaxConcurrentNodes > 0 {
+		// Initialize Frontier for concurrent execution
+		run.frontier = e.newFrontier(ctx, runID)
 
-				e.emitRoutingDecision(runID, item.NodeID, item.StepID, map[string]interface{}{
-					"next_node": nextNode,
-					"via_edge":  true,
+		// Enqueue all restored work items
+		for _, item := range runnable {
+			if err := run.frontier.Enqueue(ctx, item); err != nil {
+				return run.settle(zero, &EngineError{
+					Message: "failed to enqueue checkpoint work item: " + err.Error(),
+					Code:    "CHECKPOINT_RESTORE_ERROR",
 				})
-
-				nextItem := WorkItem[S]{
-					StepID:       item.StepID + 1,
-					OrderKey:     computeOrderKey(item.NodeID, 0),
-					NodeID:       nextNode,
-					State:        item.State,
-					Attempt:      0,
-					ParentNodeID: item.NodeID,
-					EdgeIndex:    0,
-				}
-
-				if err := e.frontier.Enqueue(workerCtx, nextItem); err != nil {
-					results <- nodeResult[S]{err: err}
-					cancel()
-					return
-				}
 			}
-		}()
-	}
-
-	// Wait for workers to complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect results from workers
-	for result := range results {
-		if result.err != nil {
-			return zero, result.err
 		}
-		collectedResults = append(collectedResults, result)
-	}
 
-	// Merge deltas deterministically
-	finalState := e.mergeDeltas(initialState, collectedResults)
+		// The checkpoint state is the base the new deltas are merged into
+		return run.settle(e.runFrontier(ctx, run, state, stepID, barriers))
+	}
 
-	return finalState, nil
+	// Sequential execution: every restored item forms the first round,
+	// exactly as if the run had never stopped.
+	return run.settle(e.runSequential(ctx, run, state, runnable, stepID, barriers))
 }
 
 // ReplayRun replays a previous execution using recorded I/O without re-invoking external services.
 //
 // This method enables exact replay of executions for debugging, auditing, or testing.
-// It loads the latest checkpoint for the given runID and replays the execution using
-// recorded I/O responses instead of making live external calls.
+// It loads the checkpoints of the given runID and re-executes the run from the
+// earliest one, serving Recorded calls from the I/O recorded in the later
+// checkpoints instead of making live external calls.
 //
 // The replay process:
-//  1. Loads the latest checkpoint containing recorded I/O and state
+//  1. Loads the run's checkpoints and the I/O each of them recorded
 //  2. Configures engine in replay mode (Options.ReplayMode=true)
-//  3. Executes nodes using recorded responses instead of live calls
-//  4. Verifies execution matches original via hash comparison (if StrictReplay=true)
+//  3. Executes nodes from the earliest checkpoint using recorded responses instead of live calls
+//  4. Fails on unrecorded calls and corrupt recordings (if StrictReplay=true)
+//
+// The earliest checkpoint is the start of the run, which is checkpointed at
+// step 0 when the engine has nodes with Recordable effects or
+// Options.CheckpointEveryStep is set, or the checkpoint a fork starts from.
+// A run whose earliest checkpoint has no pending work cannot be re-executed
+// and fails with code NOT_REPLAYABLE.
 //
 // Parameters:
 //   - ctx: Context for cancellation and request-scoped values
@@ -2398,7 +2206,6 @@ func (e *Engine[S]) runConcurrentFromCheckpoint(ctx context.Context, runID strin
 //
 // Requirements:
 //   - Original run must have been executed with recordable nodes
-//   - Checkpoint must contain recorded I/O (RecordedIOs field populated)
 //   - Engine must be configured with ReplayMode=true
 //
 // Thread-safety: This method is safe for concurrent use with different runIDs.
@@ -2413,67 +2220,117 @@ func (e *Engine[S]) ReplayRun(ctx context.Context, runID string) (S, error) {
 		}
 	}
 
-	// Load latest checkpoint for the run
-	// Find the highest step ID by loading incrementally
-	// Start with step 0 and increment until we find the latest
-	var latestCheckpoint store.CheckpointV2[S]
-	latestStep := -1
+	checkpoints, err := e.runCheckpoints(ctx, runID)
+	if err != nil {
+		return zero, err
+	}
+	start := checkpoints[0]
+	items, err := decodeFrontier[S](start.Frontier)
+	if err != nil {
+		return zero, err
+	}
+	if len(items) == 0 {
+		return zero, &EngineError{
+			Message: "earliest checkpoint of run " + runID + " has no work to re-execute",
+			Code:    "NOT_REPLAYABLE",
+		}
+	}
+
+	// Re-execute from the start, serving Recorded calls from the I/O recorded
+	// after it
+	recorded, err := collectRecordedIOs(checkpoints, start.StepID)
+	if err != nil {
+		return zero, err
+	}
+	ctx = context.WithValue(ctx, RecordedIOsKey, recorded)
+	return e.RunWithCheckpoint(ctx, start)
+}
+
+// latestCheckpoint loads the CheckpointV2 with the highest step ID of a run.
+func (e *Engine[S]) latestCheckpoint(ctx context.Context, runID string) (store.CheckpointV2[S], error) {
+	checkpoints, err := e.runCheckpoints(ctx, runID)
+	if err != nil {
+		return store.CheckpointV2[S]{}, err
+	}
+	return checkpoints[len(checkpoints)-1], nil
+}
+
+// runCheckpoints loads the CheckpointV2 of a run, ordered by step ID.
+//
+// Stores implementing store.HistoryStore list the run's checkpoints. For
+// other stores every step up to MaxSteps (or 1000 when unlimited) is probed,
+// since checkpoints are saved at arbitrary steps.
+func (e *Engine[S]) runCheckpoints(ctx context.Context, runID string) ([]store.CheckpointV2[S], error) {
+	var checkpoints []store.CheckpointV2[S]
 
-	// Try loading checkpoints from step 0 upwards
-	// In production, stores should implement a LoadLatestCheckpointV2 method
-	// For now, we'll try loading steps sequentially (this is inefficient but correct)
-	for step := 0; step < 1000; step++ { // Arbitrary upper limit
-		checkpoint, err := e.store.LoadCheckpointV2(ctx, runID, step)
+	if history, ok := e.store.(store.HistoryStore[S]); ok {
+		listed, err := history.ListCheckpoints(ctx, runID, "")
 		if err != nil {
+			return nil, &EngineError{
+				Message: "failed to list checkpoints: " + err.Error(),
+				Code:    "CHECKPOINT_LOAD_ERROR",
+			}
+		}
+		checkpoints = listed
+	} else {
+		maxStep := e.opts.MaxSteps
+		if maxStep <= 0 {
+			maxStep = 1000
+		}
+		for step := 0; step <= maxStep; step++ {
+			checkpoint, err := e.store.LoadCheckpointV2(ctx, runID, step)
 			if errors.Is(err, store.ErrNotFound) {
-				// No more checkpoints
-				break
+				continue
 			}
-			return zero, &EngineError{
-				Message: "failed to load checkpoint: " + err.Error(),
-				Code:    "CHECKPOINT_LOAD_ERROR",
+			if err != nil {
+				return nil, &EngineError{
+					Message: "failed to load checkpoint: " + err.Error(),
+					Code:    "CHECKPOINT_LOAD_ERROR",
+				}
 			}
+			checkpoints = append(checkpoints, checkpoint)
 		}
-		latestCheckpoint = checkpoint
-		latestStep = step
 	}
 
-	// Verify we found at least one checkpoint
-	if latestStep == -1 {
-		return zero, &EngineError{
+	if len(checkpoints) == 0 {
+		return nil, &EngineError{
 			Message: "no checkpoints found for runID: " + runID,
 			Code:    "NO_CHECKPOINTS",
 		}
 	}
+	return checkpoints, nil
+}
+
+// loadRecordedIOs loads the I/O recorded by a previous execution of runID
+// after step afterStep, collected from the checkpoints that stored it in the
+// order they were saved. A run without checkpoints has no recordings.
+func (e *Engine[S]) loadRecordedIOs(ctx context.Context, runID string, afterStep int) ([]RecordedIO, error) {
+	checkpoints, err := e.runCheckpoints(ctx, runID)
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) && engineErr.Code == "NO_CHECKPOINTS" {
+		return []RecordedIO{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return collectRecordedIOs(checkpoints, afterStep)
+}
 
-	// Verify checkpoint has recorded I/O
-	// Convert interface{} to []RecordedIO
-	var recordedIOs []RecordedIO
-	if latestCheckpoint.RecordedIOs != nil {
-		recordedIOsJSON, err := json.Marshal(latestCheckpoint.RecordedIOs)
-		if err != nil {
-			return zero, &EngineError{
-				Message: "failed to marshal recorded I/O: " + err.Error(),
-				Code:    "CHECKPOINT_FORMAT_ERROR",
-			}
+// collectRecordedIOs gathers the recordings stored in the checkpoints after
+// step afterStep, in step order.
+func collectRecordedIOs[S any](checkpoints []store.CheckpointV2[S], afterStep int) ([]RecordedIO, error) {
+	recorded := []RecordedIO{}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.StepID <= afterStep {
+			continue
 		}
-		if err := json.Unmarshal(recordedIOsJSON, &recordedIOs); err != nil {
-			return zero, &EngineError{
-				Message: "failed to unmarshal recorded I/O: " + err.Error(),
-				Code:    "CHECKPOINT_FORMAT_ERROR",
-			}
+		ios, err := decodeRecordedIOs(checkpoint.RecordedIOs)
+		if err != nil {
+			return nil, err
 		}
+		recorded = append(recorded, ios...)
 	}
-
-	// Store recorded I/O in context for nodes to access during replay
-	// This is a placeholder - in real implementation, nodes would check for recorded I/O
-	// and use it instead of making live calls
-	ctx = context.WithValue(ctx, RecordedIOsKey, recordedIOs)
-
-	// Resume execution from checkpoint
-	// The replay logic is handled in RunWithCheckpoint, which will use recorded I/O
-	// when nodes check for replay mode
-	return e.RunWithCheckpoint(ctx, latestCheckpoint)
+	return recorded, nil
 }
 
 // EngineError represents an error from Engine operations.
@@ -2605,9 +2605,9 @@ func TestEngine_ReducerBasedMerge(t *testing.T) {
 	})
 }
 
-// TestEngine_DeterministicMergeOrder verifies lexicographic merge ordering (T111).
+// TestEngine_DeterministicMergeOrder verifies deterministic merge ordering (T111).
 func TestEngine_DeterministicMergeOrder(t *testing.T) {
-	t.Run("branches merge in lexicographic order by nodeID", func(t *testing.T) {
+	t.Run("branches merge in OrderKey order", func(t *testing.T) {
 		type OrderedState struct {
 			Sequence []string
 		}
@@ -2693,8 +2693,9 @@ func TestEngine_DeterministicMergeOrder(t *testing.T) {
 		}
 
 		// Despite variable completion times (B finishes first, A finishes last),
-		// merge order should be lexicographic: nodeA, nodeB, nodeM, nodeZ
-		expected := []string{"A", "B", "M", "Z"}
+		// merge order should follow the branches' OrderKeys, as in concurrent
+		// mode: nodeA, nodeZ, nodeB, nodeM
+		expected := []string{"A", "Z", "B", "M"}
 		if len(final.Sequence) != len(expected) {
 			t.Fatalf("expected %d items, got %d: %v", len(expected), len(final.Sequence), final.Sequence)
 		}
@@ -4277,6 +4278,71 @@ func TestFanOutRouting(t *testing.T) {
 	})
 }
 
+// TestConcurrentRouting verifies that concurrent mode routes from a node's
+// delta applied to its input state, as sequential mode does, and that a run
+// whose context is cancelled reports the cancellation.
+func TestConcurrentRouting(t *testing.T) {
+	reducer := func(prev, delta TestState) TestState {
+		if delta.Value != "" {
+			prev.Value = delta.Value
+		}
+		prev.Counter += delta.Counter
+		return prev
+	}
+
+	t.Run("successors and edges observe the node's delta", func(t *testing.T) {
+		for _, mode := range interruptModes {
+			engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{
+				MaxSteps:           10,
+				MaxConcurrentNodes: mode.maxConcurrentNodes,
+			})
+
+			seen := 0
+			_ = engine.Add("count", NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Delta: TestState{Counter: 1}}
+			}))
+			_ = engine.Add("counted", NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+				seen = s.Counter
+				return NodeResult[TestState]{Route: Stop()}
+			}))
+			_ = engine.Add("uncounted", NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Route: Stop()}
+			}))
+			_ = engine.Connect("count", "counted", func(s TestState) bool { return s.Counter > 0 })
+			_ = engine.Connect("count", "uncounted", nil)
+			_ = engine.StartAt("count")
+
+			if _, err := engine.Run(context.Background(), "routing-delta", TestState{}); err != nil {
+				t.Fatalf("%s: Run failed: %v", mode.name, err)
+			}
+			if seen != 1 {
+				t.Errorf("%s: counted saw Counter = %d, want 1 from the edge taken on the delta", mode.name, seen)
+			}
+		}
+	})
+
+	t.Run("cancelled run returns the context error", func(t *testing.T) {
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{
+			MaxSteps:           10,
+			MaxConcurrentNodes: 4,
+		})
+
+		// The node completes normally after the caller cancels
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		_ = engine.Add("cancel", NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			cancel()
+			return NodeResult[TestState]{Delta: TestState{Counter: 1}, Route: Stop()}
+		}))
+		_ = engine.StartAt("cancel")
+
+		final, err := engine.Run(ctx, "routing-cancelled", TestState{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v with state %+v", err, final)
+		}
+	})
+}
+
 // TestConcurrencyLimit (T059) verifies MaxConcurrentNodes enforcement.
 //
 // According to spec.md FR-009: System MUST enforce MaxConcurrentNodes to limit
@@ -20,6 +20,22 @@ var ErrBackpressure = errors.New("downstream backpressure exceeded threshold")
 // - MaxDelay > 0 and MaxDelay < BaseDelay (cap cannot be less than base)
 var ErrInvalidRetryPolicy = errors.New("invalid retry policy configuration")
 
+// ErrInterrupted indicates that a node paused the run with Interrupt and the.
+// run is waiting for Engine.Resume. The returned error is an *InterruptError.
+// carrying the interrupt payload; use errors.As to inspect it.
+var ErrInterrupted = errors.New("run interrupted: waiting for input")
+
+// ErrMergeConflict indicates that concurrent branches wrote different values.
+// to the same state field under the ConflictFail or ConflictCRDT policy. The.
+// returned error is a *ConflictError naming the fields; use errors.As to.
+// inspect it.
+var ErrMergeConflict = errors.New("merge conflict between concurrent branches")
+
+// ErrCancelled indicates that the run was stopped with Engine.Cancel. The.
+// returned error is a *CancelledError carrying the reason; use errors.As to.
+// inspect it.
+var ErrCancelled = errors.New("run cancelled")
+
 // Note: The following errors are already defined in checkpoint.go:
 // - ErrReplayMismatch: replay mismatch detection.
 // - ErrNoProgress: deadlock/no runnable nodes detection.
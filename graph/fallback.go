@@ -0,0 +1,153 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"errors"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// errorEdge routes the failures of node From that Match accepts to node To.
+type errorEdge struct {
+	From  string
+	To    string
+	Match func(error) bool
+}
+
+// OnError routes failures of node from to the handler node to instead of
+// failing the run. Typical handlers fall back to a cheaper model, ask a human
+// or write an apology.
+//
+// A node fails once its retry policy gives up (see NodePolicy.RetryPolicy);
+// timeouts count as failures. match decides which failures the edge handles,
+// given the error the node failed with; nil handles every failure. When
+// several error edges leave a node, the first one added whose match accepts
+// the failure is taken. Failures caused by the run's context being cancelled
+// are never routed.
+//
+// The failed node's delta is discarded. The run continues as if the node had
+// returned Goto(to) with the delta OnErrorState derives from the failure, so
+// the handler sees the failure in its input state. A node_error_routed event
+// is emitted instead of the error event.
+//
+// Returns error if:
+//   - from or to is empty
+//   - the graph has been compiled (see Compile)
+//
+// Example:
+//
+//	engine.OnError("answer", "fallback", func(err error) bool {
+//	    return errors.Is(err, graph.ErrMaxAttemptsExceeded)
+//	})
+//	engine.OnErrorState(func(failure *graph.NodeError) MyState {
+//	    return MyState{LastError: failure.Message}
+//	})
+func (e *Engine[S]) OnError(from, to string, match func(error) bool) error {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if from == "" {
+		return &EngineError{Message: "from node ID cannot be empty"}
+	}
+	if to == "" {
+		return &EngineError{Message: "to node ID cannot be empty"}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.compiled {
+		return compiledError("add error edge " + from + " -> " + to)
+	}
+
+	e.errorEdges = append(e.errorEdges, errorEdge{From: from, To: to, Match: match})
+	return nil
+}
+
+// OnErrorState sets the hook that places a failure routed by OnError into
+// the run's state. The delta it returns is merged with the reducer before the
+// handler node runs. Without a hook, a zero delta is merged, so with a
+// typical reducer the handler sees the failed node's input state.
+//
+// failure carries the error's Code (from a NodeError or EngineError in its
+// chain, else NODE_ERROR), the node ID, the number of attempts and the
+// node's last delta.
+//
+// Returns an error with code GRAPH_COMPILED once the graph has been compiled.
+func (e *Engine[S]) OnErrorState(hook func(failure *NodeError) S) error {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.compiled {
+		return compiledError("set the error state hook")
+	}
+
+	e.errorState = hook
+	return nil
+}
+
+// routeError returns the result that sends a node failing with err after
+// attempts attempts to its error handler, if an error edge of the node
+// matches err.
+func (e *Engine[S]) routeError(runID string, item WorkItem[S], err error, attempts int, lastDelta S) (NodeResult[S], bool) {
+	e.mu.RLock()
+	var handler string
+	for _, edge := range e.errorEdges {
+		if edge.From == item.NodeID && (edge.Match == nil || edge.Match(err)) {
+			handler = edge.To
+			break
+		}
+	}
+	errorState := e.errorState
+	e.mu.RUnlock()
+	if handler == "" {
+		return NodeResult[S]{}, false
+	}
+
+	failure := &NodeError{
+		Message:  err.Error(),
+		Code:     errorCode(err),
+		NodeID:   item.NodeID,
+		Cause:    err,
+		Attempts: attempts,
+		Delta:    lastDelta,
+	}
+	var delta S
+	if errorState != nil {
+		delta = errorState(failure)
+	}
+
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    "node_error_routed",
+		Meta: map[string]interface{}{
+			"error":    err.Error(),
+			"code":     failure.Code,
+			"attempts": attempts,
+			"handler":  handler,
+		},
+	})
+	return NodeResult[S]{Delta: delta, Route: Goto(handler)}, true
+}
+
+// errorCode returns the code of the first NodeError or EngineError in err's
+// chain, or NODE_ERROR.
+func errorCode(err error) string {
+	var nodeErr *NodeError
+	if errors.As(err, &nodeErr) && nodeErr.Code != "" {
+		return nodeErr.Code
+	}
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) && engineErr.Code != "" {
+		return engineErr.Code
+	}
+	return "NODE_ERROR"
+}
@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestEngine_OnError verifies failed nodes are routed to the handler of the
+// first matching error edge, with the failure in the handler's state.
+func TestEngine_OnError(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Topic string
+		Log   []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		if delta.Topic != "" {
+			prev.Topic = delta.Topic
+		}
+		prev.Log = append(prev.Log, delta.Log...)
+		return prev
+	}
+
+	// visit logs "<id>:<topic>" and follows the node's edges; stop logs the
+	// same and stops, declaring itself a terminal node
+	visit := func(id string) Node[TestState] {
+		return NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Log: []string{id + ":" + s.Topic}}}
+		})
+	}
+	stop := func(id string) Node[TestState] {
+		return DeclareRoutes(NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Log: []string{id + ":" + s.Topic}}, Route: Stop()}
+		}))
+	}
+
+	errRateLimited := &NodeError{Message: "rate limited", Code: "RATE_LIMITED"}
+	rateLimited := func(err error) bool { return errors.Is(err, errRateLimited) }
+
+	// newEngine builds start -> answer -> done, where answer fails with
+	// errRateLimited for the topic "busy" and "model unavailable" otherwise,
+	// returning the delta "partial". Failures are logged as
+	// "failed:<node>:<code>:<attempts>:<delta topic>".
+	newEngine := func(emitter *mockEmitter, mode int, answerPolicy NodePolicy) *Engine[TestState] {
+		engine := New(reducer, store.NewMemStore[TestState](), emitter, Options{MaxSteps: 20, MaxConcurrentNodes: mode})
+
+		answer := NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+			err := errors.New("model unavailable")
+			if s.Topic == "busy" {
+				err = errRateLimited
+			}
+			return NodeResult[TestState]{Delta: TestState{Topic: "partial"}, Err: err}
+		})
+		nodes := map[string]Node[TestState]{
+			"start":       visit("start"),
+			"answer":      policyNode[TestState]{Node: answer, policy: answerPolicy},
+			"done":        stop("done"),
+			"fallback":    visit("fallback"),
+			"retry_later": visit("retry_later"),
+		}
+		for id, node := range nodes {
+			if err := engine.Add(id, node); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+		}
+		for _, edge := range [][2]string{{"start", "answer"}, {"answer", "done"}, {"fallback", "done"}, {"retry_later", "done"}} {
+			if err := engine.Connect(edge[0], edge[1], nil); err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+		}
+		if err := engine.StartAt("start"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if err := engine.OnErrorState(func(failure *NodeError) TestState {
+			last, _ := failure.Delta.(TestState)
+			return TestState{Log: []string{fmt.Sprintf("failed:%s:%s:%d:%s", failure.NodeID, failure.Code, failure.Attempts, last.Topic)}}
+		}); err != nil {
+			t.Fatalf("OnErrorState failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range []struct {
+		name string
+		mode int
+	}{
+		{name: "sequential", mode: 0},
+		{name: "concurrent", mode: 4},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("failure routes to the handler", func(t *testing.T) {
+				emitter := &mockEmitter{}
+				engine := newEngine(emitter, mode.mode, NodePolicy{})
+				if err := engine.OnError("answer", "fallback", nil); err != nil {
+					t.Fatalf("OnError failed: %v", err)
+				}
+
+				final, err := engine.Run(ctx, "run-001", TestState{Topic: "cats"})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				// Concurrent runs merge deltas in OrderKey order
+				want := []string{"done:cats", "failed:answer:NODE_ERROR:1:partial", "fallback:cats", "start:cats"}
+				if sort.Strings(final.Log); !reflect.DeepEqual(final.Log, want) {
+					t.Errorf("log %v, want %v", final.Log, want)
+				}
+
+				var routed, failed int
+				for _, event := range emitter.events {
+					switch event.Msg {
+					case "node_error_routed":
+						routed++
+						if event.NodeID != "answer" || event.Meta["handler"] != "fallback" || event.Meta["code"] != "NODE_ERROR" {
+							t.Errorf("node_error_routed event = %+v", event)
+						}
+					case "error":
+						failed++
+					}
+				}
+				if routed != 1 || failed != 0 {
+					t.Errorf("got %d node_error_routed and %d error events, want 1 and 0", routed, failed)
+				}
+			})
+
+			t.Run("first matching edge", func(t *testing.T) {
+				engine := newEngine(&mockEmitter{}, mode.mode, NodePolicy{})
+				if err := engine.OnError("answer", "retry_later", rateLimited); err != nil {
+					t.Fatalf("OnError failed: %v", err)
+				}
+				if err := engine.OnError("answer", "fallback", nil); err != nil {
+					t.Fatalf("OnError failed: %v", err)
+				}
+
+				for topic, handler := range map[string]string{"busy": "retry_later", "cats": "fallback"} {
+					final, err := engine.Run(ctx, "run-"+topic, TestState{Topic: topic})
+					if err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+					if !slices.Contains(final.Log, handler+":"+topic) {
+						t.Errorf("log %v, want %s to handle the failure", final.Log, handler)
+					}
+				}
+			})
+
+			t.Run("retries are exhausted first", func(t *testing.T) {
+				retryPol := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Retryable: func(error) bool { return true }}
+				engine := newEngine(&mockEmitter{}, mode.mode, NodePolicy{RetryPolicy: retryPol})
+				exhausted := func(err error) bool { return errors.Is(err, ErrMaxAttemptsExceeded) }
+				if err := engine.OnError("answer", "fallback", exhausted); err != nil {
+					t.Fatalf("OnError failed: %v", err)
+				}
+
+				final, err := engine.Run(ctx, "run-001", TestState{Topic: "busy"})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if !slices.Contains(final.Log, "failed:answer:RATE_LIMITED:3:partial") {
+					t.Errorf("log %v, want the failure after 3 attempts", final.Log)
+				}
+			})
+
+			t.Run("unmatched failures fail the run", func(t *testing.T) {
+				engine := newEngine(&mockEmitter{}, mode.mode, NodePolicy{})
+				if err := engine.OnError("answer", "retry_later", rateLimited); err != nil {
+					t.Fatalf("OnError failed: %v", err)
+				}
+				if _, err := engine.Run(ctx, "run-001", TestState{Topic: "cats"}); err == nil || err.Error() != "model unavailable" {
+					t.Errorf("Run returned %v, want the node's error", err)
+				}
+			})
+		})
+	}
+
+	t.Run("timeouts are failures", func(t *testing.T) {
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{MaxSteps: 20})
+		slow := NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+			<-ctx.Done()
+			return NodeResult[TestState]{Err: ctx.Err()}
+		})
+		if err := engine.Add("answer", policyNode[TestState]{Node: slow, policy: NodePolicy{Timeout: 10 * time.Millisecond}}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.Add("apology", stop("apology")); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("answer"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if err := engine.OnError("answer", "apology", nil); err != nil {
+			t.Fatalf("OnError failed: %v", err)
+		}
+		if err := engine.OnErrorState(func(failure *NodeError) TestState {
+			return TestState{Topic: failure.Code}
+		}); err != nil {
+			t.Fatalf("OnErrorState failed: %v", err)
+		}
+
+		final, err := engine.Run(ctx, "run-001", TestState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if !reflect.DeepEqual(final.Log, []string{"apology:NODE_TIMEOUT"}) {
+			t.Errorf("log %v, want the apology for a timeout", final.Log)
+		}
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		engine := newEngine(&mockEmitter{}, 0, NodePolicy{})
+		if err := engine.OnError("answer", "fallback", nil); err != nil {
+			t.Fatalf("OnError failed: %v", err)
+		}
+		if err := engine.OnError("answer", "missing", nil); err != nil {
+			t.Fatalf("OnError failed: %v", err)
+		}
+
+		report := engine.Validate()
+		if len(report.Errors) != 1 || report.Errors[0].Code != "DANGLING_EDGE" {
+			t.Errorf("validation errors = %v, want one DANGLING_EDGE", report.Errors)
+		}
+		// fallback is reached through its error edge; retry_later is not
+		if !reflect.DeepEqual(report.Unreachable, []string{"retry_later"}) {
+			t.Errorf("unreachable = %v, want [retry_later]", report.Unreachable)
+		}
+
+		var errorEdges []TopologyEdge
+		for _, edge := range engine.Topology().Edges {
+			if edge.Kind == EdgeError {
+				errorEdges = append(errorEdges, edge)
+			}
+		}
+		if len(errorEdges) != 2 || errorEdges[0].From != "answer" || errorEdges[0].To != "fallback" {
+			t.Errorf("error edges = %+v, want answer -> fallback and answer -> missing", errorEdges)
+		}
+	})
+
+	t.Run("compiled graph", func(t *testing.T) {
+		engine := newEngine(&mockEmitter{}, 0, NodePolicy{})
+		if err := engine.OnError("answer", "fallback", nil); err != nil {
+			t.Fatalf("OnError failed: %v", err)
+		}
+		if _, err := engine.Compile(); err != nil {
+			t.Fatalf("Compile failed: %v", err)
+		}
+		var engineErr *EngineError
+		if err := engine.OnError("answer", "retry_later", nil); !errors.As(err, &engineErr) || engineErr.Code != "GRAPH_COMPILED" {
+			t.Errorf("expected GRAPH_COMPILED, got %v", err)
+		}
+		if err := engine.OnErrorState(nil); !errors.As(err, &engineErr) || engineErr.Code != "GRAPH_COMPILED" {
+			t.Errorf("expected GRAPH_COMPILED, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// forkLabel labels the CheckpointV2 a forked run starts from.
+const forkLabel = "fork"
+
+// Fork starts a new run that branches off a past run at one of its steps,
+// with the state at that step optionally edited.
+//
+// The fork continues from the source run's CheckpointV2 at stepID, running
+// the same work items the source run had pending. Runs executed with
+// Options.CheckpointEveryStep are checkpointed at step 0 and after each step:
+// between rounds in sequential mode (MaxConcurrentNodes = 0) and after every
+// node in concurrent mode. Without a checkpoint at stepID, the step record
+// saved by sequential runs is used (the store must implement
+// store.HistoryStore) and execution continues along the edges of the node
+// that ran at that step. Goto, Many and Sends routes are not part of a step
+// record, so they are not followed from it: forking from the step record of a
+// node without a matching edge fails, as does forking after its last step.
+//
+// patch receives the state at stepID and returns the state the fork starts
+// from; nil keeps it unchanged. Work items inside a fan-out carry their own
+// branch state, which is patched too.
+//
+// The fork runs as newRunID in the engine's execution mode, with its own RNG
+// seed and recorded I/O, and its step numbers continue from stepID. Its
+// lineage is saved as a store.ForkRecord, the state it starts from as a
+// checkpoint labeled "fork", and a run_forked event is emitted. The source
+// run is not modified.
+//
+// Returns an EngineError with code:
+//   - FORK_UNSUPPORTED if the store does not implement store.ForkStore
+//   - INVALID_RUN_ID if newRunID is empty or equal to sourceRunID
+//   - STEP_NOT_FOUND if the source run has neither a checkpoint nor a step
+//     record at stepID
+//   - STEP_NOT_FORKABLE if there is no checkpoint at stepID and no edge of
+//     the node in its step record matches, e.g. because it routed with Goto,
+//     Many or Sends or stopped the run
+//
+// Example:
+//
+//	// Re-run the conversation from step 3 with the bad LLM answer removed
+//	final, err := engine.Fork(ctx, "run-001", 3, "run-001-retry", func(s State) State {
+//	    s.Messages = s.Messages[:len(s.Messages)-1]
+//	    return s
+//	})
+func (e *Engine[S]) Fork(ctx context.Context, sourceRunID string, stepID int, newRunID string, patch func(S) S) (S, error) {
+	var zero S
+
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return zero, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if e.reducer == nil {
+		return zero, &EngineError{
+			Message: "reducer is required",
+			Code:    "MISSING_REDUCER",
+		}
+	}
+	if e.store == nil {
+		return zero, &EngineError{
+			Message: "store is required",
+			Code:    "MISSING_STORE",
+		}
+	}
+	if newRunID == "" || newRunID == sourceRunID {
+		return zero, &EngineError{
+			Message: "fork needs a new run ID distinct from the source run: " + sourceRunID,
+			Code:    "INVALID_RUN_ID",
+		}
+	}
+
+	forks, ok := e.store.(store.ForkStore)
+	if !ok {
+		return zero, &EngineError{
+			Message: "store does not implement store.ForkStore",
+			Code:    "FORK_UNSUPPORTED",
+		}
+	}
+
+	if patch == nil {
+		patch = func(s S) S { return s }
+	}
+	state, items, err := e.forkPoint(ctx, sourceRunID, stepID, patch)
+	if err != nil {
+		return zero, err
+	}
+
+	if err := forks.SaveFork(ctx, store.ForkRecord{
+		RunID:       newRunID,
+		ParentRunID: sourceRunID,
+		ParentStep:  stepID,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		return zero, &EngineError{
+			Message: "failed to save fork: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+	if err := e.saveCheckpoint(ctx, newRunID, stepID, state, items, nil, forkLabel); err != nil {
+		return zero, err
+	}
+
+	e.publish(emit.Event{
+		RunID: newRunID,
+		Step:  stepID,
+		Msg:   "run_forked",
+		Meta: map[string]interface{}{
+			"parent_run_id": sourceRunID,
+			"parent_step":   stepID,
+			"frontier_size": len(items),
+		},
+	})
+
+	if len(items) == 0 {
+		// The source run had finished at stepID
+		return state, nil
+	}
+
+	// Enforce RunWallClockBudget for the forked run (T075)
+	if e.opts.RunWallClockBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.opts.RunWallClockBudget)
+		defer cancel()
+	}
+
+	// The fork is a run of its own: new RNG sequence and fresh recordings
+	ctx = context.WithValue(ctx, RNGKey, initRNG(newRunID))
+	ctx = context.WithValue(ctx, RecordedIOsKey, []RecordedIO{})
+
+	return e.continueRun(ctx, newRunID, state, items, stepID, nil)
+}
+
+// forkPoint loads the state and frontier of sourceRunID at stepID and applies
+// patch to them, preferring the step's CheckpointV2 over its step record.
+func (e *Engine[S]) forkPoint(ctx context.Context, sourceRunID string, stepID int, patch func(S) S) (S, []WorkItem[S], error) {
+	var zero S
+
+	checkpoint, err := e.store.LoadCheckpointV2(ctx, sourceRunID, stepID)
+	if err == nil {
+		items, err := decodeFrontier[S](checkpoint.Frontier)
+		if err != nil {
+			return zero, nil, err
+		}
+		state := patch(checkpoint.State)
+		for i := range items {
+			// Branch items observe their own state, top-level items the run's
+			if len(items[i].Forks) > 0 {
+				items[i].State = patch(items[i].State)
+			} else {
+				items[i].State = state
+			}
+			items[i].Attempt = 0
+			items[i].Interrupted = false
+		}
+		return state, items, nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		return zero, nil, &EngineError{
+			Message: "failed to load checkpoint: " + err.Error(),
+			Code:    "CHECKPOINT_LOAD_ERROR",
+		}
+	}
+
+	// No checkpoint at this step: continue along the edges of its node
+	record, err := e.stepRecord(ctx, sourceRunID, stepID)
+	if err != nil {
+		return zero, nil, err
+	}
+	state := patch(record.State)
+	next := e.evaluateEdges(record.NodeID, state)
+	if next == "" {
+		// The node stopped or routed without its edges; a step record cannot
+		// tell which, so only a checkpoint can resume from here
+		return zero, nil, &EngineError{
+			Message: "no edge of node " + record.NodeID + " to follow from its step record; forking at this step needs a checkpoint",
+			Code:    "STEP_NOT_FORKABLE",
+		}
+	}
+	return state, []WorkItem[S]{{
+		StepID:       stepID,
+		OrderKey:     computeOrderKey(record.NodeID, 0),
+		NodeID:       next,
+		State:        state,
+		ParentNodeID: record.NodeID,
+	}}, nil
+}
+
+// stepRecord returns the step record saved for runID at stepID.
+func (e *Engine[S]) stepRecord(ctx context.Context, runID string, stepID int) (store.StepRecord[S], error) {
+	notFound := &EngineError{
+		Message: "no checkpoint or step record for run " + runID + " at the requested step",
+		Code:    "STEP_NOT_FOUND",
+	}
+
+	history, ok := e.store.(store.HistoryStore[S])
+	if !ok {
+		return store.StepRecord[S]{}, notFound
+	}
+	record, err := history.GetStep(ctx, runID, stepID)
+	if errors.Is(err, store.ErrNotFound) {
+		return record, notFound
+	}
+	if err != nil {
+		return record, &EngineError{
+			Message: "failed to load step: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+	return record, nil
+}
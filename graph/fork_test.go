@@ -0,0 +1,324 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestEngine_Fork verifies runs can be branched from a past step with an
+// edited state.
+func TestEngine_Fork(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Trail []string
+		Topic string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Trail = append(prev.Trail, delta.Trail...)
+		if delta.Topic != "" {
+			prev.Topic = delta.Topic
+		}
+		return prev
+	}
+
+	// visit appends "<id>:<topic>" to the trail and routes by its edges or
+	// by route
+	visit := func(id string, route Next) Node[TestState] {
+		return NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Trail: []string{id + ":" + s.Topic}}, Route: route}
+		})
+	}
+
+	// newEngine builds a -> b -> c, where b also fans out to x and y when
+	// fanout is set: a -> b -> {x, y}, x -> c, and y stops. Every step is
+	// checkpointed.
+	newEngine := func(st store.Store[TestState], emitter *mockEmitter, mode int, fanout bool) *Engine[TestState] {
+		engine := New(reducer, st, emitter, Options{MaxSteps: 20, MaxConcurrentNodes: mode, CheckpointEveryStep: true})
+
+		nodes := map[string]Node[TestState]{"a": visit("a", Next{}), "b": visit("b", Next{}), "c": visit("c", Stop())}
+		edges := [][2]string{{"a", "b"}, {"b", "c"}}
+		if fanout {
+			nodes["b"] = visit("b", Many([]string{"x", "y"}))
+			nodes["x"] = visit("x", Next{})
+			nodes["y"] = visit("y", Stop())
+			edges = [][2]string{{"a", "b"}, {"x", "c"}}
+		}
+		for id, node := range nodes {
+			if err := engine.Add(id, node); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+		}
+		for _, edge := range edges {
+			if err := engine.Connect(edge[0], edge[1], nil); err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+		}
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	// setTopic returns a patch that changes the topic later nodes see
+	setTopic := func(topic string) func(TestState) TestState {
+		return func(s TestState) TestState {
+			s.Topic = topic
+			return s
+		}
+	}
+
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("fork from the start of a run", func(t *testing.T) {
+				st := store.NewMemStore[TestState]()
+				engine := newEngine(st, &mockEmitter{}, mode.maxConcurrentNodes, false)
+				source, err := engine.Run(ctx, "source", TestState{Topic: "cats"})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+
+				final, err := engine.Fork(ctx, "source", 0, "forked", setTopic("dogs"))
+				if err != nil {
+					t.Fatalf("Fork failed: %v", err)
+				}
+
+				// Merge order differs between modes but not between runs
+				want := strings.ReplaceAll(strings.Join(source.Trail, ","), "cats", "dogs")
+				if got := strings.Join(final.Trail, ","); got != want || len(final.Trail) != 3 {
+					t.Errorf("fork trail = %s, want %s", got, want)
+				}
+			})
+		})
+	}
+
+	t.Run("fork at a round boundary", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		emitter := &mockEmitter{}
+		engine := newEngine(st, emitter, 0, false)
+		source, err := engine.Run(ctx, "source", TestState{Topic: "cats"})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		final, err := engine.Fork(ctx, "source", 1, "forked", setTopic("dogs"))
+		if err != nil {
+			t.Fatalf("Fork failed: %v", err)
+		}
+		if got := strings.Join(final.Trail, ","); got != "a:cats,b:dogs,c:dogs" {
+			t.Errorf("fork trail = %s, want a:cats,b:dogs,c:dogs", got)
+		}
+
+		// The source run is untouched
+		latest, err := engine.latestCheckpoint(ctx, "source")
+		if err != nil {
+			t.Fatalf("latestCheckpoint failed: %v", err)
+		}
+		if fmt.Sprint(latest.State) != fmt.Sprint(source) {
+			t.Errorf("source run changed to %+v", latest.State)
+		}
+
+		// Fork steps continue from the parent step
+		steps, err := st.ListSteps(ctx, "forked", store.Page{})
+		if err != nil {
+			t.Fatalf("ListSteps failed: %v", err)
+		}
+		if len(steps) != 2 || steps[0].Step != 2 || steps[0].NodeID != "b" {
+			t.Errorf("fork steps = %+v, want b at step 2 and c at step 3", steps)
+		}
+
+		fork, err := st.LoadFork(ctx, "forked")
+		if err != nil {
+			t.Fatalf("LoadFork failed: %v", err)
+		}
+		if fork.ParentRunID != "source" || fork.ParentStep != 1 {
+			t.Errorf("fork lineage = %+v, want step 1 of source", fork)
+		}
+		checkpoint, err := st.LoadCheckpointV2(ctx, "forked", 1)
+		if err != nil || checkpoint.Label != forkLabel || checkpoint.State.Topic != "dogs" {
+			t.Errorf("fork checkpoint = %+v, %v; want the patched state labeled %q", checkpoint, err, forkLabel)
+		}
+		var forked []string
+		for _, event := range emitter.events {
+			if event.Msg == "run_forked" {
+				forked = append(forked, fmt.Sprintf("%s<-%v", event.RunID, event.Meta["parent_run_id"]))
+			}
+		}
+		if fmt.Sprint(forked) != "[forked<-source]" {
+			t.Errorf("run_forked events = %v, want one for the fork of source", forked)
+		}
+	})
+
+	t.Run("fork inside a fan-out round follows the step's edges", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := newEngine(st, &mockEmitter{}, 0, true)
+		source, err := engine.Run(ctx, "source", TestState{Topic: "cats"})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if got := strings.Join(source.Trail, ","); got != "a:cats,b:cats,x:cats,y:cats,c:cats" {
+			t.Fatalf("source trail = %s", got)
+		}
+
+		// x and y run in one round, so only the step record of x marks step 3
+		final, err := engine.Fork(ctx, "source", 3, "forked", setTopic("dogs"))
+		if err != nil {
+			t.Fatalf("Fork failed: %v", err)
+		}
+		if got := strings.Join(final.Trail, ","); got != "a:cats,b:cats,x:cats,c:dogs" {
+			t.Errorf("fork trail = %s, want a:cats,b:cats,x:cats,c:dogs", got)
+		}
+	})
+
+	t.Run("fork inside a concurrent run follows its routes", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := newEngine(st, &mockEmitter{}, 4, true)
+		if _, err := engine.Run(ctx, "source", TestState{Topic: "cats"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// One checkpoint at the start and one after each of the five nodes
+		checkpoints, err := st.ListCheckpoints(ctx, "source", "")
+		if err != nil {
+			t.Fatalf("ListCheckpoints failed: %v", err)
+		}
+		var stepIDs []int
+		for _, checkpoint := range checkpoints {
+			stepIDs = append(stepIDs, checkpoint.StepID)
+		}
+		if fmt.Sprint(stepIDs) != "[0 1 2 3 4 5]" {
+			t.Errorf("checkpoint steps = %v, want [0 1 2 3 4 5]", stepIDs)
+		}
+
+		tests := []struct {
+			step int
+			want string // Merged by OrderKey after the steps completed before the fork
+		}{
+			{step: 1, want: "a:cats,b:dogs,c:dogs,x:dogs,y:dogs"},
+			{step: 2, want: "a:cats,b:cats,c:dogs,x:dogs,y:dogs"},
+		}
+		for _, tt := range tests {
+			newRunID := fmt.Sprintf("forked-%d", tt.step)
+			final, err := engine.Fork(ctx, "source", tt.step, newRunID, setTopic("dogs"))
+			if err != nil {
+				t.Fatalf("Fork at step %d failed: %v", tt.step, err)
+			}
+			// The fan-out of b is followed from both checkpoints
+			head := append([]string(nil), final.Trail[:tt.step]...)
+			rest := append([]string(nil), final.Trail[tt.step:]...)
+			sort.Strings(rest)
+			if got := strings.Join(append(head, rest...), ","); got != tt.want {
+				t.Errorf("fork at step %d trail = %s, want %s", tt.step, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("without step checkpoints forks use step records", func(t *testing.T) {
+		for _, mode := range interruptModes {
+			st := store.NewMemStore[TestState]()
+			engine := newEngine(st, &mockEmitter{}, mode.maxConcurrentNodes, false)
+			engine.opts.CheckpointEveryStep = false
+			if _, err := engine.Run(ctx, "source", TestState{Topic: "cats"}); err != nil {
+				t.Fatalf("%s: Run failed: %v", mode.name, err)
+			}
+
+			// Only completed concurrent runs are checkpointed
+			checkpoints, err := st.ListCheckpoints(ctx, "source", "")
+			var stepIDs []int
+			for _, checkpoint := range checkpoints {
+				stepIDs = append(stepIDs, checkpoint.StepID)
+			}
+			want := "[]"
+			if mode.maxConcurrentNodes > 0 {
+				want = "[3]"
+			}
+			if err != nil || fmt.Sprint(stepIDs) != want {
+				t.Errorf("%s: checkpoint steps = %v, %v; want %s", mode.name, stepIDs, err, want)
+			}
+
+			// Sequential runs save a step record after every node
+			final, err := engine.Fork(ctx, "source", 1, "forked", setTopic("dogs"))
+			if mode.maxConcurrentNodes == 0 {
+				if got := strings.Join(final.Trail, ","); err != nil || got != "a:cats,b:dogs,c:dogs" {
+					t.Errorf("%s: fork trail = %s, %v; want a:cats,b:dogs,c:dogs", mode.name, got, err)
+				}
+
+				// c stopped the run, which its step record does not show
+				var engineErr *EngineError
+				if _, err := engine.Fork(ctx, "source", 3, "forked-3", nil); !errors.As(err, &engineErr) || engineErr.Code != "STEP_NOT_FORKABLE" {
+					t.Errorf("%s: expected STEP_NOT_FORKABLE, got %v", mode.name, err)
+				}
+				continue
+			}
+			var engineErr *EngineError
+			if !errors.As(err, &engineErr) || engineErr.Code != "STEP_NOT_FOUND" {
+				t.Errorf("%s: expected STEP_NOT_FOUND, got %v", mode.name, err)
+			}
+		}
+	})
+
+	t.Run("forks of forks record their lineage", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := newEngine(st, &mockEmitter{}, 0, false)
+		if _, err := engine.Run(ctx, "source", TestState{Topic: "cats"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if _, err := engine.Fork(ctx, "source", 1, "fork-1", nil); err != nil {
+			t.Fatalf("Fork failed: %v", err)
+		}
+		final, err := engine.Fork(ctx, "fork-1", 2, "fork-2", setTopic("dogs"))
+		if err != nil {
+			t.Fatalf("Fork of fork failed: %v", err)
+		}
+		if got := strings.Join(final.Trail, ","); got != "a:cats,b:cats,c:dogs" {
+			t.Errorf("fork trail = %s, want a:cats,b:cats,c:dogs", got)
+		}
+
+		forks, err := st.ListForks(ctx, "fork-1")
+		if err != nil || len(forks) != 1 || forks[0].RunID != "fork-2" {
+			t.Errorf("ListForks(fork-1) = %+v, %v; want fork-2", forks, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := newEngine(st, &mockEmitter{}, 0, false)
+		if _, err := engine.Run(ctx, "source", TestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// Embedding the interface hides MemStore's fork methods
+		unsupported := newEngine(struct{ store.Store[TestState] }{st}, &mockEmitter{}, 0, false)
+
+		tests := []struct {
+			name     string
+			engine   *Engine[TestState]
+			source   string
+			step     int
+			newRunID string
+			code     string
+		}{
+			{name: "missing new run ID", engine: engine, source: "source", step: 1, code: "INVALID_RUN_ID"},
+			{name: "same run ID", engine: engine, source: "source", step: 1, newRunID: "source", code: "INVALID_RUN_ID"},
+			{name: "unknown step", engine: engine, source: "source", step: 9, newRunID: "forked", code: "STEP_NOT_FOUND"},
+			{name: "unknown run", engine: engine, source: "never-ran", step: 1, newRunID: "forked", code: "STEP_NOT_FOUND"},
+			{name: "store without fork support", engine: unsupported, source: "source", step: 1, newRunID: "forked", code: "FORK_UNSUPPORTED"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.engine.Fork(ctx, tt.source, tt.step, tt.newRunID, nil)
+				var engineErr *EngineError
+				if !errors.As(err, &engineErr) || engineErr.Code != tt.code {
+					t.Errorf("expected %s, got %v", tt.code, err)
+				}
+			})
+		}
+	})
+}
@@ -684,8 +684,8 @@ func TestIntegration_ParallelExecution(t *testing.T) {
 			t.Fatalf("expected 4 results, got %d", len(finalState.Results))
 		}
 
-		// Verify deterministic merge order (lexicographic by nodeID).
-		expectedOrder := []string{"branch1-result", "branch2-result", "branch3-result", "branch4-result"}
+		// Verify deterministic merge order (by the branches' OrderKeys).
+		expectedOrder := []string{"branch2-result", "branch1-result", "branch4-result", "branch3-result"}
 		for i, expected := range expectedOrder {
 			if finalState.Results[i] != expected {
 				t.Errorf("position %d: expected %q, got %q", i, expected, finalState.Results[i])
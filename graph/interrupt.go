@@ -0,0 +1,296 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// interruptLabel labels the CheckpointV2 saved when a run pauses on Interrupt.
+const interruptLabel = "interrupt"
+
+// InterruptError is returned by Run and Resume when a node pauses the run with
+// Interrupt. It matches ErrInterrupted with errors.Is.
+//
+// When several nodes interrupt before the run settles, InterruptError reports
+// the first of them in OrderKey order. Resume delivers its input to that node;
+// the others run again and normally interrupt again, one answer at a time.
+type InterruptError struct {
+	// RunID identifies the paused run; pass it to Engine.Resume.
+	RunID string
+
+	// NodeID is the node waiting for input.
+	NodeID string
+
+	// StepID is the step at which the run paused.
+	StepID int
+
+	// Payload is the value the node passed to Interrupt.
+	Payload interface{}
+}
+
+// Error implements the error interface.
+func (e *InterruptError) Error() string {
+	return fmt.Sprintf("%s: run %s paused at node %s", ErrInterrupted.Error(), e.RunID, e.NodeID)
+}
+
+// Is reports whether target is ErrInterrupted.
+func (e *InterruptError) Is(target error) bool {
+	return target == ErrInterrupted
+}
+
+// ResumeInput returns the input passed to Engine.Resume when the current node
+// is the one that interrupted the run. ok is false on the node's first
+// execution and for every other node.
+func ResumeInput(ctx context.Context) (input interface{}, ok bool) {
+	delivery, ok := ctx.Value(ResumeInputKey).(resumeInput)
+	if !ok {
+		return nil, false
+	}
+	return delivery.value, true
+}
+
+// resumeInput wraps the Resume input so that a nil input is still delivered.
+type resumeInput struct {
+	value interface{}
+}
+
+// resumeDeliveryKey carries the pending Resume input for the run; it is
+// exposed to the interrupted node under ResumeInputKey.
+const resumeDeliveryKey contextKey = "langgraph.resume_delivery"
+
+// withResumeInput exposes the pending Resume input to item if it is the node
+// that interrupted the run. Other nodes, including those of a subgraph run by
+// the interrupted node, never see an input inherited from ctx.
+func withResumeInput[S any](ctx context.Context, item WorkItem[S]) context.Context {
+	if !item.Interrupted {
+		if ctx.Value(ResumeInputKey) != nil {
+			return context.WithValue(ctx, ResumeInputKey, nil)
+		}
+		return ctx
+	}
+	if delivery, ok := ctx.Value(resumeDeliveryKey).(resumeInput); ok {
+		return context.WithValue(ctx, ResumeInputKey, delivery)
+	}
+	return ctx
+}
+
+// pausedNode is a work item that returned Interrupt, with its payload.
+type pausedNode[S any] struct {
+	item    WorkItem[S]
+	payload interface{}
+}
+
+// Resume continues a run that paused with ErrInterrupted, delivering input to
+// the node that interrupted.
+//
+// The run continues from the frontier saved when it paused: the interrupted
+// node runs again on the same input state with input available through
+// ResumeInput, and any join arrivals that were waiting are restored. Resume
+// uses the engine's current execution mode and may itself return
+// ErrInterrupted if a node interrupts again.
+//
+// Each interrupt can be resumed once. Resuming a run that is not paused
+// returns an EngineError with code NOT_INTERRUPTED. The interrupt is only
+// consumed once the run starts, so a Resume failing with RUN_IN_PROGRESS,
+// because the run ID is executing, can be retried. Stores implementing
+// store.CheckpointClaimer consume it atomically, so engines in several
+// processes sharing the store resume a run once; with other stores only
+// calls on engines of one process are guarded.
+//
+// Parameters:
+//   - ctx: Context for cancellation and request-scoped values
+//   - runID: ID of the paused run (InterruptError.RunID)
+//   - input: The caller's answer, e.g. an approval decision or edited draft
+//
+// Returns:
+//   - Final state after the run completes (or the state at the next pause)
+//   - Error if the run is not paused, the checkpoint cannot be loaded, or execution fails
+//
+// Example:
+//
+//	_, err := engine.Run(ctx, "run-001", initial)
+//	var interrupt *graph.InterruptError
+//	if errors.As(err, &interrupt) {
+//	    answer := askHuman(interrupt.Payload)
+//	    final, err = engine.Resume(ctx, interrupt.RunID, answer)
+//	}
+func (e *Engine[S]) Resume(ctx context.Context, runID string, input interface{}) (S, error) {
+	var zero S
+
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return zero, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if e.reducer == nil {
+		return zero, &EngineError{
+			Message: "reducer is required",
+			Code:    "MISSING_REDUCER",
+		}
+	}
+	if e.store == nil {
+		return zero, &EngineError{
+			Message: "store is required",
+			Code:    "MISSING_STORE",
+		}
+	}
+
+	// Find the checkpoint saved when the run paused
+	_, stepID, err := e.store.LoadCheckpoint(ctx, interruptCheckpointID(runID))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && stepID < 0) {
+		return zero, &EngineError{
+			Message: "run is not waiting for input: " + runID,
+			Code:    "NOT_INTERRUPTED",
+		}
+	}
+	if err != nil {
+		return zero, &EngineError{
+			Message: "failed to load interrupt checkpoint: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	checkpoint, err := e.store.LoadCheckpointV2(ctx, runID, stepID)
+	if err != nil {
+		return zero, &EngineError{
+			Message: "failed to load interrupt checkpoint: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+	items, err := decodeFrontier[S](checkpoint.Frontier)
+	if err != nil {
+		return zero, err
+	}
+
+	// Consume the interrupt once the run is registered, so the same answer
+	// cannot be applied twice and a Resume that cannot start leaves it
+	// pending
+	claim := func(ctx context.Context) error {
+		notInterrupted := &EngineError{
+			Message: "run is not waiting for input: " + runID,
+			Code:    "NOT_INTERRUPTED",
+		}
+
+		// Stores shared by several processes claim it atomically
+		if claimer, ok := e.store.(store.CheckpointClaimer); ok {
+			claimed, err := claimer.ClaimCheckpoint(ctx, interruptCheckpointID(runID), stepID, -1)
+			if err != nil {
+				return &EngineError{
+					Message: "failed to clear interrupt checkpoint: " + err.Error(),
+					Code:    "STORE_ERROR",
+				}
+			}
+			if !claimed {
+				// Another Resume consumed it while this one was loading
+				return notInterrupted
+			}
+			return nil
+		}
+
+		_, current, err := e.store.LoadCheckpoint(ctx, interruptCheckpointID(runID))
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return &EngineError{
+				Message: "failed to load interrupt checkpoint: " + err.Error(),
+				Code:    "STORE_ERROR",
+			}
+		}
+		if err != nil || current != stepID {
+			return notInterrupted
+		}
+		if err := e.store.SaveCheckpoint(ctx, interruptCheckpointID(runID), zero, -1); err != nil {
+			return &EngineError{
+				Message: "failed to clear interrupt checkpoint: " + err.Error(),
+				Code:    "STORE_ERROR",
+			}
+		}
+		return nil
+	}
+
+	// Enforce RunWallClockBudget for the resumed segment (T075)
+	if e.opts.RunWallClockBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.opts.RunWallClockBudget)
+		defer cancel()
+	}
+
+	ctx = context.WithValue(ctx, RNGKey, initRNG(runID))
+	ctx = context.WithValue(ctx, resumeDeliveryKey, resumeInput{value: input})
+	if e.opts.ReplayMode && ctx.Value(RecordedIOsKey) == nil {
+		// Replay serves Recorded calls from the I/O recorded after the interrupt
+		recorded, err := e.loadRecordedIOs(ctx, runID, checkpoint.StepID)
+		if err != nil {
+			return zero, err
+		}
+		ctx = context.WithValue(ctx, RecordedIOsKey, recorded)
+	}
+
+	return e.continueRun(ctx, runID, checkpoint.State, items, checkpoint.StepID, claim)
+}
+
+// pauseRun persists a run that has paused on Interrupt and returns the
+// InterruptError describing it.
+//
+// The saved frontier holds the paused work items followed by any join
+// arrivals still waiting at a barrier, so that Resume restores both. A named
+// checkpoint (see interruptCheckpointID) records the step of the saved
+// CheckpointV2 so Resume can find it from the run ID alone.
+func (e *Engine[S]) pauseRun(ctx context.Context, runID string, stepID int, state S, paused []pausedNode[S], barriers *joinBarriers[S]) error {
+	sort.SliceStable(paused, func(i, j int) bool {
+		if paused[i].item.OrderKey != paused[j].item.OrderKey {
+			return paused[i].item.OrderKey < paused[j].item.OrderKey
+		}
+		return paused[i].item.NodeID < paused[j].item.NodeID
+	})
+
+	frontier := make([]WorkItem[S], 0, len(paused))
+	for i, p := range paused {
+		item := p.item
+		item.Attempt = 0
+		item.Interrupted = i == 0 // Only the reported node receives the resume input
+		frontier = append(frontier, item)
+	}
+	frontier = append(frontier, barriers.arrivals()...)
+
+	if err := e.saveCheckpoint(ctx, runID, stepID, state, frontier, runRecorder(ctx), interruptLabel); err != nil {
+		return err
+	}
+	if err := e.store.SaveCheckpoint(ctx, interruptCheckpointID(runID), state, stepID); err != nil {
+		return &EngineError{
+			Message: "failed to save interrupt checkpoint: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	first := paused[0]
+	return &InterruptError{
+		RunID:   runID,
+		NodeID:  first.item.NodeID,
+		StepID:  stepID,
+		Payload: first.payload,
+	}
+}
+
+// interruptCheckpointID returns the named checkpoint that points at a run's
+// pending interrupt. Its step is the StepID of the interrupt CheckpointV2, or
+// -1 once the interrupt has been resumed.
+func interruptCheckpointID(runID string) string {
+	return "__interrupt__:" + runID
+}
+
+// emitInterrupt emits an interrupt event when a node pauses the run.
+func (e *Engine[S]) emitInterrupt(runID string, item WorkItem[S], payload interface{}) {
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    "interrupt",
+		Meta: map[string]interface{}{
+			"payload": payload,
+		},
+	})
+}
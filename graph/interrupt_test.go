@@ -0,0 +1,288 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// interruptModes runs a subtest for sequential and concurrent execution.
+var interruptModes = []struct {
+	name               string
+	maxConcurrentNodes int
+}{
+	{name: "sequential", maxConcurrentNodes: 0},
+	{name: "concurrent", maxConcurrentNodes: 4},
+}
+
+// approvalNode interrupts until it receives a resume input, then records it.
+func approvalNode(runs *atomic.Int32) NodeFunc[JoinTestState] {
+	return func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		runs.Add(1)
+		answer, ok := ResumeInput(ctx)
+		if !ok {
+			return NodeResult[JoinTestState]{
+				Delta: JoinTestState{Values: []string{"discarded"}},
+				Route: Interrupt("approve draft?"),
+			}
+		}
+		return NodeResult[JoinTestState]{
+			Delta: JoinTestState{Values: []string{fmt.Sprintf("approved:%v", answer)}},
+			Route: Goto("send"),
+		}
+	}
+}
+
+// TestEngine_Interrupt verifies Interrupt pauses a run and Resume continues it.
+func TestEngine_Interrupt(t *testing.T) {
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("pauses with payload and resumes with input", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				var draftRuns, approveRuns atomic.Int32
+				addJoinTestNode(t, engine, "draft", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					draftRuns.Add(1)
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"draft"}}, Route: Goto("approve")}
+				})
+				addJoinTestNode(t, engine, "approve", approvalNode(&approveRuns))
+				addJoinTestNode(t, engine, "send", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"sent"}}, Route: Stop()}
+				})
+				if err := engine.StartAt("draft"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				paused, err := engine.Run(context.Background(), "interrupt-001", JoinTestState{})
+				if !errors.Is(err, ErrInterrupted) {
+					t.Fatalf("expected ErrInterrupted, got %v", err)
+				}
+				var interrupt *InterruptError
+				if !errors.As(err, &interrupt) {
+					t.Fatalf("expected *InterruptError, got %T", err)
+				}
+				if interrupt.RunID != "interrupt-001" || interrupt.NodeID != "approve" {
+					t.Errorf("interrupt = %+v, want run interrupt-001 at node approve", interrupt)
+				}
+				if interrupt.Payload != "approve draft?" {
+					t.Errorf("Payload = %v, want %q", interrupt.Payload, "approve draft?")
+				}
+				if len(paused.Values) != 1 || paused.Values[0] != "draft" {
+					t.Errorf("paused state Values = %v, want [draft]", paused.Values)
+				}
+
+				final, err := engine.Resume(context.Background(), interrupt.RunID, "yes")
+				if err != nil {
+					t.Fatalf("Resume failed: %v", err)
+				}
+
+				// Concurrent mode merges deltas in OrderKey order, so compare contents.
+				got := append([]string(nil), final.Values...)
+				sort.Strings(got)
+				want := []string{"approved:yes", "draft", "sent"}
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("final Values = %v, want %v in any order", final.Values, want)
+				}
+				if got := draftRuns.Load(); got != 1 {
+					t.Errorf("draft ran %d times, want 1", got)
+				}
+				if got := approveRuns.Load(); got != 2 {
+					t.Errorf("approve ran %d times, want 2", got)
+				}
+			})
+
+			t.Run("interrupt inside fan-out keeps join arrivals", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				var approveRuns, joinRuns atomic.Int32
+				var seen JoinTestState
+				addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Route: Many([]string{"approve", "b"})}
+				})
+				addJoinTestNode(t, engine, "approve", approvalNode(&approveRuns))
+				addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}, Route: Goto("send")}
+				})
+				addJoinTestNode(t, engine, "send", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+					joinRuns.Add(1)
+					seen = s
+					return NodeResult[JoinTestState]{Route: Stop()}
+				})
+				if err := engine.Join("send", "approve", "b"); err != nil {
+					t.Fatalf("Join failed: %v", err)
+				}
+				if err := engine.StartAt("fanout"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				_, err := engine.Run(context.Background(), "interrupt-join", JoinTestState{})
+				if !errors.Is(err, ErrInterrupted) {
+					t.Fatalf("expected ErrInterrupted, got %v", err)
+				}
+				if got := joinRuns.Load(); got != 0 {
+					t.Fatalf("join ran %d times before resume, want 0", got)
+				}
+
+				final, err := engine.Resume(context.Background(), "interrupt-join", true)
+				if err != nil {
+					t.Fatalf("Resume failed: %v", err)
+				}
+				if got := joinRuns.Load(); got != 1 {
+					t.Fatalf("join ran %d times, want 1", got)
+				}
+				if seen.Counter != 1 || len(seen.Values) != 1 || seen.Values[0] != "approved:true" {
+					t.Errorf("join saw %+v, want Counter 1 and Values [approved:true]", seen)
+				}
+				if final.Counter != 1 {
+					t.Errorf("final Counter = %d, want 1", final.Counter)
+				}
+			})
+		})
+	}
+}
+
+// TestEngine_ResumeNotInterrupted verifies Resume rejects runs that are not paused.
+func TestEngine_ResumeNotInterrupted(t *testing.T) {
+	engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 20})
+
+	var approveRuns atomic.Int32
+	addJoinTestNode(t, engine, "approve", approvalNode(&approveRuns))
+	addJoinTestNode(t, engine, "send", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Route: Stop()}
+	})
+	if err := engine.StartAt("approve"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+
+	expectNotInterrupted := func(t *testing.T, runID string) {
+		t.Helper()
+		_, err := engine.Resume(context.Background(), runID, "yes")
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "NOT_INTERRUPTED" {
+			t.Fatalf("expected NOT_INTERRUPTED, got %v", err)
+		}
+	}
+
+	t.Run("unknown run", func(t *testing.T) {
+		expectNotInterrupted(t, "never-started")
+	})
+
+	t.Run("interrupt is consumed by resume", func(t *testing.T) {
+		if _, err := engine.Run(context.Background(), "resume-once", JoinTestState{}); !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %v", err)
+		}
+		if _, err := engine.Resume(context.Background(), "resume-once", "yes"); err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		expectNotInterrupted(t, "resume-once")
+	})
+
+	t.Run("resume of an active run keeps the interrupt", func(t *testing.T) {
+		ctx := context.Background()
+		if _, err := engine.Run(ctx, "resume-active", JoinTestState{}); !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %v", err)
+		}
+
+		// Hold the run ID as an execution still in progress would
+		_, _, unregister, err := engine.register(ctx, "resume-active", 0, nil)
+		if err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+		_, err = engine.Resume(ctx, "resume-active", "yes")
+		unregister()
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "RUN_IN_PROGRESS" {
+			t.Fatalf("expected RUN_IN_PROGRESS, got %v", err)
+		}
+
+		final, err := engine.Resume(ctx, "resume-active", "yes")
+		if err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		if len(final.Values) != 1 || final.Values[0] != "approved:yes" {
+			t.Errorf("Values = %v, want [approved:yes]", final.Values)
+		}
+	})
+}
+
+// TestEngine_ResumeSharedStore verifies that engines in separate processes
+// sharing a store resume a paused run once.
+func TestEngine_ResumeSharedStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "interrupts.db")
+
+	// Each engine has its own store connection, as separate processes would
+	var approveRuns atomic.Int32
+	engines := make([]*Engine[JoinTestState], 2)
+	for i := range engines {
+		st, err := store.NewSQLiteStore[JoinTestState](path)
+		if err != nil {
+			t.Fatalf("NewSQLiteStore failed: %v", err)
+		}
+		t.Cleanup(func() { _ = st.Close() })
+
+		engines[i] = New(joinTestReducer, st, &mockEmitter{}, Options{MaxSteps: 20})
+		addJoinTestNode(t, engines[i], "approve", approvalNode(&approveRuns))
+		addJoinTestNode(t, engines[i], "send", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		if err := engines[i].StartAt("approve"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+	}
+
+	const runs = 10
+	for run := 0; run < runs; run++ {
+		runID := fmt.Sprintf("shared-%d", run)
+		if _, err := engines[0].Run(ctx, runID, JoinTestState{}); !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %v", err)
+		}
+
+		start := make(chan struct{})
+		errs := make([]error, len(engines))
+		var wg sync.WaitGroup
+		for i, engine := range engines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i] = engine.Resume(ctx, runID, "yes")
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		resumed := 0
+		for _, err := range errs {
+			var engineErr *EngineError
+			switch {
+			case err == nil:
+				resumed++
+			case !errors.As(err, &engineErr) || engineErr.Code != "NOT_INTERRUPTED":
+				t.Errorf("%s: expected NOT_INTERRUPTED, got %v", runID, err)
+			}
+		}
+		if resumed != 1 {
+			t.Errorf("%s resumed %d times, want once", runID, resumed)
+		}
+	}
+
+	// Each run interrupted once and was approved once
+	if got := approveRuns.Load(); got != 2*runs {
+		t.Errorf("approve ran %d times, want %d", got, 2*runs)
+	}
+}
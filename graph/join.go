@@ -0,0 +1,468 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// Join declares nodeID as a fan-in barrier that runs once after every listed
+// predecessor has routed to it.
+//
+// Without a join, each route into a node enqueues its own execution, so a node
+// reached from three parallel branches runs three times on three different
+// branch states. A join node instead waits until all predecessors have arrived
+// and then runs exactly once on their merged state.
+//
+// The merged state is built with the engine's Reducer in deterministic
+// OrderKey order: the state of the first arriving branch (lowest OrderKey) is
+// taken as the base, and the deltas that every other branch produced since the
+// fan-out are applied on top of it. Goroutine completion order never affects
+// the result.
+//
+// A predecessor started by a Sends route arrives once per sent branch; the
+// join waits for every branch and merges them in send order.
+//
+// Routing into a join node from a node that is not a declared predecessor is
+// an error, as is a predecessor (or sent branch) arriving twice before the
+// join fires. If the workflow drains while a join is still waiting, Run
+// returns ErrNoProgress. After a join fires its barrier resets, so joins
+// inside loops wait for a fresh round of arrivals.
+//
+// Joins apply in both sequential and concurrent mode. In sequential mode a
+// join that closes the outermost fan-out runs on the run's accumulated state.
+//
+// Parameters:
+//   - nodeID: ID of the fan-in node (cannot be empty)
+//   - predecessors: Node IDs that must all route to nodeID (at least one, unique)
+//
+// Node existence is not validated (lazy validation), matching Connect; use
+// Validate or Compile to check it before Run.
+//
+// Example:
+//
+//	engine.Add("fanout", fanoutNode)       // returns graph.Many([]string{"a", "b", "c"})
+//	engine.Add("a", a)                     // each branch returns graph.Goto("aggregate")
+//	engine.Add("b", b)
+//	engine.Add("c", c)
+//	engine.Add("aggregate", aggregateNode) // sees the merged state of a, b and c
+//	engine.Join("aggregate", "a", "b", "c")
+func (e *Engine[S]) Join(nodeID string, predecessors ...string) error {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if nodeID == "" {
+		return &EngineError{Message: "join node ID cannot be empty"}
+	}
+	if len(predecessors) == 0 {
+		return &EngineError{
+			Message: "join requires at least one predecessor: " + nodeID,
+			Code:    "INVALID_JOIN",
+		}
+	}
+
+	seen := make(map[string]bool, len(predecessors))
+	for _, pred := range predecessors {
+		if pred == "" {
+			return &EngineError{Message: "join predecessor ID cannot be empty"}
+		}
+		if seen[pred] {
+			return &EngineError{
+				Message: "duplicate join predecessor: " + pred,
+				Code:    "INVALID_JOIN",
+			}
+		}
+		seen[pred] = true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.compiled {
+		return compiledError("add join " + nodeID)
+	}
+	if _, exists := e.joins[nodeID]; exists {
+		return &EngineError{
+			Message: "duplicate join for node: " + nodeID,
+			Code:    "DUPLICATE_JOIN",
+		}
+	}
+
+	e.joins[nodeID] = append([]string(nil), predecessors...)
+	return nil
+}
+
+// joinArrival is a route into a join node that is waiting at the barrier.
+// The item's innermost fork level holds the deltas the branch contributes.
+type joinArrival[S any] struct {
+	item WorkItem[S]
+	pred string
+	slot ForkSlot // Branch of the innermost fan-out the arrival is on
+}
+
+// key identifies the arrival at its barrier: the predecessor, plus the branch
+// index for predecessors started by Sends.
+func (a joinArrival[S]) key() string {
+	if a.slot.Count == 0 {
+		return a.pred
+	}
+	return fmt.Sprintf("%s#%016x", a.pred, a.slot.Branch)
+}
+
+// expected returns the number of arrivals pred's barrier needs.
+func (a joinArrival[S]) expected() int {
+	if a.slot.Count == 0 {
+		return 1
+	}
+	return a.slot.Count
+}
+
+// joinBarriers tracks in-flight arrivals at every join node for a single run.
+// It is safe for concurrent use by the worker pool.
+type joinBarriers[S any] struct {
+	mu        sync.Mutex
+	specs     map[string][]string
+	pending   map[string]map[string]joinArrival[S]
+	reducer   Reducer[S]
+	conflicts *conflictTracker[S] // nil when conflicts are not detected
+}
+
+// newJoinBarriers snapshots the engine's join declarations for run runID.
+func (e *Engine[S]) newJoinBarriers(runID string) *joinBarriers[S] {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	specs := make(map[string][]string, len(e.joins))
+	for nodeID, preds := range e.joins {
+		specs[nodeID] = preds
+	}
+	return &joinBarriers[S]{
+		specs:     specs,
+		pending:   make(map[string]map[string]joinArrival[S]),
+		reducer:   e.reducer,
+		conflicts: e.newConflictTracker(runID),
+	}
+}
+
+// has reports whether nodeID is a declared join node.
+func (b *joinBarriers[S]) has(nodeID string) bool {
+	_, ok := b.specs[nodeID]
+	return ok
+}
+
+// arrive records a route into a join node. When the arrival completes the
+// barrier, the merged work item for the join node is returned with ready=true.
+// delta is the delta produced by the predecessor that routed here.
+//
+// Merging returns a *ConflictError when the engine's ConflictPolicy rejects
+// conflicting writes of the merged branches.
+func (b *joinBarriers[S]) arrive(next WorkItem[S], delta S) (WorkItem[S], bool, error) {
+	var zero WorkItem[S]
+
+	// A route that did not pass through a fan-out contributes only its own delta
+	if len(next.Forks) == 0 {
+		next.Forks = [][]S{{delta}}
+	}
+	arrival := joinArrival[S]{item: next, pred: next.ParentNodeID, slot: innermostSlot(next)}
+
+	preds := b.specs[next.NodeID]
+	from := next.ParentNodeID
+	declared := false
+	for _, pred := range preds {
+		if pred == from {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		return zero, false, &EngineError{
+			Message: fmt.Sprintf("node %s routed to join node %s but is not one of its predecessors %v", from, next.NodeID, preds),
+			Code:    "JOIN_UNEXPECTED_PREDECESSOR",
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	arrivals := b.pending[next.NodeID]
+	if arrivals == nil {
+		arrivals = make(map[string]joinArrival[S], len(preds))
+		b.pending[next.NodeID] = arrivals
+	}
+	if _, dup := arrivals[arrival.key()]; dup {
+		return zero, false, &EngineError{
+			Message: fmt.Sprintf("predecessor %s arrived twice at join node %s before it ran", arrival.key(), next.NodeID),
+			Code:    "JOIN_DUPLICATE_ARRIVAL",
+		}
+	}
+	arrivals[arrival.key()] = arrival
+
+	if len(missingArrivals(preds, arrivals)) > 0 {
+		return zero, false, nil
+	}
+
+	delete(b.pending, next.NodeID)
+	joined, err := mergeJoinArrivals(next.NodeID, arrivals, b.reducer, b.conflicts)
+	if err != nil {
+		return zero, false, err
+	}
+	return joined, true, nil
+}
+
+// arrivals returns the routes waiting at every join barrier, ordered by join
+// node and predecessor, so they can be persisted with a checkpoint frontier.
+func (b *joinBarriers[S]) arrivals() []WorkItem[S] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	joinIDs := make([]string, 0, len(b.pending))
+	for nodeID := range b.pending {
+		joinIDs = append(joinIDs, nodeID)
+	}
+	sort.Strings(joinIDs)
+
+	var items []WorkItem[S]
+	for _, nodeID := range joinIDs {
+		for _, a := range orderArrivals(b.specs[nodeID], b.pending[nodeID]) {
+			items = append(items, a.item)
+		}
+	}
+	return items
+}
+
+// orderArrivals returns arrivals grouped in predecessor declaration order, and
+// by branch index within a predecessor started by Sends.
+func orderArrivals[S any](preds []string, arrivals map[string]joinArrival[S]) []joinArrival[S] {
+	rank := make(map[string]int, len(preds))
+	for i, pred := range preds {
+		rank[pred] = i
+	}
+	ordered := make([]joinArrival[S], 0, len(arrivals))
+	for _, a := range arrivals {
+		ordered = append(ordered, a)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].pred != ordered[j].pred {
+			return rank[ordered[i].pred] < rank[ordered[j].pred]
+		}
+		return ordered[i].slot.Branch < ordered[j].slot.Branch
+	})
+	return ordered
+}
+
+// missingArrivals returns the predecessors that have not yet arrived at a
+// barrier, including predecessors started by Sends with branches still running.
+func missingArrivals[S any](preds []string, arrivals map[string]joinArrival[S]) []string {
+	arrived := make(map[string]int, len(preds))
+	expected := make(map[string]int, len(preds))
+	for _, a := range arrivals {
+		arrived[a.pred]++
+		expected[a.pred] = a.expected()
+	}
+
+	var missing []string
+	for _, pred := range preds {
+		if arrived[pred] == 0 || arrived[pred] < expected[pred] {
+			missing = append(missing, pred)
+		}
+	}
+	return missing
+}
+
+// restore re-registers join arrivals from a checkpoint frontier and returns
+// the remaining items, which are runnable. A frontier item is an arrival when
+// it targets a join node and was routed there by a predecessor rather than
+// released by the barrier.
+func (b *joinBarriers[S]) restore(items []WorkItem[S]) ([]WorkItem[S], error) {
+	runnable := make([]WorkItem[S], 0, len(items))
+	for _, item := range items {
+		if !b.has(item.NodeID) || item.ParentNodeID == "__join__" {
+			runnable = append(runnable, item)
+			continue
+		}
+		var noDelta S
+		joined, ready, err := b.arrive(item, noDelta)
+		if err != nil {
+			return nil, err
+		}
+		if ready {
+			runnable = append(runnable, joined)
+		}
+	}
+	return runnable, nil
+}
+
+// pendingErr reports joins that are still waiting for predecessors. It is
+// called once the frontier has drained, when no further arrivals are possible.
+func (b *joinBarriers[S]) pendingErr() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	joinIDs := make([]string, 0, len(b.pending))
+	for nodeID := range b.pending {
+		joinIDs = append(joinIDs, nodeID)
+	}
+	sort.Strings(joinIDs)
+
+	nodeID := joinIDs[0]
+	missing := missingArrivals(b.specs[nodeID], b.pending[nodeID])
+	return fmt.Errorf("%w: join node %s still waiting for %v", ErrNoProgress, nodeID, missing)
+}
+
+// mergeJoinArrivals folds the arrivals at a join into a single work item.
+//
+// Predecessors are ordered by the lowest OrderKey among their arrivals, and the
+// branches of a predecessor started by Sends by their index. The first
+// arrival's state is the base and the deltas each remaining branch recorded
+// since its fan-out are applied in order. The fork level opened by that
+// fan-out is closed, and every branch's deltas are carried into the enclosing
+// level so outer joins still see them.
+//
+// Conflicting writes of the branches are then resolved by conflicts, which is
+// nil when conflicts are not detected.
+func mergeJoinArrivals[S any](nodeID string, arrivals map[string]joinArrival[S], reducer Reducer[S], conflicts *conflictTracker[S]) (WorkItem[S], error) {
+	ordered := make([]joinArrival[S], 0, len(arrivals))
+	predKey := make(map[string]uint64, len(arrivals))
+	for _, a := range arrivals {
+		ordered = append(ordered, a)
+		if key, ok := predKey[a.pred]; !ok || a.item.OrderKey < key {
+			predKey[a.pred] = a.item.OrderKey
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if predKey[a.pred] != predKey[b.pred] {
+			return predKey[a.pred] < predKey[b.pred]
+		}
+		if a.pred != b.pred {
+			return a.pred < b.pred
+		}
+		return a.slot.Branch < b.slot.Branch
+	})
+
+	first := ordered[0].item
+	state := first.State
+	stepID := first.StepID
+	var branchDeltas []S
+	for i, a := range ordered {
+		deltas := branchDeltasOf(a)
+		branchDeltas = append(branchDeltas, deltas...)
+		if a.item.StepID > stepID {
+			stepID = a.item.StepID
+		}
+		if i == 0 {
+			continue // Base state already includes the first branch's deltas
+		}
+		for _, d := range deltas {
+			state = reducer(state, d)
+		}
+	}
+
+	state, err := conflicts.resolveJoin(nodeID, ordered, state)
+	if err != nil {
+		return WorkItem[S]{}, err
+	}
+
+	var forks [][]S
+	var slots []ForkSlot
+	if len(first.Forks) > 1 {
+		forks = copyForks(first.Forks[:len(first.Forks)-1])
+		top := len(forks) - 1
+		forks[top] = append(forks[top], branchDeltas...)
+		if len(first.ForkSlots) == len(first.Forks) {
+			slots = append([]ForkSlot(nil), first.ForkSlots[:top+1]...)
+		}
+	}
+
+	return WorkItem[S]{
+		StepID:       stepID,
+		OrderKey:     computeOrderKey("__join__:"+nodeID, 0),
+		NodeID:       nodeID,
+		State:        state,
+		Attempt:      0,
+		ParentNodeID: "__join__",
+		EdgeIndex:    0,
+		Forks:        forks,
+		ForkSlots:    slots,
+	}, nil
+}
+
+// branchDeltasOf returns the deltas an arrival contributes to a join: those
+// recorded since its innermost fan-out.
+func branchDeltasOf[S any](a joinArrival[S]) []S {
+	return a.item.Forks[len(a.item.Forks)-1]
+}
+
+// forkContinue returns the fork levels for a successor on the same branch,
+// recording delta in the innermost level.
+func forkContinue[S any](forks [][]S, delta S) [][]S {
+	if len(forks) == 0 {
+		return nil
+	}
+	next := copyForks(forks)
+	top := len(next) - 1
+	next[top] = append(next[top], delta)
+	return next
+}
+
+// forkBranch returns the fork levels for a fan-out branch: delta is recorded in
+// the current level and a new, empty level is opened for the branch.
+func forkBranch[S any](forks [][]S, delta S) [][]S {
+	next := forkContinue(forks, delta)
+	return append(next, nil)
+}
+
+// forkSlotsBranch returns the fork slots for a branch of a fan-out taken by
+// item, adding slot for the fork level the fan-out opens.
+func forkSlotsBranch[S any](item WorkItem[S], slot ForkSlot) []ForkSlot {
+	next := make([]ForkSlot, len(item.Forks), len(item.Forks)+1)
+	if len(item.ForkSlots) == len(item.Forks) {
+		copy(next, item.ForkSlots)
+	}
+	return append(next, slot)
+}
+
+// innermostSlot returns item's branch in its innermost fan-out.
+func innermostSlot[S any](item WorkItem[S]) ForkSlot {
+	if len(item.Forks) == 0 || len(item.ForkSlots) != len(item.Forks) {
+		return ForkSlot{}
+	}
+	return item.ForkSlots[len(item.ForkSlots)-1]
+}
+
+// fanoutKey identifies the fan-out started by item's node execution. The step
+// tells apart the same fan-out taken again in a loop.
+func fanoutKey[S any](item WorkItem[S]) uint64 {
+	return computeOrderKey(fmt.Sprintf("%s@%016x#%d", item.NodeID, item.OrderKey, item.StepID), 0)
+}
+
+// copyForks copies the fork level slices so sibling branches never share
+// backing arrays.
+func copyForks[S any](forks [][]S) [][]S {
+	next := make([][]S, len(forks))
+	for i, level := range forks {
+		next[i] = append([]S(nil), level...)
+	}
+	return next
+}
+
+// emitJoinReady emits a join_ready event when a join barrier completes.
+func (e *Engine[S]) emitJoinReady(runID string, item WorkItem[S]) {
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    "join_ready",
+		Meta: map[string]interface{}{
+			"order_key": item.OrderKey,
+		},
+	})
+}
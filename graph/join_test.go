@@ -0,0 +1,285 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// JoinTestState is the state type used by join barrier tests.
+type JoinTestState struct {
+	Values  []string
+	Counter int
+}
+
+func joinTestReducer(prev, delta JoinTestState) JoinTestState {
+	prev.Values = append(prev.Values, delta.Values...)
+	prev.Counter += delta.Counter
+	return prev
+}
+
+func newJoinTestEngine(t *testing.T) *Engine[JoinTestState] {
+	t.Helper()
+	st := store.NewMemStore[JoinTestState]()
+	return New(joinTestReducer, st, &mockEmitter{}, Options{MaxSteps: 50, MaxConcurrentNodes: 4})
+}
+
+func addJoinTestNode(t *testing.T, engine *Engine[JoinTestState], id string, node NodeFunc[JoinTestState]) {
+	t.Helper()
+	if err := engine.Add(id, node); err != nil {
+		t.Fatalf("Add(%s) failed: %v", id, err)
+	}
+}
+
+// TestEngine_Join verifies fan-in join nodes wait for all predecessors.
+func TestEngine_Join(t *testing.T) {
+	t.Run("join runs once on merged state of all branches", func(t *testing.T) {
+		engine := newJoinTestEngine(t)
+
+		var aggregateRuns atomic.Int32
+		var seen JoinTestState
+		var mu sync.Mutex
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{
+				Delta: JoinTestState{Values: []string{"fanout"}},
+				Route: Many([]string{"a", "b", "c"}),
+			}
+		})
+		for i, id := range []string{"a", "b", "c"} {
+			id := id
+			delay := time.Duration(30-10*i) * time.Millisecond // c finishes first, a last
+			addJoinTestNode(t, engine, id, func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+				time.Sleep(delay)
+				return NodeResult[JoinTestState]{
+					Delta: JoinTestState{Values: []string{id}, Counter: 1},
+					Route: Goto("aggregate"),
+				}
+			})
+		}
+		addJoinTestNode(t, engine, "aggregate", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+			aggregateRuns.Add(1)
+			mu.Lock()
+			seen = s
+			mu.Unlock()
+			return NodeResult[JoinTestState]{
+				Delta: JoinTestState{Values: []string{"aggregate"}},
+				Route: Stop(),
+			}
+		})
+
+		if err := engine.Join("aggregate", "a", "b", "c"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		final, err := engine.Run(context.Background(), "join-001", JoinTestState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		if got := aggregateRuns.Load(); got != 1 {
+			t.Fatalf("aggregate ran %d times, want 1", got)
+		}
+		if seen.Counter != 3 {
+			t.Errorf("aggregate saw Counter = %d, want 3", seen.Counter)
+		}
+		if len(seen.Values) != 4 || seen.Values[0] != "fanout" {
+			t.Fatalf("aggregate saw Values = %v, want fanout followed by a, b, c", seen.Values)
+		}
+		branches := append([]string(nil), seen.Values[1:]...)
+		sort.Strings(branches)
+		if branches[0] != "a" || branches[1] != "b" || branches[2] != "c" {
+			t.Errorf("aggregate saw branch values %v, want a, b, c", seen.Values[1:])
+		}
+		if final.Counter != 3 {
+			t.Errorf("final Counter = %d, want 3", final.Counter)
+		}
+	})
+
+	t.Run("merged state is deterministic across runs", func(t *testing.T) {
+		var first []string
+		for i := 0; i < 10; i++ {
+			engine := newJoinTestEngine(t)
+			var seen []string
+			addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+				return NodeResult[JoinTestState]{Route: Many([]string{"x", "y", "z"})}
+			})
+			for _, id := range []string{"x", "y", "z"} {
+				id := id
+				addJoinTestNode(t, engine, id, func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{
+						Delta: JoinTestState{Values: []string{id}},
+						Route: Goto("join"),
+					}
+				})
+			}
+			addJoinTestNode(t, engine, "join", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+				seen = s.Values
+				return NodeResult[JoinTestState]{Route: Stop()}
+			})
+			if err := engine.Join("join", "x", "y", "z"); err != nil {
+				t.Fatalf("Join failed: %v", err)
+			}
+			if err := engine.StartAt("fanout"); err != nil {
+				t.Fatalf("StartAt failed: %v", err)
+			}
+			if _, err := engine.Run(context.Background(), "join-determinism", JoinTestState{}); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if first == nil {
+				first = seen
+				continue
+			}
+			if len(seen) != len(first) {
+				t.Fatalf("run %d: join saw %v, first run saw %v", i, seen, first)
+			}
+			for j := range seen {
+				if seen[j] != first[j] {
+					t.Fatalf("run %d: join saw %v, first run saw %v", i, seen, first)
+				}
+			}
+		}
+	})
+
+	t.Run("multi-node branches contribute every delta", func(t *testing.T) {
+		engine := newJoinTestEngine(t)
+		var seen JoinTestState
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Many([]string{"a1", "b"})}
+		})
+		addJoinTestNode(t, engine, "a1", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}, Route: Goto("a2")}
+		})
+		addJoinTestNode(t, engine, "a2", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+			// a2 observes a1's delta on its branch.
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: s.Counter * 10}, Route: Goto("join")}
+		})
+		addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 100}, Route: Goto("join")}
+		})
+		addJoinTestNode(t, engine, "join", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+			seen = s
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+
+		if err := engine.Join("join", "a2", "b"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if _, err := engine.Run(context.Background(), "join-chain", JoinTestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// a1 (1) + a2 (10) + b (100)
+		if seen.Counter != 111 {
+			t.Errorf("join saw Counter = %d, want 111", seen.Counter)
+		}
+	})
+
+	t.Run("missing predecessor reports no progress", func(t *testing.T) {
+		engine := newJoinTestEngine(t)
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Many([]string{"a", "b"})}
+		})
+		addJoinTestNode(t, engine, "a", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Goto("join")}
+		})
+		addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		addJoinTestNode(t, engine, "join", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+
+		if err := engine.Join("join", "a", "b"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		_, err := engine.Run(context.Background(), "join-missing", JoinTestState{})
+		if !errors.Is(err, ErrNoProgress) {
+			t.Fatalf("expected ErrNoProgress, got %v", err)
+		}
+	})
+
+	t.Run("undeclared predecessor is rejected", func(t *testing.T) {
+		engine := newJoinTestEngine(t)
+
+		addJoinTestNode(t, engine, "start", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Goto("join")}
+		})
+		addJoinTestNode(t, engine, "join", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+
+		if err := engine.Join("join", "a", "b"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if err := engine.StartAt("start"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		_, err := engine.Run(context.Background(), "join-undeclared", JoinTestState{})
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "JOIN_UNEXPECTED_PREDECESSOR" {
+			t.Fatalf("expected JOIN_UNEXPECTED_PREDECESSOR, got %v", err)
+		}
+	})
+}
+
+// TestEngine_JoinValidation verifies Join argument validation.
+func TestEngine_JoinValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodeID string
+		preds  []string
+		code   string
+	}{
+		{name: "empty node ID", nodeID: "", preds: []string{"a"}},
+		{name: "no predecessors", nodeID: "join", preds: nil, code: "INVALID_JOIN"},
+		{name: "empty predecessor", nodeID: "join", preds: []string{"a", ""}},
+		{name: "duplicate predecessor", nodeID: "join", preds: []string{"a", "a"}, code: "INVALID_JOIN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newJoinTestEngine(t)
+			err := engine.Join(tt.nodeID, tt.preds...)
+			var engineErr *EngineError
+			if !errors.As(err, &engineErr) {
+				t.Fatalf("expected EngineError, got %v", err)
+			}
+			if engineErr.Code != tt.code {
+				t.Errorf("expected code %q, got %q", tt.code, engineErr.Code)
+			}
+		})
+	}
+
+	t.Run("duplicate join", func(t *testing.T) {
+		engine := newJoinTestEngine(t)
+		if err := engine.Join("join", "a"); err != nil {
+			t.Fatalf("first Join failed: %v", err)
+		}
+		err := engine.Join("join", "b")
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "DUPLICATE_JOIN" {
+			t.Fatalf("expected DUPLICATE_JOIN, got %v", err)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dshills/langgraph-go/graph/store"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -32,13 +33,25 @@ import (
 // Use: Identify flaky nodes and error patterns.
 //
 // 5. merge_conflicts_total (counter): Concurrent state merge conflicts detected.
-// Labels: run_id, conflict_type.
+// Labels: run_id, conflict_type, field.
 // Use: Monitor determinism violations in concurrent execution.
 //
 // 6. backpressure_events_total (counter): Queue saturation events triggering backpressure.
 // Labels: run_id, reason.
 // Use: Track when execution is throttled due to resource limits.
 //
+// 7. pruned_records_total (counter): Store records deleted by retention policies.
+// Labels: kind (runs, steps, checkpoints, idempotency_keys, events).
+// Use: Verify retention keeps up with the history workflows write.
+//
+// 8. node_cache_lookups_total (counter): Cache lookups of nodes with a CachePolicy.
+// Labels: node_id, result (hit, miss).
+// Use: Measure how much work node result caching saves.
+//
+// 9. resource_wait_ms (histogram): Time queued nodes waited for a resource pool slot.
+// Labels: pool.
+// Use: Size Options.ResourcePools and spot saturated pools.
+//
 // Usage:
 //
 // // Create metrics with custom registry.
@@ -65,12 +78,15 @@ type PrometheusMetrics struct {
 	queueDepth    prometheus.Gauge
 
 	// Histogram metrics (distribution observations).
-	stepLatency *prometheus.HistogramVec
+	stepLatency  *prometheus.HistogramVec
+	resourceWait *prometheus.HistogramVec
 
 	// Counter metrics (cumulative totals).
 	retries        *prometheus.CounterVec
 	mergeConflicts *prometheus.CounterVec
 	backpressure   *prometheus.CounterVec
+	pruned         *prometheus.CounterVec
+	cacheLookups   *prometheus.CounterVec
 
 	// Registry holds all registered metrics.
 	registry prometheus.Registerer
@@ -149,7 +165,7 @@ func NewPrometheusMetrics(registry prometheus.Registerer) *PrometheusMetrics {
 		Namespace: "langgraph",
 		Name:      "merge_conflicts_total",
 		Help:      "Concurrent state merge conflicts detected during parallel execution",
-	}, []string{"run_id", "conflict_type"}) // conflict_type: reducer_error, state_divergence
+	}, []string{"run_id", "conflict_type", "field"}) // conflict_type: the ConflictPolicy, or reducer_error, state_divergence
 
 	// 6. backpressure_events_total counter (T032).
 	pm.backpressure = factory.NewCounterVec(prometheus.CounterOpts{
@@ -158,6 +174,28 @@ func NewPrometheusMetrics(registry prometheus.Registerer) *PrometheusMetrics {
 		Help:      "Queue saturation events where execution was throttled due to resource limits",
 	}, []string{"run_id", "reason"}) // reason: queue_full, max_concurrent, timeout
 
+	// 7. pruned_records_total counter.
+	pm.pruned = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "langgraph",
+		Name:      "pruned_records_total",
+		Help:      "Store records deleted by retention policies",
+	}, []string{"kind"}) // kind: runs, steps, checkpoints, idempotency_keys, events
+
+	// 8. node_cache_lookups_total counter.
+	pm.cacheLookups = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "langgraph",
+		Name:      "node_cache_lookups_total",
+		Help:      "Cache lookups of nodes with a CachePolicy",
+	}, []string{"node_id", "result"}) // result: hit, miss
+
+	// 9. resource_wait_ms histogram.
+	pm.resourceWait = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "langgraph",
+		Name:      "resource_wait_ms",
+		Help:      "Time queued nodes waited for a slot in a resource pool, in milliseconds",
+		Buckets:   []float64{1, 5, 10, 50, 100, 500, 1000, 5000, 10000}, // 1ms to 10s
+	}, []string{"pool"})
+
 	return pm
 }
 
@@ -251,24 +289,31 @@ func (pm *PrometheusMetrics) UpdateInflightNodes(count int) {
 
 // IncrementMergeConflicts (T038) increments the merge conflict counter.
 //
-// This updates the merge_conflicts_total counter with labels for run_id and conflict_type.
-// Use this to detect determinism violations or reducer errors in concurrent execution.
+// This updates the merge_conflicts_total counter with labels for run_id, conflict_type.
+// and field. The counter is incremented once per affected field, or once with an empty.
+// field label when no fields are given. The engine reports field conflicts detected.
+// under a ConflictPolicy with the policy name as the conflict type.
 //
 // Parameters:
 // - runID: Unique workflow execution identifier.
-// - conflictType: Type of conflict ("reducer_error", "state_divergence").
+// - conflictType: Type of conflict ("fail", "last_writer_wins", "crdt", "reducer_error").
+// - fields: State fields affected by the conflict.
 //
 // Example:
 //
-// if err := reducer(prev, delta); err != nil {.
-// metrics.IncrementMergeConflicts(runID, "reducer_error").
-// }.
-func (pm *PrometheusMetrics) IncrementMergeConflicts(runID, conflictType string) {
+// metrics.IncrementMergeConflicts(runID, "last_writer_wins", "Summary", "Score").
+func (pm *PrometheusMetrics) IncrementMergeConflicts(runID, conflictType string, fields ...string) {
 	if !pm.enabled {
 		return
 	}
 
-	pm.mergeConflicts.WithLabelValues(runID, conflictType).Inc()
+	if len(fields) == 0 {
+		pm.mergeConflicts.WithLabelValues(runID, conflictType, "").Inc()
+		return
+	}
+	for _, field := range fields {
+		pm.mergeConflicts.WithLabelValues(runID, conflictType, field).Inc()
+	}
 }
 
 // IncrementBackpressure (T039) increments the backpressure event counter.
@@ -295,6 +340,65 @@ func (pm *PrometheusMetrics) IncrementBackpressure(runID, reason string) {
 	pm.backpressure.WithLabelValues(runID, reason).Inc()
 }
 
+// RecordPruned adds the records deleted by a retention pass to the
+// pruned_records_total counter, labeled by kind.
+//
+// Parameters:
+// - result: Counts returned by store.Pruner.Prune.
+//
+// Example:
+//
+// result, err := pruner.Prune(ctx, policy).
+// metrics.RecordPruned(result).
+func (pm *PrometheusMetrics) RecordPruned(result store.PruneResult) {
+	if !pm.enabled {
+		return
+	}
+
+	pm.pruned.WithLabelValues("runs").Add(float64(result.Runs))
+	pm.pruned.WithLabelValues("steps").Add(float64(result.Steps))
+	pm.pruned.WithLabelValues("checkpoints").Add(float64(result.Checkpoints))
+	pm.pruned.WithLabelValues("idempotency_keys").Add(float64(result.IdempotencyKeys))
+	pm.pruned.WithLabelValues("events").Add(float64(result.Events))
+}
+
+// IncrementCacheLookups increments the node_cache_lookups_total counter when a
+// node with a CachePolicy looks its result up.
+//
+// Parameters:
+// - nodeID: Node whose result was looked up.
+// - result: Lookup outcome ("hit", "miss").
+//
+// Example:
+//
+// metrics.IncrementCacheLookups("embed", "hit").
+func (pm *PrometheusMetrics) IncrementCacheLookups(nodeID, result string) {
+	if !pm.enabled {
+		return
+	}
+
+	pm.cacheLookups.WithLabelValues(nodeID, result).Inc()
+}
+
+// RecordResourceWait records how long a queued node waited for a slot in a
+// resource pool in the resource_wait_ms histogram. Nodes admitted without
+// waiting record 0.
+//
+// Parameters:
+// - pool: Resource pool name from Options.ResourcePools.
+// - wait: Time from when the node was first blocked until it took its slot.
+//
+// Example:
+//
+// metrics.RecordResourceWait("openai", 250*time.Millisecond).
+func (pm *PrometheusMetrics) RecordResourceWait(pool string, wait time.Duration) {
+	if !pm.enabled {
+		return
+	}
+
+	pm.resourceWait.WithLabelValues(pool).Observe(float64(wait.Milliseconds()))
+}
+
 // Disable temporarily disables metric recording (useful for testing).
 func (pm *PrometheusMetrics) Disable() {
 	pm.mu.Lock()
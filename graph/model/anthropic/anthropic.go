@@ -43,6 +43,7 @@ type ChatModel struct {
 // This allows for easy mocking in tests.
 type anthropicClient interface {
 	createMessage(ctx context.Context, systemPrompt string, messages []model.Message, tools []model.ToolSpec) (model.ChatOut, error)
+	streamMessage(ctx context.Context, systemPrompt string, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error)
 }
 
 // NewChatModel creates a new Anthropic ChatModel.
@@ -99,6 +100,37 @@ func (m *ChatModel) Chat(ctx context.Context, messages []model.Message, tools []
 	return out, nil
 }
 
+// ChatStream implements the model.StreamingChatModel interface.
+//
+// Streams the response from Anthropic's API, calling onChunk for each text and
+// tool-call delta as it arrives. System prompts and errors are handled as in Chat.
+//
+// Returns:
+//   - ChatOut with the complete Text and ToolCalls
+//   - Error for API failures or the error returned by onChunk
+func (m *ChatModel) ChatStream(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Check context cancellation
+	if ctx.Err() != nil {
+		return model.ChatOut{}, ctx.Err()
+	}
+
+	// Extract system prompt (Anthropic uses separate system parameter)
+	systemPrompt, conversationMessages := extractSystemPrompt(messages)
+
+	// Stream from Anthropic API
+	out, err := m.client.streamMessage(ctx, systemPrompt, conversationMessages, tools, onChunk)
+	if err != nil {
+		// Translate Anthropic errors to common format
+		var anthropicErr *anthropicError
+		if errors.As(err, &anthropicErr) {
+			return model.ChatOut{}, translateAnthropicError(anthropicErr)
+		}
+		return model.ChatOut{}, err
+	}
+
+	return out, nil
+}
+
 // extractSystemPrompt separates the system message from conversation messages.
 // Anthropic's API expects system prompts as a separate parameter, not in messages array.
 func extractSystemPrompt(messages []model.Message) (string, []model.Message) {
@@ -152,6 +184,54 @@ func (c *defaultClient) createMessage(ctx context.Context, systemPrompt string,
 	// Create Anthropic client
 	client := anthropicsdk.NewClient(option.WithAPIKey(c.apiKey))
 
+	// Call Anthropic API
+	resp, err := client.Messages.New(ctx, c.messageParams(systemPrompt, messages, tools))
+	if err != nil {
+		return model.ChatOut{}, fmt.Errorf("anthropic API error: %w", err)
+	}
+
+	// Convert response to our format (resp is already a pointer)
+	return convertResponse(resp), nil
+}
+
+func (c *defaultClient) streamMessage(ctx context.Context, systemPrompt string, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Validate API key
+	if c.apiKey == "" {
+		return model.ChatOut{}, errors.New("anthropic API key is required")
+	}
+
+	// Create Anthropic client
+	client := anthropicsdk.NewClient(option.WithAPIKey(c.apiKey))
+
+	// Stream the message, accumulating events into the full response
+	stream := client.Messages.NewStreaming(ctx, c.messageParams(systemPrompt, messages, tools))
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	var message anthropicsdk.Message
+	converter := newChunkConverter()
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return model.ChatOut{}, fmt.Errorf("anthropic stream error: %w", err)
+		}
+
+		for _, delta := range converter.convert(event) {
+			if err := onChunk(delta); err != nil {
+				return model.ChatOut{}, err
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return model.ChatOut{}, fmt.Errorf("anthropic API error: %w", err)
+	}
+
+	return convertResponse(&message), nil
+}
+
+// messageParams builds the request parameters shared by createMessage and streamMessage.
+func (c *defaultClient) messageParams(systemPrompt string, messages []model.Message, tools []model.ToolSpec) anthropicsdk.MessageNewParams {
 	// Convert messages to Anthropic format
 	anthropicMessages := convertMessages(messages)
 
@@ -174,14 +254,7 @@ func (c *defaultClient) createMessage(ctx context.Context, systemPrompt string,
 		params.Tools = convertTools(tools)
 	}
 
-	// Call Anthropic API
-	resp, err := client.Messages.New(ctx, params)
-	if err != nil {
-		return model.ChatOut{}, fmt.Errorf("anthropic API error: %w", err)
-	}
-
-	// Convert response to our format (resp is already a pointer)
-	return convertResponse(resp), nil
+	return params
 }
 
 // convertMessages converts our Message format to Anthropic's format.
@@ -271,6 +344,64 @@ func convertResponse(resp *anthropicsdk.Message) model.ChatOut {
 	return out
 }
 
+// chunkConverter converts Anthropic stream events to ChatChunk deltas.
+//
+// Anthropic indexes content blocks rather than tool calls, so the converter
+// renumbers tool-use blocks by their position among the response's tool calls.
+// A newline is streamed between text blocks, matching convertResponse.
+type chunkConverter struct {
+	toolIndex map[int64]int
+	sawText   bool
+}
+
+// newChunkConverter creates a converter for a single streamed message.
+func newChunkConverter() *chunkConverter {
+	return &chunkConverter{toolIndex: make(map[int64]int)}
+}
+
+// convert returns the deltas carried by event, if any.
+func (c *chunkConverter) convert(event anthropicsdk.MessageStreamEventUnion) []model.ChatChunk {
+	switch e := event.AsAny().(type) {
+	case anthropicsdk.ContentBlockStartEvent:
+		switch e.ContentBlock.Type {
+		case "text":
+			var deltas []model.ChatChunk
+			if c.sawText {
+				deltas = append(deltas, model.ChatChunk{Text: "\n"})
+			}
+			c.sawText = true
+			if e.ContentBlock.Text != "" {
+				deltas = append(deltas, model.ChatChunk{Text: e.ContentBlock.Text})
+			}
+			return deltas
+
+		case "tool_use":
+			index := len(c.toolIndex)
+			c.toolIndex[e.Index] = index
+			return []model.ChatChunk{{
+				ToolCall: &model.ToolCallChunk{Index: index, Name: e.ContentBlock.Name},
+			}}
+		}
+
+	case anthropicsdk.ContentBlockDeltaEvent:
+		switch e.Delta.Type {
+		case "text_delta":
+			return []model.ChatChunk{{Text: e.Delta.Text}}
+
+		case "input_json_delta":
+			index, ok := c.toolIndex[e.Index]
+			if !ok || e.Delta.PartialJSON == "" {
+				return nil
+			}
+			return []model.ChatChunk{{
+				ToolCall: &model.ToolCallChunk{Index: index, Arguments: e.Delta.PartialJSON},
+			}}
+		}
+	}
+
+	return nil
+}
+
 // convertToolInput converts Anthropic's tool input to our format.
 func convertToolInput(input interface{}) map[string]interface{} {
 	if input == nil {
@@ -2,9 +2,12 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
+	anthropicsdk "github.com/anthropics/anthropic-sdk-go"
 	"github.com/dshills/langgraph-go/graph/model"
 )
 
@@ -337,6 +340,95 @@ func TestAnthropicChatModel_MessageConversion(t *testing.T) {
 }
 
 // Mock Anthropic client for testing.
+// TestAnthropicChatModel_ChatStream verifies streaming responses.
+func TestAnthropicChatModel_ChatStream(t *testing.T) {
+	t.Run("streams chunks with system prompt extracted", func(t *testing.T) {
+		mockClient := &mockAnthropicClient{response: "Hello from Claude"}
+		m := &ChatModel{client: mockClient, modelName: "claude-3-opus-20240229"}
+
+		messages := []model.Message{
+			{Role: model.RoleSystem, Content: "You are helpful."},
+			{Role: model.RoleUser, Content: "Hi!"},
+		}
+
+		var chunks []string
+		out, err := m.ChatStream(context.Background(), messages, nil, func(chunk model.ChatChunk) error {
+			chunks = append(chunks, chunk.Text)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if strings.Join(chunks, "") != out.Text || len(chunks) != 3 {
+			t.Errorf("expected 3 chunks forming %q, got %q", out.Text, chunks)
+		}
+		if mockClient.systemPrompt != "You are helpful." {
+			t.Errorf("expected system prompt extracted, got %q", mockClient.systemPrompt)
+		}
+		if len(mockClient.lastMessages) != 1 {
+			t.Errorf("expected 1 conversation message, got %d", len(mockClient.lastMessages))
+		}
+	})
+
+	t.Run("translates stream errors", func(t *testing.T) {
+		mockClient := &mockAnthropicClient{
+			err: &anthropicError{Type: "overloaded_error", Message: "Service overloaded"},
+		}
+		m := &ChatModel{client: mockClient, modelName: "claude-3-opus-20240229"}
+
+		_, err := m.ChatStream(context.Background(), []model.Message{{Role: model.RoleUser, Content: "Hi"}}, nil, func(model.ChatChunk) error { return nil })
+
+		var anthropicErr *anthropicError
+		if !errors.As(err, &anthropicErr) || anthropicErr.Type != "overloaded_error" {
+			t.Errorf("expected overloaded_error, got %v", err)
+		}
+	})
+}
+
+// TestAnthropicChatModel_ChunkConverter verifies stream event conversion.
+func TestAnthropicChatModel_ChunkConverter(t *testing.T) {
+	events := []string{
+		`{"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+		`{"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Let me check"}}`,
+		`{"type": "content_block_start", "index": 1, "content_block": {"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {}}}`,
+		`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "{\"city\": "}}`,
+		`{"type": "content_block_delta", "index": 1, "delta": {"type": "input_json_delta", "partial_json": "\"Paris\"}"}}`,
+		`{"type": "content_block_start", "index": 2, "content_block": {"type": "text", "text": ""}}`,
+		`{"type": "content_block_delta", "index": 2, "delta": {"type": "text_delta", "text": "Done"}}`,
+		`{"type": "message_stop"}`,
+	}
+
+	converter := newChunkConverter()
+	var text, args strings.Builder
+	var toolName string
+	for _, raw := range events {
+		var event anthropicsdk.MessageStreamEventUnion
+		if err := json.Unmarshal([]byte(raw), &event); err != nil {
+			t.Fatalf("failed to decode event: %v", err)
+		}
+		for _, chunk := range converter.convert(event) {
+			text.WriteString(chunk.Text)
+			if chunk.ToolCall != nil {
+				if chunk.ToolCall.Index != 0 {
+					t.Errorf("expected tool call index 0, got %d", chunk.ToolCall.Index)
+				}
+				if chunk.ToolCall.Name != "" {
+					toolName = chunk.ToolCall.Name
+				}
+				args.WriteString(chunk.ToolCall.Arguments)
+			}
+		}
+	}
+
+	if text.String() != "Let me check\nDone" {
+		t.Errorf("expected text blocks joined by newline, got %q", text.String())
+	}
+	if toolName != "get_weather" || args.String() != `{"city": "Paris"}` {
+		t.Errorf("unexpected tool call %q with arguments %q", toolName, args.String())
+	}
+}
+
 type mockAnthropicClient struct {
 	response     string
 	toolCalls    []model.ToolCall
@@ -361,4 +453,20 @@ func (m *mockAnthropicClient) createMessage(_ context.Context, systemPrompt stri
 	}, nil
 }
 
+// streamMessage streams the mock response one word per chunk.
+func (m *mockAnthropicClient) streamMessage(ctx context.Context, systemPrompt string, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	out, err := m.createMessage(ctx, systemPrompt, messages, tools)
+	if err != nil {
+		return model.ChatOut{}, err
+	}
+
+	for _, word := range strings.SplitAfter(out.Text, " ") {
+		if err := onChunk(model.ChatChunk{Text: word}); err != nil {
+			return model.ChatOut{}, err
+		}
+	}
+
+	return out, nil
+}
+
 // anthropicError is imported from the anthropic package
@@ -1,7 +1,10 @@
 // Package model provides LLM integration adapters.
 package model
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // ChatModel defines the interface for LLM chat providers.
 //
@@ -79,6 +82,107 @@ type ChatModel interface {
 	Chat(ctx context.Context, messages []Message, tools []ToolSpec) (ChatOut, error)
 }
 
+// StreamingChatModel is a ChatModel that can stream its response as it is generated.
+//
+// Streaming is optional: adapters that support it implement ChatStream in.
+// addition to Chat. Use StreamChat to stream from any ChatModel, falling back.
+// to Chat for models that cannot stream.
+//
+// Example forwarding tokens to a graph stream from inside a node:
+//
+//	out, err := model.StreamChat(ctx, m, messages, nil, func(chunk model.ChatChunk) error {
+//	    graph.EmitCustom(ctx, chunk)
+//	    return nil
+//	})
+type StreamingChatModel interface {
+	ChatModel
+
+	// ChatStream sends messages to the LLM and calls onChunk for each text or.
+	// tool-call delta, in the order the provider produces them.
+	//
+	// Returns:
+	// - ChatOut: The complete response, as Chat would have returned it.
+	// - error: Provider errors, context cancellation, or the error returned by onChunk.
+	//
+	// If onChunk returns an error, streaming stops and that error is returned.
+	ChatStream(ctx context.Context, messages []Message, tools []ToolSpec, onChunk func(ChatChunk) error) (ChatOut, error)
+}
+
+// ChatChunk is one incremental piece of a streamed LLM response.
+//
+// A chunk carries either a fragment of the response text or a fragment of a.
+// tool call. Concatenating every Text in order yields ChatOut.Text.
+type ChatChunk struct {
+	// Text is the next fragment of the response text.
+	// Empty for tool-call chunks.
+	Text string
+
+	// ToolCall is set when the chunk extends a tool call.
+	ToolCall *ToolCallChunk
+}
+
+// ToolCallChunk is a partial tool call in a streamed response.
+//
+// Chunks with the same Index belong to the same call. Concatenating their.
+// Arguments yields the call's JSON-encoded input.
+type ToolCallChunk struct {
+	// Index is the position of the call among the response's tool calls.
+	Index int
+
+	// Name identifies the tool. Set on the first chunk of each call.
+	Name string
+
+	// Arguments is the next fragment of the call's JSON-encoded input.
+	Arguments string
+}
+
+// StreamChat streams a response from m when it implements StreamingChatModel.
+//
+// For other models StreamChat calls Chat and delivers the complete response.
+// to onChunk as one text chunk followed by one chunk per tool call, so callers.
+// can handle both kinds of model the same way.
+func StreamChat(ctx context.Context, m ChatModel, messages []Message, tools []ToolSpec, onChunk func(ChatChunk) error) (ChatOut, error) {
+	if sm, ok := m.(StreamingChatModel); ok {
+		return sm.ChatStream(ctx, messages, tools, onChunk)
+	}
+
+	out, err := m.Chat(ctx, messages, tools)
+	if err != nil {
+		return ChatOut{}, err
+	}
+	if err := EmitChunks(out, onChunk); err != nil {
+		return ChatOut{}, err
+	}
+	return out, nil
+}
+
+// EmitChunks delivers a complete response to onChunk as a text chunk followed.
+// by one chunk per tool call with its input encoded as JSON.
+//
+// StreamChat uses it for models that cannot stream, and replayed calls use it.
+// to stream a recorded response (see graph.RecordedChatModel).
+func EmitChunks(out ChatOut, onChunk func(ChatChunk) error) error {
+	if out.Text != "" {
+		if err := onChunk(ChatChunk{Text: out.Text}); err != nil {
+			return err
+		}
+	}
+	for i, call := range out.ToolCalls {
+		var args string
+		if call.Input != nil {
+			data, err := json.Marshal(call.Input)
+			if err != nil {
+				return err
+			}
+			args = string(data)
+		}
+		if err := onChunk(ChatChunk{ToolCall: &ToolCallChunk{Index: i, Name: call.Name, Arguments: args}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Message represents a single message in an LLM conversation.
 //
 // Messages are the fundamental unit of communication with LLM providers.
@@ -169,6 +273,20 @@ type ToolSpec struct {
 	Schema map[string]interface{}
 }
 
+// ChatRequest describes one Chat call: the conversation and tools sent to the.
+// model.
+//
+// graph.RecordedChatModel records it as the request of its Chat and ChatStream.
+// calls for deterministic replay (see graph.Recorded), so a replayed call.
+// matches its recording only when the conversation is unchanged.
+type ChatRequest struct {
+	// Messages is the conversation sent to the model.
+	Messages []Message
+
+	// Tools are the tools offered to the model (nil if none).
+	Tools []ToolSpec
+}
+
 // ChatOut represents the output from an LLM chat completion.
 //
 // LLMs can respond with:
@@ -416,6 +416,64 @@ func TestChatModel_Interface(t *testing.T) {
 	})
 }
 
+// TestStreamChat verifies streaming with and without StreamingChatModel support.
+func TestStreamChat(t *testing.T) {
+	messages := []Message{{Role: RoleUser, Content: "Hi"}}
+
+	t.Run("uses ChatStream when available", func(t *testing.T) {
+		var _ StreamingChatModel = &MockChatModel{}
+		mock := &MockChatModel{Responses: []ChatOut{{Text: "streamed reply"}}}
+
+		var chunks []string
+		out, err := StreamChat(context.Background(), mock, messages, nil, func(chunk ChatChunk) error {
+			chunks = append(chunks, chunk.Text)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(chunks) != 2 || out.Text != "streamed reply" {
+			t.Errorf("expected 2 chunks and full text, got %q and %q", chunks, out.Text)
+		}
+	})
+
+	t.Run("falls back to Chat for non-streaming models", func(t *testing.T) {
+		m := &testChatModel{response: ChatOut{
+			Text:      "whole reply",
+			ToolCalls: []ToolCall{{Name: "search", Input: map[string]interface{}{"query": "go"}}},
+		}}
+
+		var chunks []ChatChunk
+		out, err := StreamChat(context.Background(), m, messages, nil, func(chunk ChatChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if out.Text != "whole reply" {
+			t.Errorf("expected full response, got %q", out.Text)
+		}
+		if len(chunks) != 2 || chunks[0].Text != "whole reply" {
+			t.Fatalf("expected text chunk then tool-call chunk, got %+v", chunks)
+		}
+		call := chunks[1].ToolCall
+		if call == nil || call.Name != "search" || call.Arguments != `{"query":"go"}` {
+			t.Errorf("unexpected tool-call chunk: %+v", call)
+		}
+	})
+
+	t.Run("returns onChunk error", func(t *testing.T) {
+		m := &testChatModel{response: ChatOut{Text: "reply"}}
+		stop := errors.New("stop")
+
+		_, err := StreamChat(context.Background(), m, messages, nil, func(ChatChunk) error { return stop })
+		if !errors.Is(err, stop) {
+			t.Errorf("expected onChunk error, got %v", err)
+		}
+	})
+}
+
 // testChatModel is a simple ChatModel implementation for testing (T126).
 type testChatModel struct {
 	response ChatOut
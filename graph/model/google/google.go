@@ -3,11 +3,13 @@ package google
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/dshills/langgraph-go/graph/model"
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -48,6 +50,7 @@ type ChatModel struct {
 // This allows for easy mocking in tests.
 type googleClient interface {
 	generateContent(ctx context.Context, messages []model.Message, tools []model.ToolSpec) (model.ChatOut, error)
+	streamContent(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error)
 }
 
 // NewChatModel creates a new Google ChatModel.
@@ -101,6 +104,35 @@ func (m *ChatModel) Chat(ctx context.Context, messages []model.Message, tools []
 	return out, nil
 }
 
+// ChatStream implements the model.StreamingChatModel interface.
+//
+// Streams the response from Google's Gemini API, calling onChunk for each text
+// delta as it arrives. Gemini returns function calls whole, so each tool call is
+// delivered as a single chunk. Safety filter blocks are handled as in Chat.
+//
+// Returns:
+//   - ChatOut with the complete Text and ToolCalls
+//   - Error for authentication failures, safety blocks, API errors, or the error returned by onChunk
+func (m *ChatModel) ChatStream(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Check context cancellation
+	if ctx.Err() != nil {
+		return model.ChatOut{}, ctx.Err()
+	}
+
+	// Stream from Google API
+	out, err := m.client.streamContent(ctx, messages, tools, onChunk)
+	if err != nil {
+		// Handle safety filter errors specially
+		var safetyErr *SafetyFilterError
+		if errors.As(err, &safetyErr) {
+			return model.ChatOut{}, handleSafetyFilterError(safetyErr)
+		}
+		return model.ChatOut{}, err
+	}
+
+	return out, nil
+}
+
 // handleSafetyFilterError wraps safety filter errors with user-friendly context.
 //
 // Google's safety filters can block content in several categories:
@@ -160,6 +192,54 @@ func (c *defaultClient) generateContent(ctx context.Context, messages []model.Me
 	return convertResponse(resp), nil
 }
 
+func (c *defaultClient) streamContent(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Validate API key
+	if c.apiKey == "" {
+		return model.ChatOut{}, errors.New("google API key is required")
+	}
+
+	// Create Google Gemini client
+	client, err := genai.NewClient(ctx, option.WithAPIKey(c.apiKey))
+	if err != nil {
+		return model.ChatOut{}, fmt.Errorf("failed to create Google client: %w", err)
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			// Log error but don't override return error
+			_ = closeErr
+		}
+	}()
+
+	// Create generative model
+	genModel := client.GenerativeModel(c.modelName)
+
+	// Add tools if provided
+	if len(tools) > 0 {
+		genModel.Tools = convertTools(tools)
+	}
+
+	// Stream content, accumulating each response into the full output
+	iter := genModel.GenerateContentStream(ctx, convertMessages(messages)...)
+	var out model.ChatOut
+	for {
+		resp, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return model.ChatOut{}, fmt.Errorf("google API error: %w", err)
+		}
+
+		for _, delta := range accumulateResponse(&out, resp) {
+			if err := onChunk(delta); err != nil {
+				return model.ChatOut{}, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
 // convertMessages converts our Message format to Google's format.
 func convertMessages(messages []model.Message) []genai.Part {
 	var parts []genai.Part
@@ -282,6 +362,43 @@ func convertResponse(resp *genai.GenerateContentResponse) model.ChatOut {
 	return out
 }
 
+// accumulateResponse appends a streamed response to out and returns its deltas.
+//
+// Streamed text parts are fragments of one answer, so they are concatenated
+// without separators. Each function call becomes a tool call and a single chunk
+// carrying its arguments as JSON.
+func accumulateResponse(out *model.ChatOut, resp *genai.GenerateContentResponse) []model.ChatChunk {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil
+	}
+
+	var deltas []model.ChatChunk
+	for _, part := range resp.Candidates[0].Content.Parts {
+		switch p := part.(type) {
+		case genai.Text:
+			out.Text += string(p)
+			deltas = append(deltas, model.ChatChunk{Text: string(p)})
+
+		case genai.FunctionCall:
+			var args string
+			if p.Args != nil {
+				if data, err := json.Marshal(p.Args); err == nil {
+					args = string(data)
+				}
+			}
+			deltas = append(deltas, model.ChatChunk{
+				ToolCall: &model.ToolCallChunk{Index: len(out.ToolCalls), Name: p.Name, Arguments: args},
+			})
+			out.ToolCalls = append(out.ToolCalls, model.ToolCall{
+				Name:  p.Name,
+				Input: convertFunctionArgs(p.Args),
+			})
+		}
+	}
+
+	return deltas
+}
+
 // convertFunctionArgs converts Google's function arguments to our format.
 func convertFunctionArgs(args map[string]interface{}) map[string]interface{} {
 	if args == nil {
@@ -3,9 +3,11 @@ package google
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dshills/langgraph-go/graph/model"
+	"github.com/google/generative-ai-go/genai"
 )
 
 // TestGoogleChatModel_Construction verifies model creation (T145).
@@ -339,6 +341,73 @@ func TestGoogleChatModel_MessageConversion(t *testing.T) {
 	})
 }
 
+// TestGoogleChatModel_ChatStream verifies streaming responses.
+func TestGoogleChatModel_ChatStream(t *testing.T) {
+	t.Run("streams chunks and returns full response", func(t *testing.T) {
+		mockClient := &mockGoogleClient{response: "Hello from Gemini"}
+		m := &ChatModel{client: mockClient, modelName: "gemini-1.5-flash"}
+
+		var chunks []string
+		out, err := m.ChatStream(context.Background(), []model.Message{{Role: model.RoleUser, Content: "Hi"}}, nil, func(chunk model.ChatChunk) error {
+			chunks = append(chunks, chunk.Text)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if strings.Join(chunks, "") != out.Text || len(chunks) != 3 {
+			t.Errorf("expected 3 chunks forming %q, got %q", out.Text, chunks)
+		}
+	})
+
+	t.Run("preserves safety filter errors", func(t *testing.T) {
+		mockClient := &mockGoogleClient{
+			err: &SafetyFilterError{reason: "SAFETY", category: "HARM_CATEGORY_DANGEROUS_CONTENT"},
+		}
+		m := &ChatModel{client: mockClient, modelName: "gemini-1.5-flash"}
+
+		_, err := m.ChatStream(context.Background(), []model.Message{{Role: model.RoleUser, Content: "Hi"}}, nil, func(model.ChatChunk) error { return nil })
+
+		var safetyErr *SafetyFilterError
+		if !errors.As(err, &safetyErr) {
+			t.Errorf("expected SafetyFilterError, got %v", err)
+		}
+	})
+}
+
+// TestGoogleChatModel_AccumulateResponse verifies streamed response accumulation.
+func TestGoogleChatModel_AccumulateResponse(t *testing.T) {
+	stream := []*genai.GenerateContentResponse{
+		{Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{genai.Text("The weather ")}}}}},
+		{Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{genai.Text("is:")}}}}},
+		{Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{
+			genai.FunctionCall{Name: "get_weather", Args: map[string]interface{}{"city": "Paris"}},
+		}}}}},
+		{},
+	}
+
+	var out model.ChatOut
+	var chunks []model.ChatChunk
+	for _, resp := range stream {
+		chunks = append(chunks, accumulateResponse(&out, resp)...)
+	}
+
+	if out.Text != "The weather is:" {
+		t.Errorf("expected concatenated text, got %q", out.Text)
+	}
+	if len(out.ToolCalls) != 1 || out.ToolCalls[0].Name != "get_weather" {
+		t.Fatalf("expected get_weather tool call, got %+v", out.ToolCalls)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	call := chunks[2].ToolCall
+	if call == nil || call.Index != 0 || call.Name != "get_weather" || call.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool-call chunk: %+v", call)
+	}
+}
+
 // Mock Google client for testing.
 type mockGoogleClient struct {
 	response     string
@@ -362,4 +431,20 @@ func (m *mockGoogleClient) generateContent(_ context.Context, messages []model.M
 	}, nil
 }
 
+// streamContent streams the mock response one word per chunk.
+func (m *mockGoogleClient) streamContent(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	out, err := m.generateContent(ctx, messages, tools)
+	if err != nil {
+		return model.ChatOut{}, err
+	}
+
+	for _, word := range strings.SplitAfter(out.Text, " ") {
+		if err := onChunk(model.ChatChunk{Text: word}); err != nil {
+			return model.ChatOut{}, err
+		}
+	}
+
+	return out, nil
+}
+
 // safetyFilterError is imported from the google package
@@ -3,6 +3,7 @@ package model
 
 import (
 	"context"
+	"strings"
 	"sync"
 )
 
@@ -39,14 +40,14 @@ import (
 // // Returns the configured error.
 type MockChatModel struct {
 	// Responses contains the sequence of responses to return.
-	// Each call to Chat() returns the next response in order.
+	// Each call to Chat() or ChatStream() returns the next response in order.
 	// If all responses are consumed, the last response repeats.
 	Responses []ChatOut
 
-	// Err, if set, will be returned by Chat() instead of a response.
+	// Err, if set, will be returned by Chat() and ChatStream() instead of a response.
 	Err error
 
-	// Calls tracks the history of all Chat() invocations.
+	// Calls tracks the history of all Chat() and ChatStream() invocations.
 	// Useful for verifying that nodes called the model with expected inputs.
 	Calls []MockChatCall
 
@@ -73,6 +74,50 @@ func (m *MockChatModel) Chat(ctx context.Context, messages []Message, tools []To
 		return ChatOut{}, ctx.Err()
 	}
 
+	return m.next(messages, tools)
+}
+
+// ChatStream implements the StreamingChatModel interface.
+//
+// Selects the response exactly like Chat, then streams it to onChunk:
+// - Text is split after each space, one chunk per word.
+// - Each tool call is sent as a single chunk with its input encoded as JSON.
+//
+// Stops with ctx.Err() if the context is canceled between chunks.
+func (m *MockChatModel) ChatStream(ctx context.Context, messages []Message, tools []ToolSpec, onChunk func(ChatChunk) error) (ChatOut, error) {
+	// Check context cancellation first (before acquiring lock).
+	if ctx.Err() != nil {
+		return ChatOut{}, ctx.Err()
+	}
+
+	out, err := m.next(messages, tools)
+	if err != nil {
+		return ChatOut{}, err
+	}
+
+	for _, word := range strings.SplitAfter(out.Text, " ") {
+		if word == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ChatOut{}, ctx.Err()
+		}
+		if err := onChunk(ChatChunk{Text: word}); err != nil {
+			return ChatOut{}, err
+		}
+	}
+	if ctx.Err() != nil {
+		return ChatOut{}, ctx.Err()
+	}
+	if err := EmitChunks(ChatOut{ToolCalls: out.ToolCalls}, onChunk); err != nil {
+		return ChatOut{}, err
+	}
+
+	return out, nil
+}
+
+// next records a call and returns the configured response or error.
+func (m *MockChatModel) next(messages []Message, tools []ToolSpec) (ChatOut, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -122,7 +167,7 @@ func (m *MockChatModel) Reset() {
 	m.callIndex = 0
 }
 
-// CallCount returns the number of times Chat() has been called.
+// CallCount returns the number of times Chat() or ChatStream() has been called.
 //
 // Thread-safe convenience method:
 //
@@ -447,3 +447,70 @@ func TestMockChatModel_Concurrency(t *testing.T) {
 		}
 	})
 }
+
+// TestMockChatModel_ChatStream verifies streamed mock responses.
+func TestMockChatModel_ChatStream(t *testing.T) {
+	t.Run("streams one chunk per word then tool calls", func(t *testing.T) {
+		mock := &MockChatModel{
+			Responses: []ChatOut{{
+				Text:      "Paris is sunny",
+				ToolCalls: []ToolCall{{Name: "get_weather", Input: map[string]interface{}{"city": "Paris"}}},
+			}},
+		}
+
+		var chunks []ChatChunk
+		out, err := mock.ChatStream(context.Background(), []Message{{Role: RoleUser, Content: "Weather?"}}, nil, func(chunk ChatChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(chunks) != 4 {
+			t.Fatalf("expected 4 chunks, got %d", len(chunks))
+		}
+		if chunks[0].Text != "Paris " || chunks[1].Text != "is " || chunks[2].Text != "sunny" {
+			t.Errorf("unexpected text chunks: %+v", chunks[:3])
+		}
+		if call := chunks[3].ToolCall; call == nil || call.Name != "get_weather" || call.Arguments != `{"city":"Paris"}` {
+			t.Errorf("unexpected tool-call chunk: %+v", chunks[3].ToolCall)
+		}
+		if out.Text != "Paris is sunny" || len(out.ToolCalls) != 1 {
+			t.Errorf("expected full response, got %+v", out)
+		}
+		if mock.CallCount() != 1 {
+			t.Errorf("expected 1 recorded call, got %d", mock.CallCount())
+		}
+	})
+
+	t.Run("returns configured error", func(t *testing.T) {
+		mock := &MockChatModel{Err: errors.New("API error")}
+
+		_, err := mock.ChatStream(context.Background(), nil, nil, func(ChatChunk) error {
+			t.Error("onChunk called despite error")
+			return nil
+		})
+		if err == nil || err.Error() != "API error" {
+			t.Errorf("expected configured error, got %v", err)
+		}
+	})
+
+	t.Run("stops when context is canceled", func(t *testing.T) {
+		mock := &MockChatModel{Responses: []ChatOut{{Text: "one two three"}}}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		calls := 0
+		_, err := mock.ChatStream(ctx, nil, nil, func(ChatChunk) error {
+			calls++
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 chunk before cancellation, got %d", calls)
+		}
+	})
+}
@@ -48,6 +48,7 @@ type ChatModel struct {
 // This allows for easy mocking in tests.
 type openaiClient interface {
 	createChatCompletion(ctx context.Context, messages []model.Message, tools []model.ToolSpec) (model.ChatOut, error)
+	streamChatCompletion(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error)
 }
 
 // NewChatModel creates a new OpenAI ChatModel.
@@ -129,6 +130,66 @@ func (m *ChatModel) Chat(ctx context.Context, messages []model.Message, tools []
 	return model.ChatOut{}, fmt.Errorf("OpenAI API failed after %d retries: %w", m.maxRetries, lastErr)
 }
 
+// ChatStream implements the model.StreamingChatModel interface.
+//
+// Streams the response from OpenAI's API, calling onChunk for each text and
+// tool-call delta as it arrives. Transient errors are retried like Chat, but
+// only until the first chunk has been delivered; later failures are returned
+// so that callers never see duplicated output.
+//
+// Returns:
+//   - ChatOut with the complete Text and ToolCalls
+//   - Error for API failures, exceeded retries, or the error returned by onChunk
+func (m *ChatModel) ChatStream(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Check context cancellation
+	if ctx.Err() != nil {
+		return model.ChatOut{}, ctx.Err()
+	}
+
+	// Track delivery so a stream is never retried after output reached the caller
+	delivered := false
+	forward := func(chunk model.ChatChunk) error {
+		delivered = true
+		return onChunk(chunk)
+	}
+
+	// Attempt with retries
+	var lastErr error
+	for attempt := 0; attempt <= m.maxRetries; attempt++ {
+		out, err := m.client.streamChatCompletion(ctx, messages, tools, forward)
+		if err == nil {
+			return out, nil
+		}
+
+		lastErr = err
+
+		// Don't retry on non-transient errors or once output has been streamed
+		if !isTransientError(err) || delivered {
+			return model.ChatOut{}, err
+		}
+
+		// Don't retry if we've exhausted attempts
+		if attempt >= m.maxRetries {
+			break
+		}
+
+		// Wait before retry (with exponential backoff for rate limits)
+		delay := m.retryDelay
+		if isRateLimitError(err) {
+			delay = m.retryDelay * time.Duration(attempt+1)
+		}
+
+		select {
+		case <-time.After(delay):
+			// Continue to next attempt
+		case <-ctx.Done():
+			return model.ChatOut{}, ctx.Err()
+		}
+	}
+
+	return model.ChatOut{}, fmt.Errorf("OpenAI API failed after %d retries: %w", m.maxRetries, lastErr)
+}
+
 // isTransientError determines if an error should trigger a retry.
 func isTransientError(err error) bool {
 	if err == nil {
@@ -216,6 +277,48 @@ func (c *defaultClient) createChatCompletion(ctx context.Context, messages []mod
 	return convertResponse(resp), nil
 }
 
+func (c *defaultClient) streamChatCompletion(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	// Validate API key
+	if c.apiKey == "" {
+		return model.ChatOut{}, errors.New("OpenAI API key is required")
+	}
+
+	// Create OpenAI client
+	client := openaisdk.NewClient(option.WithAPIKey(c.apiKey))
+
+	// Build request parameters
+	params := openaisdk.ChatCompletionNewParams{
+		Model:    c.modelName,
+		Messages: convertMessages(messages),
+	}
+	if len(tools) > 0 {
+		params.Tools = convertTools(tools)
+	}
+
+	// Stream the completion, accumulating chunks into the full response
+	stream := client.Chat.Completions.NewStreaming(ctx, params)
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	acc := openaisdk.ChatCompletionAccumulator{}
+	for stream.Next() {
+		chunk := stream.Current()
+		acc.AddChunk(chunk)
+
+		for _, delta := range convertChunk(chunk) {
+			if err := onChunk(delta); err != nil {
+				return model.ChatOut{}, err
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return model.ChatOut{}, fmt.Errorf("OpenAI API error: %w", err)
+	}
+
+	return convertResponse(&acc.ChatCompletion), nil
+}
+
 // convertMessages converts our Message format to OpenAI's format.
 func convertMessages(messages []model.Message) []openaisdk.ChatCompletionMessageParamUnion {
 	result := make([]openaisdk.ChatCompletionMessageParamUnion, len(messages))
@@ -283,6 +386,33 @@ func convertResponse(resp *openaisdk.ChatCompletion) model.ChatOut {
 	return out
 }
 
+// convertChunk converts a streamed OpenAI chunk to our ChatChunk deltas.
+// Only the first choice is streamed, matching convertResponse.
+func convertChunk(chunk openaisdk.ChatCompletionChunk) []model.ChatChunk {
+	var deltas []model.ChatChunk
+
+	for _, choice := range chunk.Choices {
+		if choice.Index != 0 {
+			continue
+		}
+
+		if choice.Delta.Content != "" {
+			deltas = append(deltas, model.ChatChunk{Text: choice.Delta.Content})
+		}
+		for _, tc := range choice.Delta.ToolCalls {
+			deltas = append(deltas, model.ChatChunk{
+				ToolCall: &model.ToolCallChunk{
+					Index:     int(tc.Index),
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+		}
+	}
+
+	return deltas
+}
+
 // parseToolInput parses the JSON arguments string into a map.
 func parseToolInput(jsonStr string) map[string]interface{} {
 	// For now, return a simple map with the raw JSON
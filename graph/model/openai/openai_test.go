@@ -2,10 +2,13 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dshills/langgraph-go/graph/model"
+	openaisdk "github.com/openai/openai-go"
 )
 
 // TestOpenAIChatModel_Construction verifies model creation (T135).
@@ -301,6 +304,120 @@ func TestOpenAIChatModel_MessageConversion(t *testing.T) {
 	})
 }
 
+// TestOpenAIChatModel_ChatStream verifies streaming responses.
+func TestOpenAIChatModel_ChatStream(t *testing.T) {
+	messages := []model.Message{
+		{Role: model.RoleUser, Content: "Hi there!"},
+	}
+
+	t.Run("streams chunks and returns full response", func(t *testing.T) {
+		mockClient := &mockOpenAIClient{response: "Hello from OpenAI"}
+		m := &ChatModel{client: mockClient, modelName: "gpt-4"}
+
+		var chunks []string
+		out, err := m.ChatStream(context.Background(), messages, nil, func(chunk model.ChatChunk) error {
+			chunks = append(chunks, chunk.Text)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(chunks) != 3 || strings.Join(chunks, "") != "Hello from OpenAI" {
+			t.Errorf("expected 3 chunks forming the response, got %q", chunks)
+		}
+		if out.Text != "Hello from OpenAI" {
+			t.Errorf("expected full text, got %q", out.Text)
+		}
+	})
+
+	t.Run("retries transient errors before first chunk", func(t *testing.T) {
+		mockClient := &mockOpenAIClient{
+			errors:   []error{errors.New("temporary network error"), nil},
+			response: "recovered",
+		}
+		m := &ChatModel{client: mockClient, modelName: "gpt-4", maxRetries: 3}
+
+		out, err := m.ChatStream(context.Background(), messages, nil, func(model.ChatChunk) error { return nil })
+		if err != nil {
+			t.Fatalf("expected success after retry, got %v", err)
+		}
+		if out.Text != "recovered" {
+			t.Errorf("expected recovered response, got %q", out.Text)
+		}
+		if mockClient.callCount != 2 {
+			t.Errorf("expected 2 attempts, got %d", mockClient.callCount)
+		}
+	})
+
+	t.Run("does not retry after chunks were delivered", func(t *testing.T) {
+		mockClient := &mockOpenAIClient{
+			response:     "partial response",
+			midStreamErr: errors.New("connection reset"),
+		}
+		m := &ChatModel{client: mockClient, modelName: "gpt-4", maxRetries: 3}
+
+		_, err := m.ChatStream(context.Background(), messages, nil, func(model.ChatChunk) error { return nil })
+		if err == nil {
+			t.Fatal("expected mid-stream error")
+		}
+		if mockClient.callCount != 1 {
+			t.Errorf("expected 1 attempt, got %d", mockClient.callCount)
+		}
+	})
+
+	t.Run("stops when onChunk fails", func(t *testing.T) {
+		mockClient := &mockOpenAIClient{response: "one two three"}
+		m := &ChatModel{client: mockClient, modelName: "gpt-4"}
+
+		stop := errors.New("client went away")
+		calls := 0
+		_, err := m.ChatStream(context.Background(), messages, nil, func(model.ChatChunk) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("expected onChunk error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected streaming to stop after 1 chunk, got %d", calls)
+		}
+	})
+}
+
+// TestOpenAIChatModel_ConvertChunk verifies streamed chunk conversion.
+func TestOpenAIChatModel_ConvertChunk(t *testing.T) {
+	var chunk openaisdk.ChatCompletionChunk
+	raw := `{
+		"id": "chatcmpl-1",
+		"object": "chat.completion.chunk",
+		"created": 1,
+		"model": "gpt-4",
+		"choices": [{
+			"index": 0,
+			"delta": {
+				"content": "Hel",
+				"tool_calls": [{"index": 1, "id": "call_1", "type": "function", "function": {"name": "search", "arguments": "{\"q\":"}}]
+			}
+		}]
+	}`
+	if err := json.Unmarshal([]byte(raw), &chunk); err != nil {
+		t.Fatalf("failed to decode chunk: %v", err)
+	}
+
+	deltas := convertChunk(chunk)
+	if len(deltas) != 2 {
+		t.Fatalf("expected 2 deltas, got %d", len(deltas))
+	}
+	if deltas[0].Text != "Hel" {
+		t.Errorf("expected text delta, got %q", deltas[0].Text)
+	}
+	call := deltas[1].ToolCall
+	if call == nil || call.Index != 1 || call.Name != "search" || call.Arguments != `{"q":` {
+		t.Errorf("unexpected tool-call delta: %+v", call)
+	}
+}
+
 // Mock OpenAI client for testing.
 type mockOpenAIClient struct {
 	response     string
@@ -309,6 +426,7 @@ type mockOpenAIClient struct {
 	errors       []error // For testing retry logic
 	callCount    int
 	lastMessages []model.Message
+	midStreamErr error // Returned by streamChatCompletion after the first chunk
 }
 
 func (m *mockOpenAIClient) createChatCompletion(_ context.Context, messages []model.Message, _ []model.ToolSpec) (model.ChatOut, error) {
@@ -333,4 +451,23 @@ func (m *mockOpenAIClient) createChatCompletion(_ context.Context, messages []mo
 	}, nil
 }
 
+// streamChatCompletion streams the mock response one word per chunk.
+func (m *mockOpenAIClient) streamChatCompletion(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	out, err := m.createChatCompletion(ctx, messages, tools)
+	if err != nil {
+		return model.ChatOut{}, err
+	}
+
+	for i, word := range strings.SplitAfter(out.Text, " ") {
+		if err := onChunk(model.ChatChunk{Text: word}); err != nil {
+			return model.ChatOut{}, err
+		}
+		if i == 0 && m.midStreamErr != nil {
+			return model.ChatOut{}, m.midStreamErr
+		}
+	}
+
+	return out, nil
+}
+
 // rateLimitError is imported from the openai package
@@ -54,6 +54,18 @@ import "context"
 //
 // Pure nodes (computation only, no I/O) don't need to implement Effects() - the
 // default zero value indicates no side effects.
+//
+// Nodes that route with Goto or Many can optionally implement Routes() []string
+// to declare every node ID they may route to, so Engine.Validate can check
+// dynamic routes before Run. An empty list declares a terminal node:
+//
+//	func (n *ClassifyNode) Routes() []string {
+//	    return []string{"billing", "support", "sales"}
+//	}
+//
+// Nodes can likewise implement FanOut() []string to declare the node IDs they
+// may start in parallel with Many or Sends. Both declarations are also used by
+// Engine.Topology. For NodeFunc nodes, use DeclareRoutes and DeclareFanOut.
 type Node[S any] interface {
 	// Run executes the node's logic with the given context and state.
 	// It returns a NodeResult containing state changes, routing decisions,
@@ -75,7 +87,7 @@ type NodeResult[S any] struct {
 
 	// Route specifies the next step(s) in workflow execution.
 	// Use Stop() for terminal nodes, Goto(id) for explicit routing,
-	// or set Many for fan-out to multiple nodes.
+	// or set Many or Sends for fan-out to multiple nodes.
 	Route Next
 
 	// TODO: Add Events []Event field after T029-T030 (Event type definition)
@@ -87,22 +99,36 @@ type NodeResult[S any] struct {
 
 // Next specifies the next step(s) in workflow execution after a node completes.
 //
-// It supports three routing modes:
+// It supports five routing modes:
 //   - Terminal: Stop execution (Route.Terminal = true)
 //   - Single: Go to a specific node (Route.To = "nodeID")
 //   - Fan-out: Go to multiple nodes in parallel (Route.Many = []string{"node1", "node2"})
+//   - Map: Run nodes in parallel, each with its own input (Route.Sends, see Sends)
+//   - Interrupt: Pause the run until Engine.Resume (Route.Interrupt = true)
 type Next struct {
 	// To specifies the next single node to execute.
-	// Mutually exclusive with Many and Terminal.
+	// Mutually exclusive with Many, Sends and Terminal.
 	To string
 
 	// Many specifies multiple nodes to execute in parallel (fan-out).
-	// Mutually exclusive with To and Terminal.
+	// Mutually exclusive with To, Sends and Terminal.
 	Many []string
 
+	// Sends specifies node executions to run in parallel, each with its own
+	// input (map-reduce fan-out). Mutually exclusive with To, Many and Terminal.
+	Sends []Send
+
 	// Terminal indicates workflow execution should stop.
-	// Mutually exclusive with To and Many.
+	// Mutually exclusive with To, Many and Sends.
 	Terminal bool
+
+	// Interrupt pauses the run at this node until Engine.Resume is called.
+	// Mutually exclusive with To, Many, Sends and Terminal.
+	Interrupt bool
+
+	// InterruptPayload is reported to the caller through InterruptError,
+	// typically describing what the run is waiting for. Only used with Interrupt.
+	InterruptPayload interface{}
 }
 
 // Stop returns a Next that terminates workflow execution.
@@ -121,6 +147,25 @@ func Many(nodeIDs []string) Next {
 	return Next{Many: nodeIDs}
 }
 
+// Interrupt returns a Next that pauses the run at this node and asks the caller
+// for input.
+//
+// The node's delta is discarded and the run stops once no other work can make
+// progress, returning an InterruptError (matching ErrInterrupted) that carries
+// payload. Engine.Resume later runs the node again with the caller's answer,
+// available through ResumeInput:
+//
+//	func (n *ApprovalNode) Run(ctx context.Context, s State) graph.NodeResult[State] {
+//	    answer, ok := graph.ResumeInput(ctx)
+//	    if !ok {
+//	        return graph.NodeResult[State]{Route: graph.Interrupt(s.Draft)}
+//	    }
+//	    return graph.NodeResult[State]{Delta: State{Approved: answer.(bool)}, Route: graph.Goto("send")}
+//	}
+func Interrupt(payload interface{}) Next {
+	return Next{Interrupt: true, InterruptPayload: payload}
+}
+
 // NodeFunc is a function adapter that implements the Node interface.
 // It allows using plain functions as nodes without creating custom types.
 //
@@ -139,6 +184,51 @@ func (f NodeFunc[S]) Run(ctx context.Context, state S) NodeResult[S] {
 	return f(ctx, state)
 }
 
+// DeclareRoutes wraps fn as a node that declares the node IDs it may route to
+// with Goto (see Engine.Validate and Engine.Topology). Call it with no routes to declare a
+// terminal node.
+//
+// Example:
+//
+//	classify := DeclareRoutes(NodeFunc[MyState](classifyFn), "billing", "support")
+//	engine.Add("classify", classify)
+func DeclareRoutes[S any](fn NodeFunc[S], routes ...string) Node[S] {
+	return &routedNode[S]{fn: fn, routes: append([]string{}, routes...)}
+}
+
+// DeclareFanOut wraps fn as a node that declares the node IDs it may start in
+// parallel with Many or Sends (see Engine.Validate and Engine.Topology).
+//
+// Example:
+//
+//	fanout := DeclareFanOut(NodeFunc[MyState](fanoutFn), "search", "summarize")
+//	engine.Add("fanout", fanout)
+func DeclareFanOut[S any](fn NodeFunc[S], targets ...string) Node[S] {
+	return &routedNode[S]{fn: fn, routes: []string{}, fanOut: append([]string{}, targets...)}
+}
+
+// routedNode is a NodeFunc with declared routes.
+type routedNode[S any] struct {
+	fn     NodeFunc[S]
+	routes []string
+	fanOut []string
+}
+
+// Run implements the Node interface.
+func (n *routedNode[S]) Run(ctx context.Context, state S) NodeResult[S] {
+	return n.fn(ctx, state)
+}
+
+// Routes returns the declared Goto destinations.
+func (n *routedNode[S]) Routes() []string {
+	return n.routes
+}
+
+// FanOut returns the declared Many and Sends destinations.
+func (n *routedNode[S]) FanOut() []string {
+	return n.fanOut
+}
+
 // NodeError represents an error that occurred during node execution.
 // It provides structured error information for better observability and debugging.
 type NodeError struct {
@@ -153,6 +243,15 @@ type NodeError struct {
 
 	// Cause is the underlying error that caused this NodeError.
 	Cause error
+
+	// Attempts is how many times the node was attempted, retries included.
+	// It is set on failures routed to an error handler (see Engine.OnError).
+	Attempts int
+
+	// Delta is the delta the node returned with its last failed attempt,
+	// of the engine's state type S. It is set on failures routed to an
+	// error handler (see Engine.OnError).
+	Delta any
 }
 
 // Error implements the error interface.
@@ -1,7 +1,12 @@
 // Package graph provides the core graph execution engine for LangGraph-Go.
 package graph
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
 
 // Option is a functional option for configuring an Engine.
 //
@@ -253,73 +258,272 @@ func WithStrictReplay(enabled bool) Option {
 	}
 }
 
-// ConflictPolicy defines how concurrent state updates are handled when multiple branches.
-// modify the same state fields.
-//
-// This is a placeholder for future CRDT (Conflict-free Replicated Data Type) support.
-// Currently only ConflictFail is supported, which returns an error on detected conflicts.
-//
-// Planned policies:
-// - ConflictFail: Return error on conflict (default, implemented).
-// - LastWriterWins: Last merge wins based on OrderKey.
-// - CRDT: Use conflict-free replicated data types for automatic resolution.
+// ConflictPolicy defines how the engine handles concurrent branches of a
+// fan-out that write the same state field.
+//
+// Two branches conflict on a field when both set it to different non-zero
+// values before their deltas are merged, at a join node or into the run's
+// final state. Only fields that do not declare how concurrent writes combine
+// can conflict: fields with a reduce tag other than "replace" (see
+// NewReducer) and CRDT fields (types with a Merge method, such as those in
+// graph/crdt) are merged by design. Field-level detection requires S to be a
+// struct.
+//
+// Every detected conflict is reported as a merge_conflict event and through
+// PrometheusMetrics.IncrementMergeConflicts with the affected fields.
+//
+// ConflictNone is the zero value, so engines configured without a policy
+// merge with the reducer only, as they did before conflicts were detected.
+// Every other policy detects conflicts, whether it is set in Options or with
+// WithConflictPolicy.
 type ConflictPolicy int
 
 const (
-	// ConflictFail returns an error when concurrent branches modify the same state field.
-	// This is the safest default, requiring explicit conflict resolution.
-	ConflictFail ConflictPolicy = iota
+	// ConflictNone merges deltas with the reducer only, without conflict
+	// detection. This is the default.
+	ConflictNone ConflictPolicy = iota
 
-	// LastWriterWins uses the delta with the highest OrderKey when conflicts occur.
-	// WARNING: Not yet implemented. Will return error if specified.
+	// ConflictFail stops the run with a *ConflictError (matching
+	// ErrMergeConflict) when concurrent branches write the same field.
+	ConflictFail
+
+	// LastWriterWins resolves a conflicting field to the value written by the
+	// branch with the highest OrderKey, unless a later step overwrote it.
 	LastWriterWins
 
-	// ConflictCRDT uses CRDT semantics for automatic conflict resolution.
-	// WARNING: Not yet implemented. Will return error if specified.
+	// ConflictCRDT merges CRDT fields with their Merge method on every reducer
+	// application, whatever the reducer does with them, and otherwise behaves
+	// like ConflictFail.
 	ConflictCRDT
 )
 
-// WithConflictPolicy sets the policy for handling concurrent state update conflicts.
+// String returns the policy name used in events and metrics.
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictNone:
+		return "none"
+	case ConflictFail:
+		return "fail"
+	case LastWriterWins:
+		return "last_writer_wins"
+	case ConflictCRDT:
+		return "crdt"
+	default:
+		return "unknown"
+	}
+}
+
+// WithConflictPolicy sets the policy for handling concurrent state update
+// conflicts. Every policy but ConflictNone detects conflicts.
+//
+// Default: ConflictNone (no conflict detection).
+//
+// Example:
 //
-// Default: ConflictFail (returns error on conflict).
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithMaxConcurrent(8),
+//	    graph.WithConflictPolicy(graph.ConflictFail), // Error when branches disagree
+//	)
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(cfg *engineConfig) error {
+		if policy < ConflictNone || policy > ConflictCRDT {
+			return &EngineError{
+				Message: fmt.Sprintf("unknown conflict policy: %d", policy),
+				Code:    "UNSUPPORTED_CONFLICT_POLICY",
+			}
+		}
+		cfg.opts.ConflictPolicy = policy
+		return nil
+	}
+}
+
+// WithCheckpointOnCancel controls whether runs stopped with Engine.Cancel
+// save a checkpoint they can be continued from.
 //
-// Only ConflictFail is currently supported. Other policies are planned for future releases.
+// Default: false.
 //
 // Example:
 //
-// engine := graph.New(.
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithCheckpointOnCancel(true),
+//	)
 //
-//	reducer, store, emitter,
+//	// Later, continue the cancelled run
+//	checkpoints, _ := history.ListCheckpoints(ctx, runID, "cancel")
+//	final, err := engine.RunWithCheckpoint(ctx, checkpoints[len(checkpoints)-1])
+func WithCheckpointOnCancel(enabled bool) Option {
+	return func(cfg *engineConfig) error {
+		cfg.opts.CheckpointOnCancel = enabled
+		return nil
+	}
+}
+
+// WithCheckpointEveryStep controls whether runs save a checkpoint when they
+// start and after each step, so that Engine.Fork can branch off any step
+// with the work the run had pending.
 //
-// graph.WithConflictPolicy(graph.ConflictFail), // Explicit error on conflicts.
-// ).
-func WithConflictPolicy(policy ConflictPolicy) Option {
-	return func(_ *engineConfig) error {
-		// Currently only ConflictFail is supported.
-		// Future: Implement LastWriterWins and ConflictCRDT.
-		if policy != ConflictFail {
+// Default: false.
+//
+// Example:
+//
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithCheckpointEveryStep(true),
+//	)
+//
+//	// Later, retry the run from its third step with an edited state
+//	final, err := engine.Fork(ctx, "run-001", 3, "run-001-retry", patch)
+func WithCheckpointEveryStep(enabled bool) Option {
+	return func(cfg *engineConfig) error {
+		cfg.opts.CheckpointEveryStep = enabled
+		return nil
+	}
+}
+
+// WithRunLeases enables crash recovery: runs are recorded in the store and
+// hold a lease of the given duration while they execute, so that
+// Engine.RecoverIncomplete can resume the runs of a crashed process. The
+// store must implement store.RunTracker.
+//
+// Leased runs are checkpointed when they start and after each step, as with
+// WithCheckpointEveryStep, so a recovered run continues from its last
+// completed step rather than repeating the nodes it already executed.
+//
+// Default: disabled.
+//
+// Example:
+//
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithRunLeases(30*time.Second),
+//	)
+//
+//	// On startup, resume the runs a crashed process left behind
+//	recovered, err := engine.RecoverIncomplete(ctx)
+func WithRunLeases(duration time.Duration) Option {
+	return func(cfg *engineConfig) error {
+		cfg.opts.LeaseDuration = duration
+		return nil
+	}
+}
+
+// WithLeaseOwner sets the owner this process holds run leases as. Processes
+// sharing a store must use distinct owners.
+//
+// Default: "hostname:pid".
+//
+// Example:
+//
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithRunLeases(30*time.Second),
+//	    graph.WithLeaseOwner(os.Getenv("POD_NAME")),
+//	)
+func WithLeaseOwner(owner string) Option {
+	return func(cfg *engineConfig) error {
+		cfg.opts.LeaseOwner = owner
+		return nil
+	}
+}
+
+// WithResourcePool limits how many nodes tagged with resource name execute at
+// once across all runs of the engine. Nodes declare their tags in
+// NodePolicy.Resources. Call it once per pool.
+//
+// Default: no pools.
+//
+// Example:
+//
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithMaxConcurrent(16),
+//	    graph.WithResourcePool("openai", 3), // At most 3 OpenAI calls at once
+//	    graph.WithResourcePool("db", 1),     // One node touching the database
+//	)
+func WithResourcePool(name string, limit int) Option {
+	return func(cfg *engineConfig) error {
+		if limit < 1 {
 			return &EngineError{
-				Message: "only ConflictFail policy is currently supported",
-				Code:    "UNSUPPORTED_CONFLICT_POLICY",
+				Message: fmt.Sprintf("resource pool %q limit must be at least 1, got %d", name, limit),
+				Code:    "INVALID_RESOURCE_POOL",
 			}
 		}
-		// Note: Options struct doesn't have ConflictPolicy field yet.
-		// This is reserved for future use when field is added.
+		// Copy so pools added here do not leak into a shared Options map
+		pools := make(map[string]int, len(cfg.opts.ResourcePools)+1)
+		for pool, poolLimit := range cfg.opts.ResourcePools {
+			pools[pool] = poolLimit
+		}
+		pools[name] = limit
+		cfg.opts.ResourcePools = pools
+		return nil
+	}
+}
+
+// WithPriorityAging makes a queued work item gain one priority level for
+// each steps steps it waits behind items from later steps, so background
+// nodes with a low NodePolicy.Priority still run while higher-priority
+// work keeps arriving.
+//
+// Default: 0 (no aging).
+//
+// Example:
+//
+//	engine := graph.New(
+//	    reducer, store, emitter,
+//	    graph.WithMaxConcurrent(4),
+//	    graph.WithPriorityAging(5), // +1 priority per 5 steps queued
+//	)
+func WithPriorityAging(steps int) Option {
+	return func(cfg *engineConfig) error {
+		if steps < 0 {
+			return &EngineError{
+				Message: fmt.Sprintf("priority aging must not be negative, got %d", steps),
+				Code:    "INVALID_PRIORITY_AGING",
+			}
+		}
+		cfg.opts.PriorityAging = steps
+		return nil
+	}
+}
+
+// WithWorkQueue distributes the node executions of concurrent runs to
+// worker processes sharing the queue, typically the SQLite or MySQL store
+// the runs checkpoint to. Workers are engines with the same graph calling
+// Engine.Work.
+//
+// Default: nil (nodes execute in the process calling Run).
+//
+// Example:
+//
+//	st, _ := store.NewSQLiteStore[MyState]("./workflow.db")
+//	engine := graph.New(
+//	    reducer, st, emitter,
+//	    graph.WithMaxConcurrent(8),
+//	    graph.WithWorkQueue(st),
+//	)
+func WithWorkQueue(queue store.WorkQueue) Option {
+	return func(cfg *engineConfig) error {
+		cfg.opts.WorkQueue = queue
 		return nil
 	}
 }
 
 // WithMetrics enables Prometheus metrics collection.
 //
-// Metrics enable production monitoring with 6 key metrics:
+// Metrics enable production monitoring with 9 key metrics:
 //   - inflight_nodes: Current concurrent node count
 //   - queue_depth: Pending nodes in scheduler queue
 //   - step_latency_ms: Node execution duration histogram
 //   - retries_total: Cumulative retry attempts
 //   - merge_conflicts_total: Concurrent state merge conflicts
 //   - backpressure_events_total: Queue saturation events
+//   - pruned_records_total: Store records deleted by Prune and PruneEvery
+//   - node_cache_lookups_total: Cache hits and misses of nodes with a CachePolicy
+//   - resource_wait_ms: Time queued nodes waited for a resource pool slot
 //
-// All metrics are automatically updated during workflow execution.
+// The execution metrics are automatically updated during workflow execution.
 //
 // Example:
 //
@@ -2,8 +2,11 @@
 package graph
 
 import (
+	"math"
 	"math/rand"
 	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
 )
 
 // Policy defines node execution policies and retry strategies.
@@ -26,6 +29,52 @@ type NodePolicy struct {
 	// If nil, a default key based on node ID and step ID is used.
 	// This is useful for side-effecting nodes that need exactly-once semantics.
 	IdempotencyKeyFunc func(state any) string
+
+	// CachePolicy reuses the node's results for input states it already
+	// executed with. If nil, the node executes every time.
+	CachePolicy *CachePolicy
+
+	// Resources tags the node with the resources it uses, such as "openai"
+	// or "db". The node takes a slot in the pool of each tag configured in
+	// Options.ResourcePools while it executes.
+	Resources []string
+
+	// Priority orders the node's queued work items in concurrent mode when
+	// workers are saturated: items with a higher priority are dequeued
+	// before items with a lower one, regardless of OrderKey. Default: 0.
+	// Use it to run latency-critical branches ahead of background work.
+	Priority int
+
+	// PriorityFunc derives the priority from the node's input state, and
+	// overrides Priority if set. It must depend only on the state, so that
+	// replays schedule items the same way.
+	PriorityFunc func(state any) int
+}
+
+// CachePolicy caches the results of a deterministic node, such as an
+// embedding or a summary of an unchanged document, keyed by its input state.
+// A node executing with a state whose key is cached returns the cached result
+// without calling Node.Run, in any run and on every resume.
+//
+// Only successful results are cached. Results routing with Sends or
+// interrupting the run are not cached, and a node resuming from an interrupt
+// always executes. Cached deltas and routes are stored as JSON, so the state
+// must round-trip through encoding/json as it does for checkpoints.
+type CachePolicy struct {
+	// Key derives the cache key from the node's input state; returning ""
+	// executes the node without the cache. Keys are scoped to the node ID
+	// and, for nodes started by a Sends route, to the SHA-256 hash of the
+	// branch input's JSON encoding with sorted keys. If nil, the key is the
+	// SHA-256 hash of the state's JSON encoding, whose struct fields and map
+	// keys are always in the same order.
+	Key func(state any) string
+
+	// TTL is how long a cached result is reused. If zero, it never expires.
+	TTL time.Duration
+
+	// Cache holds the cached results. If nil, the engine's store is used,
+	// which must then implement store.ResultCache.
+	Cache store.ResultCache
 }
 
 // RetryPolicy defines automatic retry configuration for transient node failures.
@@ -98,7 +147,7 @@ type SideEffectPolicy struct {
 // Parameters:
 // - attempt: Zero-based retry attempt number (0 = first retry).
 // - base: Base delay for exponential calculation.
-// - maxDelay: Maximum allowed delay (caps exponential growth).
+// - maxDelay: Maximum allowed delay (caps exponential growth; 0 means no cap).
 // - rng: Random number generator for jitter (use context RNG for determinism).
 //
 // Returns:
@@ -112,16 +161,23 @@ type SideEffectPolicy struct {
 // - attempt 10: 30s + jitter(0, 1s) = 30-31s (capped).
 func computeBackoff(attempt int, base, maxDelay time.Duration, rng *rand.Rand) time.Duration {
 	// Compute exponential delay: base * 2^attempt.
-	// Use bit shift for efficient 2^attempt calculation.
-	exponentialDelay := base * (1 << attempt)
+	// Cap the shift so the multiplication cannot overflow.
+	shift := min(max(attempt, 0), 62)
+	for shift > 0 && base > math.MaxInt64>>shift {
+		shift--
+	}
+	exponentialDelay := base << shift
 
-	// Cap at maxDelay to prevent unbounded growth.
-	if exponentialDelay > maxDelay {
+	// Cap at maxDelay to prevent unbounded growth (0 means no cap).
+	if maxDelay > 0 && exponentialDelay > maxDelay {
 		exponentialDelay = maxDelay
 	}
 
 	// Add jitter: random value between 0 and base.
 	// This prevents synchronized retries (thundering herd).
+	if base <= 0 {
+		return exponentialDelay
+	}
 	var jitter time.Duration
 	if rng != nil {
 		jitter = time.Duration(rng.Int63n(int64(base)))
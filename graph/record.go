@@ -0,0 +1,324 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// recorderKey is the context key for the ioRecorder of the node being run.
+const recorderKey contextKey = "langgraph.recorder"
+
+// Recorded performs an external call through fn and makes it replayable.
+//
+// Inside a node whose Effects() declares Recordable=true, the call is captured
+// as a RecordedIO identified by the node ID, retry attempt, key and request,
+// and saved in the run's checkpoints. Errors are recorded too, so a replay
+// follows the same retry path as the original run.
+//
+// With Options.ReplayMode the stored response is returned and fn is not
+// called. Recordings are matched by node ID, attempt, key and request, each
+// recording being served once. When no recording matches:
+//   - StrictReplay returns an error matching ErrReplayMismatch
+//   - Otherwise a recording with the same node, attempt and key is served even
+//     if the request changed, and failing that fn is called live
+//
+// Outside a recordable node Recorded simply returns fn(). request and the
+// response must be JSON-serializable; key names the kind of call, for example
+// "openai.chat", and distinguishes several calls made by one node.
+//
+// Example:
+//
+//	out, err := graph.Recorded(ctx, "weather.lookup", city, func() (Weather, error) {
+//	    return client.Lookup(ctx, city)
+//	})
+func Recorded[T any](ctx context.Context, key string, request interface{}, fn func() (T, error)) (T, error) {
+	var zero T
+
+	rec, _ := ctx.Value(recorderKey).(*ioRecorder)
+	if rec == nil {
+		return fn()
+	}
+	nodeID, _ := ctx.Value(NodeIDKey).(string)
+	attempt, _ := ctx.Value(AttemptKey).(int)
+
+	requestJSON, err := canonicalJSON(request)
+	if err != nil {
+		return zero, fmt.Errorf("failed to marshal %s request: %w", key, err)
+	}
+
+	if rec.replay {
+		recorded, found, err := rec.lookup(nodeID, attempt, key, requestJSON)
+		if err != nil {
+			return zero, err
+		}
+		if found {
+			if recorded.Error != "" {
+				return zero, errors.New(recorded.Error)
+			}
+			var response T
+			if err := json.Unmarshal(recorded.Response, &response); err != nil {
+				return zero, fmt.Errorf("failed to decode recorded %s response: %w", key, err)
+			}
+			return response, nil
+		}
+	}
+
+	start := time.Now()
+	response, callErr := fn()
+	duration := time.Since(start)
+
+	recorded, err := recordIO(nodeID, attempt, request, response)
+	if err != nil {
+		return zero, fmt.Errorf("failed to record %s: %w", key, err)
+	}
+	recorded.Key = key
+	recorded.Duration = duration
+	if callErr != nil {
+		recorded.Error = callErr.Error()
+	}
+	rec.add(recorded)
+
+	return response, callErr
+}
+
+// ioRecorder holds the recorded I/O of one run.
+//
+// In record mode every Recorded call is appended. In replay mode the
+// recordings loaded from the run's checkpoints are served to matching calls,
+// and calls made live are appended. Each checkpoint stores the recordings
+// appended since the previous one (see unsaved), so every recording is saved
+// once.
+type ioRecorder struct {
+	mu     sync.Mutex
+	replay bool
+	strict bool
+	ios    []RecordedIO
+	served []bool
+	saved  int
+}
+
+// newIORecorder creates a recorder starting from prior recordings, which are
+// already saved: the ones to replay in replay mode.
+func newIORecorder(prior []RecordedIO, replay, strict bool) *ioRecorder {
+	served := make([]bool, len(prior))
+	for i := range served {
+		served[i] = !replay
+	}
+	return &ioRecorder{
+		replay: replay,
+		strict: strict,
+		ios:    append([]RecordedIO(nil), prior...),
+		served: served,
+		saved:  len(prior),
+	}
+}
+
+// withRecorder installs the run's ioRecorder in ctx, seeded from the
+// recordings under RecordedIOsKey. The seed is removed so that subgraphs run
+// by a node start their own recordings.
+func (e *Engine[S]) withRecorder(ctx context.Context) context.Context {
+	prior, _ := ctx.Value(RecordedIOsKey).([]RecordedIO)
+	ctx = context.WithValue(ctx, RecordedIOsKey, nil)
+	return context.WithValue(ctx, recorderKey, newIORecorder(prior, e.opts.ReplayMode, e.opts.StrictReplay))
+}
+
+// recorderFor restricts recording to nodes that declare Recordable I/O.
+func recorderFor[S any](ctx context.Context, nodeImpl Node[S]) context.Context {
+	if rec, _ := ctx.Value(recorderKey).(*ioRecorder); rec == nil {
+		return ctx
+	}
+	if effects, ok := nodeImpl.(interface{ Effects() SideEffectPolicy }); ok && effects.Effects().Recordable {
+		return ctx
+	}
+	return context.WithValue(ctx, recorderKey, (*ioRecorder)(nil))
+}
+
+// recordsIO reports whether any node declares Recordable I/O. Runs of such
+// engines are checkpointed at their start and end, so that ReplayRun can
+// re-execute them.
+func (e *Engine[S]) recordsIO() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, nodeImpl := range e.nodes {
+		if effects, ok := nodeImpl.(interface{ Effects() SideEffectPolicy }); ok && effects.Effects().Recordable {
+			return true
+		}
+	}
+	return false
+}
+
+// runRecorder returns the ioRecorder of the run executing in ctx, or nil.
+func runRecorder(ctx context.Context) *ioRecorder {
+	rec, _ := ctx.Value(recorderKey).(*ioRecorder)
+	return rec
+}
+
+// unsaved returns the recordings not yet stored in a checkpoint, and the
+// position to pass to markSaved once they are. A nil recorder has none.
+func (r *ioRecorder) unsaved() ([]RecordedIO, int) {
+	if r == nil {
+		return []RecordedIO{}, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedIO{}, r.ios[r.saved:]...), len(r.ios)
+}
+
+// markSaved records that the recordings before end are stored.
+func (r *ioRecorder) markSaved(end int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if end > r.saved {
+		r.saved = end
+	}
+}
+
+// add appends a recording made live.
+func (r *ioRecorder) add(recorded RecordedIO) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ios = append(r.ios, recorded)
+	r.served = append(r.served, true)
+}
+
+// unserved returns the recordings of nodeID that have not been served, with
+// their indices, to replay on the worker executing the node.
+func (r *ioRecorder) unserved(nodeID string) ([]RecordedIO, []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ios []RecordedIO
+	var indices []int
+	for i, recorded := range r.ios {
+		if !r.served[i] && recorded.NodeID == nodeID {
+			ios = append(ios, recorded)
+			indices = append(indices, i)
+		}
+	}
+	return ios, indices
+}
+
+// split returns which of the first n recordings a recorder seeded by
+// unserved has served, and the recordings made live after them.
+func (r *ioRecorder) split(n int) (served []int, live []RecordedIO) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < n && i < len(r.ios); i++ {
+		if r.served[i] {
+			served = append(served, i)
+		}
+	}
+	if n < len(r.ios) {
+		live = append(live, r.ios[n:]...)
+	}
+	return served, live
+}
+
+// merge applies the outcome of a node executed on a worker: served holds
+// positions in the recordings sent to it, whose indices are in indices, and
+// live the recordings it made.
+func (r *ioRecorder) merge(indices, served []int, live []RecordedIO) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, i := range served {
+		if i >= 0 && i < len(indices) && indices[i] < len(r.served) {
+			r.served[indices[i]] = true
+		}
+	}
+	for _, recorded := range live {
+		r.ios = append(r.ios, recorded)
+		r.served = append(r.served, true)
+	}
+}
+
+// lookup finds the first unserved recording for a call and marks it served.
+// It prefers a recording of the same request; under strict replay nothing
+// else matches and a missing recording is an ErrReplayMismatch.
+func (r *ioRecorder) lookup(nodeID string, attempt int, key string, request []byte) (RecordedIO, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sameCall := -1
+	for i, recorded := range r.ios {
+		if r.served[i] || recorded.NodeID != nodeID || recorded.Attempt != attempt || recorded.Key != key {
+			continue
+		}
+		if bytes.Equal(recorded.Request, request) {
+			sameCall = i
+			break
+		}
+		if sameCall < 0 && !r.strict {
+			sameCall = i
+		}
+	}
+
+	if sameCall < 0 {
+		if r.strict {
+			return RecordedIO{}, false, fmt.Errorf("%w: no recorded %s call for node %s attempt %d matches request %s",
+				ErrReplayMismatch, key, nodeID, attempt, request)
+		}
+		return RecordedIO{}, false, nil
+	}
+
+	recorded := r.ios[sameCall]
+	if r.strict {
+		if err := verifyReplayHash(recorded, recorded.Response); err != nil {
+			return RecordedIO{}, false, fmt.Errorf("recorded %s response for node %s is corrupt: %w", key, nodeID, err)
+		}
+	}
+	r.served[sameCall] = true
+	return recorded, true, nil
+}
+
+// decodeRecordedIOs converts the RecordedIOs of a checkpoint back into
+// []RecordedIO. Like Frontier, the field is stored as interface{} to avoid a
+// circular dependency, so it is round-tripped through JSON.
+func decodeRecordedIOs(recorded interface{}) ([]RecordedIO, error) {
+	var ios []RecordedIO
+	if recorded == nil {
+		return ios, nil
+	}
+
+	recordedJSON, err := json.Marshal(recorded)
+	if err != nil {
+		return nil, &EngineError{
+			Message: "failed to marshal recorded I/O: " + err.Error(),
+			Code:    "CHECKPOINT_FORMAT_ERROR",
+		}
+	}
+	if err := json.Unmarshal(recordedJSON, &ios); err != nil {
+		return nil, &EngineError{
+			Message: "failed to unmarshal recorded I/O: " + err.Error(),
+			Code:    "CHECKPOINT_FORMAT_ERROR",
+		}
+	}
+	return ios, nil
+}
+
+// canonicalJSON encodes v as compact JSON with sorted object keys, so values
+// compare and hash identically after a round trip through a store.
+func canonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
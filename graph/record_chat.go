@@ -0,0 +1,78 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+
+	"github.com/dshills/langgraph-go/graph/model"
+)
+
+// recordedChatStream performs a streaming chat call through fn and makes it
+// replayable like Recorded, under the given key and request.
+//
+// fn streams the live response, passing each chunk to onChunk as it arrives.
+// With Options.ReplayMode fn is not called: the recorded response is
+// delivered to onChunk by model.EmitChunks, as model.StreamChat delivers the
+// response of a model that cannot stream, and then returned.
+func recordedChatStream(ctx context.Context, key string, request interface{}, onChunk func(model.ChatChunk) error, fn func() (model.ChatOut, error)) (model.ChatOut, error) {
+	live := false
+	out, err := Recorded(ctx, key, request, func() (model.ChatOut, error) {
+		live = true
+		return fn()
+	})
+	if err != nil || live {
+		return out, err
+	}
+
+	// A replayed response was never streamed; deliver it in one piece
+	if err := model.EmitChunks(out, onChunk); err != nil {
+		return model.ChatOut{}, err
+	}
+	return out, nil
+}
+
+// RecordedChatModel wraps a model.ChatModel so that its Chat and ChatStream
+// calls are recorded and replayed through Recorded.
+//
+// The provider adapters in graph/model/openai, anthropic and google do not
+// record their calls; wrap them, or any other ChatModel, to make a run
+// replayable without calling the provider again.
+//
+// RecordedChatModel always implements model.StreamingChatModel; ChatStream
+// streams through model.StreamChat, so wrapped models that cannot stream
+// deliver their response in one piece.
+type RecordedChatModel struct {
+	chat model.ChatModel
+	key  string
+}
+
+// NewRecordedChatModel returns m with its calls recorded under key, for
+// example "mock.chat". Each call is recorded with a model.ChatRequest holding
+// its messages and tools.
+//
+// Example:
+//
+//	llm := graph.NewRecordedChatModel(&model.MockChatModel{
+//	    Responses: []model.ChatOut{{Text: "Paris"}},
+//	}, "mock.chat")
+func NewRecordedChatModel(m model.ChatModel, key string) *RecordedChatModel {
+	return &RecordedChatModel{chat: m, key: key}
+}
+
+// Chat calls the wrapped model's Chat through Recorded.
+func (r *RecordedChatModel) Chat(ctx context.Context, messages []model.Message, tools []model.ToolSpec) (model.ChatOut, error) {
+	request := model.ChatRequest{Messages: messages, Tools: tools}
+	return Recorded(ctx, r.key, request, func() (model.ChatOut, error) {
+		return r.chat.Chat(ctx, messages, tools)
+	})
+}
+
+// ChatStream streams from the wrapped model under the same key and request as
+// Chat, so a run recorded with one method replays with the other. In replay
+// mode the recorded response is delivered to onChunk in one piece.
+func (r *RecordedChatModel) ChatStream(ctx context.Context, messages []model.Message, tools []model.ToolSpec, onChunk func(model.ChatChunk) error) (model.ChatOut, error) {
+	request := model.ChatRequest{Messages: messages, Tools: tools}
+	return recordedChatStream(ctx, r.key, request, onChunk, func() (model.ChatOut, error) {
+		return model.StreamChat(ctx, r.chat, messages, tools, onChunk)
+	})
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/model"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// recordedChatNode asks a model a question from a node with recordable I/O,
+// streaming the answer into chunks when stream is set.
+type recordedChatNode struct {
+	llm    model.ChatModel
+	stream bool
+	chunks *[]string
+}
+
+func (n recordedChatNode) Run(ctx context.Context, _ string) NodeResult[string] {
+	messages := []model.Message{{Role: model.RoleUser, Content: "Capital of France?"}}
+	var out model.ChatOut
+	var err error
+	if n.stream {
+		out, err = model.StreamChat(ctx, n.llm, messages, nil, func(chunk model.ChatChunk) error {
+			*n.chunks = append(*n.chunks, chunk.Text)
+			return nil
+		})
+	} else {
+		out, err = n.llm.Chat(ctx, messages, nil)
+	}
+	if err != nil {
+		return NodeResult[string]{Err: err}
+	}
+	return NodeResult[string]{Delta: out.Text, Route: Stop()}
+}
+
+func (n recordedChatNode) Effects() SideEffectPolicy {
+	return SideEffectPolicy{Recordable: true}
+}
+
+// TestRecordedChatModel verifies that models wrapped in RecordedChatModel are
+// recorded and replayed, whether they are called with Chat or streamed.
+func TestRecordedChatModel(t *testing.T) {
+	run := func(st store.Store[string], node recordedChatNode, opts Options) (string, error) {
+		engine := New(func(prev, delta string) string { return prev + delta }, st, &mockEmitter{}, opts)
+		if err := engine.Add("ask", node); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("ask"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine.Run(context.Background(), "chat-run", "")
+	}
+	replay := Options{ReplayMode: true, StrictReplay: true}
+
+	tests := []struct {
+		name   string
+		stream bool
+	}{
+		{name: "chat", stream: false},
+		{name: "stream", stream: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := store.NewMemStore[string]()
+			live := &model.MockChatModel{Responses: []model.ChatOut{{Text: "Paris is the capital"}}}
+			var liveChunks, replayedChunks []string
+			recorded, err := run(st, recordedChatNode{
+				llm: NewRecordedChatModel(live, "mock.chat"), stream: tt.stream, chunks: &liveChunks,
+			}, Options{})
+			if err != nil {
+				t.Fatalf("record Run failed: %v", err)
+			}
+
+			changed := &model.MockChatModel{Responses: []model.ChatOut{{Text: "Lyon"}}}
+			replayed, err := run(st, recordedChatNode{
+				llm: NewRecordedChatModel(changed, "mock.chat"), stream: tt.stream, chunks: &replayedChunks,
+			}, replay)
+			if err != nil {
+				t.Fatalf("replay Run failed: %v", err)
+			}
+
+			if recorded != "Paris is the capital" || replayed != recorded {
+				t.Errorf("replayed %q, recorded %q, want both %q", replayed, recorded, "Paris is the capital")
+			}
+			if live.CallCount() != 1 || changed.CallCount() != 0 {
+				t.Errorf("model called %d times live and %d in replay, want 1 and 0", live.CallCount(), changed.CallCount())
+			}
+			if tt.stream && (len(liveChunks) != 4 || len(replayedChunks) != 1 || replayedChunks[0] != recorded) {
+				t.Errorf("streamed %q live and %q in replay, want 4 chunks and the recorded text", liveChunks, replayedChunks)
+			}
+		})
+	}
+
+	t.Run("unrecorded stream fails strict replay", func(t *testing.T) {
+		var chunks []string
+		llm := NewRecordedChatModel(&model.MockChatModel{Responses: []model.ChatOut{{Text: "live"}}}, "mock.chat")
+		_, err := run(store.NewMemStore[string](), recordedChatNode{llm: llm, stream: true, chunks: &chunks}, replay)
+		if err == nil || len(chunks) != 0 {
+			t.Errorf("Run = %v with chunks %q, want a replay error and no chunks", err, chunks)
+		}
+	})
+}
@@ -0,0 +1,367 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// lookupService stands in for an external service called through Recorded.
+type lookupService struct {
+	mu       sync.Mutex
+	calls    int
+	failures int
+	answer   string
+}
+
+func (s *lookupService) lookup(query string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return "", errors.New("service unavailable")
+	}
+	return s.answer + ":" + query, nil
+}
+
+func (s *lookupService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// lookupNode appends the service's answer for its query to the state.
+type lookupNode struct {
+	service    *lookupService
+	query      string
+	recordable bool
+	policy     NodePolicy
+	executed   int
+}
+
+func (n *lookupNode) Run(ctx context.Context, s string) NodeResult[string] {
+	n.executed++
+	answer, err := Recorded(ctx, "lookup", n.query, func() (string, error) {
+		return n.service.lookup(n.query)
+	})
+	if err != nil {
+		return NodeResult[string]{Err: err}
+	}
+	return NodeResult[string]{Delta: answer, Route: Stop()}
+}
+
+func (n *lookupNode) Effects() SideEffectPolicy {
+	return SideEffectPolicy{Recordable: n.recordable}
+}
+
+func (n *lookupNode) Policy() NodePolicy {
+	return n.policy
+}
+
+// TestRecorded verifies that Recorded captures external calls into checkpoints
+// and serves them in replay mode.
+func TestRecorded(t *testing.T) {
+	reducer := func(prev, delta string) string {
+		if prev == "" {
+			return delta
+		}
+		return prev + "," + delta
+	}
+
+	// newEngine builds a fan-out to two lookup nodes.
+	newEngine := func(t *testing.T, st store.Store[string], mode int, opts Options, nodes ...*lookupNode) *Engine[string] {
+		t.Helper()
+		opts.MaxSteps = 10
+		opts.MaxConcurrentNodes = mode
+		engine := New(reducer, st, &mockEmitter{}, opts)
+		targets := make([]string, len(nodes))
+		for i, node := range nodes {
+			targets[i] = fmt.Sprintf("lookup%d", i)
+			if err := engine.Add(targets[i], node); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+		}
+		fanout := NodeFunc[string](func(ctx context.Context, s string) NodeResult[string] {
+			return NodeResult[string]{Route: Many(targets)}
+		})
+		if err := engine.Add("fanout", fanout); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("replay serves recorded responses", func(t *testing.T) {
+				st := store.NewMemStore[string]()
+				live := &lookupService{answer: "live"}
+				recorded, err := newEngine(t, st, mode.maxConcurrentNodes, Options{},
+					&lookupNode{service: live, query: "a", recordable: true},
+					&lookupNode{service: live, query: "b", recordable: true},
+				).Run(context.Background(), "record-run", "")
+				if err != nil {
+					t.Fatalf("record Run failed: %v", err)
+				}
+
+				replayService := &lookupService{answer: "changed"}
+				replayed, err := newEngine(t, st, mode.maxConcurrentNodes, Options{ReplayMode: true, StrictReplay: true},
+					&lookupNode{service: replayService, query: "a", recordable: true},
+					&lookupNode{service: replayService, query: "b", recordable: true},
+				).Run(context.Background(), "record-run", "")
+				if err != nil {
+					t.Fatalf("replay Run failed: %v", err)
+				}
+
+				// Merge order differs between modes but not between record and replay
+				if replayed != recorded || len(recorded) != len("live:a,live:b") {
+					t.Errorf("replayed %q, recorded %q, want both answers from the live service", replayed, recorded)
+				}
+				if calls := replayService.callCount(); calls != 0 {
+					t.Errorf("replay made %d live calls, want 0", calls)
+				}
+			})
+
+			t.Run("ReplayRun re-executes the run", func(t *testing.T) {
+				st := store.NewMemStore[string]()
+				live := &lookupService{answer: "live"}
+				recorded, err := newEngine(t, st, mode.maxConcurrentNodes, Options{},
+					&lookupNode{service: live, query: "a", recordable: true},
+					&lookupNode{service: live, query: "b", recordable: true},
+				).Run(context.Background(), "replayed-run", "")
+				if err != nil {
+					t.Fatalf("record Run failed: %v", err)
+				}
+
+				replayService := &lookupService{answer: "changed"}
+				nodes := []*lookupNode{
+					{service: replayService, query: "a", recordable: true},
+					{service: replayService, query: "b", recordable: true},
+				}
+				replayed, err := newEngine(t, st, mode.maxConcurrentNodes, Options{ReplayMode: true, StrictReplay: true}, nodes...).
+					ReplayRun(context.Background(), "replayed-run")
+				if err != nil {
+					t.Fatalf("ReplayRun failed: %v", err)
+				}
+
+				if replayed != recorded || nodes[0].executed != 1 || nodes[1].executed != 1 {
+					t.Errorf("replayed %q executing the lookups %d and %d times, want %q executing each once",
+						replayed, nodes[0].executed, nodes[1].executed, recorded)
+				}
+				if calls := replayService.callCount(); calls != 0 {
+					t.Errorf("replay made %d live calls, want 0", calls)
+				}
+			})
+
+			t.Run("each recording is stored in one checkpoint", func(t *testing.T) {
+				st := store.NewMemStore[string]()
+				service := &lookupService{answer: "live"}
+				if _, err := newEngine(t, st, mode.maxConcurrentNodes, Options{CheckpointEveryStep: true},
+					&lookupNode{service: service, query: "a", recordable: true},
+					&lookupNode{service: service, query: "b", recordable: true},
+				).Run(context.Background(), "stepped-run", ""); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+
+				checkpoints, err := st.ListCheckpoints(context.Background(), "stepped-run", "")
+				if err != nil {
+					t.Fatalf("ListCheckpoints failed: %v", err)
+				}
+				stored := 0
+				for _, checkpoint := range checkpoints {
+					ios, err := decodeRecordedIOs(checkpoint.RecordedIOs)
+					if err != nil {
+						t.Fatalf("decodeRecordedIOs failed: %v", err)
+					}
+					stored += len(ios)
+				}
+				if len(checkpoints) < 3 || stored != 2 {
+					t.Errorf("%d checkpoints store %d recordings, want the 2 lookups stored once", len(checkpoints), stored)
+				}
+			})
+
+			t.Run("checkpoints hold recordings of recordable nodes only", func(t *testing.T) {
+				st := store.NewMemStore[string]()
+				service := &lookupService{answer: "live"}
+				engine := newEngine(t, st, mode.maxConcurrentNodes, Options{},
+					&lookupNode{service: service, query: "a", recordable: true},
+					&lookupNode{service: service, query: "b"},
+				)
+				if _, err := engine.Run(context.Background(), "partial-run", ""); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+
+				checkpoint, err := engine.latestCheckpoint(context.Background(), "partial-run")
+				if err != nil {
+					t.Fatalf("latestCheckpoint failed: %v", err)
+				}
+				ios, err := decodeRecordedIOs(checkpoint.RecordedIOs)
+				if err != nil {
+					t.Fatalf("decodeRecordedIOs failed: %v", err)
+				}
+				if len(ios) != 1 || ios[0].NodeID != "lookup0" || ios[0].Key != "lookup" || string(ios[0].Request) != `"a"` {
+					t.Errorf("recorded %+v, want one lookup of \"a\" by lookup0", ios)
+				}
+			})
+
+			t.Run("strict replay rejects changed requests", func(t *testing.T) {
+				st := store.NewMemStore[string]()
+				service := &lookupService{answer: "live"}
+				if _, err := newEngine(t, st, mode.maxConcurrentNodes, Options{},
+					&lookupNode{service: service, query: "a", recordable: true},
+				).Run(context.Background(), "changed-run", ""); err != nil {
+					t.Fatalf("record Run failed: %v", err)
+				}
+
+				_, err := newEngine(t, st, mode.maxConcurrentNodes, Options{ReplayMode: true, StrictReplay: true},
+					&lookupNode{service: service, query: "z", recordable: true},
+				).Run(context.Background(), "changed-run", "")
+				if !errors.Is(err, ErrReplayMismatch) {
+					t.Errorf("expected ErrReplayMismatch, got %v", err)
+				}
+
+				// Best-effort replay serves the node's recording regardless
+				final, err := newEngine(t, st, mode.maxConcurrentNodes, Options{ReplayMode: true},
+					&lookupNode{service: service, query: "z", recordable: true},
+				).Run(context.Background(), "changed-run", "")
+				if err != nil || final != "live:a" {
+					t.Errorf("best-effort replay = %q, %v; want %q", final, err, "live:a")
+				}
+				if calls := service.callCount(); calls != 1 {
+					t.Errorf("service called %d times, want 1", calls)
+				}
+			})
+
+			t.Run("replayed errors follow the recorded retries", func(t *testing.T) {
+				policy := NodePolicy{RetryPolicy: &RetryPolicy{
+					MaxAttempts: 3,
+					BaseDelay:   time.Millisecond,
+					Retryable:   func(error) bool { return true },
+				}}
+				st := store.NewMemStore[string]()
+				flaky := &lookupService{answer: "live", failures: 1}
+				if _, err := newEngine(t, st, mode.maxConcurrentNodes, Options{},
+					&lookupNode{service: flaky, query: "a", recordable: true, policy: policy},
+				).Run(context.Background(), "flaky-run", ""); err != nil {
+					t.Fatalf("record Run failed: %v", err)
+				}
+
+				replayService := &lookupService{answer: "changed"}
+				final, err := newEngine(t, st, mode.maxConcurrentNodes, Options{ReplayMode: true, StrictReplay: true},
+					&lookupNode{service: replayService, query: "a", recordable: true, policy: policy},
+				).Run(context.Background(), "flaky-run", "")
+				if err != nil || final != "live:a" {
+					t.Errorf("replay = %q, %v; want %q", final, err, "live:a")
+				}
+				if calls := replayService.callCount(); calls != 0 {
+					t.Errorf("replay made %d live calls, want 0", calls)
+				}
+			})
+		})
+	}
+
+	t.Run("sequential runs without recordable nodes are not checkpointed", func(t *testing.T) {
+		st := store.NewMemStore[string]()
+		engine := newEngine(t, st, 0, Options{},
+			&lookupNode{service: &lookupService{answer: "live"}, query: "a"},
+		)
+		if _, err := engine.Run(context.Background(), "unrecorded-run", ""); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		_, err := engine.latestCheckpoint(context.Background(), "unrecorded-run")
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "NO_CHECKPOINTS" {
+			t.Errorf("expected NO_CHECKPOINTS, got %v", err)
+		}
+	})
+
+	t.Run("outside a run fn is called directly", func(t *testing.T) {
+		got, err := Recorded(context.Background(), "lookup", "q", func() (int, error) { return 42, nil })
+		if err != nil || got != 42 {
+			t.Errorf("Recorded = %d, %v; want 42", got, err)
+		}
+	})
+}
+
+// TestIORecorder_StrictIntegrity verifies that strict replay refuses a
+// recording whose response no longer matches its hash.
+func TestIORecorder_StrictIntegrity(t *testing.T) {
+	recorded, err := recordIO("node", 0, "q", map[string]int{"b": 2, "a": 1})
+	if err != nil {
+		t.Fatalf("recordIO failed: %v", err)
+	}
+	recorded.Key = "lookup"
+	if string(recorded.Response) != `{"a":1,"b":2}` {
+		t.Errorf("response %s is not canonical JSON", recorded.Response)
+	}
+
+	request, _ := canonicalJSON("q")
+	rec := newIORecorder([]RecordedIO{recorded}, true, true)
+	if _, found, err := rec.lookup("node", 0, "lookup", request); err != nil || !found {
+		t.Fatalf("lookup = %v, %v; want found", found, err)
+	}
+	if _, _, err := rec.lookup("node", 0, "lookup", request); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("second lookup: expected ErrReplayMismatch, got %v", err)
+	}
+
+	recorded.Response = []byte(`{"a":1,"b":3}`)
+	rec = newIORecorder([]RecordedIO{recorded}, true, true)
+	if _, _, err := rec.lookup("node", 0, "lookup", request); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("tampered response: expected ErrReplayMismatch, got %v", err)
+	}
+}
+
+// TestEngine_ReplayRun verifies that a run's checkpoints are found through
+// store.HistoryStore instead of probing step IDs, and that ReplayRun refuses
+// runs whose earliest checkpoint has no work to re-execute.
+func TestEngine_ReplayRun(t *testing.T) {
+	ctx := context.Background()
+	reducer := func(prev, delta string) string { return delta }
+
+	st := store.NewMemStore[string]()
+	for _, step := range []int{0, 5000} {
+		if err := st.SaveCheckpointV2(ctx, store.CheckpointV2[string]{
+			RunID:          "long-run",
+			StepID:         step,
+			State:          fmt.Sprintf("state at %d", step),
+			IdempotencyKey: fmt.Sprintf("long-run:%d", step),
+		}); err != nil {
+			t.Fatalf("SaveCheckpointV2 failed: %v", err)
+		}
+	}
+
+	t.Run("history stores list checkpoints past MaxSteps", func(t *testing.T) {
+		engine := New(reducer, st, &mockEmitter{}, Options{MaxSteps: 10, ReplayMode: true})
+		latest, err := engine.latestCheckpoint(ctx, "long-run")
+		if err != nil || latest.State != "state at 5000" {
+			t.Errorf("latestCheckpoint = %q, %v; want the checkpoint at step 5000", latest.State, err)
+		}
+	})
+
+	t.Run("other stores are probed up to MaxSteps", func(t *testing.T) {
+		// Embedding the interface hides MemStore's history methods
+		engine := New(reducer, struct{ store.Store[string] }{st}, &mockEmitter{}, Options{MaxSteps: 10, ReplayMode: true})
+		latest, err := engine.latestCheckpoint(ctx, "long-run")
+		if err != nil || latest.State != "state at 0" {
+			t.Errorf("latestCheckpoint = %q, %v; want the checkpoint at step 0", latest.State, err)
+		}
+	})
+
+	t.Run("runs without a start to re-execute are not replayable", func(t *testing.T) {
+		engine := New(reducer, st, &mockEmitter{}, Options{ReplayMode: true})
+		_, err := engine.ReplayRun(ctx, "long-run")
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "NOT_REPLAYABLE" {
+			t.Errorf("expected NOT_REPLAYABLE, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,319 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// RecoveredRun reports a run resumed by Engine.RecoverIncomplete.
+type RecoveredRun[S any] struct {
+	// RunID identifies the recovered run.
+	RunID string
+
+	// StepID is the step of the checkpoint the run resumed from.
+	StepID int
+
+	// State is the final state of the resumed run.
+	State S
+
+	// Err is the error the resumed run returned, or the reason it could not
+	// be resumed.
+	Err error
+}
+
+// RecoverIncomplete resumes the runs abandoned by crashed processes. It claims
+// every run whose lease expired in the store (see Options.LeaseDuration),
+// continues each one from its latest CheckpointV2 frontier as
+// RunWithCheckpoint does, and waits for them to finish.
+//
+// Call it when a process starts, once the graph is built. Claims are atomic,
+// so processes sharing a store can recover at the same time without resuming
+// a run twice. Each resumed run is reported in a run_recovered event.
+//
+// Leased runs are checkpointed after each step, so a resumed run continues
+// from its last completed step: only the nodes that were executing when its
+// process crashed run again, and their side effects may repeat. A run without
+// a checkpoint cannot be resumed and is recorded as failed.
+// The outcome of each run is reported in the result, ordered by RunID; the
+// returned error only reports that recovery could not start.
+//
+// Returns an EngineError with code:
+//   - LEASES_DISABLED if Options.LeaseDuration is not set
+//   - RECOVERY_UNSUPPORTED if the store does not implement store.RunTracker
+//   - STORE_ERROR if the expired runs cannot be claimed
+//
+// Example:
+//
+//	engine := graph.New(reducer, st, emitter, graph.WithRunLeases(30*time.Second))
+//	// ... add nodes and edges ...
+//	recovered, err := engine.RecoverIncomplete(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, run := range recovered {
+//	    log.Printf("recovered %s from step %d: %v", run.RunID, run.StepID, run.Err)
+//	}
+func (e *Engine[S]) RecoverIncomplete(ctx context.Context) ([]RecoveredRun[S], error) {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return nil, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if e.opts.LeaseDuration <= 0 {
+		return nil, &EngineError{
+			Message: "run leases are disabled; set Options.LeaseDuration",
+			Code:    "LEASES_DISABLED",
+		}
+	}
+	tracker := e.runTracker()
+	if tracker == nil {
+		return nil, &EngineError{
+			Message: "store does not implement store.RunTracker",
+			Code:    "RECOVERY_UNSUPPORTED",
+		}
+	}
+
+	claimed, err := tracker.ClaimExpiredRuns(ctx, e.leaseOwner(), time.Now().Add(e.opts.LeaseDuration), 0)
+	if err != nil {
+		return nil, &EngineError{
+			Message: "failed to claim expired runs: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].RunID < claimed[j].RunID
+	})
+
+	recovered := make([]RecoveredRun[S], len(claimed))
+	var wg sync.WaitGroup
+	for i, record := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recovered[i] = e.recoverRun(ctx, tracker, record.RunID)
+		}()
+	}
+	wg.Wait()
+	return recovered, nil
+}
+
+// recoverRun resumes a claimed run from its latest checkpoint.
+func (e *Engine[S]) recoverRun(ctx context.Context, tracker store.RunTracker, runID string) RecoveredRun[S] {
+	recovered := RecoveredRun[S]{RunID: runID}
+	lease := e.newLease(tracker, runID)
+
+	checkpoint, err := e.latestCheckpoint(ctx, runID)
+	if err != nil {
+		var engineErr *EngineError
+		if errors.As(err, &engineErr) && engineErr.Code == "NO_CHECKPOINTS" {
+			recovered.Err = lease.settle(ctx, err)
+		} else {
+			// The checkpoint may load on a later attempt
+			lease.release(ctx)
+			recovered.Err = err
+		}
+		return recovered
+	}
+	recovered.StepID = checkpoint.StepID
+
+	e.publish(emit.Event{
+		RunID: runID,
+		Step:  checkpoint.StepID,
+		Msg:   "run_recovered",
+		Meta: map[string]interface{}{
+			"owner": lease.owner,
+		},
+	})
+	recovered.State, recovered.Err = e.RunWithCheckpoint(ctx, checkpoint)
+
+	// A checkpoint with nothing left to run returns without executing, so the
+	// outcome is recorded here too. Finishing a run again changes nothing.
+	var engineErr *EngineError
+	if !errors.As(recovered.Err, &engineErr) || engineErr.Code != "RUN_IN_PROGRESS" {
+		_ = lease.settle(ctx, recovered.Err)
+	}
+	return recovered
+}
+
+// runTracker returns the store's RunTracker if runs are tracked under
+// Options.LeaseDuration, or nil.
+func (e *Engine[S]) runTracker() store.RunTracker {
+	if e.opts.LeaseDuration <= 0 {
+		return nil
+	}
+	tracker, _ := e.store.(store.RunTracker)
+	return tracker
+}
+
+// checkpointsSteps reports whether run is checkpointed when it starts and
+// after each step: under Options.CheckpointEveryStep, or while it holds a
+// lease, so that RecoverIncomplete can continue it from its last step.
+func (e *Engine[S]) checkpointsSteps(run *activeRun[S]) bool {
+	return e.opts.CheckpointEveryStep || run.lease != nil
+}
+
+// leaseOwner returns Options.LeaseOwner, defaulting to "hostname:pid".
+func (e *Engine[S]) leaseOwner() string {
+	if e.opts.LeaseOwner != "" {
+		return e.opts.LeaseOwner
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// newLease returns the lease of a run held by this engine's owner.
+func (e *Engine[S]) newLease(tracker store.RunTracker, runID string) *runLease {
+	return &runLease{
+		tracker:  tracker,
+		runID:    runID,
+		owner:    e.leaseOwner(),
+		duration: e.opts.LeaseDuration,
+		publish:  e.publish,
+	}
+}
+
+// startLease records that a run started and renews its lease every third of
+// Options.LeaseDuration until the run settles. If the lease is lost to
+// another owner the run is cancelled through cancel. Returns nil if runs are
+// not tracked.
+func (e *Engine[S]) startLease(ctx context.Context, runID string, cancel context.CancelCauseFunc) (*runLease, error) {
+	tracker := e.runTracker()
+	if tracker == nil {
+		return nil, nil
+	}
+	lease := e.newLease(tracker, runID)
+	if err := tracker.StartRun(ctx, runID, lease.owner, time.Now().Add(lease.duration)); err != nil {
+		return nil, &EngineError{
+			Message: "failed to record run start: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	lease.stop = make(chan struct{})
+	lease.stopped = make(chan struct{})
+	go lease.heartbeat(context.WithoutCancel(ctx), cancel)
+	return lease, nil
+}
+
+// runLease is a run's status record in a store.RunTracker, held by one owner.
+type runLease struct {
+	tracker  store.RunTracker
+	runID    string
+	owner    string
+	duration time.Duration
+	publish  func(emit.Event)
+
+	stop     chan struct{} // Closed to stop the heartbeat, nil without one
+	stopped  chan struct{} // Closed once the heartbeat returned
+	stopOnce sync.Once
+	lost     atomic.Bool // Set once another owner claimed the run
+}
+
+// heartbeat renews the lease until stop is closed. A lease that cannot be
+// renewed for a while is only reported, since the run may still finish
+// before it expires.
+func (l *runLease) heartbeat(ctx context.Context, cancel context.CancelCauseFunc) {
+	defer close(l.stopped)
+	ticker := time.NewTicker(max(l.duration/3, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := l.tracker.RenewLease(ctx, l.runID, l.owner, time.Now().Add(l.duration))
+		if errors.Is(err, store.ErrLeaseLost) {
+			l.lost.Store(true)
+			cancel(err)
+			return
+		}
+		if err != nil {
+			l.publish(emit.Event{
+				RunID: l.runID,
+				Msg:   "lease_renew_failed",
+				Meta: map[string]interface{}{
+					"error": err.Error(),
+				},
+			})
+		}
+	}
+}
+
+// halt stops the heartbeat and waits for it to return, so that it cannot
+// renew the lease after the run settled.
+func (l *runLease) halt() {
+	if l.stop == nil {
+		return
+	}
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.stopped
+}
+
+// settle stops the heartbeat and records the outcome err of the run, which
+// it returns. A run abandoned because ctx was cancelled stays running with
+// its lease released, so RecoverIncomplete can resume it. A run that lost
+// its lease reports LEASE_LOST and leaves the record to its new owner.
+func (l *runLease) settle(ctx context.Context, err error) error {
+	l.halt()
+	if err != nil && l.lost.Load() {
+		return &EngineError{
+			Message: "run was claimed by another owner: " + l.runID,
+			Code:    "LEASE_LOST",
+		}
+	}
+
+	status, errMsg := store.RunCompleted, ""
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrInterrupted):
+		status = store.RunInterrupted
+	case errors.Is(err, ErrCancelled):
+		status = store.RunCancelled
+	case ctx.Err() != nil:
+		l.release(ctx)
+		return err
+	default:
+		status, errMsg = store.RunFailed, err.Error()
+	}
+
+	// The run's context may be cancelled, but the outcome must still be saved
+	saveCtx := context.WithoutCancel(ctx)
+	l.report(l.tracker.FinishRun(saveCtx, l.runID, l.owner, status, errMsg))
+	return err
+}
+
+// release expires the lease of a run that is still running.
+func (l *runLease) release(ctx context.Context) {
+	l.report(l.tracker.RenewLease(context.WithoutCancel(ctx), l.runID, l.owner, time.Now()))
+}
+
+// report emits run_status_save_failed if the run's record could not be
+// updated. A record held by another owner is no longer this owner's to
+// update, so ErrLeaseLost is not reported.
+func (l *runLease) report(err error) {
+	if err == nil || errors.Is(err, store.ErrLeaseLost) {
+		return
+	}
+	l.publish(emit.Event{
+		RunID: l.runID,
+		Msg:   "run_status_save_failed",
+		Meta: map[string]interface{}{
+			"error": err.Error(),
+		},
+	})
+}
@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestEngine_RecoverIncomplete verifies runs record their outcome under a
+// lease and that runs abandoned by another owner are resumed from their
+// latest checkpoint.
+func TestEngine_RecoverIncomplete(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Visited []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Visited = append(prev.Visited, delta.Visited...)
+		return prev
+	}
+
+	// newEngine builds a -> b -> c, where b signals started and then blocks
+	// until release is closed or its context is cancelled
+	newEngine := func(st store.Store[TestState], emitter *mockEmitter, opts Options, started chan<- struct{}, release <-chan struct{}) *Engine[TestState] {
+		engine := New(reducer, st, emitter, opts)
+		a := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Visited: []string{"a"}}, Route: Goto("b")}
+		})
+		b := NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+			started <- struct{}{}
+			select {
+			case <-release:
+				return NodeResult[TestState]{Delta: TestState{Visited: []string{"b"}}, Route: Goto("c")}
+			case <-ctx.Done():
+				return NodeResult[TestState]{Err: ctx.Err()}
+			}
+		})
+		c := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Visited: []string{"c"}}, Route: Stop()}
+		})
+		_ = engine.Add("a", a)
+		_ = engine.Add("b", b)
+		_ = engine.Add("c", c)
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range []struct {
+		name string
+		opts Options
+	}{
+		{name: "sequential", opts: Options{MaxSteps: 20, LeaseDuration: time.Minute}},
+		{name: "concurrent", opts: Options{MaxSteps: 20, MaxConcurrentNodes: 4, LeaseDuration: time.Minute}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("outcome is recorded", func(t *testing.T) {
+				st := store.NewMemStore[TestState]()
+				started, release := make(chan struct{}, 1), make(chan struct{})
+				close(release)
+				engine := newEngine(st, &mockEmitter{}, mode.opts, started, release)
+				if _, err := engine.Run(ctx, "run-001", TestState{}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if record, err := st.LoadRun(ctx, "run-001"); err != nil || record.Status != store.RunCompleted || !record.LeaseExpiresAt.IsZero() {
+					t.Errorf("LoadRun = %+v, %v; want a completed run", record, err)
+				}
+
+				started = make(chan struct{})
+				blocked := newEngine(st, &mockEmitter{}, mode.opts, started, nil)
+				done := make(chan error)
+				go func() {
+					_, err := blocked.Run(ctx, "run-002", TestState{})
+					done <- err
+				}()
+				<-started
+				if record, err := st.LoadRun(ctx, "run-002"); err != nil || record.Status != store.RunRunning || !record.LeaseExpiresAt.After(time.Now()) {
+					t.Errorf("LoadRun = %+v, %v; want a running run holding its lease", record, err)
+				}
+				_ = blocked.Cancel("run-002", "user request")
+				if err := <-done; !errors.Is(err, ErrCancelled) {
+					t.Fatalf("Run returned %v, want ErrCancelled", err)
+				}
+				if record, err := st.LoadRun(ctx, "run-002"); err != nil || record.Status != store.RunCancelled {
+					t.Errorf("LoadRun = %+v, %v; want a cancelled run", record, err)
+				}
+			})
+
+			t.Run("abandoned run is resumed", func(t *testing.T) {
+				st := store.NewMemStore[TestState]()
+				opts := mode.opts
+				opts.LeaseOwner = "worker-1"
+				started := make(chan struct{})
+				crashed := newEngine(st, &mockEmitter{}, opts, started, nil)
+				runCtx, stop := context.WithCancel(ctx)
+				done := make(chan error)
+				go func() {
+					_, err := crashed.Run(runCtx, "run-001", TestState{})
+					done <- err
+				}()
+				<-started
+
+				opts.LeaseOwner = "worker-2"
+				emitter := &mockEmitter{}
+				restarted, release := make(chan struct{}, 1), make(chan struct{})
+				close(release)
+				engine := newEngine(st, emitter, opts, restarted, release)
+
+				// A run whose lease is held is not recovered
+				if recovered, err := engine.RecoverIncomplete(ctx); err != nil || len(recovered) != 0 {
+					t.Fatalf("RecoverIncomplete = %+v, %v; want nothing to recover", recovered, err)
+				}
+
+				// Stopping the process releases the lease of its runs
+				stop()
+				if err := <-done; !errors.Is(err, context.Canceled) {
+					t.Fatalf("Run returned %v, want context.Canceled", err)
+				}
+				record, err := st.LoadRun(ctx, "run-001")
+				if err != nil || record.Status != store.RunRunning || record.LeaseExpiresAt.After(time.Now()) {
+					t.Fatalf("LoadRun = %+v, %v; want a running run with an expired lease", record, err)
+				}
+
+				recovered, err := engine.RecoverIncomplete(ctx)
+				if err != nil {
+					t.Fatalf("RecoverIncomplete failed: %v", err)
+				}
+				if len(recovered) != 1 || recovered[0].RunID != "run-001" || recovered[0].Err != nil {
+					t.Fatalf("RecoverIncomplete = %+v, want run-001 resumed", recovered)
+				}
+				// a completed before the crash, so the run continues from b
+				// without running a again
+				if recovered[0].StepID != 1 {
+					t.Errorf("resumed from step %d, want 1", recovered[0].StepID)
+				}
+				// Concurrent mode merges deltas by OrderKey rather than step
+				visited := recovered[0].State.Visited
+				sort.Strings(visited)
+				if !reflect.DeepEqual(visited, []string{"a", "b", "c"}) {
+					t.Errorf("visited %v, want a, b and c", visited)
+				}
+				if record, err := st.LoadRun(ctx, "run-001"); err != nil || record.Status != store.RunCompleted || record.Owner != "worker-2" {
+					t.Errorf("LoadRun = %+v, %v; want run-001 completed by worker-2", record, err)
+				}
+
+				var steps []int
+				for _, event := range emitter.events {
+					if event.Msg == "run_recovered" {
+						steps = append(steps, event.Step)
+					}
+				}
+				if !reflect.DeepEqual(steps, []int{recovered[0].StepID}) {
+					t.Errorf("run_recovered events at steps %v, want one at step %d", steps, recovered[0].StepID)
+				}
+			})
+
+			t.Run("lease lost to another owner", func(t *testing.T) {
+				st := store.NewMemStore[TestState]()
+				opts := mode.opts
+				opts.LeaseDuration = 30 * time.Millisecond
+				opts.LeaseOwner = "worker-1"
+				started := make(chan struct{})
+				engine := newEngine(st, &mockEmitter{}, opts, started, nil)
+				done := make(chan error)
+				go func() {
+					_, err := engine.Run(ctx, "run-001", TestState{})
+					done <- err
+				}()
+				<-started
+
+				// Another process took the run over, e.g. after a long pause
+				if err := st.StartRun(ctx, "run-001", "worker-2", time.Now().Add(time.Hour)); err != nil {
+					t.Fatalf("StartRun failed: %v", err)
+				}
+				var engineErr *EngineError
+				if err := <-done; !errors.As(err, &engineErr) || engineErr.Code != "LEASE_LOST" {
+					t.Fatalf("Run returned %v, want LEASE_LOST", err)
+				}
+				if record, _ := st.LoadRun(ctx, "run-001"); record.Status != store.RunRunning || record.Owner != "worker-2" {
+					t.Errorf("LoadRun = %+v, want the record left to worker-2", record)
+				}
+			})
+		})
+	}
+
+	t.Run("run without checkpoint fails", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		if err := st.StartRun(ctx, "orphan", "worker-1", time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("StartRun failed: %v", err)
+		}
+		engine := New(reducer, st, &mockEmitter{}, Options{LeaseDuration: time.Minute})
+
+		recovered, err := engine.RecoverIncomplete(ctx)
+		if err != nil {
+			t.Fatalf("RecoverIncomplete failed: %v", err)
+		}
+		var engineErr *EngineError
+		if len(recovered) != 1 || !errors.As(recovered[0].Err, &engineErr) || engineErr.Code != "NO_CHECKPOINTS" {
+			t.Fatalf("RecoverIncomplete = %+v, want orphan without checkpoints", recovered)
+		}
+		if record, err := st.LoadRun(ctx, "orphan"); err != nil || record.Status != store.RunFailed || record.Error == "" {
+			t.Errorf("LoadRun = %+v, %v; want a failed run", record, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		disabled := New(reducer, st, &mockEmitter{}, Options{})
+		// Embedding the interface hides MemStore's RunTracker methods
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		close(release)
+		unsupported := newEngine(struct{ store.Store[TestState] }{st}, &mockEmitter{}, Options{LeaseDuration: time.Minute}, started, release)
+
+		tests := []struct {
+			name     string
+			engine   *Engine[TestState]
+			wantCode string
+		}{
+			{name: "leases disabled", engine: disabled, wantCode: "LEASES_DISABLED"},
+			{name: "store without run tracking", engine: unsupported, wantCode: "RECOVERY_UNSUPPORTED"},
+			{name: "nil engine", engine: nil, wantCode: "NIL_ENGINE"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var engineErr *EngineError
+				if _, err := tt.engine.RecoverIncomplete(ctx); !errors.As(err, &engineErr) || engineErr.Code != tt.wantCode {
+					t.Errorf("expected %s, got %v", tt.wantCode, err)
+				}
+			})
+		}
+
+		// Runs on a store without run tracking execute untracked
+		if _, err := unsupported.Run(ctx, "run-001", TestState{}); err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	})
+}
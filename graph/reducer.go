@@ -0,0 +1,344 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Merge strategies for the reduce struct tag (see NewReducer).
+const (
+	ReduceReplace  = "replace"
+	ReduceAppend   = "append"
+	ReduceSum      = "sum"
+	ReduceMax      = "max"
+	ReduceMin      = "min"
+	ReduceMerge    = "merge"
+	ReduceSetUnion = "set_union"
+	ReduceKeep     = "-"
+)
+
+// fieldMerger merges a delta field into the corresponding field of the
+// reducer's result. dst is settable and holds the previous value.
+type fieldMerger func(dst, delta reflect.Value)
+
+// NewReducer builds a Reducer for the struct type S from reduce struct tags,
+// so a state type declares how each field merges instead of hand-writing a
+// reducer that must remember every field.
+//
+// Supported tags:
+//   - reduce:"replace" (the default for untagged fields): the delta value
+//     replaces the previous one unless it is the zero value
+//   - reduce:"append": slices; delta elements are appended
+//   - reduce:"sum": numbers; the delta is added
+//   - reduce:"max", reduce:"min": numbers and strings; the larger or smaller
+//     value is kept
+//   - reduce:"merge": maps, whose delta entries overwrite previous ones,
+//     types with a Merge method such as the CRDTs in graph/crdt (the default
+//     for those types), and structs, whose fields are merged by their own
+//     reduce tags
+//   - reduce:"set_union": slices of comparable elements; delta elements not
+//     already present are appended in order. Interface elements holding
+//     values that cannot be map keys, such as slices, are compared with
+//     reflect.DeepEqual
+//   - reduce:"-": the field is never updated by deltas
+//
+// A zero field means "not set": a zero delta leaves the previous value alone,
+// and max and min take the delta when the previous value is zero. To set a
+// field explicitly to its zero value, or to compare against zero, declare it
+// as a pointer: a nil pointer is not set, while a pointer to zero is.
+// Pointers work with replace, sum, max, min and merge. Likewise an empty
+// non-nil slice or map replaces the previous value, while a nil one does not.
+//
+// The previous state is never modified: slices, maps and pointees are copied
+// before they are changed, so states held by other branches stay intact.
+// Unexported fields are kept from the previous state.
+//
+// Returns an error if S is not a struct, a tag names an unknown strategy, or a
+// strategy does not apply to the field's type.
+//
+// Example:
+//
+//	type State struct {
+//	    Query    string            // replace
+//	    Messages []string          `reduce:"append"`
+//	    Tokens   int               `reduce:"sum"`
+//	    Score    *float64          `reduce:"max"`
+//	    Labels   map[string]string `reduce:"merge"`
+//	    Tags     []string          `reduce:"set_union"`
+//	    Approved *bool             // replace; can be set back to false
+//	    Views    crdt.GCounter     // merge, using GCounter.Merge
+//	}
+//
+//	reducer, err := graph.NewReducer[State]()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	engine := graph.New(reducer, st, emitter, opts)
+func NewReducer[S any]() (Reducer[S], error) {
+	t := reflect.TypeOf((*S)(nil)).Elem()
+	merge, err := structMerger(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(prev, delta S) S {
+		out := prev
+		merge(reflect.ValueOf(&out).Elem(), reflect.ValueOf(delta))
+		return out
+	}, nil
+}
+
+// structMerger returns a merger applying the reduce tags of struct type t.
+func structMerger(t reflect.Type) (fieldMerger, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("reducer state must be a struct, got %s", t)
+	}
+
+	var indexes []int
+	var mergers []fieldMerger
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		strategy, ok := field.Tag.Lookup("reduce")
+		if !ok {
+			strategy = ReduceReplace
+			if mergeMethod(field.Type) >= 0 {
+				strategy = ReduceMerge
+			}
+		}
+		if strategy == ReduceKeep {
+			continue
+		}
+		merger, err := newFieldMerger(field.Type, strategy)
+		if err != nil {
+			name := field.Name
+			if t.Name() != "" {
+				name = t.Name() + "." + name
+			}
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		indexes = append(indexes, i)
+		mergers = append(mergers, merger)
+	}
+
+	return func(dst, delta reflect.Value) {
+		for i, index := range indexes {
+			mergers[i](dst.Field(index), delta.Field(index))
+		}
+	}, nil
+}
+
+// newFieldMerger returns the merger for a field of type t using strategy.
+func newFieldMerger(t reflect.Type, strategy string) (fieldMerger, error) {
+	switch strategy {
+	case ReduceReplace:
+		return func(dst, delta reflect.Value) {
+			if !delta.IsZero() {
+				dst.Set(delta)
+			}
+		}, nil
+
+	case ReduceAppend:
+		if t.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("reduce:%q requires a slice, got %s", strategy, t)
+		}
+		return func(dst, delta reflect.Value) {
+			if delta.Len() == 0 {
+				return
+			}
+			merged := reflect.MakeSlice(t, 0, dst.Len()+delta.Len())
+			merged = reflect.AppendSlice(merged, dst)
+			dst.Set(reflect.AppendSlice(merged, delta))
+		}, nil
+
+	case ReduceSetUnion:
+		if t.Kind() != reflect.Slice || !t.Elem().Comparable() {
+			return nil, fmt.Errorf("reduce:%q requires a slice of comparable elements, got %s", strategy, t)
+		}
+		return func(dst, delta reflect.Value) {
+			if delta.Len() == 0 {
+				return
+			}
+			seen := make(map[interface{}]bool, dst.Len()+delta.Len())
+			var unhashable []interface{} // Would panic as map keys
+			merged := reflect.MakeSlice(t, 0, dst.Len()+delta.Len())
+			for _, values := range []reflect.Value{dst, delta} {
+				for i := 0; i < values.Len(); i++ {
+					value := values.Index(i)
+					key := value.Interface()
+					if value.Comparable() {
+						if seen[key] {
+							continue
+						}
+						seen[key] = true
+					} else {
+						if slices.ContainsFunc(unhashable, func(v interface{}) bool { return reflect.DeepEqual(v, key) }) {
+							continue
+						}
+						unhashable = append(unhashable, key)
+					}
+					merged = reflect.Append(merged, value)
+				}
+			}
+			dst.Set(merged)
+		}, nil
+
+	case ReduceSum, ReduceMax, ReduceMin:
+		elem := t
+		if t.Kind() == reflect.Pointer {
+			elem = t.Elem()
+		}
+		combine, err := numericCombiner(elem, strategy)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind() == reflect.Pointer {
+			return pointerMerger(elem, combine), nil
+		}
+		return func(dst, delta reflect.Value) {
+			switch {
+			case delta.IsZero():
+			case dst.IsZero() && strategy != ReduceSum:
+				dst.Set(delta) // An unset previous value takes part in no comparison
+			default:
+				dst.Set(combine(dst, delta))
+			}
+		}, nil
+
+	case ReduceMerge:
+		if method := mergeMethod(t); method >= 0 {
+			return func(dst, delta reflect.Value) {
+				if !delta.IsZero() {
+					dst.Set(dst.Method(method).Call([]reflect.Value{delta})[0])
+				}
+			}, nil
+		}
+		switch {
+		case t.Kind() == reflect.Map:
+			return func(dst, delta reflect.Value) {
+				if delta.IsNil() {
+					return
+				}
+				merged := reflect.MakeMapWithSize(t, dst.Len()+delta.Len())
+				for _, values := range []reflect.Value{dst, delta} {
+					iter := values.MapRange()
+					for iter.Next() {
+						merged.SetMapIndex(iter.Key(), iter.Value())
+					}
+				}
+				dst.Set(merged)
+			}, nil
+		case t.Kind() == reflect.Struct:
+			return structMerger(t)
+		case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct:
+			nested, err := structMerger(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			return pointerMerger(t.Elem(), func(prev, delta reflect.Value) reflect.Value {
+				merged := reflect.New(t.Elem()).Elem()
+				merged.Set(prev)
+				nested(merged, delta)
+				return merged
+			}), nil
+		}
+		return nil, fmt.Errorf("reduce:%q requires a map, struct or struct pointer, got %s", strategy, t)
+	}
+
+	return nil, fmt.Errorf("unknown reduce strategy %q", strategy)
+}
+
+// mergeMethod returns the index of t's Merge(t) t method, as implemented by
+// the CRDTs in graph/crdt, or -1 if t has none.
+func mergeMethod(t reflect.Type) int {
+	method, ok := t.MethodByName("Merge")
+	if !ok {
+		return -1
+	}
+	mt := method.Type // The receiver is the first parameter
+	if mt.NumIn() != 2 || mt.In(1) != t || mt.NumOut() != 1 || mt.Out(0) != t {
+		return -1
+	}
+	return method.Index
+}
+
+// pointerMerger adapts combine to a pointer field: a nil delta is not set, and
+// a set delta is combined with the previous value into a new pointee.
+func pointerMerger(elem reflect.Type, combine func(prev, delta reflect.Value) reflect.Value) fieldMerger {
+	return func(dst, delta reflect.Value) {
+		if delta.IsNil() {
+			return
+		}
+		value := delta.Elem()
+		if !dst.IsNil() {
+			value = combine(dst.Elem(), value)
+		}
+		ptr := reflect.New(elem)
+		ptr.Elem().Set(value)
+		dst.Set(ptr)
+	}
+}
+
+// numericCombiner returns the sum, max or min function for values of type t.
+func numericCombiner(t reflect.Type, strategy string) (func(prev, delta reflect.Value) reflect.Value, error) {
+	var less func(a, b reflect.Value) bool
+	var add func(a, b reflect.Value) reflect.Value
+
+	result := func() reflect.Value { return reflect.New(t).Elem() }
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+		add = func(a, b reflect.Value) reflect.Value {
+			v := result()
+			v.SetInt(a.Int() + b.Int())
+			return v
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+		add = func(a, b reflect.Value) reflect.Value {
+			v := result()
+			v.SetUint(a.Uint() + b.Uint())
+			return v
+		}
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+		add = func(a, b reflect.Value) reflect.Value {
+			v := result()
+			v.SetFloat(a.Float() + b.Float())
+			return v
+		}
+	case reflect.String:
+		if strategy == ReduceSum {
+			return nil, fmt.Errorf("reduce:%q requires a number, got %s", strategy, t)
+		}
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	default:
+		if strategy == ReduceSum {
+			return nil, fmt.Errorf("reduce:%q requires a number, got %s", strategy, t)
+		}
+		return nil, fmt.Errorf("reduce:%q requires a number or string, got %s", strategy, t)
+	}
+
+	switch strategy {
+	case ReduceSum:
+		return add, nil
+	case ReduceMax:
+		return func(prev, delta reflect.Value) reflect.Value {
+			if less(prev, delta) {
+				return delta
+			}
+			return prev
+		}, nil
+	default:
+		return func(prev, delta reflect.Value) reflect.Value {
+			if less(delta, prev) {
+				return delta
+			}
+			return prev
+		}, nil
+	}
+}
@@ -0,0 +1,249 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ReducerTestState covers every reduce strategy.
+type ReducerTestState struct {
+	Query    string
+	Messages []string          `reduce:"append"`
+	Tokens   int               `reduce:"sum"`
+	Cost     *float64          `reduce:"sum"`
+	Best     float64           `reduce:"max"`
+	Lowest   *int              `reduce:"min"`
+	Labels   map[string]string `reduce:"merge"`
+	Tags     []string          `reduce:"set_union"`
+	Approved *bool             `reduce:"replace"`
+	Meta     ReducerTestMeta   `reduce:"merge"`
+	Audit    *ReducerTestMeta  `reduce:"merge"`
+	RunID    string            `reduce:"-"`
+	internal int
+}
+
+// ReducerTestMeta is a nested struct merged by its own tags.
+type ReducerTestMeta struct {
+	Source string
+	Hits   int `reduce:"sum"`
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestNewReducer verifies reducers built from reduce struct tags.
+func TestNewReducer(t *testing.T) {
+	reducer, err := NewReducer[ReducerTestState]()
+	if err != nil {
+		t.Fatalf("NewReducer failed: %v", err)
+	}
+
+	prev := ReducerTestState{
+		Query:    "first",
+		Messages: []string{"a"},
+		Tokens:   10,
+		Cost:     ptr(0.5),
+		Best:     0.4,
+		Lowest:   ptr(3),
+		Labels:   map[string]string{"lang": "go", "env": "dev"},
+		Tags:     []string{"x", "y"},
+		Approved: ptr(true),
+		Meta:     ReducerTestMeta{Source: "web", Hits: 1},
+		RunID:    "run-1",
+		internal: 7,
+	}
+
+	t.Run("merges each field by its strategy", func(t *testing.T) {
+		got := reducer(prev, ReducerTestState{
+			Query:    "second",
+			Messages: []string{"b"},
+			Tokens:   5,
+			Cost:     ptr(0.25),
+			Best:     0.9,
+			Lowest:   ptr(-1),
+			Labels:   map[string]string{"env": "prod"},
+			Tags:     []string{"y", "z", "z"},
+			Approved: ptr(false),
+			Meta:     ReducerTestMeta{Hits: 2},
+			Audit:    &ReducerTestMeta{Source: "cli", Hits: 1},
+			RunID:    "ignored",
+		})
+
+		want := ReducerTestState{
+			Query:    "second",
+			Messages: []string{"a", "b"},
+			Tokens:   15,
+			Cost:     ptr(0.75),
+			Best:     0.9,
+			Lowest:   ptr(-1),
+			Labels:   map[string]string{"lang": "go", "env": "prod"},
+			Tags:     []string{"x", "y", "z"},
+			Approved: ptr(false),
+			Meta:     ReducerTestMeta{Source: "web", Hits: 3},
+			Audit:    &ReducerTestMeta{Source: "cli", Hits: 1},
+			RunID:    "run-1",
+			internal: 7,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("reducer() =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+
+	t.Run("zero delta leaves state unchanged", func(t *testing.T) {
+		got := reducer(prev, ReducerTestState{})
+		if !reflect.DeepEqual(got, prev) {
+			t.Errorf("reducer() =\n%+v\nwant\n%+v", got, prev)
+		}
+	})
+
+	t.Run("empty slice explicitly clears a replace field", func(t *testing.T) {
+		type State struct {
+			Items []string
+		}
+		reducer, err := NewReducer[State]()
+		if err != nil {
+			t.Fatalf("NewReducer failed: %v", err)
+		}
+		if got := reducer(State{Items: []string{"a"}}, State{Items: []string{}}); got.Items == nil || len(got.Items) != 0 {
+			t.Errorf("Items = %#v, want empty non-nil slice", got.Items)
+		}
+		if got := reducer(State{Items: []string{"a"}}, State{}); fmt.Sprint(got.Items) != "[a]" {
+			t.Errorf("Items = %v, want [a]", got.Items)
+		}
+	})
+
+	t.Run("max and min treat a zero previous value as unset", func(t *testing.T) {
+		type State struct {
+			Low  int `reduce:"min"`
+			High int `reduce:"max"`
+		}
+		reducer, err := NewReducer[State]()
+		if err != nil {
+			t.Fatalf("NewReducer failed: %v", err)
+		}
+		got := reducer(State{}, State{Low: 5, High: -2})
+		got = reducer(got, State{Low: 7, High: -4})
+		if got.Low != 5 || got.High != -2 {
+			t.Errorf("got %+v, want {Low:5 High:-2}", got)
+		}
+	})
+
+	t.Run("set_union of interface elements", func(t *testing.T) {
+		type State struct {
+			Items []any `reduce:"set_union"`
+		}
+		reducer, err := NewReducer[State]()
+		if err != nil {
+			t.Fatalf("NewReducer failed: %v", err)
+		}
+		got := reducer(State{Items: []any{"a", []int{1}}}, State{Items: []any{[]int{1}, "a", []int{2}, 3}})
+		if want := []any{"a", []int{1}, []int{2}, 3}; !reflect.DeepEqual(got.Items, want) {
+			t.Errorf("Items = %v, want %v", got.Items, want)
+		}
+	})
+
+	t.Run("previous state is not modified", func(t *testing.T) {
+		base := ReducerTestState{
+			Messages: make([]string, 1, 4),
+			Labels:   map[string]string{"k": "v"},
+			Cost:     ptr(1.0),
+			Audit:    &ReducerTestMeta{Hits: 1},
+		}
+		_ = reducer(base, ReducerTestState{
+			Messages: []string{"b"},
+			Labels:   map[string]string{"k": "changed"},
+			Cost:     ptr(1.0),
+			Audit:    &ReducerTestMeta{Hits: 1},
+		})
+		_ = reducer(base, ReducerTestState{Messages: []string{"c"}})
+
+		if base.Labels["k"] != "v" || *base.Cost != 1.0 || base.Audit.Hits != 1 {
+			t.Errorf("previous state modified: %+v", base)
+		}
+		if extended := base.Messages[:2]; extended[1] != "" {
+			t.Errorf("previous slice backing array modified: %v", extended)
+		}
+	})
+}
+
+// TestNewReducer_Errors verifies invalid tags are rejected when building.
+func TestNewReducer_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() error
+		want  string
+	}{
+		{
+			name: "non-struct state",
+			build: func() error {
+				_, err := NewReducer[map[string]int]()
+				return err
+			},
+			want: "must be a struct",
+		},
+		{
+			name: "unknown strategy",
+			build: func() error {
+				_, err := NewReducer[struct {
+					Count int `reduce:"average"`
+				}]()
+				return err
+			},
+			want: `unknown reduce strategy "average"`,
+		},
+		{
+			name: "append on a non-slice",
+			build: func() error {
+				_, err := NewReducer[struct {
+					Name string `reduce:"append"`
+				}]()
+				return err
+			},
+			want: "field Name: reduce:\"append\" requires a slice",
+		},
+		{
+			name: "sum on a string",
+			build: func() error {
+				_, err := NewReducer[struct {
+					Name string `reduce:"sum"`
+				}]()
+				return err
+			},
+			want: "requires a number",
+		},
+		{
+			name: "set_union of incomparable elements",
+			build: func() error {
+				_, err := NewReducer[struct {
+					Groups [][]string `reduce:"set_union"`
+				}]()
+				return err
+			},
+			want: "comparable elements",
+		},
+		{
+			name: "invalid tag in nested struct",
+			build: func() error {
+				_, err := NewReducer[struct {
+					Meta struct {
+						Flags bool `reduce:"max"`
+					} `reduce:"merge"`
+				}]()
+				return err
+			},
+			want: "field Flags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
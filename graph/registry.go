@@ -0,0 +1,354 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// cancelLabel labels the CheckpointV2 saved when a run is cancelled with
+// Options.CheckpointOnCancel set.
+const cancelLabel = "cancel"
+
+// CancelledError is returned by a run stopped with Engine.Cancel. It matches
+// ErrCancelled and context.Canceled with errors.Is.
+type CancelledError struct {
+	// RunID identifies the cancelled run.
+	RunID string
+
+	// Reason is the reason passed to Engine.Cancel.
+	Reason string
+
+	// StepID is the step at which the run stopped.
+	StepID int
+
+	// Checkpointed reports whether a checkpoint labeled "cancel" was saved at
+	// StepID, from which the run can be continued with RunWithCheckpoint.
+	Checkpointed bool
+}
+
+// Error implements the error interface.
+func (e *CancelledError) Error() string {
+	return fmt.Sprintf("%s: run %s: %s", ErrCancelled.Error(), e.RunID, e.Reason)
+}
+
+// Is reports whether target is ErrCancelled.
+func (e *CancelledError) Is(target error) bool {
+	return target == ErrCancelled
+}
+
+// Unwrap returns context.Canceled, since a cancelled run stops the way one
+// whose context was cancelled does.
+func (e *CancelledError) Unwrap() error {
+	return context.Canceled
+}
+
+// RunStatus is a snapshot of a run executing on an Engine (see Engine.Status).
+type RunStatus struct {
+	// RunID identifies the run.
+	RunID string
+
+	// Step is the run's step counter: the steps started so far, including
+	// those before the checkpoint a resumed run continues from.
+	Step int
+
+	// InFlight lists the IDs of the nodes executing, sorted. A node running on
+	// several branches at once is listed once per branch.
+	InFlight []string
+
+	// QueueDepth is the number of work items waiting to execute.
+	QueueDepth int
+
+	// StartedAt records when this execution of the run started.
+	StartedAt time.Time
+
+	// Elapsed is the time since StartedAt.
+	Elapsed time.Duration
+}
+
+// Cancel stops the active run runID from another goroutine, as cancelling the
+// context passed to Run would, and makes the run return a *CancelledError
+// carrying reason.
+//
+// Executing nodes see their context cancelled; the run stops once they
+// return, emitting a run_cancelled event. With Options.CheckpointOnCancel set
+// it first saves a checkpoint labeled "cancel" holding the work that had not
+// completed, so it can be continued later with RunWithCheckpoint. Cancelling
+// a run that is already stopping has no further effect.
+//
+// Returns an EngineError with code RUN_NOT_FOUND if no run with this ID is
+// executing on the engine.
+//
+// Example:
+//
+//	http.HandleFunc("/runs/cancel", func(w http.ResponseWriter, r *http.Request) {
+//	    if err := engine.Cancel(r.FormValue("run_id"), "cancelled by user"); err != nil {
+//	        http.Error(w, err.Error(), http.StatusNotFound)
+//	    }
+//	})
+func (e *Engine[S]) Cancel(runID, reason string) error {
+	run, err := e.activeRun(runID)
+	if err != nil {
+		return err
+	}
+	run.cancel(&CancelledError{RunID: runID, Reason: reason})
+	return nil
+}
+
+// Status reports the progress of the active run runID: its step, the nodes
+// executing, the work waiting and how long it has been running.
+//
+// Returns an EngineError with code RUN_NOT_FOUND if no run with this ID is
+// executing on the engine.
+func (e *Engine[S]) Status(runID string) (RunStatus, error) {
+	run, err := e.activeRun(runID)
+	if err != nil {
+		return RunStatus{}, err
+	}
+	return run.status(), nil
+}
+
+// ActiveRuns reports the status of every run executing on the engine, in the
+// order they started.
+func (e *Engine[S]) ActiveRuns() []RunStatus {
+	if e == nil {
+		return nil
+	}
+	statuses := make([]RunStatus, 0)
+	e.runs.Range(func(_, run interface{}) bool {
+		statuses = append(statuses, run.(*activeRun[S]).status())
+		return true
+	})
+	sort.Slice(statuses, func(i, j int) bool {
+		if !statuses[i].StartedAt.Equal(statuses[j].StartedAt) {
+			return statuses[i].StartedAt.Before(statuses[j].StartedAt)
+		}
+		return statuses[i].RunID < statuses[j].RunID
+	})
+	return statuses
+}
+
+// activeRun returns the registry entry of the executing run runID.
+func (e *Engine[S]) activeRun(runID string) (*activeRun[S], error) {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return nil, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	run, ok := e.runs.Load(runID)
+	if !ok {
+		return nil, &EngineError{
+			Message: "no active run: " + runID,
+			Code:    "RUN_NOT_FOUND",
+		}
+	}
+	return run.(*activeRun[S]), nil
+}
+
+// register adds runID to the engine's active runs for the duration of its
+// execution. It returns the context the run executes with, which Cancel
+// cancels, and a function that removes the run again.
+//
+// step is the run's step counter when execution starts. A run ID executes
+// once at a time: registering an active run returns an EngineError with code
+// RUN_IN_PROGRESS. claim, if set, is called once the run ID is held and
+// before anything is recorded, to take over what the run continues from;
+// its error is returned and the run is not registered.
+//
+// With Options.LeaseDuration set, the run is also recorded as running in the
+// store until its outcome passes through activeRun.settle.
+func (e *Engine[S]) register(ctx context.Context, runID string, step int, claim func(context.Context) error) (context.Context, *activeRun[S], func(), error) {
+	parent := ctx
+	ctx, cancel := context.WithCancelCause(ctx)
+	run := &activeRun[S]{
+		runID:     runID,
+		startedAt: time.Now(),
+		parent:    parent,
+		cancel:    cancel,
+		step:      step,
+		inFlight:  make(map[int]WorkItem[S]),
+	}
+	if _, loaded := e.runs.LoadOrStore(runID, run); loaded {
+		cancel(nil)
+		return nil, nil, nil, &EngineError{
+			Message: "run is already executing: " + runID,
+			Code:    "RUN_IN_PROGRESS",
+		}
+	}
+	if claim != nil {
+		if err := claim(parent); err != nil {
+			e.runs.Delete(runID)
+			cancel(nil)
+			return nil, nil, nil, err
+		}
+	}
+
+	// Track the run's status in the store when leases are enabled
+	lease, err := e.startLease(parent, runID, cancel)
+	if err != nil {
+		e.runs.Delete(runID)
+		cancel(nil)
+		return nil, nil, nil, err
+	}
+	run.lease = lease
+
+	return ctx, run, func() {
+		if lease != nil {
+			lease.halt()
+		}
+		e.runs.Delete(runID)
+		cancel(nil)
+	}, nil
+}
+
+// cancelled returns the CancelledError of a run stopped with Cancel, or nil if
+// ctx was not cancelled that way.
+func cancelled(ctx context.Context) *CancelledError {
+	if cause, ok := context.Cause(ctx).(*CancelledError); ok {
+		return cause
+	}
+	return nil
+}
+
+// stopCancelled ends a run stopped with Cancel: it saves the cancel
+// checkpoint if Options.CheckpointOnCancel is set, emits run_cancelled and
+// returns the CancelledError the run reports.
+//
+// state and frontier describe the run after its last completed step, with
+// the work that had not completed in the frontier.
+func (e *Engine[S]) stopCancelled(ctx context.Context, runID string, cause *CancelledError, stepID int, state S, frontier []WorkItem[S]) error {
+	// A nested run reports the cancellation of its parent under its own ID
+	stopped := *cause
+	stopped.RunID = runID
+	stopped.StepID = stepID
+
+	if e.opts.CheckpointOnCancel {
+		// The run's context is cancelled, but the checkpoint must still be saved
+		saveCtx := context.WithoutCancel(ctx)
+		if err := e.saveCheckpoint(saveCtx, stopped.RunID, stepID, state, frontier, runRecorder(ctx), cancelLabel); err != nil {
+			return err
+		}
+		stopped.Checkpointed = true
+	}
+
+	e.publish(emit.Event{
+		RunID: stopped.RunID,
+		Step:  stepID,
+		Msg:   "run_cancelled",
+		Meta: map[string]interface{}{
+			"reason":        stopped.Reason,
+			"checkpointed":  stopped.Checkpointed,
+			"frontier_size": len(frontier),
+		},
+	})
+	return &stopped
+}
+
+// activeRun is the registry entry of an executing run, and holds the state
+// scoped to that run so runs on one Engine never share it. The step counter,
+// RNG and recorded I/O live in the run's own variables and context.
+type activeRun[S any] struct {
+	runID     string
+	startedAt time.Time
+	parent    context.Context // Context the run was started with
+	cancel    context.CancelCauseFunc
+
+	// lease records the run's status while Options.LeaseDuration is set, and
+	// is nil otherwise.
+	lease *runLease
+
+	// restored reports whether the run continues from a checkpoint (see
+	// continueRun) rather than from its start node.
+	restored bool
+
+	// frontier queues the work items of a concurrent run. It is set before
+	// the run starts executing and is nil in sequential mode.
+	frontier *Frontier[S]
+
+	mu         sync.Mutex
+	step       int
+	nextID     int
+	inFlight   map[int]WorkItem[S] // Executing work items by the ID start returned
+	queueDepth func() int          // Number of queued work items, nil until tracked
+}
+
+// settle records the outcome of the run in its lease, if it has one, and
+// returns the state and error the run reports.
+func (r *activeRun[S]) settle(state S, err error) (S, error) {
+	if r.lease == nil {
+		return state, err
+	}
+	return state, r.lease.settle(r.parent, err)
+}
+
+// track sets the function reporting the run's queued work items.
+func (r *activeRun[S]) track(queueDepth func() int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queueDepth = queueDepth
+}
+
+// start records item as executing and returns the ID to pass to finish.
+func (r *activeRun[S]) start(item WorkItem[S]) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.inFlight[r.nextID] = item
+	return r.nextID
+}
+
+// finish records that the work item with the given ID has completed.
+func (r *activeRun[S]) finish(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.inFlight, id)
+}
+
+// setStep advances the run's step counter to step. Concurrent branches may
+// report their steps out of order, so the counter never moves back.
+func (r *activeRun[S]) setStep(step int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.step = max(r.step, step)
+}
+
+// unfinished returns the work items started and not finished, keyed by the
+// ID start returned.
+func (r *activeRun[S]) unfinished() map[int]WorkItem[S] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make(map[int]WorkItem[S], len(r.inFlight))
+	for id, item := range r.inFlight {
+		items[id] = item
+	}
+	return items
+}
+
+// status returns a snapshot of the run.
+func (r *activeRun[S]) status() RunStatus {
+	r.mu.Lock()
+	inFlight := make([]string, 0, len(r.inFlight))
+	for _, item := range r.inFlight {
+		inFlight = append(inFlight, item.NodeID)
+	}
+	step, queueDepth := r.step, r.queueDepth
+	r.mu.Unlock()
+	sort.Strings(inFlight)
+
+	queued := 0
+	if queueDepth != nil {
+		queued = queueDepth()
+	}
+	return RunStatus{
+		RunID:      r.runID,
+		Step:       step,
+		InFlight:   inFlight,
+		QueueDepth: queued,
+		StartedAt:  r.startedAt,
+		Elapsed:    time.Since(r.startedAt),
+	}
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestEngine_RunRegistry verifies active runs can be inspected and cancelled
+// from another goroutine in both execution modes.
+func TestEngine_RunRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Done []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Done = append(prev.Done, delta.Done...)
+		return prev
+	}
+
+	// newEngine builds a -> b -> c, where b signals started and then blocks
+	// until release is closed or its context is cancelled
+	newEngine := func(st store.Store[TestState], emitter *mockEmitter, opts Options, started chan<- struct{}, release <-chan struct{}) *Engine[TestState] {
+		engine := New(reducer, st, emitter, opts)
+		a := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Done: []string{"a"}}, Route: Goto("b")}
+		})
+		b := NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+			started <- struct{}{}
+			select {
+			case <-release:
+				return NodeResult[TestState]{Delta: TestState{Done: []string{"b"}}, Route: Goto("c")}
+			case <-ctx.Done():
+				return NodeResult[TestState]{Err: ctx.Err()}
+			}
+		})
+		c := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Done: []string{"c"}}, Route: Stop()}
+		})
+		_ = engine.Add("a", a)
+		_ = engine.Add("b", b)
+		_ = engine.Add("c", c)
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine
+	}
+
+	for _, mode := range []struct {
+		name string
+		opts Options
+	}{
+		{name: "sequential", opts: Options{MaxSteps: 20}},
+		{name: "concurrent", opts: Options{MaxSteps: 20, MaxConcurrentNodes: 4}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("status of an active run", func(t *testing.T) {
+				started, release := make(chan struct{}), make(chan struct{})
+				engine := newEngine(store.NewMemStore[TestState](), &mockEmitter{}, mode.opts, started, release)
+				done := make(chan error)
+				go func() {
+					_, err := engine.Run(ctx, "run-001", TestState{})
+					done <- err
+				}()
+				<-started
+
+				status, err := engine.Status("run-001")
+				if err != nil {
+					t.Fatalf("Status failed: %v", err)
+				}
+				if status.RunID != "run-001" || status.Step != 2 || !reflect.DeepEqual(status.InFlight, []string{"b"}) || status.QueueDepth != 0 {
+					t.Errorf("Status = %+v, want step 2 with b in flight", status)
+				}
+				if status.StartedAt.IsZero() || status.Elapsed < 0 {
+					t.Errorf("Status = %+v, want its start time", status)
+				}
+				if active := engine.ActiveRuns(); len(active) != 1 || active[0].RunID != "run-001" {
+					t.Errorf("ActiveRuns = %+v, want run-001", active)
+				}
+
+				// A run ID executes once at a time
+				var engineErr *EngineError
+				if _, err := engine.Run(ctx, "run-001", TestState{}); !errors.As(err, &engineErr) || engineErr.Code != "RUN_IN_PROGRESS" {
+					t.Errorf("expected RUN_IN_PROGRESS, got %v", err)
+				}
+
+				close(release)
+				if err := <-done; err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if active := engine.ActiveRuns(); len(active) != 0 {
+					t.Errorf("ActiveRuns after completion = %+v, want none", active)
+				}
+				if _, err := engine.Status("run-001"); !errors.As(err, &engineErr) || engineErr.Code != "RUN_NOT_FOUND" {
+					t.Errorf("expected RUN_NOT_FOUND, got %v", err)
+				}
+			})
+
+			t.Run("cancel stops the run", func(t *testing.T) {
+				started := make(chan struct{})
+				emitter := &mockEmitter{}
+				engine := newEngine(store.NewMemStore[TestState](), emitter, mode.opts, started, nil)
+				done := make(chan error)
+				go func() {
+					_, err := engine.Run(ctx, "run-001", TestState{})
+					done <- err
+				}()
+				<-started
+
+				if err := engine.Cancel("run-001", "user request"); err != nil {
+					t.Fatalf("Cancel failed: %v", err)
+				}
+				err := <-done
+				var cancelled *CancelledError
+				if !errors.As(err, &cancelled) || !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+					t.Fatalf("Run returned %v, want a CancelledError", err)
+				}
+				if cancelled.RunID != "run-001" || cancelled.Reason != "user request" || cancelled.StepID != 1 || cancelled.Checkpointed {
+					t.Errorf("CancelledError = %+v, want run-001 stopped at step 1 without a checkpoint", cancelled)
+				}
+
+				var events []string
+				for _, event := range emitter.events {
+					if event.Msg == "run_cancelled" {
+						events = append(events, event.Meta["reason"].(string))
+					}
+				}
+				if !reflect.DeepEqual(events, []string{"user request"}) {
+					t.Errorf("run_cancelled events = %v, want one with the reason", events)
+				}
+			})
+
+			t.Run("checkpoint on cancel", func(t *testing.T) {
+				started, release := make(chan struct{}), make(chan struct{})
+				st := store.NewMemStore[TestState]()
+				opts := mode.opts
+				opts.CheckpointOnCancel = true
+				engine := newEngine(st, &mockEmitter{}, opts, started, release)
+				done := make(chan error)
+				go func() {
+					_, err := engine.Run(ctx, "run-001", TestState{})
+					done <- err
+				}()
+				<-started
+
+				if err := engine.Cancel("run-001", "deploy"); err != nil {
+					t.Fatalf("Cancel failed: %v", err)
+				}
+				var cancelled *CancelledError
+				if err := <-done; !errors.As(err, &cancelled) || !cancelled.Checkpointed {
+					t.Fatalf("Run returned %v, want a checkpointed CancelledError", err)
+				}
+
+				checkpoints, err := st.ListCheckpoints(ctx, "run-001", cancelLabel)
+				if err != nil || len(checkpoints) != 1 {
+					t.Fatalf("ListCheckpoints = %v, %v, want the cancel checkpoint", checkpoints, err)
+				}
+				checkpoint, err := st.LoadCheckpointV2(ctx, "run-001", checkpoints[0].StepID)
+				if err != nil {
+					t.Fatalf("LoadCheckpointV2 failed: %v", err)
+				}
+				if !reflect.DeepEqual(checkpoint.State.Done, []string{"a"}) {
+					t.Errorf("checkpoint state = %+v, want a's delta", checkpoint.State)
+				}
+
+				// The cancelled node runs again when the run is continued
+				close(release)
+				go func() { <-started }()
+				final, err := engine.RunWithCheckpoint(ctx, checkpoint)
+				if err != nil {
+					t.Fatalf("RunWithCheckpoint failed: %v", err)
+				}
+				// Concurrent mode merges deltas by OrderKey rather than step
+				sort.Strings(final.Done)
+				if !reflect.DeepEqual(final.Done, []string{"a", "b", "c"}) {
+					t.Errorf("final state = %v, want a, b and c done", final.Done)
+				}
+			})
+		})
+	}
+
+	t.Run("cancel unknown run", func(t *testing.T) {
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{})
+		var engineErr *EngineError
+		if err := engine.Cancel("missing", "stop"); !errors.As(err, &engineErr) || engineErr.Code != "RUN_NOT_FOUND" {
+			t.Errorf("expected RUN_NOT_FOUND, got %v", err)
+		}
+	})
+}
+
+// TestCancelledError verifies CancelledError formats its reason and matches
+// ErrCancelled and context.Canceled.
+func TestCancelledError(t *testing.T) {
+	var err error = &CancelledError{RunID: "run-001", Reason: "shutdown"}
+	if got := err.Error(); got != "run cancelled: run run-001: shutdown" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v to match ErrCancelled and context.Canceled", err)
+	}
+	if errors.Is(err, ErrMaxStepsExceeded) {
+		t.Errorf("expected %v not to match ErrMaxStepsExceeded", err)
+	}
+}
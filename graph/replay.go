@@ -67,7 +67,8 @@ import (
 //	// ✅ Deterministic:
 //	rng := ctx.Value(RNGKey).(*rand.Rand)
 //
-// 3. Record external I/O for replay (implement Effects() for nodes with side effects):
+// 3. Record external I/O for replay (implement Effects() for nodes with side effects
+// and make calls through Recorded; the built-in model and tool adapters already do):
 //
 //	func (n *APINode) Effects() SideEffectPolicy {
 //	    return SideEffectPolicy{
@@ -76,6 +77,13 @@ import (
 //	    }
 //	}
 //
+//	func (n *APINode) Run(ctx context.Context, state S) NodeResult[S] {
+//	    resp, err := Recorded(ctx, "api.get", state.Query, func() (APIResponse, error) {
+//	        return n.client.Get(ctx, state.Query)
+//	    })
+//	    ...
+//	}
+//
 // 4. Use deterministic data structures (avoid map iteration):
 //
 //	// ❌ Non-deterministic map iteration:
@@ -113,13 +121,19 @@ import (
 //     engine := New(reducer, store, emitter, opts)
 //     _, err := engine.Run(ctx, "run-001", initialState)
 //
-//  2. Replay execution:
+//  2. Replay execution, re-running the same run ID and initial state:
 //     replayOpts := Options{ReplayMode: true, StrictReplay: true}
 //     replayEngine := New(reducer, store, emitter, replayOpts)
-//     replayedState, err := replayEngine.ReplayRun(ctx, "run-001")
+//     replayedState, err := replayEngine.Run(ctx, "run-001", initialState)
 //
-// Replayed executions will:
+// In replay mode Run serves Recorded calls from the I/O stored in the run's
+// checkpoints. ReplayRun re-executes the run from its earliest checkpoint, so
+// it needs no initial state.
+//
+// Each checkpoint stores the I/O recorded since the run's previous one, so
+// every recording is stored once. Replayed executions will:
 //   - Use recorded I/O responses instead of making live calls
+//   - Fail with ErrReplayMismatch on calls that were not recorded (if StrictReplay=true)
 //   - Verify response hashes match expected values (if StrictReplay=true)
 //   - Produce byte-identical final states
 //   - Complete without invoking external services
@@ -133,14 +147,13 @@ import (
 // RecordedIO captures an external interaction (API call, database query, etc.).
 // for deterministic replay without re-invoking the external service.
 //
-// During initial execution, RecordedIO instances are created for nodes with.
-// SideEffectPolicy.Recordable=true. During replay, these recorded I/Os are.
-// matched by (NodeID, Attempt) and their responses are returned directly.
-// without executing the node.
+// RecordedIO instances are created by Recorded for calls made inside nodes.
+// with SideEffectPolicy.Recordable=true, and saved in the run's checkpoints.
+// During replay, these recorded I/Os are matched by (NodeID, Attempt, Key).
+// and request, and their responses are returned without making the call.
 //
-// The Hash field enables mismatch detection: if a live execution produces.
-// a different response hash than recorded, ErrReplayMismatch is raised,
-// indicating non-deterministic behavior.
+// The Hash field enables corruption detection: under StrictReplay a recorded
+// response whose hash no longer matches raises ErrReplayMismatch.
 type RecordedIO struct {
 	// NodeID identifies the node that performed this I/O operation.
 	NodeID string `json:"node_id"`
@@ -149,14 +162,22 @@ type RecordedIO struct {
 	// This allows matching I/O recordings to specific retry attempts.
 	Attempt int `json:"attempt"`
 
+	// Key names the kind of call (for example "openai.chat"), distinguishing.
+	// several calls made by the same node.
+	Key string `json:"key,omitempty"`
+
 	// Request is the serialized request data sent to the external service.
-	// Stored as JSON for cross-language compatibility and human readability.
+	// Stored as canonical JSON (sorted keys) so it compares equal after a store round trip.
 	Request json.RawMessage `json:"request"`
 
 	// Response is the serialized response data received from the external service.
-	// Stored as JSON for cross-language compatibility and human readability.
+	// Stored as canonical JSON (sorted keys) so it compares equal after a store round trip.
 	Response json.RawMessage `json:"response"`
 
+	// Error is the message of the error the call returned, if any.
+	// A replayed call returns an error with this message instead of Response.
+	Error string `json:"error,omitempty"`
+
 	// Hash is a SHA-256 hash of the response content, used for mismatch detection.
 	// during replay. Format: "sha256:hex_encoded_hash".
 	Hash string `json:"hash"`
@@ -171,14 +192,10 @@ type RecordedIO struct {
 
 // recordIO captures an external I/O operation for deterministic replay.
 //
-// This function serializes the request and response to JSON, computes a SHA-256.
-// hash of the response for mismatch detection, and creates a RecordedIO structure.
-// that can be stored in a checkpoint.
-//
-// During initial execution, nodes with SideEffectPolicy.Recordable=true should.
-// call this function to record their external interactions. During replay, these.
-// recordings are looked up by (nodeID, attempt) tuple and replayed without.
-// re-executing the external call.
+// This function serializes the request and response to canonical JSON, computes.
+// a SHA-256 hash of the response for mismatch detection, and creates a RecordedIO.
+// structure that can be stored in a checkpoint. Recorded calls it for every.
+// call it captures and fills in Key, Error and Duration.
 //
 // Parameters:
 // - nodeID: The identifier of the node performing the I/O.
@@ -189,129 +206,59 @@ type RecordedIO struct {
 // Returns:
 // - RecordedIO: The complete I/O recording with hash.
 // - error: Serialization error if request or response cannot be marshaled to JSON.
-//
-// Example usage in a node:
-//
-// recording, err := recordIO("api_call_node", 0, apiRequest, apiResponse).
-// if err != nil {.
-// return NodeResult[S]{Err: fmt.Errorf("failed to record I/O: %w", err)}.
-// }.
-// // Store recording in checkpoint via engine.
-//
-//nolint:unused // Reserved for future replay functionality
 func recordIO(nodeID string, attempt int, request, response interface{}) (RecordedIO, error) {
-	start := time.Now()
-
 	// Serialize request to JSON.
-	requestJSON, err := json.Marshal(request)
+	requestJSON, err := canonicalJSON(request)
 	if err != nil {
 		return RecordedIO{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Serialize response to JSON.
-	responseJSON, err := json.Marshal(response)
+	responseJSON, err := canonicalJSON(response)
 	if err != nil {
 		return RecordedIO{}, fmt.Errorf("failed to marshal response: %w", err)
 	}
 
-	// Compute SHA-256 hash of response for mismatch detection.
-	hasher := sha256.New()
-	hasher.Write(responseJSON)
-	hashBytes := hasher.Sum(nil)
-	hashStr := "sha256:" + hex.EncodeToString(hashBytes)
-
-	duration := time.Since(start)
-
 	return RecordedIO{
 		NodeID:    nodeID,
 		Attempt:   attempt,
 		Request:   json.RawMessage(requestJSON),
 		Response:  json.RawMessage(responseJSON),
-		Hash:      hashStr,
+		Hash:      responseHash(responseJSON),
 		Timestamp: time.Now(),
-		Duration:  duration,
 	}, nil
 }
 
-// lookupRecordedIO retrieves a previously recorded I/O operation by (nodeID, attempt) tuple.
+// verifyReplayHash validates that a response matches a recorded response.
 //
-// During replay mode, the engine uses this function to find recorded responses.
-// instead of re-executing nodes. The recordings are indexed by the combination.
-// of node ID and retry attempt number, allowing the same node to have different.
-// recordings for different retry attempts.
-//
-// Parameters:
-// - recordings: Slice of recorded I/O operations from a checkpoint.
-// - nodeID: The identifier of the node to look up.
-// - attempt: The retry attempt number to look up.
-//
-// Returns:
-// - RecordedIO: The matched recording if found.
-// - bool: true if a matching recording was found, false otherwise.
-//
-// Example usage in replay mode:
-//
-// recording, found := lookupRecordedIO(checkpoint.RecordedIOs, "api_call_node", 0).
-// if found {.
-// // Use recording.Response instead of executing node.
-// var response MyResponse.
-// json.Unmarshal(recording.Response, &response).
-// }.
-//
-//nolint:unused // Reserved for future replay functionality
-func lookupRecordedIO(recordings []RecordedIO, nodeID string, attempt int) (RecordedIO, bool) {
-	// Linear search through recordings to find matching (nodeID, attempt).
-	for _, rec := range recordings {
-		if rec.NodeID == nodeID && rec.Attempt == attempt {
-			return rec, true
-		}
-	}
-	return RecordedIO{}, false
-}
-
-// verifyReplayHash validates that a live execution response matches a recorded response.
-//
-// During replay verification mode (when StrictReplay=true), this function compares.
-// the SHA-256 hash of the actual response with the hash from the recorded I/O.
-// If they differ, it indicates non-deterministic behavior in the node.
-//
-// Common causes of replay mismatch:
-// - Node using random number generation without seeded RNG.
-// - Node reading system time (use context-provided values instead).
-// - Node depending on external state that has changed.
-// - Node using map iteration order (non-deterministic in Go).
+// This function compares the SHA-256 hash of the actual response, in canonical.
+// JSON form, with the hash from the recorded I/O. Under StrictReplay, Recorded.
+// verifies each recorded response against its own hash before serving it, so a.
+// recording edited or corrupted in the store is never replayed.
 //
 // Parameters:
 // - recorded: The recorded I/O with the expected response hash.
-// - actualResponse: The actual response from live execution.
+// - actualResponse: The response to verify.
 //
 // Returns:
 // - error: ErrReplayMismatch if hashes don't match, nil if they match.
-//
-// Example usage:
-//
-// if err := verifyReplayHash(recorded, actualResponse); err != nil {.
-// return NodeResult[S]{Err: fmt.Errorf("replay verification failed: %w", err)}.
-// }.
-//
-//nolint:unused // Reserved for future replay functionality
 func verifyReplayHash(recorded RecordedIO, actualResponse interface{}) error {
 	// Serialize actual response to JSON.
-	actualJSON, err := json.Marshal(actualResponse)
+	actualJSON, err := canonicalJSON(actualResponse)
 	if err != nil {
 		return fmt.Errorf("failed to marshal actual response: %w", err)
 	}
 
-	// Compute SHA-256 hash of actual response.
-	hasher := sha256.New()
-	hasher.Write(actualJSON)
-	hashBytes := hasher.Sum(nil)
-	actualHash := "sha256:" + hex.EncodeToString(hashBytes)
-
 	// Compare with recorded hash.
-	if actualHash != recorded.Hash {
+	if actualHash := responseHash(actualJSON); actualHash != recorded.Hash {
 		return fmt.Errorf("%w: expected %s, got %s", ErrReplayMismatch, recorded.Hash, actualHash)
 	}
 
 	return nil
 }
+
+// responseHash returns the "sha256:<hex>" hash of a serialized response.
+func responseHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
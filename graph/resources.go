@@ -0,0 +1,178 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// resourcePools limits how many nodes holding each resource tag execute at
+// once across all runs of an engine (see Options.ResourcePools).
+type resourcePools struct {
+	mu       sync.Mutex
+	limits   map[string]int
+	inUse    map[string]int
+	released chan struct{} // Closed and replaced whenever slots are released
+}
+
+// newResourcePools returns the pools for limits, or nil if there are none.
+// A limit below 1 is treated as 1.
+func newResourcePools(limits map[string]int) *resourcePools {
+	if len(limits) == 0 {
+		return nil
+	}
+	p := &resourcePools{
+		limits:   make(map[string]int, len(limits)),
+		inUse:    make(map[string]int, len(limits)),
+		released: make(chan struct{}),
+	}
+	for name, limit := range limits {
+		p.limits[name] = max(limit, 1)
+	}
+	return p
+}
+
+// pooled returns the tags of a node that name a configured pool, each once.
+func (p *resourcePools) pooled(tags []string) []string {
+	if p == nil {
+		return nil
+	}
+	var names []string
+	for _, tag := range tags {
+		if _, exists := p.limits[tag]; exists && !slices.Contains(names, tag) {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// tryAcquire takes a slot in every pool of names if all have one free, and
+// reports whether it did. It never takes some slots without the others.
+func (p *resourcePools) tryAcquire(names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range names {
+		if p.inUse[name] >= p.limits[name] {
+			return false
+		}
+	}
+	for _, name := range names {
+		p.inUse[name]++
+	}
+	return true
+}
+
+// acquire takes a slot in every pool of names, waiting until all have one
+// free or ctx is cancelled.
+func (p *resourcePools) acquire(ctx context.Context, names []string) error {
+	for {
+		wake := p.changed()
+		if p.tryAcquire(names) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+// release returns the slots taken for names and wakes the callers waiting
+// for them.
+func (p *resourcePools) release(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range names {
+		p.inUse[name]--
+	}
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+// changed returns a channel closed the next time slots are released, or nil
+// (which never fires) without pools. Take it before trying to acquire, so a
+// release in between is not missed.
+func (p *resourcePools) changed() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.released
+}
+
+// resourcesOf returns the pools a node's NodePolicy.Resources take a slot in.
+func (e *Engine[S]) resourcesOf(nodeID string) []string {
+	if e.pools == nil {
+		return nil
+	}
+	e.mu.RLock()
+	nodeImpl := e.nodes[nodeID]
+	e.mu.RUnlock()
+	if policy := nodePolicy(nodeImpl); policy != nil {
+		return e.pools.pooled(policy.Resources)
+	}
+	return nil
+}
+
+// poolAdmission returns the admission check a run's frontier applies to its
+// work items: it takes the slots of an item's pools if they are all free.
+// The time an item waited for its pools from the first time it was blocked is
+// recorded in the resource_wait_ms metric for each of them.
+//
+// The returned function is called under the frontier's lock, which also
+// guards its state.
+func (e *Engine[S]) poolAdmission() func(WorkItem[S]) bool {
+	blockedSince := make(map[uint64]time.Time)
+	return func(item WorkItem[S]) bool {
+		names := e.resourcesOf(item.NodeID)
+		if len(names) == 0 {
+			return true
+		}
+		if !e.pools.tryAcquire(names) {
+			if _, blocked := blockedSince[item.OrderKey]; !blocked {
+				blockedSince[item.OrderKey] = time.Now()
+			}
+			return false
+		}
+
+		var waited time.Duration
+		if since, blocked := blockedSince[item.OrderKey]; blocked {
+			waited = time.Since(since)
+			delete(blockedSince, item.OrderKey)
+		}
+		e.recordResourceWait(names, waited)
+		return true
+	}
+}
+
+// acquireResources takes a slot in every pool of names for a node about to
+// execute, waiting until they are free or ctx is cancelled, and records the
+// wait in the resource_wait_ms metric.
+func (e *Engine[S]) acquireResources(ctx context.Context, names []string) error {
+	start := time.Now()
+	if err := e.pools.acquire(ctx, names); err != nil {
+		return err
+	}
+	e.recordResourceWait(names, time.Since(start))
+	return nil
+}
+
+// recordResourceWait records the time a node waited for the pools of names.
+func (e *Engine[S]) recordResourceWait(names []string, waited time.Duration) {
+	if e.metrics == nil {
+		return
+	}
+	for _, name := range names {
+		e.metrics.RecordResourceWait(name, waited)
+	}
+}
@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TestEngine_ResourcePools verifies nodes tagged with a resource pool execute
+// no more than its limit at once, without holding up other nodes.
+func TestEngine_ResourcePools(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Ran []string
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Ran = append(prev.Ran, delta.Ran...)
+		return prev
+	}
+
+	// poolProbe tracks how many nodes of a resource pool execute at once
+	type poolProbe struct {
+		mu     sync.Mutex
+		active int
+		peak   int
+
+		// gate, if set, holds the nodes until it is closed
+		gate <-chan struct{}
+	}
+	// probed returns a node counting towards the probe's peak concurrency,
+	// which sleeps for pause, waits for the probe's gate and stops
+	probed := func(p *poolProbe, id string, pause time.Duration) Node[TestState] {
+		return NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+			p.mu.Lock()
+			p.active++
+			p.peak = max(p.peak, p.active)
+			p.mu.Unlock()
+			defer func() {
+				p.mu.Lock()
+				p.active--
+				p.mu.Unlock()
+			}()
+
+			time.Sleep(pause)
+			if p.gate != nil {
+				select {
+				case <-p.gate:
+				case <-ctx.Done():
+					return NodeResult[TestState]{Err: ctx.Err()}
+				}
+			}
+			return NodeResult[TestState]{Delta: TestState{Ran: []string{id}}, Route: Stop()}
+		})
+	}
+
+	t.Run("blocked items are skipped", func(t *testing.T) {
+		// The openai nodes wait for the untagged nodes to finish, which they
+		// only do if the worker not holding the pool skips the blocked items
+		gate := make(chan struct{})
+		var untagged atomic.Int32
+		probe := &poolProbe{gate: gate}
+		metrics := NewPrometheusMetrics(prometheus.NewRegistry())
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{},
+			Options{MaxSteps: 20, MaxConcurrentNodes: 2, Metrics: metrics},
+			WithResourcePool("openai", 1))
+
+		// fan queues the o* nodes, tagged with the pool, and the f* nodes at
+		// once; in this order the OrderKeys of the o* nodes are the smallest
+		branches := []string{"f1", "f2", "o3", "o1", "o2", "f3"}
+		fan := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Route: Many(branches)}
+		})
+		if err := engine.Add("fan", fan); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		for _, id := range branches {
+			var node Node[TestState]
+			if id[0] == 'o' {
+				node = policyNode[TestState]{Node: probed(probe, id, 0), policy: NodePolicy{Resources: []string{"openai"}}}
+			} else {
+				node = NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+					if untagged.Add(1) == 3 {
+						close(gate)
+					}
+					return NodeResult[TestState]{Delta: TestState{Ran: []string{id}}, Route: Stop()}
+				})
+			}
+			if err := engine.Add(id, node); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+		}
+		if err := engine.StartAt("fan"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		final, err := engine.Run(runCtx, "run-001", TestState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(final.Ran) != len(branches) {
+			t.Errorf("ran %v, want all %d branches", final.Ran, len(branches))
+		}
+		if probe.peak != 1 {
+			t.Errorf("%d openai nodes executed at once, want 1", probe.peak)
+		}
+
+		var m dto.Metric
+		if err := metrics.resourceWait.WithLabelValues("openai").(prometheus.Histogram).Write(&m); err != nil {
+			t.Fatalf("reading resource_wait_ms failed: %v", err)
+		}
+		if got := m.GetHistogram().GetSampleCount(); got != 3 {
+			t.Errorf("resource_wait_ms{pool=openai} has %d samples, want 3", got)
+		}
+	})
+
+	t.Run("pools are shared across runs", func(t *testing.T) {
+		probe := &poolProbe{}
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{},
+			Options{MaxSteps: 20}, WithResourcePool("db", 1))
+		node := policyNode[TestState]{Node: probed(probe, "write", 20*time.Millisecond), policy: NodePolicy{Resources: []string{"db", "unpooled"}}}
+		if err := engine.Add("write", node); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("write"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		var failed atomic.Int32
+		for _, runID := range []string{"run-001", "run-002", "run-003"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := engine.Run(ctx, runID, TestState{}); err != nil {
+					failed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if failed.Load() != 0 {
+			t.Fatalf("%d runs failed", failed.Load())
+		}
+		if probe.peak != 1 {
+			t.Errorf("%d db nodes executed at once, want 1", probe.peak)
+		}
+	})
+
+	t.Run("cancellation while waiting", func(t *testing.T) {
+		engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{},
+			Options{MaxSteps: 20}, WithResourcePool("db", 1))
+		node := policyNode[TestState]{Node: probed(&poolProbe{}, "write", 0), policy: NodePolicy{Resources: []string{"db"}}}
+		if err := engine.Add("write", node); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("write"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		// Hold the only slot so the run waits for it
+		engine.pools.tryAcquire([]string{"db"})
+		runCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := engine.Run(runCtx, "run-001", TestState{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Run returned %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		cfg := &engineConfig{}
+		var engineErr *EngineError
+		if err := WithResourcePool("openai", 0)(cfg); !errors.As(err, &engineErr) || engineErr.Code != "INVALID_RESOURCE_POOL" {
+			t.Errorf("expected INVALID_RESOURCE_POOL, got %v", err)
+		}
+		if len(cfg.opts.ResourcePools) != 0 {
+			t.Errorf("ResourcePools = %v, want the invalid pool not configured", cfg.opts.ResourcePools)
+		}
+	})
+}
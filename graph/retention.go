@@ -0,0 +1,129 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// Prune deletes the history the engine's store no longer retains under
+// policy: old step records, completed runs, unlabeled checkpoints, expired
+// idempotency keys and emitted outbox events (see store.RetentionPolicy).
+//
+// The deleted records are added to the pruned_records_total metric when
+// Options.Metrics is set, and reported in a store_pruned event whose Meta
+// holds the counts of store.PruneResult.
+//
+// Returns an EngineError with code:
+//   - PRUNE_UNSUPPORTED if the store does not implement store.Pruner
+//   - STORE_ERROR if the store fails to delete the records
+//
+// Example:
+//
+//	// Keep the last 100 steps of each run and drop runs a week after they complete
+//	result, err := engine.Prune(ctx, store.RetentionPolicy{
+//	    KeepLastSteps:   100,
+//	    CompletedRunTTL: 7 * 24 * time.Hour,
+//	})
+func (e *Engine[S]) Prune(ctx context.Context, policy store.RetentionPolicy) (store.PruneResult, error) {
+	pruner, err := e.pruner()
+	if err != nil {
+		return store.PruneResult{}, err
+	}
+	return e.prune(ctx, pruner, policy)
+}
+
+// PruneEvery prunes the engine's store with policy immediately and then every
+// interval, until ctx is cancelled. It blocks, so it is typically started in
+// its own goroutine:
+//
+//	go engine.PruneEvery(ctx, policy, time.Hour)
+//
+// A failed pass does not stop the schedule; it is reported in a prune_failed
+// event and retried at the next interval.
+//
+// Returns ctx.Err() once ctx is cancelled, or an EngineError with code:
+//   - PRUNE_UNSUPPORTED if the store does not implement store.Pruner
+//   - INVALID_INTERVAL if interval is not positive
+func (e *Engine[S]) PruneEvery(ctx context.Context, policy store.RetentionPolicy, interval time.Duration) error {
+	pruner, err := e.pruner()
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return &EngineError{
+			Message: "prune interval must be positive",
+			Code:    "INVALID_INTERVAL",
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.prune(ctx, pruner, policy); err != nil && ctx.Err() == nil {
+			e.publish(emit.Event{
+				Msg: "prune_failed",
+				Meta: map[string]interface{}{
+					"error": err.Error(),
+				},
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruner returns the engine's store as a store.Pruner.
+func (e *Engine[S]) pruner() (store.Pruner, error) {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return nil, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if e.store == nil {
+		return nil, &EngineError{
+			Message: "store is required",
+			Code:    "MISSING_STORE",
+		}
+	}
+	pruner, ok := e.store.(store.Pruner)
+	if !ok {
+		return nil, &EngineError{
+			Message: "store does not implement store.Pruner",
+			Code:    "PRUNE_UNSUPPORTED",
+		}
+	}
+	return pruner, nil
+}
+
+// prune runs one retention pass and reports what it deleted.
+func (e *Engine[S]) prune(ctx context.Context, pruner store.Pruner, policy store.RetentionPolicy) (store.PruneResult, error) {
+	result, err := pruner.Prune(ctx, policy)
+	if err != nil {
+		return result, &EngineError{
+			Message: "failed to prune store: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	if e.metrics != nil {
+		e.metrics.RecordPruned(result)
+	}
+	e.publish(emit.Event{
+		Msg: "store_pruned",
+		Meta: map[string]interface{}{
+			"runs":             result.Runs,
+			"steps":            result.Steps,
+			"checkpoints":      result.Checkpoints,
+			"idempotency_keys": result.IdempotencyKeys,
+			"events":           result.Events,
+		},
+	})
+	return result, nil
+}
@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestEngine_Prune verifies retention passes delete history from the store
+// and report what they deleted.
+func TestEngine_Prune(t *testing.T) {
+	ctx := context.Background()
+
+	type TestState struct {
+		Visited []string
+		Review  bool
+	}
+	reducer := func(prev, delta TestState) TestState {
+		prev.Visited = append(prev.Visited, delta.Visited...)
+		return prev
+	}
+
+	t.Run("prunes the store and records metrics", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		emitter := &mockEmitter{}
+		metrics := NewPrometheusMetrics(prometheus.NewRegistry())
+		engine := New(reducer, st, emitter, Options{MaxSteps: 10, CheckpointEveryStep: true, Metrics: metrics})
+
+		// a -> b -> c, checkpointed at every step
+		visit := func(id string, route Next) Node[TestState] {
+			return NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Delta: TestState{Visited: []string{id}}, Route: route}
+			})
+		}
+		_ = engine.Add("a", visit("a", Goto("b")))
+		_ = engine.Add("b", visit("b", Goto("c")))
+		_ = engine.Add("c", visit("c", Stop()))
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if _, err := engine.Run(ctx, "run-001", TestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		before, _ := st.ListCheckpoints(ctx, "run-001", "")
+
+		result, err := engine.Prune(ctx, store.RetentionPolicy{KeepLastSteps: 1, LabeledCheckpointsOnly: true})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Steps != 2 || result.Checkpoints != len(before)-1 {
+			t.Errorf("Prune = %+v, want 2 steps and %d checkpoints deleted", result, len(before)-1)
+		}
+
+		// The run can still be resumed from its latest checkpoint
+		after, _ := st.ListCheckpoints(ctx, "run-001", "")
+		if len(after) != 1 || after[0].StepID != before[len(before)-1].StepID {
+			t.Errorf("checkpoints after Prune = %+v, want only the latest", after)
+		}
+		if steps, _ := st.ListSteps(ctx, "run-001", store.Page{}); len(steps) != 1 || steps[0].NodeID != "c" {
+			t.Errorf("steps after Prune = %+v, want only the last", steps)
+		}
+
+		if got := testutil.ToFloat64(metrics.pruned.WithLabelValues("steps")); got != 2 {
+			t.Errorf("pruned_records_total{kind=steps} = %v, want 2", got)
+		}
+		var pruned []int
+		for _, event := range emitter.events {
+			if event.Msg == "store_pruned" {
+				pruned = append(pruned, event.Meta["steps"].(int))
+			}
+		}
+		if len(pruned) != 1 || pruned[0] != 2 {
+			t.Errorf("store_pruned events = %v, want one reporting 2 steps", pruned)
+		}
+	})
+
+	t.Run("completed run TTL under default options", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := New(reducer, st, &mockEmitter{}, Options{MaxSteps: 20})
+		// gate pauses the runs started for review until resumed
+		gate := NodeFunc[TestState](func(ctx context.Context, s TestState) NodeResult[TestState] {
+			if _, resumed := ResumeInput(ctx); s.Review && !resumed {
+				return NodeResult[TestState]{Route: Interrupt("approve?")}
+			}
+			return NodeResult[TestState]{Delta: TestState{Visited: []string{"gate"}}, Route: Goto("done")}
+		})
+		done := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+			return NodeResult[TestState]{Delta: TestState{Visited: []string{"done"}}, Route: Stop()}
+		})
+		_ = engine.Add("gate", gate)
+		_ = engine.Add("done", done)
+		if err := engine.StartAt("gate"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		// No run saves a checkpoint at every step
+		if _, err := engine.Run(ctx, "plain", TestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if _, err := engine.Run(ctx, "resumed", TestState{Review: true}); !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %v", err)
+		}
+		if _, err := engine.Resume(ctx, "resumed", "yes"); err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		if _, err := engine.Run(ctx, "paused", TestState{Review: true}); !errors.Is(err, ErrInterrupted) {
+			t.Fatalf("expected ErrInterrupted, got %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		result, err := engine.Prune(ctx, store.RetentionPolicy{CompletedRunTTL: time.Millisecond})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Runs != 2 || result.Steps != 4 {
+			t.Errorf("Prune = %+v, want 2 runs and their 4 steps deleted", result)
+		}
+		runs, _ := st.ListRuns(ctx, store.RunFilter{})
+		if len(runs) != 1 || runs[0].RunID != "paused" {
+			t.Errorf("runs after Prune = %+v, want only the paused run", runs)
+		}
+	})
+
+	t.Run("prune every interval until cancelled", func(t *testing.T) {
+		emitter := &mockEmitter{}
+		engine := New(reducer, store.NewMemStore[TestState](), emitter, Options{})
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			done <- engine.PruneEvery(ctx, store.RetentionPolicy{KeepLastSteps: 1}, time.Millisecond)
+		}()
+
+		deadline := time.After(5 * time.Second)
+		for {
+			emitter.mu.Lock()
+			passes := len(emitter.events)
+			emitter.mu.Unlock()
+			if passes >= 3 {
+				break
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("expected 3 prune passes, got %d", passes)
+			case <-time.After(time.Millisecond):
+			}
+		}
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("PruneEvery returned %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		st := store.NewMemStore[TestState]()
+		engine := New(reducer, st, &mockEmitter{}, Options{})
+		// Embedding the interface hides MemStore's Prune method
+		unsupported := New(reducer, struct{ store.Store[TestState] }{st}, &mockEmitter{}, Options{})
+
+		tests := []struct {
+			name     string
+			prune    func() error
+			wantCode string
+		}{
+			{
+				name: "store without prune support",
+				prune: func() error {
+					_, err := unsupported.Prune(ctx, store.RetentionPolicy{})
+					return err
+				},
+				wantCode: "PRUNE_UNSUPPORTED",
+			},
+			{
+				name: "schedule on a store without prune support",
+				prune: func() error {
+					return unsupported.PruneEvery(ctx, store.RetentionPolicy{}, time.Hour)
+				},
+				wantCode: "PRUNE_UNSUPPORTED",
+			},
+			{
+				name: "non-positive interval",
+				prune: func() error {
+					return engine.PruneEvery(ctx, store.RetentionPolicy{}, 0)
+				},
+				wantCode: "INVALID_INTERVAL",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var engineErr *EngineError
+				if err := tt.prune(); !errors.As(err, &engineErr) || engineErr.Code != tt.wantCode {
+					t.Errorf("expected %s, got %v", tt.wantCode, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,182 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// Backoff used for the deprecated Options.Retries setting.
+const (
+	legacyRetryBaseDelay = 100 * time.Millisecond
+	legacyRetryMaxDelay  = 30 * time.Second
+)
+
+// nodePolicy returns the NodePolicy of nodeImpl, or nil if it declares none.
+func nodePolicy[S any](nodeImpl Node[S]) *NodePolicy {
+	policyProvider, ok := nodeImpl.(interface{ Policy() NodePolicy })
+	if !ok {
+		return nil
+	}
+	p := policyProvider.Policy()
+	return &p
+}
+
+// retryPolicyFor returns the retry policy that applies to a node: its
+// NodePolicy.RetryPolicy, or else one derived from the deprecated
+// Options.Retries, which retries every error with a 100ms base delay.
+// Returns nil if the node is never retried.
+func (e *Engine[S]) retryPolicyFor(policy *NodePolicy) *RetryPolicy {
+	if policy != nil && policy.RetryPolicy != nil {
+		return policy.RetryPolicy
+	}
+	if e.opts.Retries > 0 {
+		return &RetryPolicy{
+			MaxAttempts: e.opts.Retries + 1,
+			BaseDelay:   legacyRetryBaseDelay,
+			MaxDelay:    legacyRetryMaxDelay,
+			Retryable:   func(error) bool { return true },
+		}
+	}
+	return nil
+}
+
+// runNode executes item's node with the engine's timeout and retry rules. It
+// is the single execution path shared by sequential and concurrent mode.
+//
+// Each attempt runs with AttemptKey set (starting at item.Attempt) and the
+// node's timeout (NodePolicy.Timeout or Options.DefaultNodeTimeout); a timeout
+// counts as a failed attempt. A failed attempt is retried when the node's
+// retry policy (see retryPolicyFor) deems the error retryable and attempts
+// remain: a node_retry event is emitted, the retry metric incremented, and the
+// attempt delayed by computeBackoff using the RNG from ctx, so backoff is
+// deterministic across replays. Nodes declaring Recordable effects record
+// their Recorded calls in the run's recorder.
+//
+// Once the node gives up, an error event is emitted and the error returned.
+// An error that exhausted the retry policy's attempts is wrapped so it matches
+// both ErrMaxAttemptsExceeded and the node's last error. A failure matching
+// one of the node's error edges instead returns the result routing to its
+// handler (see OnError). Step latency is recorded for every attempt.
+//
+// A node with a CachePolicy returns its cached result for the item's state
+// without executing, and caches the result it succeeds with (see cacheFor).
+func (e *Engine[S]) runNode(ctx context.Context, runID string, item WorkItem[S], nodeImpl Node[S]) (NodeResult[S], error) {
+	var zero NodeResult[S]
+
+	policy := nodePolicy(nodeImpl)
+	cache, err := e.cacheFor(runID, policy, item)
+	if err != nil {
+		e.emitError(runID, item.NodeID, item.StepID, err)
+		return zero, err
+	}
+	if cache != nil {
+		if result, hit := e.loadCached(ctx, runID, item, cache); hit {
+			return result, nil
+		}
+	}
+
+	retryPol := e.retryPolicyFor(policy)
+	rng, _ := ctx.Value(RNGKey).(*rand.Rand)
+	ctx = recorderFor(ctx, nodeImpl)
+
+	for attempt := item.Attempt; ; attempt++ {
+		attemptCtx := context.WithValue(ctx, AttemptKey, attempt)
+
+		startTime := time.Now()
+		result, timeoutErr := executeNodeWithTimeout(attemptCtx, nodeImpl, item.NodeID, item.State, policy, e.opts.DefaultNodeTimeout)
+		if timeoutErr != nil {
+			result.Err = timeoutErr
+		}
+
+		status := "success"
+		if result.Err != nil {
+			status = "error"
+		}
+		if e.metrics != nil {
+			e.metrics.RecordStepLatency(runID, item.NodeID, time.Since(startTime), status)
+		}
+
+		if result.Err == nil {
+			if cache != nil {
+				e.saveCached(ctx, runID, item, cache, result)
+			}
+			return result, nil
+		}
+
+		retry, err := shouldRetry(retryPol, item.NodeID, attempt, result.Err)
+		if !retry {
+			// Failures of a cancelled run are not the node's to handle
+			if ctx.Err() == nil {
+				if routed, ok := e.routeError(runID, item, err, attempt+1, result.Delta); ok {
+					return routed, nil
+				}
+			}
+			e.emitError(runID, item.NodeID, item.StepID, err)
+			return zero, err
+		}
+
+		delay := computeBackoff(attempt, retryPol.BaseDelay, retryPol.MaxDelay, rng)
+		e.emitRetry(runID, item, attempt, delay, result.Err)
+		if e.metrics != nil {
+			e.metrics.IncrementRetries(runID, item.NodeID, retryReason(result.Err))
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// shouldRetry decides whether a node that failed with nodeErr on attempt is
+// retried under retryPol. When it is not, the returned error is the one the
+// node fails with.
+func shouldRetry(retryPol *RetryPolicy, nodeID string, attempt int, nodeErr error) (bool, error) {
+	if retryPol == nil {
+		return false, nodeErr
+	}
+	if err := retryPol.Validate(); err != nil {
+		return false, fmt.Errorf("retry policy validation failed for node %s: %w", nodeID, err)
+	}
+	if retryPol.Retryable == nil || !retryPol.Retryable(nodeErr) {
+		return false, nodeErr
+	}
+	// MaxAttempts includes the initial attempt, so MaxAttempts=3 allows
+	// attempts 0, 1 and 2
+	if attempt+1 >= retryPol.MaxAttempts {
+		return false, fmt.Errorf("%w: node %s failed after %d attempts: %w", ErrMaxAttemptsExceeded, nodeID, retryPol.MaxAttempts, nodeErr)
+	}
+	return true, nil
+}
+
+// retryReason classifies a retried error for the retries metric.
+func retryReason(err error) string {
+	var engineErr *EngineError
+	if errors.As(err, &engineErr) && engineErr.Code == "NODE_TIMEOUT" {
+		return "timeout"
+	}
+	return "error"
+}
+
+// emitRetry emits a node_retry event before a failed attempt is retried.
+func (e *Engine[S]) emitRetry(runID string, item WorkItem[S], attempt int, delay time.Duration, err error) {
+	e.publish(emit.Event{
+		RunID:  runID,
+		Step:   item.StepID,
+		NodeID: item.NodeID,
+		Msg:    "node_retry",
+		Meta: map[string]interface{}{
+			"error":         err.Error(),
+			"retryable":     true,
+			"retry_attempt": attempt + 1,
+			"delay_ms":      delay.Milliseconds(),
+		},
+	})
+}
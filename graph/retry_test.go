@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// retryTestNode fails until it has run failures times, recording the attempt
+// number each run saw.
+type retryTestNode struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	attempts []int
+	policy   NodePolicy
+}
+
+func (n *retryTestNode) Run(ctx context.Context, s int) NodeResult[int] {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	attempt, _ := ctx.Value(AttemptKey).(int)
+	n.attempts = append(n.attempts, attempt)
+	if len(n.attempts) <= n.failures {
+		return NodeResult[int]{Err: n.err}
+	}
+	return NodeResult[int]{Delta: s + 1, Route: Stop()}
+}
+
+func (n *retryTestNode) Policy() NodePolicy {
+	return n.policy
+}
+
+// TestEngine_RetryPolicy verifies node retry policies behave the same in both
+// execution modes.
+func TestEngine_RetryPolicy(t *testing.T) {
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+
+	retryable := &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+	}
+
+	run := func(t *testing.T, mode int, node *retryTestNode, opts Options) (*mockEmitter, error) {
+		t.Helper()
+		emitter := &mockEmitter{}
+		opts.MaxSteps = 10
+		opts.MaxConcurrentNodes = mode
+		reducer := func(prev, delta int) int { return prev + delta }
+		engine := New(reducer, store.NewMemStore[int](), emitter, opts)
+		if err := engine.Add("node", node); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("node"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		_, err := engine.Run(context.Background(), "retry-run", 0)
+		return emitter, err
+	}
+
+	countEvents := func(emitter *mockEmitter, msg string) int {
+		emitter.mu.Lock()
+		defer emitter.mu.Unlock()
+		n := 0
+		for _, event := range emitter.events {
+			if event.Msg == msg {
+				n++
+			}
+		}
+		return n
+	}
+
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("retryable errors are retried", func(t *testing.T) {
+				node := &retryTestNode{failures: 2, err: errTransient, policy: NodePolicy{RetryPolicy: retryable}}
+
+				emitter, err := run(t, mode.maxConcurrentNodes, node, Options{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if fmt.Sprint(node.attempts) != "[0 1 2]" {
+					t.Errorf("attempts = %v, want [0 1 2]", node.attempts)
+				}
+				if got := countEvents(emitter, "node_retry"); got != 2 {
+					t.Errorf("got %d node_retry events, want 2", got)
+				}
+				if got := countEvents(emitter, "node_start"); got != 1 {
+					t.Errorf("got %d node_start events, want 1", got)
+				}
+			})
+
+			t.Run("non-retryable errors fail immediately", func(t *testing.T) {
+				node := &retryTestNode{failures: 1, err: errFatal, policy: NodePolicy{RetryPolicy: retryable}}
+
+				_, err := run(t, mode.maxConcurrentNodes, node, Options{})
+				if !errors.Is(err, errFatal) || errors.Is(err, ErrMaxAttemptsExceeded) {
+					t.Errorf("expected the node's error, got %v", err)
+				}
+				if len(node.attempts) != 1 {
+					t.Errorf("node ran %d times, want 1", len(node.attempts))
+				}
+			})
+
+			t.Run("exhausted attempts wrap the last error", func(t *testing.T) {
+				node := &retryTestNode{failures: 5, err: errTransient, policy: NodePolicy{RetryPolicy: retryable}}
+
+				emitter, err := run(t, mode.maxConcurrentNodes, node, Options{})
+				if !errors.Is(err, ErrMaxAttemptsExceeded) || !errors.Is(err, errTransient) {
+					t.Errorf("expected ErrMaxAttemptsExceeded wrapping the node error, got %v", err)
+				}
+				if len(node.attempts) != 3 {
+					t.Errorf("node ran %d times, want 3", len(node.attempts))
+				}
+				if got := countEvents(emitter, "error"); got != 1 {
+					t.Errorf("got %d error events, want 1", got)
+				}
+
+				// A single allowed attempt is exhausted by the first failure
+				once := *retryable
+				once.MaxAttempts = 1
+				node = &retryTestNode{failures: 1, err: errTransient, policy: NodePolicy{RetryPolicy: &once}}
+				if _, err := run(t, mode.maxConcurrentNodes, node, Options{}); !errors.Is(err, ErrMaxAttemptsExceeded) || !errors.Is(err, errTransient) {
+					t.Errorf("MaxAttempts 1: expected ErrMaxAttemptsExceeded wrapping the node error, got %v", err)
+				}
+			})
+
+			t.Run("timeouts are retried", func(t *testing.T) {
+				calls := 0
+				policy := NodePolicy{
+					Timeout: 10 * time.Millisecond,
+					RetryPolicy: &RetryPolicy{
+						MaxAttempts: 2,
+						BaseDelay:   time.Millisecond,
+						Retryable: func(err error) bool {
+							var engineErr *EngineError
+							return errors.As(err, &engineErr) && engineErr.Code == "NODE_TIMEOUT"
+						},
+					},
+				}
+				slow := NodeFunc[int](func(ctx context.Context, s int) NodeResult[int] {
+					calls++
+					if calls == 1 {
+						<-ctx.Done()
+						return NodeResult[int]{Err: ctx.Err()}
+					}
+					return NodeResult[int]{Delta: s + 1, Route: Stop()}
+				})
+
+				reducer := func(prev, delta int) int { return prev + delta }
+				engine := New(reducer, store.NewMemStore[int](), &mockEmitter{}, Options{MaxSteps: 10, MaxConcurrentNodes: mode.maxConcurrentNodes})
+				if err := engine.Add("node", policyNode[int]{Node: slow, policy: policy}); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+				if err := engine.StartAt("node"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+				final, err := engine.Run(context.Background(), "retry-timeout", 0)
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if final != 1 || calls != 2 {
+					t.Errorf("final = %d after %d calls, want 1 after 2", final, calls)
+				}
+			})
+
+			t.Run("deprecated Retries retries every error", func(t *testing.T) {
+				node := &retryTestNode{failures: 1, err: errFatal}
+
+				if _, err := run(t, mode.maxConcurrentNodes, node, Options{Retries: 1}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if fmt.Sprint(node.attempts) != "[0 1]" {
+					t.Errorf("attempts = %v, want [0 1]", node.attempts)
+				}
+			})
+		})
+	}
+}
+
+// policyNode attaches a NodePolicy to a node.
+type policyNode[S any] struct {
+	Node[S]
+	policy NodePolicy
+}
+
+func (n policyNode[S]) Policy() NodePolicy {
+	return n.policy
+}
+
+// TestComputeBackoff verifies backoff growth, capping and jitter bounds.
+func TestComputeBackoff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1)) // #nosec G404 -- deterministic test RNG
+
+	t.Run("doubles per attempt with jitter below base", func(t *testing.T) {
+		for attempt, want := range []time.Duration{10, 20, 40} {
+			got := computeBackoff(attempt, 10*time.Millisecond, time.Second, rng)
+			if min := want * time.Millisecond; got < min || got >= min+10*time.Millisecond {
+				t.Errorf("attempt %d: delay %v, want [%v, %v)", attempt, got, min, min+10*time.Millisecond)
+			}
+		}
+	})
+
+	t.Run("caps at MaxDelay", func(t *testing.T) {
+		if got := computeBackoff(20, 10*time.Millisecond, 50*time.Millisecond, rng); got >= 60*time.Millisecond {
+			t.Errorf("delay %v exceeds cap", got)
+		}
+	})
+
+	t.Run("zero MaxDelay means no cap", func(t *testing.T) {
+		if got := computeBackoff(3, 10*time.Millisecond, 0, rng); got < 80*time.Millisecond {
+			t.Errorf("delay %v, want at least 80ms", got)
+		}
+	})
+
+	t.Run("zero base has no delay", func(t *testing.T) {
+		if got := computeBackoff(3, 0, 0, rng); got != 0 {
+			t.Errorf("delay %v, want 0", got)
+		}
+	})
+
+	t.Run("large attempts do not overflow", func(t *testing.T) {
+		if got := computeBackoff(200, time.Second, time.Minute, rng); got < time.Minute || got > time.Minute+time.Second {
+			t.Errorf("delay %v, want about 1m", got)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/binary"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -44,6 +45,41 @@ type WorkItem[S any] struct {
 
 	// EdgeIndex is the index of the edge taken from parent, used for deterministic ordering
 	EdgeIndex int `json:"edge_index"`
+
+	// Forks records, per enclosing fan-out, the deltas applied along this path
+	// since that fan-out. Join barriers replay them to merge sibling branches.
+	// It is maintained by the engine; leave it nil when constructing work items.
+	Forks [][]S `json:"forks,omitempty"`
+
+	// ForkSlots records, parallel to Forks, which branch of each enclosing
+	// fan-out the item is on. It is maintained by the engine.
+	ForkSlots []ForkSlot `json:"fork_slots,omitempty"`
+
+	// Input is the branch input of an item started by a Sends route, exposed
+	// to its node through SendInput.
+	Input interface{} `json:"input,omitempty"`
+
+	// Interrupted marks the node that paused the run with Interrupt. When the
+	// run is resumed, this item runs again with the resume input in its context.
+	Interrupted bool `json:"interrupted,omitempty"`
+
+	// Priority is the scheduling priority of the item's node, from its
+	// NodePolicy. Items with a higher priority are dequeued first. It is set
+	// by the engine when the item is queued.
+	Priority int `json:"priority,omitempty"`
+}
+
+// ForkSlot identifies a work item's branch within an enclosing fan-out.
+type ForkSlot struct {
+	// Fanout identifies the fan-out: the routing node's execution that
+	// started it.
+	Fanout uint64 `json:"fanout"`
+
+	// Branch is the OrderKey the branch started with.
+	Branch uint64 `json:"branch"`
+
+	// Count is the number of branches a Sends fan-out started, or 0 for Many.
+	Count int `json:"count,omitempty"`
 }
 
 // ComputeOrderKey generates a deterministic sort key from the parent node ID and edge index.
@@ -87,38 +123,67 @@ func computeOrderKey(parentNodeID string, edgeIndex int) uint64 {
 	return orderKey
 }
 
-// workHeap implements heap.Interface for priority queue ordering by OrderKey.
-// This internal type is used by Frontier to maintain sorted order of work items.
-type workHeap[S any] []WorkItem[S]
+// workHeap implements heap.Interface for priority queue ordering by Priority,
+// then OrderKey. This internal type is used by Frontier to maintain sorted
+// order of work items.
+type workHeap[S any] struct {
+	items []WorkItem[S]
 
-func (h workHeap[S]) Len() int { return len(h) }
+	// aging is the number of steps after which a queued item gains one
+	// priority level (see Options.PriorityAging). Zero disables aging.
+	aging int
+}
 
-func (h workHeap[S]) Less(i, j int) bool {
-	// Min-heap: smaller OrderKey has higher priority
-	return h[i].OrderKey < h[j].OrderKey
+func (h *workHeap[S]) Len() int { return len(h.items) }
+
+func (h *workHeap[S]) Less(i, j int) bool {
+	return h.before(h.items[i], h.items[j])
 }
 
-func (h workHeap[S]) Swap(i, j int) {
-	h[i], h[j] = h[j], h[i]
+func (h *workHeap[S]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
 }
 
 func (h *workHeap[S]) Push(x interface{}) {
-	*h = append(*h, x.(WorkItem[S]))
+	h.items = append(h.items, x.(WorkItem[S]))
 }
 
 func (h *workHeap[S]) Pop() interface{} {
-	old := *h
+	old := h.items
 	n := len(old)
 	item := old[n-1]
-	*h = old[0 : n-1]
+	h.items = old[0 : n-1]
 	return item
 }
 
+// before reports whether a is dequeued before b: the item with the higher
+// priority first, then the smaller OrderKey.
+//
+// With aging, an item gains one priority level for every aging steps it is
+// behind the item it is compared to, so items queued at an early step are
+// not starved by a stream of higher-priority items from later steps. Age is
+// measured in StepIDs rather than time, so the order depends only on the
+// items and is the same on every replay.
+func (h *workHeap[S]) before(a, b WorkItem[S]) bool {
+	if h.aging > 0 {
+		// a's priority at b's step, against b's, both scaled by aging
+		rankA := int64(a.Priority)*int64(h.aging) - int64(a.StepID)
+		rankB := int64(b.Priority)*int64(h.aging) - int64(b.StepID)
+		if rankA != rankB {
+			return rankA > rankB
+		}
+	} else if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	// Min-heap: smaller OrderKey has higher priority
+	return a.OrderKey < b.OrderKey
+}
+
 // Frontier manages the work queue for concurrent graph execution with bounded capacity
 // and deterministic ordering. It combines a priority queue (heap) for ordering with a
 // buffered channel for bounded queue depth and backpressure.
 //
-// The Frontier ensures that work items are dequeued in deterministic order (by OrderKey)
+// The Frontier ensures that work items are dequeued in deterministic order (by Priority, then OrderKey)
 // even when they are enqueued concurrently from multiple goroutines. This is critical
 // for deterministic replay of graph executions.
 //
@@ -141,6 +206,13 @@ type Frontier[S any] struct {
 	ctx      context.Context // Context for cancellation
 	mu       sync.Mutex      // Protects heap and len operations
 
+	// priority, if set, computes the Priority of items as they are enqueued
+	priority func(WorkItem[S]) int
+
+	// Admission control (see dequeueAdmitted), guarded by mu
+	spare   int           // Notifications taken by workers waiting for an admissible item
+	changed chan struct{} // Closed on the next Enqueue, nil until a worker waits
+
 	// Metrics tracking (T068) - use atomic operations for thread-safe updates
 	totalEnqueued      atomic.Int64 // Total work items enqueued
 	totalDequeued      atomic.Int64 // Total work items dequeued
@@ -165,7 +237,7 @@ type Frontier[S any] struct {
 // BUG-003 fix (T019): Channel is notification-only (empty struct), not data carrier.
 func NewFrontier[S any](ctx context.Context, capacity int, runID string, metrics *PrometheusMetrics, emitter emit.Emitter) *Frontier[S] {
 	f := &Frontier[S]{
-		heap:     make(workHeap[S], 0),
+		heap:     workHeap[S]{items: make([]WorkItem[S], 0)},
 		queue:    make(chan struct{}, capacity), // T019: Empty struct channel
 		capacity: capacity,
 		ctx:      ctx,
@@ -178,7 +250,7 @@ func NewFrontier[S any](ctx context.Context, capacity int, runID string, metrics
 }
 
 // Enqueue adds a work item to the frontier queue. The item is first added to the
-// internal heap (sorted by OrderKey), then sent to the buffered channel.
+// internal heap (sorted by Priority, then OrderKey), then sent to the buffered channel.
 //
 // If the channel is full, this method blocks until:
 //   - Space becomes available in the channel (backpressure), or
@@ -203,6 +275,10 @@ func (f *Frontier[S]) Enqueue(ctx context.Context, item WorkItem[S]) error {
 		return ctx.Err()
 	}
 
+	if f.priority != nil {
+		item.Priority = f.priority(item)
+	}
+
 	// Add to heap under lock
 	f.mu.Lock()
 	heap.Push(&f.heap, item)
@@ -233,6 +309,7 @@ func (f *Frontier[S]) Enqueue(ctx context.Context, item WorkItem[S]) error {
 			return ctx.Err()
 		case f.queue <- struct{}{}:
 			f.totalEnqueued.Add(1)
+			f.notifyWaiting()
 			if time.Since(waitStart) > time.Millisecond && f.emitter != nil {
 				go f.emitter.Emit(emit.Event{RunID: f.runID, Step: item.StepID, NodeID: item.NodeID, Msg: "backpressure_resolved", Meta: map[string]interface{}{"wait_duration_ms": time.Since(waitStart).Milliseconds(), "queue_depth": currentDepth}})
 			}
@@ -244,16 +321,29 @@ func (f *Frontier[S]) Enqueue(ctx context.Context, item WorkItem[S]) error {
 		return ctx.Err()
 	case f.queue <- struct{}{}:
 		f.totalEnqueued.Add(1)
+		f.notifyWaiting()
 		return nil
 	}
 }
 
-// Dequeue retrieves the work item with the smallest OrderKey from the frontier.
+// notifyWaiting wakes the workers waiting in dequeueAdmitted for an
+// admissible item, since the new item may be one.
+func (f *Frontier[S]) notifyWaiting() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.changed != nil {
+		close(f.changed)
+		f.changed = nil
+	}
+}
+
+// Dequeue retrieves the work item with the highest Priority and, among those,
+// the smallest OrderKey from the frontier.
 // This method blocks until:
 //   - A work item becomes available, or
 //   - The context is cancelled
 //
-// Returns the work item with the minimum OrderKey and nil error on success.
+// Returns the next work item and nil error on success.
 // Returns a zero-value work item and context error if the context is cancelled.
 //
 // BUG-003 fix (T021): Dequeue waits for notification, then pops from heap.
@@ -272,7 +362,7 @@ func (f *Frontier[S]) Dequeue(ctx context.Context) (WorkItem[S], error) {
 	case <-ctx.Done():
 		return zero, ctx.Err()
 	case <-f.queue: // T021: Receive notification (empty struct discarded)
-		// T021: Pop min OrderKey item from heap under lock
+		// T021: Pop the next item from heap under lock
 		// Heap is the single source of truth for ordering
 		f.mu.Lock()
 		defer f.mu.Unlock()
@@ -291,6 +381,84 @@ func (f *Frontier[S]) Dequeue(ctx context.Context) (WorkItem[S], error) {
 	}
 }
 
+// dequeueAdmitted retrieves the first work item in dequeue order that admit
+// accepts, skipping items it rejects, such as items whose resource
+// pools are full. admit is called under the frontier's lock and is expected
+// to take whatever the item needs once it accepts it.
+//
+// When no queued item is admitted, the worker waits until an item is
+// enqueued, released fires, or ctx is cancelled, and tries again. released
+// returns a channel that fires when rejected items may have become
+// admissible; it is taken before items are tried so that no wake-up is lost.
+//
+// The notification of the queued items a waiting worker could not admit is
+// kept as a spare, so that the channel and heap stay in step. Dequeue does
+// not use spares, so a frontier must be consumed through one of the two.
+func (f *Frontier[S]) dequeueAdmitted(ctx context.Context, admit func(WorkItem[S]) bool, released func() <-chan struct{}) (WorkItem[S], error) {
+	var zero WorkItem[S]
+
+	for {
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+
+		// Take the notification of a queued item
+		f.mu.Lock()
+		spare := f.spare > 0
+		if spare {
+			f.spare--
+		}
+		f.mu.Unlock()
+		if !spare {
+			select {
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			case <-f.queue:
+			}
+		}
+		wake := released()
+
+		f.mu.Lock()
+		if f.heap.Len() == 0 {
+			// Should not happen if queue and heap are synchronized
+			f.mu.Unlock()
+			return zero, context.Canceled
+		}
+
+		// Try the queued items in dequeue order
+		order := make([]int, f.heap.Len())
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return f.heap.before(f.heap.items[order[a]], f.heap.items[order[b]])
+		})
+		for _, i := range order {
+			if admit(f.heap.items[i]) {
+				item := heap.Remove(&f.heap, i).(WorkItem[S])
+				f.totalDequeued.Add(1)
+				f.mu.Unlock()
+				return item, nil
+			}
+		}
+
+		// Every queued item is blocked: keep the notification and wait
+		f.spare++
+		if f.changed == nil {
+			f.changed = make(chan struct{})
+		}
+		enqueued := f.changed
+		f.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-enqueued:
+		case <-wake:
+		}
+	}
+}
+
 // Len returns the current number of work items in the frontier queue.
 // This method is thread-safe and can be called concurrently with Enqueue/Dequeue.
 func (f *Frontier[S]) Len() int {
@@ -299,6 +467,27 @@ func (f *Frontier[S]) Len() int {
 	return f.heap.Len()
 }
 
+// drain removes and returns the work items left in the frontier, in dequeue
+// order. It is used once the workers have stopped, to checkpoint the work a
+// cancelled run had not started.
+func (f *Frontier[S]) drain() []WorkItem[S] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	items := make([]WorkItem[S], 0, f.heap.Len())
+	for f.heap.Len() > 0 {
+		items = append(items, heap.Pop(&f.heap).(WorkItem[S]))
+	}
+	return items
+}
+
+// items returns a copy of the work items queued in the frontier, in no
+// particular order.
+func (f *Frontier[S]) items() []WorkItem[S] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]WorkItem[S](nil), f.heap.items...)
+}
+
 // SchedulerMetrics tracks execution metrics for monitoring and observability (T067).
 //
 // These metrics provide insight into the runtime behavior of the concurrent scheduler,
@@ -4,6 +4,7 @@ package graph
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -173,3 +174,98 @@ func TestFrontierStandalone(t *testing.T) {
 		}
 	})
 }
+
+// TestFrontierPriority verifies the frontier dequeues items by priority
+// before OrderKey, with aging lifting items queued at earlier steps.
+func TestFrontierPriority(t *testing.T) {
+	type testState struct {
+		Value int
+	}
+	ctx := context.Background()
+
+	dequeueAll := func(t *testing.T, f *Frontier[testState], items []WorkItem[testState]) []string {
+		t.Helper()
+		for _, item := range items {
+			if err := f.Enqueue(ctx, item); err != nil {
+				t.Fatalf("Enqueue failed: %v", err)
+			}
+		}
+		var order []string
+		for f.Len() > 0 {
+			item, err := f.Dequeue(ctx)
+			if err != nil {
+				t.Fatalf("Dequeue failed: %v", err)
+			}
+			order = append(order, item.NodeID)
+		}
+		return order
+	}
+
+	t.Run("priority before order key", func(t *testing.T) {
+		f := NewFrontier[testState](ctx, 10, "", nil, nil)
+		order := dequeueAll(t, f, []WorkItem[testState]{
+			{OrderKey: 100, NodeID: "enrich", StepID: 1},
+			{OrderKey: 300, NodeID: "answer", StepID: 1, Priority: 10},
+			{OrderKey: 200, NodeID: "audit", StepID: 1, Priority: -1},
+			{OrderKey: 50, NodeID: "index", StepID: 1},
+		})
+		want := []string{"answer", "index", "enrich", "audit"}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("dequeue order = %v, want %v", order, want)
+		}
+	})
+
+	t.Run("priority computed on enqueue", func(t *testing.T) {
+		f := NewFrontier[testState](ctx, 10, "", nil, nil)
+		f.priority = func(item WorkItem[testState]) int { return item.State.Value }
+		order := dequeueAll(t, f, []WorkItem[testState]{
+			{OrderKey: 100, NodeID: "low", State: testState{Value: 1}},
+			{OrderKey: 200, NodeID: "high", State: testState{Value: 5}},
+		})
+		if want := []string{"high", "low"}; !reflect.DeepEqual(order, want) {
+			t.Errorf("dequeue order = %v, want %v", order, want)
+		}
+	})
+
+	t.Run("aging", func(t *testing.T) {
+		f := NewFrontier[testState](ctx, 10, "", nil, nil)
+		f.heap.aging = 2
+		// background has waited 1 step behind answer@2 (not enough to pass
+		// it) and 4 steps behind answer@5 (two levels, passing it)
+		order := dequeueAll(t, f, []WorkItem[testState]{
+			{OrderKey: 100, NodeID: "answer@5", StepID: 5, Priority: 1},
+			{OrderKey: 200, NodeID: "answer@2", StepID: 2, Priority: 1},
+			{OrderKey: 300, NodeID: "background", StepID: 1},
+		})
+		want := []string{"answer@2", "background", "answer@5"}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("dequeue order = %v, want %v", order, want)
+		}
+	})
+
+	t.Run("admission follows priority", func(t *testing.T) {
+		f := NewFrontier[testState](ctx, 10, "", nil, nil)
+		for _, item := range []WorkItem[testState]{
+			{OrderKey: 100, NodeID: "blocked", Priority: 5},
+			{OrderKey: 200, NodeID: "answer", Priority: 1},
+			{OrderKey: 50, NodeID: "enrich"},
+		} {
+			if err := f.Enqueue(ctx, item); err != nil {
+				t.Fatalf("Enqueue failed: %v", err)
+			}
+		}
+		admit := func(item WorkItem[testState]) bool { return item.NodeID != "blocked" }
+		released := func() <-chan struct{} { return nil }
+		var order []string
+		for i := 0; i < 2; i++ {
+			item, err := f.dequeueAdmitted(ctx, admit, released)
+			if err != nil {
+				t.Fatalf("dequeueAdmitted failed: %v", err)
+			}
+			order = append(order, item.NodeID)
+		}
+		if want := []string{"answer", "enrich"}; !reflect.DeepEqual(order, want) {
+			t.Errorf("dequeue order = %v, want %v", order, want)
+		}
+	})
+}
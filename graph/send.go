@@ -0,0 +1,146 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Send is a single branch of a map-reduce fan-out: one execution of NodeID
+// that receives Input as its own branch input.
+//
+// Unlike Many, which starts each listed node once, Sends can start the same
+// node any number of times, so the number of branches can depend on the
+// state, for example one branch per document or per batch of files.
+type Send struct {
+	// NodeID is the node to execute.
+	NodeID string
+
+	// Input is the branch input, available to the node through SendInput.
+	// Inputs should be JSON-serializable so paused runs can be checkpointed.
+	Input interface{}
+}
+
+// Sends returns a Next that starts one branch per Send, in parallel.
+//
+// Every branch runs on a deep copy of the routing node's state with its delta
+// applied, and receives its own input through SendInput. Branch deltas are
+// merged back with the Reducer in send order, both in the run's final state
+// and at a join node waiting for the sent node (see Engine.Join), which fires
+// once every branch has arrived.
+//
+// Sends with no branches is treated as no route, so the node's edges are
+// evaluated instead.
+//
+// Example:
+//
+//	func (n *SplitNode) Run(ctx context.Context, s State) graph.NodeResult[State] {
+//	    sends := make([]graph.Send, len(s.Documents))
+//	    for i, doc := range s.Documents {
+//	        sends[i] = graph.Send{NodeID: "summarize", Input: doc}
+//	    }
+//	    return graph.NodeResult[State]{Route: graph.Sends(sends...)}
+//	}
+func Sends(sends ...Send) Next {
+	return Next{Sends: sends}
+}
+
+// SendEach returns a Next that starts nodeID once per input, in parallel.
+// It is shorthand for Sends with one Send per element of inputs.
+//
+// Example:
+//
+//	return graph.NodeResult[State]{Route: graph.SendEach("review", s.Batches)}
+func SendEach[T any](nodeID string, inputs []T) Next {
+	sends := make([]Send, len(inputs))
+	for i, input := range inputs {
+		sends[i] = Send{NodeID: nodeID, Input: input}
+	}
+	return Next{Sends: sends}
+}
+
+// SendInput returns the branch input of the current node when it was started
+// by a Sends route. ok is false for every other node and when the branch was
+// sent a nil input. Nodes routed to from a sent node do not inherit its input.
+//
+// Inputs of branches restored from a checkpoint (for example after Resume)
+// are decoded as generic JSON values; use SendInputAs to get a typed value in
+// both cases.
+func SendInput(ctx context.Context) (input interface{}, ok bool) {
+	input = ctx.Value(SendInputKey)
+	return input, input != nil
+}
+
+// SendInputAs returns the branch input of the current node as a T. Inputs
+// that are not a T, such as those restored from a checkpoint, are converted
+// through JSON. ok is false if there is no input or it cannot be converted.
+//
+// Example:
+//
+//	batch, ok := graph.SendInputAs[[]File](ctx)
+//	if !ok {
+//	    return graph.NodeResult[State]{Err: errors.New("review requires a batch")}
+//	}
+func SendInputAs[T any](ctx context.Context) (T, bool) {
+	var zero T
+
+	input, ok := SendInput(ctx)
+	if !ok {
+		return zero, false
+	}
+	if value, ok := input.(T); ok {
+		return value, true
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return zero, false
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return zero, false
+	}
+	return value, true
+}
+
+// withSendInput exposes item's branch input to its node. Other nodes,
+// including those of a subgraph run by a sent node, never see an input
+// inherited from ctx.
+func withSendInput[S any](ctx context.Context, item WorkItem[S]) context.Context {
+	if item.Input == nil {
+		if ctx.Value(SendInputKey) != nil {
+			return context.WithValue(ctx, SendInputKey, nil)
+		}
+		return ctx
+	}
+	return context.WithValue(ctx, SendInputKey, item.Input)
+}
+
+// sendOrderKey returns the OrderKey of branch index of a Sends route taken by
+// item. The high 32 bits identify the routing path and the low 32 bits hold
+// the index, so a route's branches are adjacent and ordered as sent.
+func sendOrderKey[S any](item WorkItem[S], index int) uint64 {
+	return successorOrderKey(item, 0)&^0xFFFFFFFF | uint64(uint32(index)) // #nosec G115 -- index is a slice index
+}
+
+// successorOrderKey returns the OrderKey of the successor item reaches over
+// edgeIdx. Paths inside a Sends fan-out all pass through the same nodes, so
+// their keys also depend on item's own OrderKey to keep sibling branches
+// apart.
+func successorOrderKey[S any](item WorkItem[S], edgeIdx int) uint64 {
+	if !inSendBranch(item.ForkSlots) {
+		return computeOrderKey(item.NodeID, edgeIdx)
+	}
+	return computeOrderKey(fmt.Sprintf("%s@%016x", item.NodeID, item.OrderKey), edgeIdx)
+}
+
+// inSendBranch reports whether any enclosing fan-out is a Sends route.
+func inSendBranch(slots []ForkSlot) bool {
+	for _, slot := range slots {
+		if slot.Count > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestEngine_Sends verifies map-reduce fan-out with per-branch inputs.
+func TestEngine_Sends(t *testing.T) {
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("join merges sent branches in send order", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				var aggregateRuns atomic.Int32
+				var seen JoinTestState
+				addJoinTestNode(t, engine, "split", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{
+						Delta: JoinTestState{Values: []string{"split"}},
+						Route: SendEach("review", []int{30, 20, 10, 0}),
+					}
+				})
+				addJoinTestNode(t, engine, "review", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					delay, ok := SendInputAs[int](ctx)
+					if !ok {
+						return NodeResult[JoinTestState]{Err: errors.New("missing send input")}
+					}
+					time.Sleep(time.Duration(delay) * time.Millisecond) // Later branches finish first
+					return NodeResult[JoinTestState]{
+						Delta: JoinTestState{Values: []string{fmt.Sprintf("review:%d", delay)}, Counter: 1},
+						Route: Goto("aggregate"),
+					}
+				})
+				addJoinTestNode(t, engine, "aggregate", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+					aggregateRuns.Add(1)
+					seen = s
+					return NodeResult[JoinTestState]{Route: Stop()}
+				})
+				if err := engine.Join("aggregate", "review"); err != nil {
+					t.Fatalf("Join failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				final, err := engine.Run(context.Background(), "sends-join", JoinTestState{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+
+				if got := aggregateRuns.Load(); got != 1 {
+					t.Errorf("aggregate ran %d times, want 1", got)
+				}
+				want := "[split review:30 review:20 review:10 review:0]"
+				if fmt.Sprint(seen.Values) != want || seen.Counter != 4 {
+					t.Errorf("aggregate saw %v (counter %d), want %s (counter 4)", seen.Values, seen.Counter, want)
+				}
+				if final.Counter != 4 {
+					t.Errorf("final Counter = %d, want 4", final.Counter)
+				}
+
+				// Sent branch deltas are merged into the final state in send order
+				var reviews []string
+				for _, v := range final.Values {
+					if v != "split" {
+						reviews = append(reviews, v)
+					}
+				}
+				if fmt.Sprint(reviews) != "[review:30 review:20 review:10 review:0]" {
+					t.Errorf("final reviews = %v, want send order", reviews)
+				}
+			})
+
+			t.Run("each branch observes its own state and input", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				var mu sync.Mutex
+				posted := make(map[string]uint64)
+				addJoinTestNode(t, engine, "split", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{
+						Delta: JoinTestState{Values: []string{"split"}},
+						Route: Sends(Send{NodeID: "summarize", Input: "a"}, Send{NodeID: "summarize", Input: "b"}),
+					}
+				})
+				addJoinTestNode(t, engine, "summarize", func(ctx context.Context, s JoinTestState) NodeResult[JoinTestState] {
+					doc, _ := SendInput(ctx)
+					if len(s.Values) != 1 {
+						return NodeResult[JoinTestState]{Err: fmt.Errorf("branch state leaked: %v", s.Values)}
+					}
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{fmt.Sprint(doc)}}}
+				})
+				addJoinTestNode(t, engine, "post", func(ctx context.Context, s JoinTestState) NodeResult[JoinTestState] {
+					if input, ok := SendInput(ctx); ok {
+						return NodeResult[JoinTestState]{Err: fmt.Errorf("post inherited send input %v", input)}
+					}
+					mu.Lock()
+					posted[fmt.Sprint(s.Values)] = ctx.Value(OrderKeyKey).(uint64)
+					mu.Unlock()
+					return NodeResult[JoinTestState]{Route: Stop()}
+				})
+				if err := engine.Connect("summarize", "post", nil); err != nil {
+					t.Fatalf("Connect failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				if _, err := engine.Run(context.Background(), "sends-branches", JoinTestState{}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+
+				if len(posted) != 2 || posted["[split a]"] == 0 || posted["[split b]"] == 0 {
+					t.Fatalf("post saw %v, want one run per branch", posted)
+				}
+				if posted["[split a]"] == posted["[split b]"] {
+					t.Errorf("successors of sent branches share OrderKey %d", posted["[split a]"])
+				}
+			})
+
+			t.Run("empty sends falls back to edges", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				addJoinTestNode(t, engine, "split", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Route: SendEach("review", []string{})}
+				})
+				addJoinTestNode(t, engine, "review", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Err: errors.New("review should not run")}
+				})
+				addJoinTestNode(t, engine, "done", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"done"}}, Route: Stop()}
+				})
+				if err := engine.Connect("split", "done", nil); err != nil {
+					t.Fatalf("Connect failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				final, err := engine.Run(context.Background(), "sends-empty", JoinTestState{})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if fmt.Sprint(final.Values) != "[done]" {
+					t.Errorf("final Values = %v, want [done]", final.Values)
+				}
+			})
+
+			t.Run("interrupted branch keeps its input on resume", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+
+				var seen JoinTestState
+				addJoinTestNode(t, engine, "split", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Route: SendEach("review", []string{"auto", "ask"})}
+				})
+				addJoinTestNode(t, engine, "review", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					file, ok := SendInputAs[string](ctx)
+					if !ok {
+						return NodeResult[JoinTestState]{Err: errors.New("missing send input")}
+					}
+					if file == "ask" {
+						answer, ok := ResumeInput(ctx)
+						if !ok {
+							return NodeResult[JoinTestState]{Route: Interrupt("approve " + file)}
+						}
+						file = fmt.Sprintf("%s:%v", file, answer)
+					}
+					return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{file}}, Route: Goto("aggregate")}
+				})
+				addJoinTestNode(t, engine, "aggregate", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+					seen = s
+					return NodeResult[JoinTestState]{Route: Stop()}
+				})
+				if err := engine.Join("aggregate", "review"); err != nil {
+					t.Fatalf("Join failed: %v", err)
+				}
+				if err := engine.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				_, err := engine.Run(context.Background(), "sends-interrupt", JoinTestState{})
+				if !errors.Is(err, ErrInterrupted) {
+					t.Fatalf("expected ErrInterrupted, got %v", err)
+				}
+				if _, err := engine.Resume(context.Background(), "sends-interrupt", "yes"); err != nil {
+					t.Fatalf("Resume failed: %v", err)
+				}
+				if fmt.Sprint(seen.Values) != "[auto ask:yes]" {
+					t.Errorf("aggregate saw %v, want [auto ask:yes]", seen.Values)
+				}
+			})
+		})
+	}
+}
+
+// TestSendInputAs verifies typed access to branch inputs.
+func TestSendInputAs(t *testing.T) {
+	type batch struct {
+		Files []string `json:"files"`
+	}
+
+	t.Run("no input", func(t *testing.T) {
+		if _, ok := SendInputAs[batch](context.Background()); ok {
+			t.Error("expected ok=false without a send input")
+		}
+	})
+
+	t.Run("typed input", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), SendInputKey, batch{Files: []string{"a.go"}})
+		got, ok := SendInputAs[batch](ctx)
+		if !ok || fmt.Sprint(got.Files) != "[a.go]" {
+			t.Errorf("SendInputAs = %+v, %v", got, ok)
+		}
+	})
+
+	t.Run("input decoded from a checkpoint", func(t *testing.T) {
+		restored := map[string]interface{}{"files": []interface{}{"a.go", "b.go"}}
+		ctx := context.WithValue(context.Background(), SendInputKey, restored)
+		got, ok := SendInputAs[batch](ctx)
+		if !ok || fmt.Sprint(got.Files) != "[a.go b.go]" {
+			t.Errorf("SendInputAs = %+v, %v", got, ok)
+		}
+	})
+
+	t.Run("unconvertible input", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), SendInputKey, "not a batch")
+		if _, ok := SendInputAs[batch](ctx); ok {
+			t.Error("expected ok=false for an unconvertible input")
+		}
+	})
+}
@@ -0,0 +1,313 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// runSequential executes the workflow in sequential mode (MaxConcurrentNodes == 0).
+//
+// Execution proceeds in rounds. Each round runs every pending work item:
+//   - A single item runs inline with retry and timeout support
+//   - Several items (the branches of a Route.Many fan-out) run in parallel,
+//     each on an isolated deep copy of its state (T104-T108)
+//
+// Deltas are merged into the run state in OrderKey order, as in concurrent
+// mode (T111-T112), and every node's routing decision is then followed, so execution continues
+// past a fan-out until every path has stopped. Routing follows the same rules
+// as concurrent mode:
+//   - Nodes inside a fan-out branch observe their branch's state
+//   - Routes into join nodes wait at the barrier until all predecessors arrive
+//   - A node reached from several branches without a join runs once per branch
+//
+// Nodes outside any fan-out (including join nodes closing the outermost
+// fan-out) observe the run's accumulated state.
+//
+// Under Options.CheckpointEveryStep, and for leased runs (see
+// checkpointsSteps), a CheckpointV2 is saved between rounds (see
+// roundFrontier), so a run can be forked or resumed from any round boundary.
+//
+// Nodes that return Interrupt stop their path without contributing a delta.
+// Once every other path has settled the run pauses (see pauseRun) and the
+// accumulated state is returned together with the InterruptError.
+//
+// A run stopped with Cancel returns a CancelledError once its current round
+// has returned; the round's items form the frontier of the cancel checkpoint
+// (see Options.CheckpointOnCancel).
+//
+// step is the number of steps already persisted for runID, and barriers holds
+// the run's join arrivals (restored ones when continuing from a checkpoint).
+func (e *Engine[S]) runSequential(ctx context.Context, run *activeRun[S], initial S, pending []WorkItem[S], step int, barriers *joinBarriers[S]) (S, error) {
+	var zero S
+	runID := run.runID
+
+	// Report the items waiting for the next round in the run's Status
+	var queued atomic.Int64
+	run.track(func() int { return int(queued.Load()) })
+
+	// Collect the run's recorded I/O for its checkpoints
+	ctx = e.withRecorder(ctx)
+
+	state := initial
+	var paused []pausedNode[S]
+
+	for len(pending) > 0 {
+		queued.Store(int64(len(pending)))
+
+		// Check context cancellation
+		select {
+		case <-ctx.Done():
+			if cause := cancelled(ctx); cause != nil {
+				return zero, e.stopCancelled(ctx, runID, cause, step, state, roundFrontier(pending, paused, barriers))
+			}
+			return zero, ctx.Err()
+		default:
+		}
+
+		// Check MaxSteps limit (T060)
+		if e.opts.MaxSteps > 0 && step+len(pending) > e.opts.MaxSteps {
+			return zero, &EngineError{
+				Message: "workflow exceeded MaxSteps limit",
+				Code:    "MAX_STEPS_EXCEEDED",
+			}
+		}
+
+		// Order the round by OrderKey, as concurrent mode merges deltas, so
+		// step numbers and merge order never depend on goroutine completion
+		// order (T111-T112)
+		round := pending
+		sort.SliceStable(round, func(i, j int) bool {
+			return round[i].OrderKey < round[j].OrderKey
+		})
+		for i := range round {
+			round[i].StepID = step + i // Events use 0-based step indexing
+			if len(round[i].Forks) == 0 {
+				round[i].State = state
+			}
+		}
+
+		queued.Store(0)
+		var results []NodeResult[S]
+		var err error
+		if len(round) == 1 {
+			var result NodeResult[S]
+			result, err = e.executeStep(ctx, run, round[0])
+			results = []NodeResult[S]{result}
+		} else {
+			results, err = e.executeParallel(ctx, run, round)
+		}
+		if err != nil {
+			// A cancelled round runs again when the run is continued
+			if cause := cancelled(ctx); cause != nil {
+				return zero, e.stopCancelled(ctx, runID, cause, step, state, roundFrontier(round, paused, barriers))
+			}
+			return zero, err
+		}
+
+		pending = nil
+		for i, item := range round {
+			result := results[i]
+			step++
+
+			// Interrupted paths wait for Resume; their delta is discarded
+			if result.Route.Interrupt {
+				e.emitInterrupt(runID, item, result.Route.InterruptPayload)
+				paused = append(paused, pausedNode[S]{item: item, payload: result.Route.InterruptPayload})
+				continue
+			}
+
+			// Merge state update (only on success), resolving conflicting
+			// writes of concurrent branches
+			state = e.reducer(state, result.Delta)
+			barriers.conflicts.observe(item.ForkSlots, item.StepID, result.Delta)
+			resolved, err := barriers.conflicts.resolve(state)
+			if err != nil {
+				return zero, err
+			}
+			state = resolved
+
+			// Persist state after node execution (T058)
+			if err := e.store.SaveStep(ctx, runID, step, item.NodeID, state); err != nil {
+				return zero, &EngineError{
+					Message: "failed to save step: " + err.Error(),
+					Code:    "STORE_ERROR",
+				}
+			}
+
+			// Emit node_end event with delta (T155)
+			e.emitNodeEnd(runID, item.NodeID, item.StepID, result.Delta)
+			e.publishState(runID, item, state)
+
+			// Branch nodes route from their branch state, others from the run state
+			nextState := state
+			if len(item.Forks) > 0 {
+				nextState = e.reducer(item.State, result.Delta)
+			}
+
+			successors, err := e.routeSuccessors(runID, item, result, nextState)
+			if err != nil {
+				return zero, err
+			}
+			for _, next := range successors {
+				if !barriers.has(next.NodeID) {
+					pending = append(pending, next)
+					continue
+				}
+				joined, ready, err := barriers.arrive(next, result.Delta)
+				if err != nil {
+					return zero, err
+				}
+				if ready {
+					e.emitJoinReady(runID, joined)
+					pending = append(pending, joined)
+				}
+			}
+		}
+
+		// Checkpoint the round boundary so the run can be forked or resumed
+		// from this step
+		if e.checkpointsSteps(run) && len(pending) > 0 {
+			if err := e.saveCheckpoint(ctx, runID, step, state, roundFrontier(pending, paused, barriers), runRecorder(ctx), ""); err != nil {
+				return zero, err
+			}
+		}
+	}
+
+	// Paused paths keep their join arrivals waiting until Resume
+	if len(paused) > 0 {
+		return state, e.pauseRun(ctx, runID, step, state, paused, barriers)
+	}
+
+	// Every path has stopped; a join still waiting can never fire
+	if err := barriers.pendingErr(); err != nil {
+		return zero, err
+	}
+
+	// The final checkpoint keeps the run's recorded I/O for replay, and
+	// replaces the pending work of a run continued from a checkpoint so the
+	// store sees it completed. Runs of engines without recordable nodes are
+	// otherwise only checkpointed when every step is
+	if e.recordsIO() || e.opts.ReplayMode || e.checkpointsSteps(run) || run.restored {
+		e.saveFinalCheckpoint(ctx, runID, step, state)
+	}
+
+	return state, nil
+}
+
+// roundFrontier returns the frontier checkpointed between two sequential
+// rounds: the next round's items, the paths paused so far (which run again
+// when restored) and the join arrivals still waiting at a barrier.
+func roundFrontier[S any](pending []WorkItem[S], paused []pausedNode[S], barriers *joinBarriers[S]) []WorkItem[S] {
+	frontier := make([]WorkItem[S], 0, len(pending)+len(paused))
+	frontier = append(frontier, pending...)
+	for _, p := range paused {
+		item := p.item
+		item.Attempt = 0
+		frontier = append(frontier, item)
+	}
+	return append(frontier, barriers.arrivals()...)
+}
+
+// executeStep runs a single work item in sequential mode.
+//
+// The node runs on item.State through runNode, which enforces its timeout
+// (US2) and retries it according to its RetryPolicy (US1), exactly as in
+// concurrent mode. node_start is emitted before the first attempt and an error
+// event after the final failure. The caller is responsible for merging the
+// delta and emitting node_end.
+//
+// The item is reported in the run's Status while its node executes.
+func (e *Engine[S]) executeStep(ctx context.Context, run *activeRun[S], item WorkItem[S]) (NodeResult[S], error) {
+	var zero NodeResult[S]
+	runID := run.runID
+
+	// Get current node implementation
+	e.mu.RLock()
+	nodeImpl, exists := e.nodes[item.NodeID]
+	e.mu.RUnlock()
+
+	if !exists {
+		return zero, &EngineError{
+			Message: "node not found during execution: " + item.NodeID,
+			Code:    "NODE_NOT_FOUND",
+		}
+	}
+
+	// Emit node_start event (T153)
+	e.emitNodeStart(runID, item.NodeID, item.StepID)
+
+	// Expose run metadata, EmitCustom and any Resume input to the node
+	ctx = e.nodeContext(ctx, runID, item)
+
+	id := run.start(item)
+	defer run.finish(id)
+	run.setStep(item.StepID + 1)
+
+	// Wait for the node's resource pools, shared with the engine's other runs
+	if resources := e.resourcesOf(item.NodeID); len(resources) > 0 {
+		if err := e.acquireResources(ctx, resources); err != nil {
+			return zero, err
+		}
+		defer e.pools.release(resources)
+	}
+
+	return e.runNode(ctx, runID, item, nodeImpl)
+}
+
+// executeParallel executes multiple work items in parallel with isolated state copies (T104-T108).
+//
+// Each branch:
+//  1. Receives a deep copy of its state (T104)
+//  2. Executes in its own goroutine via executeStep (T106)
+//  3. Uses an RNG seeded from its OrderKey, so branches never share RNG state
+//
+// Results are returned in the same order as items. If any branch fails, the
+// error of the first failed item in that order is returned once every branch
+// has finished (T113-T114).
+//
+// Uses sync.WaitGroup for coordination (T108).
+func (e *Engine[S]) executeParallel(ctx context.Context, run *activeRun[S], items []WorkItem[S]) ([]NodeResult[S], error) {
+	results := make([]NodeResult[S], len(items))
+	errs := make([]error, len(items))
+	baseSeed := runSeed(run.runID)
+
+	// WaitGroup for synchronization (T108)
+	var wg sync.WaitGroup
+
+	// Launch goroutines for each branch (T106)
+	for i, item := range items {
+		wg.Add(1)
+
+		go func(i int, item WorkItem[S]) {
+			defer wg.Done()
+
+			// Deep copy state for isolation (T104)
+			branchState, err := deepCopy(item.State)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			item.State = branchState
+
+			itemRNG := rand.New(rand.NewSource(baseSeed ^ int64(item.OrderKey))) // #nosec G115 G404 -- deterministic RNG for replay, not security
+			branchCtx := context.WithValue(ctx, RNGKey, itemRNG)
+
+			results[i], errs[i] = e.executeStep(branchCtx, run, item)
+		}(i, item)
+	}
+
+	// Wait for all branches to complete
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
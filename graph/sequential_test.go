@@ -0,0 +1,277 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// buildFanOutJoinGraph wires fanout -> {a1 -> a2, b} -> join -> finish.
+// The join node records the state it observes in seen.
+func buildFanOutJoinGraph(t *testing.T, engine *Engine[JoinTestState], seen *JoinTestState) {
+	t.Helper()
+
+	addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{
+			Delta: JoinTestState{Values: []string{"fanout"}},
+			Route: Many([]string{"a1", "b"}),
+		}
+	})
+	addJoinTestNode(t, engine, "a1", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"a1"}, Counter: 1}, Route: Goto("a2")}
+	})
+	addJoinTestNode(t, engine, "a2", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+		// a2 observes only its own branch.
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"a2"}, Counter: s.Counter * 10}, Route: Goto("join")}
+	})
+	addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"b"}, Counter: 100}, Route: Goto("join")}
+	})
+	addJoinTestNode(t, engine, "join", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+		*seen = s
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"join"}}}
+	})
+	addJoinTestNode(t, engine, "finish", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"finish"}}, Route: Stop()}
+	})
+
+	if err := engine.Connect("join", "finish", nil); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := engine.Join("join", "a2", "b"); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if err := engine.StartAt("fanout"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+}
+
+// TestEngine_SequentialFanOut verifies sequential mode keeps routing after Route.Many.
+func TestEngine_SequentialFanOut(t *testing.T) {
+	t.Run("continues past fan-out through join and edges", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 50})
+		var seen JoinTestState
+		buildFanOutJoinGraph(t, engine, &seen)
+
+		final, err := engine.Run(context.Background(), "seq-fanout", JoinTestState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// a1 (1) + a2 (10) + b (100)
+		if seen.Counter != 111 {
+			t.Errorf("join saw Counter = %d, want 111", seen.Counter)
+		}
+		if final.Counter != 111 {
+			t.Errorf("final Counter = %d, want 111", final.Counter)
+		}
+		// a1 and b merge in OrderKey order, as in concurrent mode
+		want := []string{"fanout", "b", "a1", "a2", "join", "finish"}
+		if len(final.Values) != len(want) {
+			t.Fatalf("final Values = %v, want %v", final.Values, want)
+		}
+		for i := range want {
+			if final.Values[i] != want[i] {
+				t.Fatalf("final Values = %v, want %v", final.Values, want)
+			}
+		}
+	})
+
+	t.Run("matches concurrent mode", func(t *testing.T) {
+		run := func(maxConcurrent int) (JoinTestState, JoinTestState) {
+			engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+				MaxSteps:           50,
+				MaxConcurrentNodes: maxConcurrent,
+			})
+			var seen JoinTestState
+			buildFanOutJoinGraph(t, engine, &seen)
+
+			final, err := engine.Run(context.Background(), "seq-vs-concurrent", JoinTestState{})
+			if err != nil {
+				t.Fatalf("Run (MaxConcurrentNodes=%d) failed: %v", maxConcurrent, err)
+			}
+			return seen, final
+		}
+
+		seqSeen, seqFinal := run(0)
+		concSeen, concFinal := run(4)
+
+		if seqSeen.Counter != concSeen.Counter {
+			t.Errorf("join Counter: sequential %d, concurrent %d", seqSeen.Counter, concSeen.Counter)
+		}
+		if seqFinal.Counter != concFinal.Counter {
+			t.Errorf("final Counter: sequential %d, concurrent %d", seqFinal.Counter, concFinal.Counter)
+		}
+		if len(seqFinal.Values) != len(concFinal.Values) {
+			t.Errorf("final Values: sequential %v, concurrent %v", seqFinal.Values, concFinal.Values)
+		}
+	})
+
+	t.Run("branches merge in the same order as concurrent mode", func(t *testing.T) {
+		type TestState struct {
+			Last string
+			Sum  int
+		}
+		reducer := func(prev, delta TestState) TestState {
+			if delta.Last != "" {
+				prev.Last = delta.Last
+			}
+			prev.Sum += delta.Sum
+			return prev
+		}
+
+		run := func(maxConcurrent int) TestState {
+			engine := New(reducer, store.NewMemStore[TestState](), &mockEmitter{}, Options{
+				MaxSteps:           50,
+				MaxConcurrentNodes: maxConcurrent,
+			})
+			// The OrderKeys of alpha and beta sort in the opposite order of
+			// their node IDs, and the sent branches all run the same node
+			fanout := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Route: Many([]string{"alpha", "beta", "send"})}
+			})
+			branch := func(id string) NodeFunc[TestState] {
+				return func(_ context.Context, _ TestState) NodeResult[TestState] {
+					return NodeResult[TestState]{Delta: TestState{Last: id, Sum: 1}, Route: Goto("join")}
+				}
+			}
+			send := NodeFunc[TestState](func(_ context.Context, _ TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Route: SendEach("worker", []string{"x", "y", "z"})}
+			})
+			worker := NodeFunc[TestState](func(ctx context.Context, _ TestState) NodeResult[TestState] {
+				input, _ := SendInputAs[string](ctx)
+				return NodeResult[TestState]{Delta: TestState{Sum: len(input) * 10}, Route: Goto("join")}
+			})
+			join := NodeFunc[TestState](func(_ context.Context, s TestState) NodeResult[TestState] {
+				return NodeResult[TestState]{Delta: TestState{Sum: s.Sum * 100}, Route: Stop()}
+			})
+			nodes := map[string]Node[TestState]{
+				"fanout": fanout, "alpha": branch("alpha"), "beta": branch("beta"),
+				"send": send, "worker": worker, "join": join,
+			}
+			for id, node := range nodes {
+				if err := engine.Add(id, node); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+			}
+			if err := engine.Join("join", "alpha", "beta", "worker"); err != nil {
+				t.Fatalf("Join failed: %v", err)
+			}
+			if err := engine.StartAt("fanout"); err != nil {
+				t.Fatalf("StartAt failed: %v", err)
+			}
+
+			final, err := engine.Run(context.Background(), "merge-order", TestState{})
+			if err != nil {
+				t.Fatalf("Run (MaxConcurrentNodes=%d) failed: %v", maxConcurrent, err)
+			}
+			return final
+		}
+
+		sequential, concurrent := run(0), run(4)
+		if !reflect.DeepEqual(sequential, concurrent) {
+			t.Errorf("final state: sequential %+v, concurrent %+v", sequential, concurrent)
+		}
+		if sequential.Last != "alpha" || sequential.Sum != 3232 {
+			t.Errorf("final state = %+v, want alpha merged last and Sum 3232", sequential)
+		}
+	})
+
+	t.Run("node reached from each branch without join runs per branch", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 50})
+
+		var mu sync.Mutex
+		var observed []int
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Many([]string{"a", "b"})}
+		})
+		addJoinTestNode(t, engine, "a", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}}
+		})
+		addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 2}}
+		})
+		addJoinTestNode(t, engine, "collect", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+			mu.Lock()
+			observed = append(observed, s.Counter)
+			mu.Unlock()
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 10}, Route: Stop()}
+		})
+		for _, from := range []string{"a", "b"} {
+			if err := engine.Connect(from, "collect", nil); err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		final, err := engine.Run(context.Background(), "seq-no-join", JoinTestState{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// Each branch's collect sees only that branch's delta.
+		if len(observed) != 2 || observed[0]+observed[1] != 3 {
+			t.Errorf("collect observed %v, want one run per branch seeing 1 and 2", observed)
+		}
+		if final.Counter != 23 {
+			t.Errorf("final Counter = %d, want 23", final.Counter)
+		}
+	})
+
+	t.Run("missing join predecessor reports no progress", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 50})
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Many([]string{"a", "b"})}
+		})
+		addJoinTestNode(t, engine, "a", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Goto("join")}
+		})
+		addJoinTestNode(t, engine, "b", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		addJoinTestNode(t, engine, "join", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		if err := engine.Join("join", "a", "b"); err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		_, err := engine.Run(context.Background(), "seq-join-missing", JoinTestState{})
+		if !errors.Is(err, ErrNoProgress) {
+			t.Fatalf("expected ErrNoProgress, got %v", err)
+		}
+	})
+
+	t.Run("branches count toward MaxSteps", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 3})
+
+		addJoinTestNode(t, engine, "fanout", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Many([]string{"a", "b", "c"})}
+		})
+		for _, id := range []string{"a", "b", "c"} {
+			addJoinTestNode(t, engine, id, func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+				return NodeResult[JoinTestState]{Route: Stop()}
+			})
+		}
+		if err := engine.StartAt("fanout"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+
+		_, err := engine.Run(context.Background(), "seq-max-steps", JoinTestState{})
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "MAX_STEPS_EXCEEDED" {
+			t.Fatalf("expected MAX_STEPS_EXCEEDED, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dshills/langgraph-go/graph"
+	"github.com/dshills/langgraph-go/graph/emit"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// Build creates an Engine from doc using the node types and predicates in reg.
+//
+// Document options become the base graph.Options; options are applied on top
+// of them. Every problem in the document (unknown node types or predicates,
+// duplicate IDs, factory errors) is reported together in an error wrapping
+// ErrInvalidDocument. The built graph is then compiled (see
+// graph.Engine.Compile), so dangling edges are rejected and the returned
+// engine cannot be modified.
+func Build[S any](doc *Document, reg *Registry[S], reducer graph.Reducer[S], st store.Store[S], emitter emit.Emitter, options ...graph.Option) (*graph.Engine[S], error) {
+	if doc == nil {
+		return nil, fmt.Errorf("%w: document is nil", ErrInvalidDocument)
+	}
+	if reg == nil {
+		return nil, errors.New("registry cannot be nil")
+	}
+
+	engineOptions := []interface{}{graph.Options{
+		MaxSteps:            doc.Options.MaxSteps,
+		MaxConcurrentNodes:  doc.Options.MaxConcurrentNodes,
+		QueueDepth:          doc.Options.QueueDepth,
+		BackpressureTimeout: time.Duration(doc.Options.BackpressureTimeout),
+		DefaultNodeTimeout:  time.Duration(doc.Options.DefaultNodeTimeout),
+		RunWallClockBudget:  time.Duration(doc.Options.RunWallClockBudget),
+	}}
+	for _, opt := range options {
+		engineOptions = append(engineOptions, opt)
+	}
+	engine := graph.New(reducer, st, emitter, engineOptions...)
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	for i, node := range doc.Nodes {
+		factory, ok := reg.nodes[node.Type]
+		if !ok {
+			fail("nodes[%d] %q: unknown node type %q", i, node.ID, node.Type)
+			continue
+		}
+		impl, err := factory(node.ID, node.Config)
+		if err != nil {
+			fail("nodes[%d] %q: %v", i, node.ID, err)
+			continue
+		}
+		if err := engine.Add(node.ID, impl); err != nil {
+			fail("nodes[%d] %q: %v", i, node.ID, err)
+			continue
+		}
+		if len(node.Join) > 0 {
+			if err := engine.Join(node.ID, node.Join...); err != nil {
+				fail("nodes[%d] %q: %v", i, node.ID, err)
+			}
+		}
+	}
+
+	for i, edge := range doc.Edges {
+		var predicate graph.Predicate[S]
+		if edge.When != "" {
+			var ok bool
+			if predicate, ok = reg.predicates[edge.When]; !ok {
+				fail("edges[%d] %s -> %s: unknown predicate %q", i, edge.From, edge.To, edge.When)
+				continue
+			}
+		}
+		label := edge.Label
+		if label == "" {
+			label = edge.When
+		}
+		if err := engine.ConnectLabeled(edge.From, edge.To, label, predicate); err != nil {
+			fail("edges[%d] %s -> %s: %v", i, edge.From, edge.To, err)
+		}
+	}
+
+	if doc.Start == "" {
+		fail("start node is required")
+	} else if err := engine.StartAt(doc.Start); err != nil {
+		fail("start: %v", err)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, errors.Join(problems...))
+	}
+
+	if _, err := engine.Compile(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	return engine, nil
+}
+
+// Load parses a YAML or JSON document and builds its Engine (see Build).
+func Load[S any](data []byte, reg *Registry[S], reducer graph.Reducer[S], st store.Store[S], emitter emit.Emitter, options ...graph.Option) (*graph.Engine[S], error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return Build(doc, reg, reducer, st, emitter, options...)
+}
+
+// LoadFile reads a YAML or JSON document and builds its Engine (see Build).
+func LoadFile[S any](path string, reg *Registry[S], reducer graph.Reducer[S], st store.Store[S], emitter emit.Emitter, options ...graph.Option) (*graph.Engine[S], error) {
+	doc, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Build(doc, reg, reducer, st, emitter, options...)
+}
@@ -0,0 +1,94 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// TestLoadFile verifies a document builds a runnable, compiled engine.
+func TestLoadFile(t *testing.T) {
+	engine, err := LoadFile("testdata/ticket.yaml", newTicketRegistry(t), ticketReducer, store.NewMemStore[Ticket](), nil)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	for category, queue := range map[string]string{"billing": "billing", "bug": "support"} {
+		final, err := engine.Run(context.Background(), "ticket-"+category, Ticket{Category: category})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if final.Queue != queue {
+			t.Errorf("category %s routed to %q, want %q", category, final.Queue, queue)
+		}
+	}
+
+	// Predicate names label their edges
+	if edge := engine.Topology().Edges[0]; edge.Label != "is_billing" {
+		t.Errorf("edge label = %q, want is_billing", edge.Label)
+	}
+
+	var engineErr *graph.EngineError
+	if err := engine.Connect("classify", "billing", nil); !errors.As(err, &engineErr) || engineErr.Code != "GRAPH_COMPILED" {
+		t.Errorf("Connect on a loaded engine = %v, want GRAPH_COMPILED", err)
+	}
+}
+
+// TestBuild_Errors verifies every problem in a document is reported.
+func TestBuild_Errors(t *testing.T) {
+	reg := newTicketRegistry(t)
+
+	t.Run("document problems are reported together", func(t *testing.T) {
+		doc := &Document{
+			Nodes: []NodeSpec{
+				{ID: "classify", Type: "llm"},
+				{ID: "billing", Type: "handoff", Config: []byte(`{"queu": "x"}`)},
+			},
+			Edges: []EdgeSpec{{From: "classify", To: "billing", When: "is_urgent"}},
+		}
+
+		_, err := Build(doc, reg, ticketReducer, store.NewMemStore[Ticket](), nil)
+		if !errors.Is(err, ErrInvalidDocument) {
+			t.Fatalf("expected ErrInvalidDocument, got %v", err)
+		}
+		for _, want := range []string{`unknown node type "llm"`, `"billing"`, `unknown predicate "is_urgent"`, "start node is required"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not mention %s", err, want)
+			}
+		}
+	})
+
+	t.Run("topology errors fail compile", func(t *testing.T) {
+		doc := &Document{
+			Start: "classify",
+			Nodes: []NodeSpec{{ID: "classify", Type: "classifier"}},
+			Edges: []EdgeSpec{{From: "classify", To: "missing"}},
+		}
+
+		_, err := Build(doc, reg, ticketReducer, store.NewMemStore[Ticket](), nil)
+		var engineErr *graph.EngineError
+		if !errors.Is(err, ErrInvalidDocument) || !errors.As(err, &engineErr) || engineErr.Code != "INVALID_GRAPH" {
+			t.Fatalf("expected ErrInvalidDocument wrapping INVALID_GRAPH, got %v", err)
+		}
+	})
+
+	t.Run("document options configure the engine", func(t *testing.T) {
+		doc, err := Parse([]byte("start: loop\noptions: {max_steps: 3}\nnodes: [{id: loop, type: classifier}]\nedges: [{from: loop, to: loop}]\n"))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		engine, err := Build(doc, reg, ticketReducer, store.NewMemStore[Ticket](), nil)
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		_, err = engine.Run(context.Background(), "loop", Ticket{})
+		var engineErr *graph.EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "MAX_STEPS_EXCEEDED" {
+			t.Errorf("expected MaxSteps to stop the loop, got %v", err)
+		}
+	})
+}
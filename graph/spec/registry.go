@@ -0,0 +1,73 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// NodeFactory creates a node of a registered type from its ID and config.
+type NodeFactory[S any] func(id string, config json.RawMessage) (graph.Node[S], error)
+
+// Registry holds the node types and predicates a Document can refer to.
+// It is safe for concurrent use.
+type Registry[S any] struct {
+	mu         sync.RWMutex
+	nodes      map[string]NodeFactory[S]
+	predicates map[string]graph.Predicate[S]
+}
+
+// NewRegistry creates an empty registry for state type S.
+func NewRegistry[S any]() *Registry[S] {
+	return &Registry[S]{
+		nodes:      make(map[string]NodeFactory[S]),
+		predicates: make(map[string]graph.Predicate[S]),
+	}
+}
+
+// RegisterNode registers a node type under typeName.
+//
+// Returns an error if typeName is empty, factory is nil, or the type is
+// already registered.
+func (r *Registry[S]) RegisterNode(typeName string, factory NodeFactory[S]) error {
+	if typeName == "" {
+		return errors.New("node type name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("node factory for %q cannot be nil", typeName)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.nodes[typeName]; exists {
+		return fmt.Errorf("node type %q is already registered", typeName)
+	}
+	r.nodes[typeName] = factory
+	return nil
+}
+
+// RegisterPredicate registers a named edge predicate.
+//
+// Returns an error if name is empty, predicate is nil, or the name is already
+// registered.
+func (r *Registry[S]) RegisterPredicate(name string, predicate graph.Predicate[S]) error {
+	if name == "" {
+		return errors.New("predicate name cannot be empty")
+	}
+	if predicate == nil {
+		return fmt.Errorf("predicate %q cannot be nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.predicates[name]; exists {
+		return fmt.Errorf("predicate %q is already registered", name)
+	}
+	r.predicates[name] = predicate
+	return nil
+}
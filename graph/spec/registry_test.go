@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// Ticket is the state type for spec tests.
+type Ticket struct {
+	Category string
+	Queue    string
+}
+
+func ticketReducer(prev, delta Ticket) Ticket {
+	if delta.Category != "" {
+		prev.Category = delta.Category
+	}
+	if delta.Queue != "" {
+		prev.Queue = delta.Queue
+	}
+	return prev
+}
+
+// newTicketRegistry registers the classifier and handoff node types and the
+// is_billing predicate.
+func newTicketRegistry(t *testing.T) *Registry[Ticket] {
+	t.Helper()
+
+	reg := NewRegistry[Ticket]()
+	if err := reg.RegisterNode("classifier", func(string, json.RawMessage) (graph.Node[Ticket], error) {
+		return graph.NodeFunc[Ticket](func(_ context.Context, _ Ticket) graph.NodeResult[Ticket] {
+			return graph.NodeResult[Ticket]{}
+		}), nil
+	}); err != nil {
+		t.Fatalf("RegisterNode failed: %v", err)
+	}
+	if err := reg.RegisterNode("handoff", func(_ string, config json.RawMessage) (graph.Node[Ticket], error) {
+		var cfg struct {
+			Queue string `json:"queue"`
+		}
+		if err := Decode(config, &cfg); err != nil {
+			return nil, err
+		}
+		return graph.NodeFunc[Ticket](func(_ context.Context, _ Ticket) graph.NodeResult[Ticket] {
+			return graph.NodeResult[Ticket]{Delta: Ticket{Queue: cfg.Queue}, Route: graph.Stop()}
+		}), nil
+	}); err != nil {
+		t.Fatalf("RegisterNode failed: %v", err)
+	}
+	if err := reg.RegisterPredicate("is_billing", func(s Ticket) bool { return s.Category == "billing" }); err != nil {
+		t.Fatalf("RegisterPredicate failed: %v", err)
+	}
+	return reg
+}
+
+// TestRegistry verifies registrations are validated.
+func TestRegistry(t *testing.T) {
+	reg := newTicketRegistry(t)
+	noop := func(string, json.RawMessage) (graph.Node[Ticket], error) { return nil, nil }
+
+	if err := reg.RegisterNode("handoff", noop); err == nil {
+		t.Error("expected an error for a duplicate node type")
+	}
+	if err := reg.RegisterNode("", noop); err == nil {
+		t.Error("expected an error for an empty node type")
+	}
+	if err := reg.RegisterNode("nil", nil); err == nil {
+		t.Error("expected an error for a nil factory")
+	}
+	if err := reg.RegisterPredicate("is_billing", func(Ticket) bool { return true }); err == nil {
+		t.Error("expected an error for a duplicate predicate")
+	}
+	if err := reg.RegisterPredicate("nil", nil); err == nil {
+		t.Error("expected an error for a nil predicate")
+	}
+}
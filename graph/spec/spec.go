@@ -0,0 +1,230 @@
+// Package spec builds LangGraph-Go engines from declarative YAML or JSON
+// graph definitions.
+//
+// A Document lists nodes by registered type name, the edges between them,
+// named predicates, the start node and engine options. Node implementations
+// and predicates stay in Go and are registered in a typed Registry, so
+// routing and limits can change without recompiling:
+//
+//	start: classify
+//	options:
+//	  max_steps: 50
+//	  max_concurrent_nodes: 4
+//	  default_node_timeout: 30s
+//	nodes:
+//	  - id: classify
+//	    type: llm_classifier
+//	    config:
+//	      model: gpt-4o
+//	  - id: billing
+//	    type: handoff
+//	    config: {queue: billing}
+//	  - id: support
+//	    type: handoff
+//	    config: {queue: support}
+//	edges:
+//	  - from: classify
+//	    to: billing
+//	    when: is_billing
+//	  - from: classify
+//	    to: support
+//
+// Load the document with a registry:
+//
+//	reg := spec.NewRegistry[Ticket]()
+//	reg.RegisterNode("llm_classifier", newClassifier)
+//	reg.RegisterNode("handoff", newHandoff)
+//	reg.RegisterPredicate("is_billing", func(t Ticket) bool { return t.Category == "billing" })
+//
+//	engine, err := spec.LoadFile("workflow.yaml", reg, reducer, store, emitter)
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// ErrInvalidDocument is returned (wrapped) when a document cannot be parsed
+// or refers to unknown nodes, node types or predicates.
+var ErrInvalidDocument = errors.New("invalid graph document")
+
+// Document is a declarative graph definition.
+type Document struct {
+	// Start is the ID of the start node.
+	Start string `json:"start"`
+
+	// Options configures the engine.
+	Options OptionsSpec `json:"options,omitempty"`
+
+	// Nodes lists the graph's nodes.
+	Nodes []NodeSpec `json:"nodes"`
+
+	// Edges lists the transitions between nodes.
+	Edges []EdgeSpec `json:"edges,omitempty"`
+}
+
+// NodeSpec declares a node.
+type NodeSpec struct {
+	// ID is the unique node ID.
+	ID string `json:"id"`
+
+	// Type is the node type name registered with Registry.RegisterNode.
+	Type string `json:"type"`
+
+	// Config is passed to the node type's factory as JSON (see Decode).
+	Config json.RawMessage `json:"config,omitempty"`
+
+	// Join lists the predecessors the node waits for, making it a fan-in
+	// join node (see graph.Engine.Join).
+	Join []string `json:"join,omitempty"`
+}
+
+// EdgeSpec declares an edge.
+type EdgeSpec struct {
+	// From is the source node ID.
+	From string `json:"from"`
+
+	// To is the destination node ID.
+	To string `json:"to"`
+
+	// When names a predicate registered with Registry.RegisterPredicate.
+	// Empty for an unconditional edge.
+	When string `json:"when,omitempty"`
+
+	// Label describes the edge when rendering the graph. Defaults to When.
+	Label string `json:"label,omitempty"`
+}
+
+// OptionsSpec holds the engine options a document can set. Zero values keep
+// the engine defaults (see graph.Options).
+type OptionsSpec struct {
+	MaxSteps            int      `json:"max_steps,omitempty"`
+	MaxConcurrentNodes  int      `json:"max_concurrent_nodes,omitempty"`
+	QueueDepth          int      `json:"queue_depth,omitempty"`
+	BackpressureTimeout Duration `json:"backpressure_timeout,omitempty"`
+	DefaultNodeTimeout  Duration `json:"default_node_timeout,omitempty"`
+	RunWallClockBudget  Duration `json:"run_wall_clock_budget,omitempty"`
+}
+
+// Duration is a time.Duration written as a Go duration string such as "30s"
+// or "1m30s".
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Parse parses a YAML or JSON document.
+//
+// Documents that are valid JSON are decoded as JSON; anything else, including
+// YAML flow mappings such as "{start: a}", is decoded as YAML. Unknown fields
+// are rejected so typos in a document are caught at load time.
+func Parse(data []byte) (*Document, error) {
+	jsonData := bytes.TrimSpace(data)
+	if !json.Valid(jsonData) {
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+		converted, err := jsonValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+		if jsonData, err = json.Marshal(converted); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+
+	var doc Document
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return &doc, nil
+}
+
+// ParseFile reads and parses a YAML or JSON document.
+func ParseFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the caller
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Decode unmarshals a node's config into v, rejecting unknown fields. An
+// empty config leaves v unchanged, so factories can set defaults first.
+//
+// Example:
+//
+//	func newHandoff(id string, config json.RawMessage) (graph.Node[Ticket], error) {
+//	    cfg := struct{ Queue string `json:"queue"` }{Queue: "general"}
+//	    if err := spec.Decode(config, &cfg); err != nil {
+//	        return nil, err
+//	    }
+//	    return &HandoffNode{Queue: cfg.Queue}, nil
+//	}
+func Decode(config json.RawMessage, v interface{}) error {
+	if len(config) == 0 || string(config) == "null" {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// jsonValue converts a value decoded from YAML into one encoding/json can
+// marshal, turning map[interface{}]interface{} into map[string]interface{}.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key %v is not a string", k)
+			}
+			convertedItem, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[key] = convertedItem
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			convertedItem, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = convertedItem
+		}
+		return converted, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,82 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParse verifies YAML and JSON documents decode into the same Document.
+func TestParse(t *testing.T) {
+	t.Run("yaml file", func(t *testing.T) {
+		doc, err := ParseFile("testdata/ticket.yaml")
+		if err != nil {
+			t.Fatalf("ParseFile failed: %v", err)
+		}
+		if doc.Start != "classify" || len(doc.Nodes) != 3 || len(doc.Edges) != 2 {
+			t.Fatalf("unexpected document: %+v", doc)
+		}
+		if doc.Options.MaxSteps != 10 || time.Duration(doc.Options.DefaultNodeTimeout) != 5*time.Second {
+			t.Errorf("Options = %+v, want max_steps 10 and a 5s node timeout", doc.Options)
+		}
+		if string(doc.Nodes[1].Config) != `{"queue":"billing"}` {
+			t.Errorf("billing config = %s", doc.Nodes[1].Config)
+		}
+		if doc.Edges[0].When != "is_billing" {
+			t.Errorf("edge When = %q, want is_billing", doc.Edges[0].When)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		doc, err := Parse([]byte(`{"start": "a", "nodes": [{"id": "a", "type": "t", "join": ["b"]}]}`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if doc.Start != "a" || doc.Nodes[0].Join[0] != "b" {
+			t.Errorf("unexpected document: %+v", doc)
+		}
+	})
+
+	t.Run("yaml flow mapping", func(t *testing.T) {
+		doc, err := Parse([]byte(`{start: a, nodes: [{id: a, type: t}], options: {max_steps: 5}}`))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if doc.Start != "a" || len(doc.Nodes) != 1 || doc.Options.MaxSteps != 5 {
+			t.Errorf("unexpected document: %+v", doc)
+		}
+	})
+
+	t.Run("rejects unknown fields and bad durations", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"unknown field":  "start: a\nnodez: []\n",
+			"unknown json":   `{"start": "a", "nodez": []}`,
+			"bad duration":   "start: a\noptions:\n  default_node_timeout: soon\n",
+			"invalid yaml":   "start: [a\n",
+			"non-string key": "start: a\nnodes:\n  - id: a\n    type: t\n    config: {1: x}\n",
+		} {
+			if _, err := Parse([]byte(data)); !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("%s: expected ErrInvalidDocument, got %v", name, err)
+			}
+		}
+	})
+}
+
+// TestDecode verifies node configs decode strictly and keep defaults when empty.
+func TestDecode(t *testing.T) {
+	type handoffConfig struct {
+		Queue string `json:"queue"`
+	}
+
+	cfg := handoffConfig{Queue: "general"}
+	if err := Decode(nil, &cfg); err != nil || cfg.Queue != "general" {
+		t.Errorf("empty config: cfg = %+v, err = %v", cfg, err)
+	}
+	if err := Decode(json.RawMessage(`{"queue":"billing"}`), &cfg); err != nil || cfg.Queue != "billing" {
+		t.Errorf("cfg = %+v, err = %v", cfg, err)
+	}
+	if err := Decode(json.RawMessage(`{"queu":"billing"}`), &cfg); err == nil {
+		t.Error("expected an error for an unknown config field")
+	}
+}
@@ -3,9 +3,14 @@ package store_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -329,3 +334,579 @@ func TestStoreContractConsistency(t *testing.T) {
 		})
 	}
 }
+
+// historyTestStore is a Store that also answers history queries.
+type historyTestStore[S any] interface {
+	store.Store[S]
+	store.HistoryStore[S]
+}
+
+// TestHistoryStoreContract verifies that every HistoryStore lists runs, steps
+// and checkpoints the same way.
+func TestHistoryStoreContract(t *testing.T) {
+	type SimpleState struct {
+		Value int `json:"value"`
+	}
+
+	testScenarios := []struct {
+		name      string
+		storeFunc func(*testing.T) (historyTestStore[SimpleState], func())
+	}{
+		{
+			name: "MemStore",
+			storeFunc: func(_ *testing.T) (historyTestStore[SimpleState], func()) {
+				return store.NewMemStore[SimpleState](), func() {}
+			},
+		},
+		{
+			name: "SQLiteStore",
+			storeFunc: func(t *testing.T) (historyTestStore[SimpleState], func()) {
+				st, err := store.NewSQLiteStore[SimpleState](filepath.Join(t.TempDir(), "test.db"))
+				if err != nil {
+					t.Fatalf("Failed to create SQLiteStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+		{
+			name: "MySQLStore",
+			storeFunc: func(t *testing.T) (historyTestStore[SimpleState], func()) {
+				dsn := os.Getenv("TEST_MYSQL_DSN")
+				if dsn == "" {
+					t.Skip("Skipping MySQL test: TEST_MYSQL_DSN not set")
+				}
+				st, err := store.NewMySQLStore[SimpleState](dsn)
+				if err != nil {
+					t.Fatalf("Failed to create MySQLStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			ctx := context.Background()
+			st, cleanup := scenario.storeFunc(t)
+			defer cleanup()
+
+			// Run IDs are unique per test so shared databases can be reused
+			prefix := fmt.Sprintf("history-%d-", time.Now().UnixNano())
+			chat1, chat2, batch := prefix+"chat-1", prefix+"chat-2", prefix+"batch-1"
+			for step := 1; step <= 5; step++ {
+				if err := st.SaveStep(ctx, chat1, step, fmt.Sprintf("n%d", step), SimpleState{Value: step}); err != nil {
+					t.Fatalf("SaveStep failed: %v", err)
+				}
+			}
+			_ = st.SaveStep(ctx, chat2, 1, "n1", SimpleState{Value: 1})
+			for _, checkpoint := range []store.CheckpointV2[SimpleState]{
+				{RunID: batch, StepID: 3, State: SimpleState{Value: 3}, Label: "milestone"},
+				{RunID: batch, StepID: 0, State: SimpleState{Value: 0}},
+			} {
+				checkpoint.Frontier = []interface{}{}
+				checkpoint.RecordedIOs = []interface{}{}
+				checkpoint.IdempotencyKey = fmt.Sprintf("sha256:%s%d", batch, checkpoint.StepID)
+				checkpoint.Timestamp = time.Now()
+				if err := st.SaveCheckpointV2(ctx, checkpoint); err != nil {
+					t.Fatalf("SaveCheckpointV2 failed: %v", err)
+				}
+			}
+
+			t.Run("ListSteps pages through steps", func(t *testing.T) {
+				steps, err := st.ListSteps(ctx, chat1, store.Page{Offset: 1, Limit: 2})
+				if err != nil {
+					t.Fatalf("ListSteps failed: %v", err)
+				}
+				if len(steps) != 2 || steps[0].Step != 2 || steps[1].NodeID != "n3" || steps[1].State.Value != 3 {
+					t.Errorf("unexpected page: %+v", steps)
+				}
+				if steps, err := st.ListSteps(ctx, chat1, store.Page{Offset: 5}); err != nil || len(steps) != 0 {
+					t.Errorf("page past the last step = %+v, %v; want empty", steps, err)
+				}
+				if _, err := st.ListSteps(ctx, batch, store.Page{}); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound for a run without steps, got %v", err)
+				}
+			})
+
+			t.Run("GetStep returns the node and state of a step", func(t *testing.T) {
+				step, err := st.GetStep(ctx, chat1, 4)
+				if err != nil {
+					t.Fatalf("GetStep failed: %v", err)
+				}
+				if step.NodeID != "n4" || step.State.Value != 4 || step.CreatedAt.IsZero() {
+					t.Errorf("unexpected step: %+v", step)
+				}
+				if _, err := st.GetStep(ctx, chat1, 9); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+			})
+
+			t.Run("ListCheckpoints filters by label", func(t *testing.T) {
+				checkpoints, err := st.ListCheckpoints(ctx, batch, "")
+				if err != nil {
+					t.Fatalf("ListCheckpoints failed: %v", err)
+				}
+				if len(checkpoints) != 2 || checkpoints[0].StepID != 0 || checkpoints[1].StepID != 3 {
+					t.Errorf("expected checkpoints at steps 0 and 3, got %+v", checkpoints)
+				}
+				labeled, err := st.ListCheckpoints(ctx, batch, "milestone")
+				if err != nil || len(labeled) != 1 || labeled[0].State.Value != 3 {
+					t.Errorf("labeled checkpoints = %+v, %v; want step 3", labeled, err)
+				}
+				if none, err := st.ListCheckpoints(ctx, chat1, ""); err != nil || len(none) != 0 {
+					t.Errorf("checkpoints of a run without any = %+v, %v; want empty", none, err)
+				}
+			})
+
+			t.Run("ListRuns summarizes matching runs", func(t *testing.T) {
+				runs, err := st.ListRuns(ctx, store.RunFilter{Prefix: prefix})
+				if err != nil {
+					t.Fatalf("ListRuns failed: %v", err)
+				}
+				if len(runs) != 3 {
+					t.Fatalf("expected 3 runs, got %+v", runs)
+				}
+				summaries := make(map[string]store.RunSummary)
+				for _, run := range runs {
+					summaries[run.RunID] = run
+				}
+				if run := summaries[chat1]; run.Steps != 5 || run.Checkpoints != 0 || run.LastStep != 5 || run.StartedAt.IsZero() || run.UpdatedAt.Before(run.StartedAt) {
+					t.Errorf("unexpected summary of %s: %+v", chat1, run)
+				}
+				if run := summaries[batch]; run.Steps != 0 || run.Checkpoints != 2 || run.LastStep != 3 {
+					t.Errorf("unexpected summary of %s: %+v", batch, run)
+				}
+
+				page, err := st.ListRuns(ctx, store.RunFilter{Prefix: prefix, Page: store.Page{Offset: 1, Limit: 1}})
+				if err != nil || len(page) != 1 || page[0].RunID != runs[1].RunID {
+					t.Errorf("second page = %+v, %v; want %s", page, err, runs[1].RunID)
+				}
+				chats, err := st.ListRuns(ctx, store.RunFilter{Prefix: prefix + "chat-"})
+				if err != nil || len(chats) != 2 {
+					t.Errorf("chat runs = %+v, %v; want 2", chats, err)
+				}
+				if upper, _ := st.ListRuns(ctx, store.RunFilter{Prefix: prefix + "CHAT-"}); len(upper) != 0 {
+					t.Errorf("prefix matched case-insensitively: %+v", upper)
+				}
+
+				hourAgo := time.Now().Add(-time.Hour)
+				if recent, _ := st.ListRuns(ctx, store.RunFilter{Prefix: prefix, UpdatedAfter: hourAgo}); len(recent) != 3 {
+					t.Errorf("runs updated in the last hour = %+v, want 3", recent)
+				}
+				if old, _ := st.ListRuns(ctx, store.RunFilter{Prefix: prefix, UpdatedBefore: hourAgo}); len(old) != 0 {
+					t.Errorf("runs updated over an hour ago = %+v, want none", old)
+				}
+			})
+		})
+	}
+}
+
+// runTrackerTestStore is a Store that also keeps run status records.
+type runTrackerTestStore interface {
+	store.Store[int]
+	store.RunTracker
+}
+
+// TestRunTrackerContract verifies that every RunTracker records run status
+// and hands out expired leases the same way.
+func TestRunTrackerContract(t *testing.T) {
+	testScenarios := []struct {
+		name      string
+		storeFunc func(*testing.T) (runTrackerTestStore, func())
+	}{
+		{
+			name: "MemStore",
+			storeFunc: func(_ *testing.T) (runTrackerTestStore, func()) {
+				return store.NewMemStore[int](), func() {}
+			},
+		},
+		{
+			name: "SQLiteStore",
+			storeFunc: func(t *testing.T) (runTrackerTestStore, func()) {
+				st, err := store.NewSQLiteStore[int](filepath.Join(t.TempDir(), "test.db"))
+				if err != nil {
+					t.Fatalf("Failed to create SQLiteStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+		{
+			name: "MySQLStore",
+			storeFunc: func(t *testing.T) (runTrackerTestStore, func()) {
+				dsn := os.Getenv("TEST_MYSQL_DSN")
+				if dsn == "" {
+					t.Skip("Skipping MySQL test: TEST_MYSQL_DSN not set")
+				}
+				st, err := store.NewMySQLStore[int](dsn)
+				if err != nil {
+					t.Fatalf("Failed to create MySQLStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			ctx := context.Background()
+			st, cleanup := scenario.storeFunc(t)
+			defer cleanup()
+
+			// Run IDs are unique per test so shared databases can be reused
+			prefix := fmt.Sprintf("lease-%d-", time.Now().UnixNano())
+			live, crashedA, crashedB := prefix+"live", prefix+"crashed-a", prefix+"crashed-b"
+			now := time.Now()
+			if err := st.StartRun(ctx, live, "worker-1", now.Add(time.Hour)); err != nil {
+				t.Fatalf("StartRun failed: %v", err)
+			}
+			_ = st.StartRun(ctx, crashedA, "worker-1", now.Add(-time.Second))
+			_ = st.StartRun(ctx, crashedB, "worker-1", now.Add(-time.Minute))
+
+			t.Run("LoadRun returns the record", func(t *testing.T) {
+				record, err := st.LoadRun(ctx, live)
+				if err != nil {
+					t.Fatalf("LoadRun failed: %v", err)
+				}
+				if record.Status != store.RunRunning || record.Owner != "worker-1" || !record.LeaseExpiresAt.After(now) || record.StartedAt.IsZero() {
+					t.Errorf("unexpected record: %+v", record)
+				}
+				if _, err := st.LoadRun(ctx, prefix+"missing"); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+			})
+
+			t.Run("only the owner renews a lease", func(t *testing.T) {
+				if err := st.RenewLease(ctx, live, "worker-2", now.Add(2*time.Hour)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for another owner, got %v", err)
+				}
+				if err := st.RenewLease(ctx, live, "worker-1", now.Add(2*time.Hour)); err != nil {
+					t.Errorf("RenewLease failed: %v", err)
+				}
+			})
+
+			t.Run("expired runs are claimed once", func(t *testing.T) {
+				first, err := st.ClaimExpiredRuns(ctx, "worker-2", now.Add(time.Hour), 1)
+				if err != nil {
+					t.Fatalf("ClaimExpiredRuns failed: %v", err)
+				}
+				if len(first) != 1 {
+					t.Fatalf("claimed %d runs with limit 1", len(first))
+				}
+				rest, err := st.ClaimExpiredRuns(ctx, "worker-2", now.Add(time.Hour), 0)
+				if err != nil {
+					t.Fatalf("ClaimExpiredRuns failed: %v", err)
+				}
+
+				claimed := make(map[string]store.RunRecord)
+				for _, record := range append(first, rest...) {
+					if strings.HasPrefix(record.RunID, prefix) {
+						claimed[record.RunID] = record
+					}
+				}
+				if len(claimed) != 2 || claimed[crashedA].Owner != "worker-2" || claimed[crashedB].Owner != "worker-2" {
+					t.Errorf("claimed %+v, want both crashed runs", claimed)
+				}
+				if again, _ := st.ClaimExpiredRuns(ctx, "worker-3", now.Add(time.Hour), 0); len(again) != 0 {
+					t.Errorf("claimed %+v again", again)
+				}
+				if err := st.RenewLease(ctx, crashedA, "worker-1", now.Add(time.Hour)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for the previous owner, got %v", err)
+				}
+			})
+
+			t.Run("FinishRun records the outcome", func(t *testing.T) {
+				if err := st.FinishRun(ctx, crashedA, "worker-1", store.RunCompleted, ""); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for the previous owner, got %v", err)
+				}
+				if err := st.FinishRun(ctx, crashedA, "worker-2", store.RunFailed, "boom"); err != nil {
+					t.Fatalf("FinishRun failed: %v", err)
+				}
+				if err := st.FinishRun(ctx, crashedA, "worker-2", store.RunFailed, "boom"); err != nil {
+					t.Errorf("FinishRun again failed: %v", err)
+				}
+				record, err := st.LoadRun(ctx, crashedA)
+				if err != nil || record.Status != store.RunFailed || record.Error != "boom" || !record.LeaseExpiresAt.IsZero() {
+					t.Errorf("LoadRun = %+v, %v; want a failed run without a lease", record, err)
+				}
+				if err := st.RenewLease(ctx, crashedA, "worker-2", now.Add(time.Hour)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for a finished run, got %v", err)
+				}
+				if err := st.FinishRun(ctx, prefix+"missing", "worker-2", store.RunCompleted, ""); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+
+				// A resumed run is running again but keeps its start time
+				if err := st.StartRun(ctx, crashedA, "worker-3", now.Add(time.Hour)); err != nil {
+					t.Fatalf("StartRun failed: %v", err)
+				}
+				restarted, _ := st.LoadRun(ctx, crashedA)
+				if restarted.Status != store.RunRunning || restarted.Error != "" || !restarted.StartedAt.Equal(record.StartedAt) {
+					t.Errorf("restarted record = %+v, want running since %v", restarted, record.StartedAt)
+				}
+			})
+
+			t.Run("ListRunRecords filters by status", func(t *testing.T) {
+				_ = st.FinishRun(ctx, crashedB, "worker-2", store.RunCompleted, "")
+				ours := func(status store.RunState) []string {
+					records, err := st.ListRunRecords(ctx, status)
+					if err != nil {
+						t.Fatalf("ListRunRecords failed: %v", err)
+					}
+					var runIDs []string
+					for _, record := range records {
+						if strings.HasPrefix(record.RunID, prefix) {
+							runIDs = append(runIDs, record.RunID)
+						}
+					}
+					sort.Strings(runIDs)
+					return runIDs
+				}
+				if got := ours(store.RunCompleted); !reflect.DeepEqual(got, []string{crashedB}) {
+					t.Errorf("completed runs = %v", got)
+				}
+				if got := ours(store.RunRunning); !reflect.DeepEqual(got, []string{crashedA, live}) {
+					t.Errorf("running runs = %v", got)
+				}
+				if got := ours(""); len(got) != 3 {
+					t.Errorf("all runs = %v", got)
+				}
+			})
+		})
+	}
+}
+
+// workQueueTestStore is a Store that also queues work items.
+type workQueueTestStore interface {
+	store.Store[int]
+	store.WorkQueue
+}
+
+// TestWorkQueueContract verifies that every WorkQueue leases, renews and
+// completes work items the same way.
+func TestWorkQueueContract(t *testing.T) {
+	testScenarios := []struct {
+		name      string
+		storeFunc func(*testing.T) (workQueueTestStore, func())
+	}{
+		{
+			name: "MemStore",
+			storeFunc: func(_ *testing.T) (workQueueTestStore, func()) {
+				return store.NewMemStore[int](), func() {}
+			},
+		},
+		{
+			name: "SQLiteStore",
+			storeFunc: func(t *testing.T) (workQueueTestStore, func()) {
+				st, err := store.NewSQLiteStore[int](filepath.Join(t.TempDir(), "test.db"))
+				if err != nil {
+					t.Fatalf("Failed to create SQLiteStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+		{
+			name: "MySQLStore",
+			storeFunc: func(t *testing.T) (workQueueTestStore, func()) {
+				dsn := os.Getenv("TEST_MYSQL_DSN")
+				if dsn == "" {
+					t.Skip("Skipping MySQL test: TEST_MYSQL_DSN not set")
+				}
+				st, err := store.NewMySQLStore[int](dsn)
+				if err != nil {
+					t.Fatalf("Failed to create MySQLStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			ctx := context.Background()
+			st, cleanup := scenario.storeFunc(t)
+			defer cleanup()
+
+			// Run IDs are unique per test so shared databases can be reused
+			runID := fmt.Sprintf("work-%d", time.Now().UnixNano())
+			defer func() { _ = st.DeleteWork(ctx, runID) }()
+			first, second := runID+":first", runID+":second"
+			for _, id := range []string{first, second, first} {
+				err := st.PushWork(ctx, store.WorkRecord{ID: id, RunID: runID, NodeID: "n", Item: []byte(`{"node_id":"n"}`)})
+				if err != nil {
+					t.Fatalf("PushWork failed: %v", err)
+				}
+			}
+
+			// lease takes the next item of this test's run, skipping items
+			// other tests left in a shared database
+			lease := func(owner string, until time.Time) (store.WorkRecord, error) {
+				for {
+					work, err := st.LeaseWork(ctx, owner, until)
+					if err != nil || work.RunID == runID {
+						return work, err
+					}
+				}
+			}
+
+			t.Run("items are leased once in push order", func(t *testing.T) {
+				work, err := lease("worker-1", time.Now().Add(time.Hour))
+				if err != nil {
+					t.Fatalf("LeaseWork failed: %v", err)
+				}
+				if work.ID != first || work.Status != store.WorkLeased || work.Owner != "worker-1" || string(work.Item) != `{"node_id":"n"}` {
+					t.Errorf("leased %+v, want the first item", work)
+				}
+				// An item whose lease expired can be leased again
+				if work, err := lease("worker-2", time.Now().Add(-time.Second)); err != nil || work.ID != second {
+					t.Fatalf("LeaseWork = %+v, %v; want the second item", work, err)
+				}
+				work, err = lease("worker-3", time.Now().Add(time.Hour))
+				if err != nil || work.ID != second || work.Owner != "worker-3" {
+					t.Fatalf("LeaseWork = %+v, %v; want the expired second item", work, err)
+				}
+				if _, err := lease("worker-4", time.Now().Add(time.Hour)); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+			})
+
+			t.Run("only the owner renews and completes", func(t *testing.T) {
+				if err := st.RenewWork(ctx, second, "worker-2", time.Now().Add(time.Hour)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for the previous owner, got %v", err)
+				}
+				if err := st.RenewWork(ctx, second, "worker-3", time.Now().Add(2*time.Hour)); err != nil {
+					t.Errorf("RenewWork failed: %v", err)
+				}
+				if err := st.CompleteWork(ctx, second, "worker-2", []byte(`"stale"`)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for the previous owner, got %v", err)
+				}
+				if err := st.CompleteWork(ctx, second, "worker-3", []byte(`{"ok":true}`)); err != nil {
+					t.Fatalf("CompleteWork failed: %v", err)
+				}
+				if err := st.CompleteWork(ctx, second, "worker-3", []byte(`{"ok":false}`)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost completing twice, got %v", err)
+				}
+
+				work, err := st.LoadWork(ctx, second)
+				if err != nil || work.Status != store.WorkDone || work.RunID != runID || work.CreatedAt.IsZero() {
+					t.Fatalf("LoadWork = %+v, %v; want a done item", work, err)
+				}
+				var result map[string]bool
+				if err := json.Unmarshal(work.Result, &result); err != nil || !result["ok"] {
+					t.Errorf("result = %s, %v; want the first committed result", work.Result, err)
+				}
+				// Pushing a done item again keeps its result
+				_ = st.PushWork(ctx, store.WorkRecord{ID: second, RunID: runID, NodeID: "n", Item: []byte(`{}`)})
+				if work, _ := st.LoadWork(ctx, second); work.Status != store.WorkDone {
+					t.Errorf("re-pushed item = %+v, want it still done", work)
+				}
+			})
+
+			t.Run("DeleteWork removes the run's items", func(t *testing.T) {
+				if err := st.DeleteWork(ctx, runID); err != nil {
+					t.Fatalf("DeleteWork failed: %v", err)
+				}
+				if _, err := st.LoadWork(ctx, first); !errors.Is(err, store.ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+				if err := st.CompleteWork(ctx, first, "worker-1", []byte(`{}`)); !errors.Is(err, store.ErrLeaseLost) {
+					t.Errorf("expected ErrLeaseLost for a deleted item, got %v", err)
+				}
+			})
+		})
+	}
+}
+
+// TestResultCacheContract verifies that every ResultCache caches, replaces and
+// expires values the same way.
+func TestResultCacheContract(t *testing.T) {
+	testScenarios := []struct {
+		name      string
+		storeFunc func(*testing.T) (store.ResultCache, func())
+	}{
+		{
+			name: "MemStore",
+			storeFunc: func(_ *testing.T) (store.ResultCache, func()) {
+				return store.NewMemStore[int](), func() {}
+			},
+		},
+		{
+			name: "SQLiteStore",
+			storeFunc: func(t *testing.T) (store.ResultCache, func()) {
+				st, err := store.NewSQLiteStore[int](filepath.Join(t.TempDir(), "test.db"))
+				if err != nil {
+					t.Fatalf("Failed to create SQLiteStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+		{
+			name: "MySQLStore",
+			storeFunc: func(t *testing.T) (store.ResultCache, func()) {
+				dsn := os.Getenv("TEST_MYSQL_DSN")
+				if dsn == "" {
+					t.Skip("Skipping MySQL test: TEST_MYSQL_DSN not set")
+				}
+				st, err := store.NewMySQLStore[int](dsn)
+				if err != nil {
+					t.Fatalf("Failed to create MySQLStore: %v", err)
+				}
+				return st, func() {
+					_ = st.Close()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, cleanup := scenario.storeFunc(t)
+			defer cleanup()
+
+			// Keys are unique per test so shared databases can be reused
+			prefix := fmt.Sprintf("cache-%d:", time.Now().UnixNano())
+			key, expiring := prefix+"key", prefix+"expiring"
+
+			if _, err := cache.LoadCachedResult(ctx, key); !errors.Is(err, store.ErrNotFound) {
+				t.Fatalf("LoadCachedResult of a missing key = %v, want ErrNotFound", err)
+			}
+
+			// A zero expiry never expires, and saving again replaces the value
+			for _, value := range []string{`{"v":1}`, `{"v":2}`} {
+				if err := cache.SaveCachedResult(ctx, key, []byte(value), time.Time{}); err != nil {
+					t.Fatalf("SaveCachedResult failed: %v", err)
+				}
+			}
+			if value, err := cache.LoadCachedResult(ctx, key); err != nil || string(value) != `{"v":2}` {
+				t.Errorf("LoadCachedResult = %s, %v; want the latest value", value, err)
+			}
+
+			if err := cache.SaveCachedResult(ctx, expiring, []byte(`{}`), time.Now().Add(-time.Second)); err != nil {
+				t.Fatalf("SaveCachedResult failed: %v", err)
+			}
+			if _, err := cache.LoadCachedResult(ctx, expiring); !errors.Is(err, store.ErrNotFound) {
+				t.Errorf("LoadCachedResult of an expired value = %v, want ErrNotFound", err)
+			}
+			if err := cache.SaveCachedResult(ctx, expiring, []byte(`{}`), time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("SaveCachedResult failed: %v", err)
+			}
+			if value, err := cache.LoadCachedResult(ctx, expiring); err != nil || string(value) != `{}` {
+				t.Errorf("LoadCachedResult = %s, %v; want the value saved again", value, err)
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/dshills/langgraph-go/graph/emit"
 )
@@ -34,8 +38,20 @@ type MemStore[S any] struct {
 	checkpointsV2  map[string]CheckpointV2[S] // "runID:stepID" -> checkpoint
 	labelIndex     map[string]string          // label -> "runID:stepID"
 	idempotencyMap map[string]bool            // idempotency key -> exists
+	idempotencyAt  map[string]time.Time       // idempotency key -> when it was used
 	pendingEvents  []emit.Event               // pending events queue
 	eventIDSet     map[string]int             // eventID -> index in pendingEvents
+	forks          map[string]ForkRecord      // forked runID -> lineage
+	runs           map[string]RunRecord       // runID -> status record
+	work           map[string]WorkRecord      // work item ID -> queued work item
+	workOrder      []string                   // work item IDs in push order
+	results        map[string]cachedResult    // cache key -> cached node result
+}
+
+// cachedResult is a value held by MemStore's ResultCache.
+type cachedResult struct {
+	value     []byte
+	expiresAt time.Time
 }
 
 // NewMemStore creates a new in-memory store.
@@ -51,8 +67,13 @@ func NewMemStore[S any]() *MemStore[S] {
 		checkpointsV2:  make(map[string]CheckpointV2[S]),
 		labelIndex:     make(map[string]string),
 		idempotencyMap: make(map[string]bool),
+		idempotencyAt:  make(map[string]time.Time),
 		pendingEvents:  make([]emit.Event, 0),
 		eventIDSet:     make(map[string]int),
+		forks:          make(map[string]ForkRecord),
+		runs:           make(map[string]RunRecord),
+		work:           make(map[string]WorkRecord),
+		results:        make(map[string]cachedResult),
 	}
 }
 
@@ -65,9 +86,10 @@ func (m *MemStore[S]) SaveStep(_ context.Context, runID string, step int, nodeID
 	defer m.mu.Unlock()
 
 	record := StepRecord[S]{
-		Step:   step,
-		NodeID: nodeID,
-		State:  state,
+		Step:      step,
+		NodeID:    nodeID,
+		State:     state,
+		CreatedAt: time.Now(),
 	}
 
 	m.steps[runID] = append(m.steps[runID], record)
@@ -99,6 +121,110 @@ func (m *MemStore[S]) LoadLatest(_ context.Context, runID string) (state S, step
 	return latest.State, latest.Step, nil
 }
 
+// ListSteps returns a page of the steps saved for a run, ordered by step
+// number (implements StepLister).
+func (m *MemStore[S]) ListSteps(_ context.Context, runID string, page Page) ([]StepRecord[S], error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records, exists := m.steps[runID]
+	if !exists || len(records) == 0 {
+		return nil, ErrNotFound
+	}
+
+	steps := append([]StepRecord[S](nil), records...)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Step < steps[j].Step
+	})
+	start, end := page.bounds(len(steps))
+	return steps[start:end], nil
+}
+
+// GetStep returns the step saved for a run at the given step number
+// (implements HistoryStore). If the step was saved more than once, the
+// latest save is returned.
+func (m *MemStore[S]) GetStep(_ context.Context, runID string, step int) (StepRecord[S], error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := m.steps[runID]
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Step == step {
+			return records[i], nil
+		}
+	}
+	return StepRecord[S]{}, ErrNotFound
+}
+
+// ListRuns returns the runs matching filter, most recently updated first
+// (implements HistoryStore).
+func (m *MemStore[S]) ListRuns(_ context.Context, filter RunFilter) ([]RunSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runs := make(map[string]*RunSummary)
+	summary := func(runID string, step int, saved time.Time) *RunSummary {
+		run, exists := runs[runID]
+		if !exists {
+			run = &RunSummary{RunID: runID, LastStep: step, StartedAt: saved, UpdatedAt: saved}
+			runs[runID] = run
+		}
+		run.LastStep = max(run.LastStep, step)
+		if saved.Before(run.StartedAt) {
+			run.StartedAt = saved
+		}
+		if saved.After(run.UpdatedAt) {
+			run.UpdatedAt = saved
+		}
+		return run
+	}
+	for runID, records := range m.steps {
+		for _, record := range records {
+			summary(runID, record.Step, record.CreatedAt).Steps++
+		}
+	}
+	for _, checkpoint := range m.checkpointsV2 {
+		summary(checkpoint.RunID, checkpoint.StepID, checkpoint.Timestamp).Checkpoints++
+	}
+
+	matches := make([]RunSummary, 0, len(runs))
+	for _, run := range runs {
+		if !strings.HasPrefix(run.RunID, filter.Prefix) ||
+			(!filter.UpdatedAfter.IsZero() && run.UpdatedAt.Before(filter.UpdatedAfter)) ||
+			(!filter.UpdatedBefore.IsZero() && !run.UpdatedAt.Before(filter.UpdatedBefore)) {
+			continue
+		}
+		matches = append(matches, *run)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].UpdatedAt.Equal(matches[j].UpdatedAt) {
+			return matches[i].UpdatedAt.After(matches[j].UpdatedAt)
+		}
+		return matches[i].RunID < matches[j].RunID
+	})
+
+	start, end := filter.Page.bounds(len(matches))
+	return matches[start:end], nil
+}
+
+// ListCheckpoints returns the CheckpointV2 saved for a run, optionally only
+// those with label, ordered by step ID (implements HistoryStore).
+func (m *MemStore[S]) ListCheckpoints(_ context.Context, runID string, label string) ([]CheckpointV2[S], error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	checkpoints := make([]CheckpointV2[S], 0)
+	for _, checkpoint := range m.checkpointsV2 {
+		if checkpoint.RunID == runID && (label == "" || checkpoint.Label == label) {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].StepID < checkpoints[j].StepID
+	})
+	return checkpoints, nil
+}
+
 // SaveCheckpoint creates a named checkpoint (T040).
 //
 // Checkpoints can be used to:
@@ -136,6 +262,21 @@ func (m *MemStore[S]) LoadCheckpoint(_ context.Context, cpID string) (state S, s
 	return cp.State, cp.Step, nil
 }
 
+// ClaimCheckpoint changes the step of a named checkpoint from step to claimed
+// if it is at step (implements CheckpointClaimer).
+func (m *MemStore[S]) ClaimCheckpoint(_ context.Context, cpID string, step, claimed int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cp, exists := m.checkpoints[cpID]
+	if !exists || cp.Step != step {
+		return false, nil
+	}
+	cp.Step = claimed
+	m.checkpoints[cpID] = cp
+	return true, nil
+}
+
 // serializableMemStore is the JSON-serializable representation of MemStore.
 //
 // Used for persisting MemStore contents to disk or transmitting over network.
@@ -146,7 +287,11 @@ type serializableMemStore[S any] struct {
 	CheckpointsV2  map[string]CheckpointV2[S] `json:"checkpoints_v2"`
 	LabelIndex     map[string]string          `json:"label_index"`
 	IdempotencyMap map[string]bool            `json:"idempotency_map"`
+	IdempotencyAt  map[string]time.Time       `json:"idempotency_at,omitempty"`
 	PendingEvents  []emit.Event               `json:"pending_events"`
+	Forks          map[string]ForkRecord      `json:"forks,omitempty"`
+	Runs           map[string]RunRecord       `json:"runs,omitempty"`
+	Work           []WorkRecord               `json:"work,omitempty"`
 }
 
 // MarshalJSON serializes the MemStore to JSON (T072).
@@ -176,7 +321,13 @@ func (m *MemStore[S]) MarshalJSON() ([]byte, error) {
 		CheckpointsV2:  m.checkpointsV2,
 		LabelIndex:     m.labelIndex,
 		IdempotencyMap: m.idempotencyMap,
+		IdempotencyAt:  m.idempotencyAt,
 		PendingEvents:  m.pendingEvents,
+		Forks:          m.forks,
+		Runs:           m.runs,
+	}
+	for _, id := range m.workOrder {
+		s.Work = append(s.Work, m.work[id])
 	}
 
 	return json.Marshal(s)
@@ -213,7 +364,16 @@ func (m *MemStore[S]) UnmarshalJSON(data []byte) error {
 	m.checkpointsV2 = s.CheckpointsV2
 	m.labelIndex = s.LabelIndex
 	m.idempotencyMap = s.IdempotencyMap
+	m.idempotencyAt = s.IdempotencyAt
 	m.pendingEvents = s.PendingEvents
+	m.forks = s.Forks
+	m.runs = s.Runs
+	m.work = make(map[string]WorkRecord, len(s.Work))
+	m.workOrder = nil
+	for _, work := range s.Work {
+		m.work[work.ID] = work
+		m.workOrder = append(m.workOrder, work.ID)
+	}
 
 	// Initialize empty maps if nil (for empty JSON objects)
 	if m.steps == nil {
@@ -231,9 +391,18 @@ func (m *MemStore[S]) UnmarshalJSON(data []byte) error {
 	if m.idempotencyMap == nil {
 		m.idempotencyMap = make(map[string]bool)
 	}
+	if m.idempotencyAt == nil {
+		m.idempotencyAt = make(map[string]time.Time)
+	}
 	if m.pendingEvents == nil {
 		m.pendingEvents = make([]emit.Event, 0)
 	}
+	if m.forks == nil {
+		m.forks = make(map[string]ForkRecord)
+	}
+	if m.runs == nil {
+		m.runs = make(map[string]RunRecord)
+	}
 
 	// Rebuild eventIDSet from pendingEvents
 	m.eventIDSet = make(map[string]int)
@@ -248,6 +417,289 @@ func (m *MemStore[S]) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SaveFork records the lineage of a forked run (implements ForkStore).
+func (m *MemStore[S]) SaveFork(_ context.Context, fork ForkRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.forks[fork.RunID] = fork
+	return nil
+}
+
+// LoadFork returns the lineage of a forked run (implements ForkStore).
+func (m *MemStore[S]) LoadFork(_ context.Context, runID string) (ForkRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fork, exists := m.forks[runID]
+	if !exists {
+		return ForkRecord{}, ErrNotFound
+	}
+	return fork, nil
+}
+
+// ListForks returns the runs forked from parentRunID (implements ForkStore).
+func (m *MemStore[S]) ListForks(_ context.Context, parentRunID string) ([]ForkRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	forks := make([]ForkRecord, 0)
+	for _, fork := range m.forks {
+		if fork.ParentRunID == parentRunID {
+			forks = append(forks, fork)
+		}
+	}
+	sort.Slice(forks, func(i, j int) bool {
+		if forks[i].ParentStep != forks[j].ParentStep {
+			return forks[i].ParentStep < forks[j].ParentStep
+		}
+		if !forks[i].CreatedAt.Equal(forks[j].CreatedAt) {
+			return forks[i].CreatedAt.Before(forks[j].CreatedAt)
+		}
+		return forks[i].RunID < forks[j].RunID
+	})
+	return forks, nil
+}
+
+// StartRun records that owner is executing a run (implements RunTracker).
+func (m *MemStore[S]) StartRun(_ context.Context, runID, owner string, leaseUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	record, exists := m.runs[runID]
+	if !exists {
+		record = RunRecord{RunID: runID, StartedAt: now}
+	}
+	record.Status = RunRunning
+	record.Owner = owner
+	record.LeaseExpiresAt = leaseUntil
+	record.Error = ""
+	record.UpdatedAt = now
+	m.runs[runID] = record
+	return nil
+}
+
+// RenewLease extends owner's lease on a running run (implements RunTracker).
+func (m *MemStore[S]) RenewLease(_ context.Context, runID, owner string, leaseUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, exists := m.runs[runID]
+	if !exists || record.Status != RunRunning || record.Owner != owner {
+		return ErrLeaseLost
+	}
+	record.LeaseExpiresAt = leaseUntil
+	record.UpdatedAt = time.Now()
+	m.runs[runID] = record
+	return nil
+}
+
+// FinishRun records the final status of a run (implements RunTracker).
+func (m *MemStore[S]) FinishRun(_ context.Context, runID, owner string, status RunState, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, exists := m.runs[runID]
+	if !exists {
+		return ErrNotFound
+	}
+	if record.Owner != owner {
+		return ErrLeaseLost
+	}
+	record.Status = status
+	record.LeaseExpiresAt = time.Time{}
+	record.Error = errMsg
+	record.UpdatedAt = time.Now()
+	m.runs[runID] = record
+	return nil
+}
+
+// ClaimExpiredRuns transfers abandoned runs to owner (implements RunTracker).
+// Runs are claimed in the order their leases expired.
+func (m *MemStore[S]) ClaimExpiredRuns(_ context.Context, owner string, leaseUntil time.Time, limit int) ([]RunRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	claimed := make([]RunRecord, 0)
+	for _, record := range m.runs {
+		if record.Status == RunRunning && record.LeaseExpiresAt.Before(now) {
+			claimed = append(claimed, record)
+		}
+	}
+	sort.Slice(claimed, func(i, j int) bool {
+		if !claimed[i].LeaseExpiresAt.Equal(claimed[j].LeaseExpiresAt) {
+			return claimed[i].LeaseExpiresAt.Before(claimed[j].LeaseExpiresAt)
+		}
+		return claimed[i].RunID < claimed[j].RunID
+	})
+	if limit > 0 && len(claimed) > limit {
+		claimed = claimed[:limit]
+	}
+	for i := range claimed {
+		claimed[i].Owner = owner
+		claimed[i].LeaseExpiresAt = leaseUntil
+		claimed[i].UpdatedAt = now
+		m.runs[claimed[i].RunID] = claimed[i]
+	}
+	return claimed, nil
+}
+
+// LoadRun returns the status record of a run (implements RunTracker).
+func (m *MemStore[S]) LoadRun(_ context.Context, runID string) (RunRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	record, exists := m.runs[runID]
+	if !exists {
+		return RunRecord{}, ErrNotFound
+	}
+	return record, nil
+}
+
+// ListRunRecords returns the status records of runs (implements RunTracker).
+func (m *MemStore[S]) ListRunRecords(_ context.Context, status RunState) ([]RunRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]RunRecord, 0)
+	for _, record := range m.runs {
+		if status == "" || record.Status == status {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].StartedAt.Equal(records[j].StartedAt) {
+			return records[i].StartedAt.Before(records[j].StartedAt)
+		}
+		return records[i].RunID < records[j].RunID
+	})
+	return records, nil
+}
+
+// PushWork queues a work item (implements WorkQueue).
+func (m *MemStore[S]) PushWork(_ context.Context, work WorkRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.work[work.ID]; exists {
+		return nil
+	}
+	work.Status = WorkPending
+	work.Owner = ""
+	work.LeaseExpiresAt = time.Time{}
+	work.Result = nil
+	work.CreatedAt = time.Now()
+	m.work[work.ID] = work
+	m.workOrder = append(m.workOrder, work.ID)
+	return nil
+}
+
+// LeaseWork hands owner the next available work item (implements WorkQueue).
+func (m *MemStore[S]) LeaseWork(_ context.Context, owner string, leaseUntil time.Time) (WorkRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range m.workOrder {
+		work := m.work[id]
+		if work.Status == WorkPending || (work.Status == WorkLeased && work.LeaseExpiresAt.Before(now)) {
+			work.Status = WorkLeased
+			work.Owner = owner
+			work.LeaseExpiresAt = leaseUntil
+			m.work[id] = work
+			return work, nil
+		}
+	}
+	return WorkRecord{}, ErrNotFound
+}
+
+// RenewWork extends owner's lease on a work item (implements WorkQueue).
+func (m *MemStore[S]) RenewWork(_ context.Context, id, owner string, leaseUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	work, exists := m.work[id]
+	if !exists || work.Status != WorkLeased || work.Owner != owner {
+		return ErrLeaseLost
+	}
+	work.LeaseExpiresAt = leaseUntil
+	m.work[id] = work
+	return nil
+}
+
+// CompleteWork commits the result of a work item (implements WorkQueue).
+func (m *MemStore[S]) CompleteWork(_ context.Context, id, owner string, result []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	work, exists := m.work[id]
+	if !exists || work.Status != WorkLeased || work.Owner != owner {
+		return ErrLeaseLost
+	}
+	work.Status = WorkDone
+	work.LeaseExpiresAt = time.Time{}
+	work.Result = result
+	m.work[id] = work
+	return nil
+}
+
+// LoadWork returns a queued work item (implements WorkQueue).
+func (m *MemStore[S]) LoadWork(_ context.Context, id string) (WorkRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	work, exists := m.work[id]
+	if !exists {
+		return WorkRecord{}, ErrNotFound
+	}
+	return work, nil
+}
+
+// DeleteWork removes the work items of a run (implements WorkQueue).
+func (m *MemStore[S]) DeleteWork(_ context.Context, runID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.workOrder[:0]
+	for _, id := range m.workOrder {
+		if m.work[id].RunID == runID {
+			delete(m.work, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.workOrder = kept
+	return nil
+}
+
+// LoadCachedResult returns a cached node result (implements ResultCache).
+func (m *MemStore[S]) LoadCachedResult(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, exists := m.results[key]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if !cached.expiresAt.IsZero() && !cached.expiresAt.After(time.Now()) {
+		delete(m.results, key)
+		return nil, ErrNotFound
+	}
+	return cached.value, nil
+}
+
+// SaveCachedResult caches a node result (implements ResultCache). Cached
+// results are not included in MarshalJSON.
+func (m *MemStore[S]) SaveCachedResult(_ context.Context, key string, value []byte, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results[key] = cachedResult{value: value, expiresAt: expiresAt}
+	return nil
+}
+
 // SaveCheckpointV2 persists an enhanced checkpoint with full execution context (T094).
 //
 // Stores checkpoint indexed by (runID, stepID) and optionally by label if provided.
@@ -265,6 +717,7 @@ func (m *MemStore[S]) SaveCheckpointV2(_ context.Context, checkpoint CheckpointV
 		}
 		// Mark idempotency key as used
 		m.idempotencyMap[checkpoint.IdempotencyKey] = true
+		m.idempotencyAt[checkpoint.IdempotencyKey] = time.Now()
 	}
 
 	// Create composite key for primary index
@@ -385,3 +838,124 @@ func (m *MemStore[S]) MarkEventsEmitted(_ context.Context, eventIDs []string) er
 
 	return nil
 }
+
+// Prune deletes the history policy does not retain (implements Pruner).
+//
+// A completed run's age is taken from the UpdatedAt of its status record, the
+// Timestamp of its latest checkpoint or the CreatedAt of its last step (see
+// RetentionPolicy.CompletedRunTTL). Steps and idempotency keys restored by
+// UnmarshalJSON from data without their save times never expire.
+// MarkEventsEmitted already removes emitted events from MemStore, so
+// EmittedEventTTL finds nothing to delete.
+func (m *MemStore[S]) Prune(_ context.Context, policy RetentionPolicy) (PruneResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result PruneResult
+	now := time.Now()
+
+	// The latest checkpoint tells whether a run completed and must survive compaction
+	latest := make(map[string]CheckpointV2[S])
+	for _, checkpoint := range m.checkpointsV2 {
+		if current, exists := latest[checkpoint.RunID]; !exists || checkpoint.StepID > current.StepID {
+			latest[checkpoint.RunID] = checkpoint
+		}
+	}
+
+	expired := make(map[string]bool)
+	if policy.CompletedRunTTL > 0 {
+		cutoff := now.Add(-policy.CompletedRunTTL)
+
+		// A run's status record tells when it completed; without one its
+		// latest checkpoint does, and a run saved without checkpoints
+		// finished with its last step
+		completedAt := make(map[string]time.Time)
+		for runID, record := range m.runs {
+			if record.Status == RunCompleted {
+				completedAt[runID] = record.UpdatedAt
+			}
+		}
+		for runID, checkpoint := range latest {
+			if _, tracked := m.runs[runID]; !tracked && emptyFrontier(checkpoint.Frontier) {
+				completedAt[runID] = checkpoint.Timestamp
+			}
+		}
+		for runID, records := range m.steps {
+			_, tracked := m.runs[runID]
+			_, checkpointed := latest[runID]
+			if tracked || checkpointed {
+				continue
+			}
+			var last time.Time
+			for _, record := range records {
+				if record.CreatedAt.After(last) {
+					last = record.CreatedAt
+				}
+			}
+			completedAt[runID] = last
+		}
+
+		for runID, at := range completedAt {
+			// Steps restored by UnmarshalJSON without save times never expire
+			if !at.IsZero() && at.Before(cutoff) {
+				expired[runID] = true
+				result.Runs++
+				result.Steps += len(m.steps[runID])
+				delete(m.steps, runID)
+				delete(m.forks, runID)
+				delete(m.runs, runID)
+			}
+		}
+	}
+
+	for key, checkpoint := range m.checkpointsV2 {
+		compacted := policy.LabeledCheckpointsOnly && checkpoint.Label == "" &&
+			checkpoint.StepID < latest[checkpoint.RunID].StepID
+		if expired[checkpoint.RunID] || compacted {
+			delete(m.checkpointsV2, key)
+			result.Checkpoints++
+		}
+	}
+	for label, key := range m.labelIndex {
+		if _, exists := m.checkpointsV2[key]; !exists {
+			delete(m.labelIndex, label)
+		}
+	}
+
+	if policy.KeepLastSteps > 0 {
+		for runID, records := range m.steps {
+			if len(records) <= policy.KeepLastSteps {
+				continue
+			}
+			kept := append([]StepRecord[S](nil), records...)
+			sort.SliceStable(kept, func(i, j int) bool {
+				return kept[i].Step < kept[j].Step
+			})
+			result.Steps += len(kept) - policy.KeepLastSteps
+			m.steps[runID] = kept[len(kept)-policy.KeepLastSteps:]
+		}
+	}
+
+	if policy.IdempotencyKeyTTL > 0 {
+		cutoff := now.Add(-policy.IdempotencyKeyTTL)
+		for key, usedAt := range m.idempotencyAt {
+			if usedAt.Before(cutoff) {
+				delete(m.idempotencyAt, key)
+				delete(m.idempotencyMap, key)
+				result.IdempotencyKeys++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// emptyFrontier reports whether a checkpoint frontier holds no work items, as
+// the checkpoint saved when a run completes does.
+func emptyFrontier(frontier interface{}) bool {
+	if frontier == nil {
+		return true
+	}
+	value := reflect.ValueOf(frontier)
+	return value.Kind() == reflect.Slice && value.Len() == 0
+}
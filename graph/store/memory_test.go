@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/dshills/langgraph-go/graph/emit"
 )
@@ -212,6 +213,129 @@ func TestMemStore_LoadLatest(t *testing.T) {
 	})
 }
 
+// TestMemStore_ListSteps verifies a run's step history is listed in step order.
+func TestMemStore_ListSteps(t *testing.T) {
+	t.Run("unknown run", func(t *testing.T) {
+		store := NewMemStore[TestState]()
+
+		_, err := store.ListSteps(context.Background(), "nonexistent", Page{})
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("steps ordered by step number", func(t *testing.T) {
+		store := NewMemStore[TestState]()
+		ctx := context.Background()
+
+		_ = store.SaveStep(ctx, "run-001", 2, "node2", TestState{Value: "step2"})
+		_ = store.SaveStep(ctx, "run-001", 1, "node1", TestState{Value: "step1"})
+		_ = store.SaveStep(ctx, "run-002", 1, "other", TestState{Value: "other"})
+
+		steps, err := store.ListSteps(ctx, "run-001", Page{})
+		if err != nil {
+			t.Fatalf("ListSteps failed: %v", err)
+		}
+		if len(steps) != 2 {
+			t.Fatalf("expected 2 steps, got %d", len(steps))
+		}
+		if steps[0].NodeID != "node1" || steps[1].NodeID != "node2" || steps[1].State.Value != "step2" {
+			t.Errorf("unexpected steps: %+v", steps)
+		}
+	})
+}
+
+// TestMemStore_Forks verifies fork lineage is saved, listed and serialized.
+func TestMemStore_Forks(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := NewMemStore[TestState]()
+	var _ ForkStore = store
+	if _, err := store.LoadFork(ctx, "run-001"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a run that is not a fork, got %v", err)
+	}
+
+	_ = store.SaveFork(ctx, ForkRecord{RunID: "fork-b", ParentRunID: "run-001", ParentStep: 3, CreatedAt: created})
+	_ = store.SaveFork(ctx, ForkRecord{RunID: "fork-a", ParentRunID: "run-001", ParentStep: 1, CreatedAt: created.Add(time.Minute)})
+	_ = store.SaveFork(ctx, ForkRecord{RunID: "fork-c", ParentRunID: "fork-a", ParentStep: 2, CreatedAt: created})
+
+	fork, err := store.LoadFork(ctx, "fork-b")
+	if err != nil {
+		t.Fatalf("LoadFork failed: %v", err)
+	}
+	if fork.ParentRunID != "run-001" || fork.ParentStep != 3 || !fork.CreatedAt.Equal(created) {
+		t.Errorf("unexpected fork: %+v", fork)
+	}
+
+	forks, err := store.ListForks(ctx, "run-001")
+	if err != nil {
+		t.Fatalf("ListForks failed: %v", err)
+	}
+	if len(forks) != 2 || forks[0].RunID != "fork-a" || forks[1].RunID != "fork-b" {
+		t.Errorf("expected forks ordered by parent step, got %+v", forks)
+	}
+	if forks, _ := store.ListForks(ctx, "fork-b"); len(forks) != 0 {
+		t.Errorf("expected no forks of fork-b, got %+v", forks)
+	}
+
+	data, err := store.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	restored := NewMemStore[TestState]()
+	if err := restored.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if fork, err := restored.LoadFork(ctx, "fork-c"); err != nil || fork.ParentRunID != "fork-a" {
+		t.Errorf("restored LoadFork = %+v, %v; want fork of fork-a", fork, err)
+	}
+}
+
+// TestMemStore_Prune verifies retention policies delete old history.
+func TestMemStore_Prune(t *testing.T) {
+	var _ Pruner = NewMemStore[TestState]()
+
+	newStore := func(*testing.T) pruneTestStore {
+		return NewMemStore[TestState]()
+	}
+	age := func(st pruneTestStore, runIDs ...string) {
+		m := st.(*MemStore[TestState])
+		for _, runID := range runIDs {
+			for key, checkpoint := range m.checkpointsV2 {
+				if checkpoint.RunID == runID {
+					checkpoint.Timestamp = checkpoint.Timestamp.Add(-2 * time.Hour)
+					m.checkpointsV2[key] = checkpoint
+					m.idempotencyAt[checkpoint.IdempotencyKey] = checkpoint.Timestamp
+				}
+			}
+			for i := range m.steps[runID] {
+				m.steps[runID][i].CreatedAt = m.steps[runID][i].CreatedAt.Add(-2 * time.Hour)
+			}
+			if record, exists := m.runs[runID]; exists {
+				record.UpdatedAt = record.UpdatedAt.Add(-2 * time.Hour)
+				m.runs[runID] = record
+			}
+		}
+	}
+	runPruneTests(t, newStore, age)
+
+	t.Run("restored keys without save times are kept", func(t *testing.T) {
+		ctx := context.Background()
+		st := NewMemStore[TestState]()
+		if err := st.UnmarshalJSON([]byte(`{"idempotency_map": {"old-key": true}}`)); err != nil {
+			t.Fatalf("UnmarshalJSON failed: %v", err)
+		}
+		result, err := st.Prune(ctx, RetentionPolicy{IdempotencyKeyTTL: time.Nanosecond})
+		if err != nil || result.IdempotencyKeys != 0 {
+			t.Errorf("Prune = %+v, %v; want no keys deleted", result, err)
+		}
+		if used, _ := st.CheckIdempotency(ctx, "old-key"); !used {
+			t.Error("restored key was deleted")
+		}
+	})
+}
+
 // TestMemStore_SaveCheckpoint verifies checkpoint save with labels (T039).
 func TestMemStore_SaveCheckpoint(t *testing.T) {
 	t.Run("save checkpoint with label", func(t *testing.T) {
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -194,6 +196,75 @@ func (m *MySQLStore[S]) createTables(ctx context.Context) error {
 		return fmt.Errorf("failed to create events_outbox table: %w", err)
 	}
 
+	// workflow_forks table: records the lineage of forked runs
+	forksTable := `
+		CREATE TABLE IF NOT EXISTS workflow_forks (
+			run_id VARCHAR(255) NOT NULL PRIMARY KEY,
+			parent_run_id VARCHAR(255) NOT NULL,
+			parent_step INT NOT NULL,
+			created_at TIMESTAMP(6) NOT NULL,
+			INDEX idx_parent (parent_run_id, parent_step)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`
+
+	if _, err := m.db.ExecContext(ctx, forksTable); err != nil {
+		return fmt.Errorf("failed to create workflow_forks table: %w", err)
+	}
+
+	// workflow_runs table: status records and leases of runs (see RunTracker)
+	runsTable := `
+		CREATE TABLE IF NOT EXISTS workflow_runs (
+			run_id VARCHAR(255) NOT NULL PRIMARY KEY,
+			status VARCHAR(32) NOT NULL,
+			owner VARCHAR(255) NOT NULL,
+			lease_expires_at BIGINT NOT NULL,
+			error TEXT NOT NULL,
+			started_at TIMESTAMP(6) NOT NULL,
+			updated_at TIMESTAMP(6) NOT NULL,
+			INDEX idx_lease (status, lease_expires_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`
+
+	if _, err := m.db.ExecContext(ctx, runsTable); err != nil {
+		return fmt.Errorf("failed to create workflow_runs table: %w", err)
+	}
+
+	// workflow_work table: work items queued for workers (see WorkQueue)
+	workTable := `
+		CREATE TABLE IF NOT EXISTS workflow_work (
+			seq BIGINT AUTO_INCREMENT PRIMARY KEY,
+			id VARCHAR(255) NOT NULL UNIQUE,
+			run_id VARCHAR(255) NOT NULL,
+			node_id VARCHAR(255) NOT NULL,
+			item JSON NOT NULL,
+			status VARCHAR(32) NOT NULL,
+			owner VARCHAR(255) NOT NULL DEFAULT '',
+			lease_expires_at BIGINT NOT NULL DEFAULT 0,
+			result JSON,
+			created_at TIMESTAMP(6) NOT NULL,
+			INDEX idx_status (status, seq),
+			INDEX idx_run_id (run_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`
+
+	if _, err := m.db.ExecContext(ctx, workTable); err != nil {
+		return fmt.Errorf("failed to create workflow_work table: %w", err)
+	}
+
+	// workflow_cache table: cached node results (see ResultCache)
+	cacheTable := `
+		CREATE TABLE IF NOT EXISTS workflow_cache (
+			cache_key VARCHAR(512) NOT NULL PRIMARY KEY,
+			value LONGBLOB NOT NULL,
+			expires_at BIGINT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP(6) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`
+
+	if _, err := m.db.ExecContext(ctx, cacheTable); err != nil {
+		return fmt.Errorf("failed to create workflow_cache table: %w", err)
+	}
+
 	return nil
 }
 
@@ -275,6 +346,179 @@ func (m *MySQLStore[S]) LoadLatest(ctx context.Context, runID string) (state S,
 	return state, step, nil
 }
 
+// ListSteps returns a page of the steps saved for a run, ordered by step
+// number (implements StepLister).
+func (m *MySQLStore[S]) ListSteps(ctx context.Context, runID string, page Page) ([]StepRecord[S], error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT step, node_id, state, created_at
+		FROM workflow_steps
+		WHERE run_id = ?
+		ORDER BY step ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := m.db.QueryContext(ctx, query, runID, mysqlLimit(page), max(page.Offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list steps: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	steps := make([]StepRecord[S], 0)
+	for rows.Next() {
+		record, err := scanMySQLStep[S](rows)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list steps: %w", err)
+	}
+
+	// An empty page is only an error if the run has no steps at all
+	if len(steps) == 0 {
+		var count int
+		if err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM workflow_steps WHERE run_id = ?", runID).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count steps: %w", err)
+		}
+		if count == 0 {
+			return nil, ErrNotFound
+		}
+	}
+
+	return steps, nil
+}
+
+// GetStep returns the step saved for a run at the given step number
+// (implements HistoryStore).
+func (m *MySQLStore[S]) GetStep(ctx context.Context, runID string, step int) (StepRecord[S], error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return StepRecord[S]{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT step, node_id, state, created_at
+		FROM workflow_steps
+		WHERE run_id = ? AND step = ?
+	`
+
+	record, err := scanMySQLStep[S](m.db.QueryRowContext(ctx, query, runID, step))
+	if errors.Is(err, sql.ErrNoRows) {
+		return StepRecord[S]{}, ErrNotFound
+	}
+	if err != nil {
+		return StepRecord[S]{}, err
+	}
+
+	return record, nil
+}
+
+// scanMySQLStep reads a workflow_steps row selected as step, node_id, state
+// and created_at.
+func scanMySQLStep[S any](row rowScanner) (StepRecord[S], error) {
+	var record StepRecord[S]
+	var stateJSON []byte
+	if err := row.Scan(&record.Step, &record.NodeID, &stateJSON, &record.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record, err
+		}
+		return record, fmt.Errorf("failed to scan step: %w", err)
+	}
+	if err := json.Unmarshal(stateJSON, &record.State); err != nil {
+		return record, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	return record, nil
+}
+
+// mysqlLimit returns the LIMIT value of a page; MySQL has no "no limit"
+// value, so the largest row count stands in for it.
+func mysqlLimit(page Page) uint64 {
+	if page.Limit > 0 {
+		return uint64(page.Limit)
+	}
+	return math.MaxUint64
+}
+
+// ListRuns returns the runs matching filter, most recently updated first
+// (implements HistoryStore).
+//
+// Runs are gathered from workflow_steps and workflow_checkpoints_v2; their
+// times come from the created_at columns, which have second precision.
+func (m *MySQLStore[S]) ListRuns(ctx context.Context, filter RunFilter) ([]RunSummary, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	var (
+		where  []string
+		having []string
+		args   []interface{}
+	)
+	if filter.Prefix != "" {
+		// Compare in binary, as the column collation ignores case
+		where = append(where, "LEFT(run_id, ?) = CAST(? AS BINARY)")
+		args = append(args, len([]rune(filter.Prefix)), filter.Prefix)
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		having = append(having, "MAX(created_at) >= ?")
+		args = append(args, filter.UpdatedAfter)
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		having = append(having, "MAX(created_at) < ?")
+		args = append(args, filter.UpdatedBefore)
+	}
+
+	query := `
+		SELECT run_id, SUM(is_step), SUM(1 - is_step), MAX(step), MIN(created_at), MAX(created_at)
+		FROM (
+			SELECT run_id, step, 1 AS is_step, created_at FROM workflow_steps
+			UNION ALL
+			SELECT run_id, step_id, 0, created_at FROM workflow_checkpoints_v2
+		) AS history`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " GROUP BY run_id"
+	if len(having) > 0 {
+		query += " HAVING " + strings.Join(having, " AND ")
+	}
+	query += " ORDER BY MAX(created_at) DESC, run_id ASC LIMIT ? OFFSET ?"
+	args = append(args, mysqlLimit(filter.Page), max(filter.Page.Offset, 0))
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]RunSummary, 0)
+	for rows.Next() {
+		var run RunSummary
+		if err := rows.Scan(&run.RunID, &run.Steps, &run.Checkpoints, &run.LastStep, &run.StartedAt, &run.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	return runs, nil
+}
+
 // SaveCheckpoint creates a named checkpoint (implements Store interface).
 //
 // Checkpoints are stored in the workflow_checkpoints table.
@@ -313,6 +557,34 @@ func (m *MySQLStore[S]) SaveCheckpoint(ctx context.Context, cpID string, state S
 	return nil
 }
 
+// ClaimCheckpoint changes the step of a named checkpoint from step to claimed
+// if it is at step (implements CheckpointClaimer). The conditional update is
+// atomic, so one of several connections claiming the same step succeeds.
+func (m *MySQLStore[S]) ClaimCheckpoint(ctx context.Context, cpID string, step, claimed int) (bool, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return false, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_checkpoints
+		SET step = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE checkpoint_id = ? AND step = ?
+	`
+
+	result, err := m.db.ExecContext(ctx, query, claimed, cpID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint: %w", err)
+	}
+	return rows == 1, nil
+}
+
 // LoadCheckpoint retrieves a named checkpoint (implements Store interface).
 //
 // Returns ErrNotFound if the checkpoint ID doesn't exist.
@@ -351,6 +623,153 @@ func (m *MySQLStore[S]) LoadCheckpoint(ctx context.Context, cpID string) (state
 	return state, step, nil
 }
 
+// Prune deletes the history policy does not retain in one transaction
+// (implements Pruner).
+//
+// Ages are measured from the created_at column of each row, and a completed
+// run's age from the updated_at of its status record, the creation of its
+// latest checkpoint or the creation of its last step (see
+// RetentionPolicy.CompletedRunTTL).
+func (m *MySQLStore[S]) Prune(ctx context.Context, policy RetentionPolicy) (PruneResult, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return PruneResult{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	var result PruneResult
+	now := time.Now()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // No-op once committed
+
+	deleteRows := func(count *int, query string, args ...interface{}) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		*count += int(n)
+		return err
+	}
+
+	// MySQL cannot delete from a table a subquery reads, so the statements
+	// below join derived tables, which GROUP BY and window functions keep
+	// from being merged into the DELETE.
+
+	if policy.CompletedRunTTL > 0 {
+		// A run's status record tells whether it completed; without one its
+		// latest checkpoint does, and a run saved without checkpoints
+		// finished with its last step
+		cutoff := now.Add(-policy.CompletedRunTTL)
+		rows, err := tx.QueryContext(ctx, `
+			SELECT run_id
+			FROM workflow_runs
+			WHERE status = ? AND updated_at < ?
+			UNION
+			SELECT c.run_id
+			FROM workflow_checkpoints_v2 c
+			JOIN (
+				SELECT run_id, MAX(step_id) AS last_step FROM workflow_checkpoints_v2 GROUP BY run_id
+			) latest ON c.run_id = latest.run_id AND c.step_id = latest.last_step
+			WHERE (JSON_LENGTH(c.frontier) = 0 OR JSON_TYPE(c.frontier) = 'NULL')
+				AND c.created_at < ?
+				AND c.run_id NOT IN (SELECT run_id FROM workflow_runs)
+			UNION
+			SELECT run_id
+			FROM workflow_steps
+			WHERE run_id NOT IN (SELECT run_id FROM workflow_runs)
+				AND run_id NOT IN (SELECT run_id FROM workflow_checkpoints_v2)
+			GROUP BY run_id
+			HAVING MAX(created_at) < ?
+		`, RunCompleted, cutoff, cutoff, cutoff)
+		if err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+		var runIDs []string
+		for rows.Next() {
+			var runID string
+			if err := rows.Scan(&runID); err != nil {
+				_ = rows.Close()
+				return result, fmt.Errorf("failed to scan run ID: %w", err)
+			}
+			runIDs = append(runIDs, runID)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+
+		var records int
+		for _, runID := range runIDs {
+			if err := deleteRows(&result.Steps, "DELETE FROM workflow_steps WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete steps of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&result.Checkpoints, "DELETE FROM workflow_checkpoints_v2 WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete checkpoints of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&records, "DELETE FROM workflow_forks WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete fork record of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&records, "DELETE FROM workflow_runs WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete run record of run %s: %w", runID, err)
+			}
+		}
+		result.Runs = len(runIDs)
+	}
+
+	if policy.KeepLastSteps > 0 {
+		query := `
+			DELETE s FROM workflow_steps s
+			JOIN (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY run_id ORDER BY step DESC) AS position
+				FROM workflow_steps
+			) ranked ON s.id = ranked.id
+			WHERE ranked.position > ?
+		`
+		if err := deleteRows(&result.Steps, query, policy.KeepLastSteps); err != nil {
+			return result, fmt.Errorf("failed to prune steps: %w", err)
+		}
+	}
+
+	if policy.LabeledCheckpointsOnly {
+		query := `
+			DELETE c FROM workflow_checkpoints_v2 c
+			JOIN (
+				SELECT run_id, MAX(step_id) AS last_step FROM workflow_checkpoints_v2 GROUP BY run_id
+			) latest ON c.run_id = latest.run_id
+			WHERE COALESCE(c.label, '') = '' AND c.step_id < latest.last_step
+		`
+		if err := deleteRows(&result.Checkpoints, query); err != nil {
+			return result, fmt.Errorf("failed to compact checkpoints: %w", err)
+		}
+	}
+
+	if policy.IdempotencyKeyTTL > 0 {
+		query := "DELETE FROM idempotency_keys WHERE created_at < ?"
+		if err := deleteRows(&result.IdempotencyKeys, query, now.Add(-policy.IdempotencyKeyTTL)); err != nil {
+			return result, fmt.Errorf("failed to expire idempotency keys: %w", err)
+		}
+	}
+
+	if policy.EmittedEventTTL > 0 {
+		query := "DELETE FROM events_outbox WHERE emitted_at IS NOT NULL AND emitted_at < ?"
+		if err := deleteRows(&result.Events, query, now.Add(-policy.EmittedEventTTL)); err != nil {
+			return result, fmt.Errorf("failed to purge emitted events: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PruneResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
 // Close closes the database connection pool.
 //
 // After Close, all operations will return an error.
@@ -652,14 +1071,70 @@ func (m *MySQLStore[S]) LoadCheckpointV2(ctx context.Context, runID string, step
 		LIMIT 1
 	`
 
+	checkpoint, err := scanMySQLCheckpointV2[S](m.db.QueryRowContext(ctx, query, runID, stepID))
+	if errors.Is(err, sql.ErrNoRows) {
+		var zero CheckpointV2[S]
+		return zero, ErrNotFound
+	}
+	if err != nil {
+		var zero CheckpointV2[S]
+		return zero, err
+	}
+
+	return checkpoint, nil
+}
+
+// ListCheckpoints returns the CheckpointV2 saved for a run, optionally only
+// those with label, ordered by step ID (implements HistoryStore).
+func (m *MySQLStore[S]) ListCheckpoints(ctx context.Context, runID string, label string) ([]CheckpointV2[S], error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT run_id, step_id, state, frontier, rng_seed, recorded_ios, idempotency_key, timestamp, label
+		FROM workflow_checkpoints_v2
+		WHERE run_id = ? AND (? = '' OR label = ?)
+		ORDER BY step_id ASC
+	`
+
+	rows, err := m.db.QueryContext(ctx, query, runID, label, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	checkpoints := make([]CheckpointV2[S], 0)
+	for rows.Next() {
+		checkpoint, err := scanMySQLCheckpointV2[S](rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// scanMySQLCheckpointV2 reads a workflow_checkpoints_v2 row selected as
+// run_id, step_id, state, frontier, rng_seed, recorded_ios, idempotency_key,
+// timestamp and label. sql.ErrNoRows is returned unwrapped.
+func scanMySQLCheckpointV2[S any](row rowScanner) (CheckpointV2[S], error) {
 	var (
 		stateJSON       []byte
 		frontierJSON    []byte
 		recordedIOsJSON []byte
 		checkpoint      CheckpointV2[S]
+		zero            CheckpointV2[S]
 	)
 
-	err := m.db.QueryRowContext(ctx, query, runID, stepID).Scan(
+	err := row.Scan(
 		&checkpoint.RunID,
 		&checkpoint.StepID,
 		&stateJSON,
@@ -670,35 +1145,629 @@ func (m *MySQLStore[S]) LoadCheckpointV2(ctx context.Context, runID string, step
 		&checkpoint.Timestamp,
 		&checkpoint.Label,
 	)
-
-	if err == sql.ErrNoRows {
-		var zero CheckpointV2[S]
-		return zero, ErrNotFound
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero, err
 	}
 	if err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to load checkpoint: %w", err)
 	}
 
 	// Deserialize JSON fields
 	if err := json.Unmarshal(stateJSON, &checkpoint.State); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal state: %w", err)
 	}
 
 	if err := json.Unmarshal(frontierJSON, &checkpoint.Frontier); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal frontier: %w", err)
 	}
 
 	if err := json.Unmarshal(recordedIOsJSON, &checkpoint.RecordedIOs); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal recorded IOs: %w", err)
 	}
 
 	return checkpoint, nil
 }
 
+// SaveFork records the lineage of a forked run (implements ForkStore).
+//
+// Forks are stored in the workflow_forks table, keyed by the forked run ID.
+func (m *MySQLStore[S]) SaveFork(ctx context.Context, fork ForkRecord) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_forks (run_id, parent_run_id, parent_step, created_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			parent_run_id = VALUES(parent_run_id),
+			parent_step = VALUES(parent_step),
+			created_at = VALUES(created_at)
+	`
+
+	if _, err := m.db.ExecContext(ctx, query, fork.RunID, fork.ParentRunID, fork.ParentStep, fork.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save fork: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFork returns the lineage of a forked run (implements ForkStore).
+//
+// Returns ErrNotFound if the run is not a fork.
+func (m *MySQLStore[S]) LoadFork(ctx context.Context, runID string) (ForkRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ForkRecord{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT run_id, parent_run_id, parent_step, created_at
+		FROM workflow_forks
+		WHERE run_id = ?
+	`
+
+	var fork ForkRecord
+	err := m.db.QueryRowContext(ctx, query, runID).Scan(&fork.RunID, &fork.ParentRunID, &fork.ParentStep, &fork.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ForkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return ForkRecord{}, fmt.Errorf("failed to load fork: %w", err)
+	}
+
+	return fork, nil
+}
+
+// ListForks returns the runs forked from parentRunID (implements ForkStore).
+func (m *MySQLStore[S]) ListForks(ctx context.Context, parentRunID string) ([]ForkRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT run_id, parent_run_id, parent_step, created_at
+		FROM workflow_forks
+		WHERE parent_run_id = ?
+		ORDER BY parent_step ASC, created_at ASC, run_id ASC
+	`
+
+	rows, err := m.db.QueryContext(ctx, query, parentRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list forks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	forks := make([]ForkRecord, 0)
+	for rows.Next() {
+		var fork ForkRecord
+		if err := rows.Scan(&fork.RunID, &fork.ParentRunID, &fork.ParentStep, &fork.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fork: %w", err)
+		}
+		forks = append(forks, fork)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list forks: %w", err)
+	}
+
+	return forks, nil
+}
+
+// StartRun records that owner is executing a run (implements RunTracker).
+//
+// Runs are stored in the workflow_runs table, keyed by run ID.
+func (m *MySQLStore[S]) StartRun(ctx context.Context, runID, owner string, leaseUntil time.Time) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_runs (run_id, status, owner, lease_expires_at, error, started_at, updated_at)
+		VALUES (?, ?, ?, ?, '', ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			owner = VALUES(owner),
+			lease_expires_at = VALUES(lease_expires_at),
+			error = '',
+			updated_at = VALUES(updated_at)
+	`
+
+	now := time.Now()
+	if _, err := m.db.ExecContext(ctx, query, runID, RunRunning, owner, leaseMillis(leaseUntil), now, now); err != nil {
+		return fmt.Errorf("failed to start run: %w", err)
+	}
+
+	return nil
+}
+
+// RenewLease extends owner's lease on a running run (implements RunTracker).
+func (m *MySQLStore[S]) RenewLease(ctx context.Context, runID, owner string, leaseUntil time.Time) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_runs
+		SET lease_expires_at = ?, updated_at = ?
+		WHERE run_id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := m.db.ExecContext(ctx, query, leaseMillis(leaseUntil), time.Now(), runID, RunRunning, owner)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return m.checkRunOwner(ctx, res, runID, owner, true)
+}
+
+// FinishRun records the final status of a run (implements RunTracker).
+func (m *MySQLStore[S]) FinishRun(ctx context.Context, runID, owner string, status RunState, errMsg string) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_runs
+		SET status = ?, lease_expires_at = 0, error = ?, updated_at = ?
+		WHERE run_id = ? AND owner = ?
+	`
+
+	res, err := m.db.ExecContext(ctx, query, status, errMsg, time.Now(), runID, owner)
+	if err != nil {
+		return fmt.Errorf("failed to finish run: %w", err)
+	}
+	return m.checkRunOwner(ctx, res, runID, owner, false)
+}
+
+// checkRunOwner reports whether a lease UPDATE matched the run. MySQL counts
+// only the rows an UPDATE changed, so when none changed the record is read
+// back to tell an unchanged row from one held by another owner.
+func (m *MySQLStore[S]) checkRunOwner(ctx context.Context, res sql.Result, runID, owner string, running bool) error {
+	changed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update run: %w", err)
+	}
+	if changed > 0 {
+		return nil
+	}
+
+	record, err := m.LoadRun(ctx, runID)
+	switch {
+	case errors.Is(err, ErrNotFound) && running:
+		return ErrLeaseLost
+	case err != nil:
+		return err
+	case record.Owner != owner || (running && record.Status != RunRunning):
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ClaimExpiredRuns transfers abandoned runs to owner (implements RunTracker).
+//
+// Runs are claimed in the order their leases expired. Expired rows are locked
+// with SELECT ... FOR UPDATE SKIP LOCKED, so processes claiming at the same
+// time each claim different runs.
+func (m *MySQLStore[S]) ClaimExpiredRuns(ctx context.Context, owner string, leaseUntil time.Time, limit int) ([]RunRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // No-op once committed
+
+	query := `
+		SELECT run_id, status, owner, lease_expires_at, error, started_at, updated_at
+		FROM workflow_runs
+		WHERE status = ? AND lease_expires_at < ?
+		ORDER BY lease_expires_at ASC, run_id ASC
+	`
+	now := time.Now()
+	args := []interface{}{RunRunning, now.UnixMilli()}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	query += " FOR UPDATE SKIP LOCKED"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired runs: %w", err)
+	}
+	claimed := make([]RunRecord, 0)
+	for rows.Next() {
+		record, err := scanMySQLRunRecord(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		claimed = append(claimed, record)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find expired runs: %w", err)
+	}
+
+	for i := range claimed {
+		claimed[i].Owner = owner
+		claimed[i].LeaseExpiresAt = leaseTime(leaseMillis(leaseUntil))
+		claimed[i].UpdatedAt = now
+		_, err := tx.ExecContext(ctx, "UPDATE workflow_runs SET owner = ?, lease_expires_at = ?, updated_at = ? WHERE run_id = ?",
+			owner, leaseMillis(leaseUntil), now, claimed[i].RunID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim run %s: %w", claimed[i].RunID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// LoadRun returns the status record of a run (implements RunTracker).
+//
+// Returns ErrNotFound if the run was never started.
+func (m *MySQLStore[S]) LoadRun(ctx context.Context, runID string) (RunRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return RunRecord{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT run_id, status, owner, lease_expires_at, error, started_at, updated_at
+		FROM workflow_runs
+		WHERE run_id = ?
+	`
+
+	record, err := scanMySQLRunRecord(m.db.QueryRowContext(ctx, query, runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return RunRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return RunRecord{}, fmt.Errorf("failed to load run: %w", err)
+	}
+
+	return record, nil
+}
+
+// ListRunRecords returns the status records of runs (implements RunTracker).
+func (m *MySQLStore[S]) ListRunRecords(ctx context.Context, status RunState) ([]RunRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT run_id, status, owner, lease_expires_at, error, started_at, updated_at
+		FROM workflow_runs
+		WHERE ? = '' OR status = ?
+		ORDER BY started_at ASC, run_id ASC
+	`
+
+	rows, err := m.db.QueryContext(ctx, query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]RunRecord, 0)
+	for rows.Next() {
+		record, err := scanMySQLRunRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	return records, nil
+}
+
+// scanMySQLRunRecord reads a workflow_runs row.
+func scanMySQLRunRecord(row rowScanner) (RunRecord, error) {
+	var record RunRecord
+	var leaseExpiresAt int64
+	if err := row.Scan(&record.RunID, &record.Status, &record.Owner, &leaseExpiresAt, &record.Error, &record.StartedAt, &record.UpdatedAt); err != nil {
+		return RunRecord{}, err
+	}
+	record.LeaseExpiresAt = leaseTime(leaseExpiresAt)
+	return record, nil
+}
+
+// PushWork queues a work item (implements WorkQueue).
+//
+// Work items are stored in the workflow_work table, keyed by their ID and
+// leased in the order of their seq column.
+func (m *MySQLStore[S]) PushWork(ctx context.Context, work WorkRecord) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_work (id, run_id, node_id, item, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	if _, err := m.db.ExecContext(ctx, query, work.ID, work.RunID, work.NodeID, work.Item, WorkPending, time.Now()); err != nil {
+		return fmt.Errorf("failed to push work: %w", err)
+	}
+
+	return nil
+}
+
+// LeaseWork hands owner the next available work item (implements WorkQueue).
+//
+// The item is locked with SELECT ... FOR UPDATE SKIP LOCKED, so processes
+// leasing at the same time each lease a different item.
+func (m *MySQLStore[S]) LeaseWork(ctx context.Context, owner string, leaseUntil time.Time) (WorkRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return WorkRecord{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // No-op once committed
+
+	query := `
+		SELECT id, run_id, node_id, item, status, owner, lease_expires_at, result, created_at
+		FROM workflow_work
+		WHERE status = ? OR (status = ? AND lease_expires_at < ?)
+		ORDER BY seq ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	work, err := scanMySQLWork(tx.QueryRowContext(ctx, query, WorkPending, WorkLeased, time.Now().UnixMilli()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WorkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to lease work: %w", err)
+	}
+
+	work.Status = WorkLeased
+	work.Owner = owner
+	work.LeaseExpiresAt = leaseTime(leaseMillis(leaseUntil))
+	_, err = tx.ExecContext(ctx, "UPDATE workflow_work SET status = ?, owner = ?, lease_expires_at = ? WHERE id = ?",
+		WorkLeased, owner, leaseMillis(leaseUntil), work.ID)
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to lease work %s: %w", work.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return work, nil
+}
+
+// RenewWork extends owner's lease on a work item (implements WorkQueue).
+func (m *MySQLStore[S]) RenewWork(ctx context.Context, id, owner string, leaseUntil time.Time) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_work
+		SET lease_expires_at = ?
+		WHERE id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := m.db.ExecContext(ctx, query, leaseMillis(leaseUntil), id, WorkLeased, owner)
+	if err != nil {
+		return fmt.Errorf("failed to renew work lease: %w", err)
+	}
+	return m.checkWorkOwner(ctx, res, id, owner)
+}
+
+// CompleteWork commits the result of a work item (implements WorkQueue).
+func (m *MySQLStore[S]) CompleteWork(ctx context.Context, id, owner string, result []byte) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_work
+		SET status = ?, lease_expires_at = 0, result = ?
+		WHERE id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := m.db.ExecContext(ctx, query, WorkDone, result, id, WorkLeased, owner)
+	if err != nil {
+		return fmt.Errorf("failed to complete work: %w", err)
+	}
+	completed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to complete work: %w", err)
+	}
+	if completed == 0 {
+		// The status changes on completion, so no row changed only if the
+		// item was not leased by owner
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// checkWorkOwner reports whether a work lease UPDATE matched the item. MySQL
+// counts only the rows an UPDATE changed, so when none changed the item is
+// read back to tell an unchanged row from one that is no longer owner's.
+func (m *MySQLStore[S]) checkWorkOwner(ctx context.Context, res sql.Result, id, owner string) error {
+	changed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update work: %w", err)
+	}
+	if changed > 0 {
+		return nil
+	}
+
+	work, err := m.LoadWork(ctx, id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return ErrLeaseLost
+	case err != nil:
+		return err
+	case work.Status != WorkLeased || work.Owner != owner:
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// LoadWork returns a queued work item (implements WorkQueue).
+func (m *MySQLStore[S]) LoadWork(ctx context.Context, id string) (WorkRecord, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return WorkRecord{}, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		SELECT id, run_id, node_id, item, status, owner, lease_expires_at, result, created_at
+		FROM workflow_work
+		WHERE id = ?
+	`
+
+	work, err := scanMySQLWork(m.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WorkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to load work: %w", err)
+	}
+
+	return work, nil
+}
+
+// DeleteWork removes the work items of a run (implements WorkQueue).
+func (m *MySQLStore[S]) DeleteWork(ctx context.Context, runID string) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	if _, err := m.db.ExecContext(ctx, "DELETE FROM workflow_work WHERE run_id = ?", runID); err != nil {
+		return fmt.Errorf("failed to delete work: %w", err)
+	}
+
+	return nil
+}
+
+// LoadCachedResult returns a cached node result (implements ResultCache).
+//
+// Cached results are stored in the workflow_cache table. An expired result
+// is deleted when it is looked up.
+func (m *MySQLStore[S]) LoadCachedResult(ctx context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	var value []byte
+	var expiresAt int64
+	err := m.db.QueryRowContext(ctx, "SELECT value, expires_at FROM workflow_cache WHERE cache_key = ?", key).Scan(&value, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cached result: %w", err)
+	}
+
+	if expiresAt != 0 && expiresAt <= leaseMillis(time.Now()) {
+		_, err := m.db.ExecContext(ctx, "DELETE FROM workflow_cache WHERE cache_key = ? AND expires_at = ?", key, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete expired result: %w", err)
+		}
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// SaveCachedResult caches a node result (implements ResultCache).
+func (m *MySQLStore[S]) SaveCachedResult(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	m.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_cache (cache_key, value, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			value = VALUES(value),
+			expires_at = VALUES(expires_at),
+			created_at = VALUES(created_at)
+	`
+
+	if _, err := m.db.ExecContext(ctx, query, key, value, leaseMillis(expiresAt), time.Now()); err != nil {
+		return fmt.Errorf("failed to save cached result: %w", err)
+	}
+
+	return nil
+}
+
+// scanMySQLWork reads a workflow_work row.
+func scanMySQLWork(row rowScanner) (WorkRecord, error) {
+	var work WorkRecord
+	var leaseExpiresAt int64
+	if err := row.Scan(&work.ID, &work.RunID, &work.NodeID, &work.Item, &work.Status, &work.Owner, &leaseExpiresAt, &work.Result, &work.CreatedAt); err != nil {
+		return WorkRecord{}, err
+	}
+	work.LeaseExpiresAt = leaseTime(leaseExpiresAt)
+	return work, nil
+}
+
 // CheckIdempotency verifies if an idempotency key has been used.
 //
 // Returns true if the key exists in the idempotency_keys table.
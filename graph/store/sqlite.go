@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -235,6 +236,85 @@ func (s *SQLiteStore[S]) createTables(ctx context.Context) error {
 		return fmt.Errorf("failed to create idx_events_run_id: %w", err)
 	}
 
+	// workflow_forks table: records the lineage of forked runs
+	forksTable := `
+		CREATE TABLE IF NOT EXISTS workflow_forks (
+			run_id TEXT NOT NULL PRIMARY KEY,
+			parent_run_id TEXT NOT NULL,
+			parent_step INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`
+	if _, err := s.db.ExecContext(ctx, forksTable); err != nil {
+		return fmt.Errorf("failed to create workflow_forks table: %w", err)
+	}
+
+	// Create index for workflow_forks
+	if _, err := s.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_forks_parent ON workflow_forks(parent_run_id, parent_step)"); err != nil {
+		return fmt.Errorf("failed to create idx_forks_parent: %w", err)
+	}
+
+	// workflow_runs table: status records and leases of runs (see RunTracker)
+	runsTable := `
+		CREATE TABLE IF NOT EXISTS workflow_runs (
+			run_id TEXT NOT NULL PRIMARY KEY,
+			status TEXT NOT NULL,
+			owner TEXT NOT NULL,
+			lease_expires_at INTEGER NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`
+	if _, err := s.db.ExecContext(ctx, runsTable); err != nil {
+		return fmt.Errorf("failed to create workflow_runs table: %w", err)
+	}
+
+	// Create index for claiming expired runs
+	if _, err := s.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_runs_lease ON workflow_runs(status, lease_expires_at)"); err != nil {
+		return fmt.Errorf("failed to create idx_runs_lease: %w", err)
+	}
+
+	// workflow_work table: work items queued for workers (see WorkQueue)
+	workTable := `
+		CREATE TABLE IF NOT EXISTS workflow_work (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT NOT NULL UNIQUE,
+			run_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			item TEXT NOT NULL,
+			status TEXT NOT NULL,
+			owner TEXT NOT NULL DEFAULT '',
+			lease_expires_at INTEGER NOT NULL DEFAULT 0,
+			result TEXT,
+			created_at TIMESTAMP NOT NULL
+		)
+	`
+	if _, err := s.db.ExecContext(ctx, workTable); err != nil {
+		return fmt.Errorf("failed to create workflow_work table: %w", err)
+	}
+
+	// Create indexes for workflow_work
+	if _, err := s.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_work_status ON workflow_work(status, seq)"); err != nil {
+		return fmt.Errorf("failed to create idx_work_status: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_work_run_id ON workflow_work(run_id)"); err != nil {
+		return fmt.Errorf("failed to create idx_work_run_id: %w", err)
+	}
+
+	// workflow_cache table: cached node results (see ResultCache)
+	cacheTable := `
+		CREATE TABLE IF NOT EXISTS workflow_cache (
+			cache_key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			expires_at INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		)
+	`
+	if _, err := s.db.ExecContext(ctx, cacheTable); err != nil {
+		return fmt.Errorf("failed to create workflow_cache table: %w", err)
+	}
+
 	return nil
 }
 
@@ -316,6 +396,200 @@ func (s *SQLiteStore[S]) LoadLatest(ctx context.Context, runID string) (state S,
 	return state, step, nil
 }
 
+// sqliteTime formats a CURRENT_TIMESTAMP column as RFC 3339 in a query, so it
+// scans the same way whatever the column's declared type.
+func sqliteTime(column string) string {
+	return "strftime('%Y-%m-%dT%H:%M:%SZ', " + column + ")"
+}
+
+// ListSteps returns a page of the steps saved for a run, ordered by step
+// number (implements StepLister).
+func (s *SQLiteStore[S]) ListSteps(ctx context.Context, runID string, page Page) ([]StepRecord[S], error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT step, node_id, state, ` + sqliteTime("created_at") + `
+		FROM workflow_steps
+		WHERE run_id = ?
+		ORDER BY step ASC
+		LIMIT ? OFFSET ?
+	`
+
+	limit := -1 // No limit
+	if page.Limit > 0 {
+		limit = page.Limit
+	}
+	rows, err := s.db.QueryContext(ctx, query, runID, limit, max(page.Offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list steps: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	steps := make([]StepRecord[S], 0)
+	for rows.Next() {
+		record, err := scanStep[S](rows)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list steps: %w", err)
+	}
+
+	// An empty page is only an error if the run has no steps at all
+	if len(steps) == 0 {
+		var count int
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM workflow_steps WHERE run_id = ?", runID).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count steps: %w", err)
+		}
+		if count == 0 {
+			return nil, ErrNotFound
+		}
+	}
+
+	return steps, nil
+}
+
+// GetStep returns the step saved for a run at the given step number
+// (implements HistoryStore).
+func (s *SQLiteStore[S]) GetStep(ctx context.Context, runID string, step int) (StepRecord[S], error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return StepRecord[S]{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT step, node_id, state, ` + sqliteTime("created_at") + `
+		FROM workflow_steps
+		WHERE run_id = ? AND step = ?
+	`
+
+	record, err := scanStep[S](s.db.QueryRowContext(ctx, query, runID, step))
+	if errors.Is(err, sql.ErrNoRows) {
+		return StepRecord[S]{}, ErrNotFound
+	}
+	if err != nil {
+		return StepRecord[S]{}, err
+	}
+
+	return record, nil
+}
+
+// scanStep reads a workflow_steps row selected as step, node_id, state and
+// created_at in RFC 3339.
+func scanStep[S any](row rowScanner) (StepRecord[S], error) {
+	var record StepRecord[S]
+	var stateJSON, createdAt string
+	if err := row.Scan(&record.Step, &record.NodeID, &stateJSON, &createdAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return record, err
+		}
+		return record, fmt.Errorf("failed to scan step: %w", err)
+	}
+	if err := json.Unmarshal([]byte(stateJSON), &record.State); err != nil {
+		return record, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+
+	var err error
+	record.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return record, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return record, nil
+}
+
+// ListRuns returns the runs matching filter, most recently updated first
+// (implements HistoryStore).
+//
+// Runs are gathered from workflow_steps and workflow_checkpoints_v2; their
+// times come from the created_at columns, which have second precision.
+func (s *SQLiteStore[S]) ListRuns(ctx context.Context, filter RunFilter) ([]RunSummary, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	var (
+		where  []string
+		having []string
+		args   []interface{}
+	)
+	if filter.Prefix != "" {
+		// substr compares exactly, unlike LIKE which ignores ASCII case
+		where = append(where, "substr(run_id, 1, ?) = ?")
+		args = append(args, len([]rune(filter.Prefix)), filter.Prefix)
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		having = append(having, "MAX(created_at) >= ?")
+		args = append(args, filter.UpdatedAfter.UTC().Format(time.DateTime))
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		having = append(having, "MAX(created_at) < ?")
+		args = append(args, filter.UpdatedBefore.UTC().Format(time.DateTime))
+	}
+
+	query := `
+		SELECT run_id, SUM(is_step), SUM(1 - is_step), MAX(step),
+			` + sqliteTime("MIN(created_at)") + `,
+			` + sqliteTime("MAX(created_at)") + `
+		FROM (
+			SELECT run_id, step, 1 AS is_step, created_at FROM workflow_steps
+			UNION ALL
+			SELECT run_id, step_id, 0, created_at FROM workflow_checkpoints_v2
+		)`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " GROUP BY run_id"
+	if len(having) > 0 {
+		query += " HAVING " + strings.Join(having, " AND ")
+	}
+	query += " ORDER BY MAX(created_at) DESC, run_id ASC LIMIT ? OFFSET ?"
+
+	limit := -1 // No limit
+	if filter.Page.Limit > 0 {
+		limit = filter.Page.Limit
+	}
+	args = append(args, limit, max(filter.Page.Offset, 0))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]RunSummary, 0)
+	for rows.Next() {
+		var run RunSummary
+		var startedAt, updatedAt string
+		if err := rows.Scan(&run.RunID, &run.Steps, &run.Checkpoints, &run.LastStep, &startedAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		if run.StartedAt, err = time.Parse(time.RFC3339, startedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse started_at: %w", err)
+		}
+		if run.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	return runs, nil
+}
+
 // SaveCheckpoint creates a named checkpoint (implements Store interface).
 //
 // Checkpoints are stored in the workflow_checkpoints table.
@@ -354,6 +628,34 @@ func (s *SQLiteStore[S]) SaveCheckpoint(ctx context.Context, cpID string, state
 	return nil
 }
 
+// ClaimCheckpoint changes the step of a named checkpoint from step to claimed
+// if it is at step (implements CheckpointClaimer). The conditional update is
+// atomic, so one of several connections claiming the same step succeeds.
+func (s *SQLiteStore[S]) ClaimCheckpoint(ctx context.Context, cpID string, step, claimed int) (bool, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return false, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_checkpoints
+		SET step = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE checkpoint_id = ? AND step = ?
+	`
+
+	result, err := s.db.ExecContext(ctx, query, claimed, cpID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint: %w", err)
+	}
+	return rows == 1, nil
+}
+
 // LoadCheckpoint retrieves a named checkpoint (implements Store interface).
 //
 // Returns ErrNotFound if the checkpoint ID doesn't exist.
@@ -512,15 +814,71 @@ func (s *SQLiteStore[S]) LoadCheckpointV2(ctx context.Context, runID string, ste
 		LIMIT 1
 	`
 
+	checkpoint, err := scanCheckpointV2[S](s.db.QueryRowContext(ctx, query, runID, stepID))
+	if errors.Is(err, sql.ErrNoRows) {
+		var zero CheckpointV2[S]
+		return zero, ErrNotFound
+	}
+	if err != nil {
+		var zero CheckpointV2[S]
+		return zero, err
+	}
+
+	return checkpoint, nil
+}
+
+// ListCheckpoints returns the CheckpointV2 saved for a run, optionally only
+// those with label, ordered by step ID (implements HistoryStore).
+func (s *SQLiteStore[S]) ListCheckpoints(ctx context.Context, runID string, label string) ([]CheckpointV2[S], error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT run_id, step_id, state, frontier, rng_seed, recorded_ios, idempotency_key, timestamp, label
+		FROM workflow_checkpoints_v2
+		WHERE run_id = ? AND (? = '' OR label = ?)
+		ORDER BY step_id ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, runID, label, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	checkpoints := make([]CheckpointV2[S], 0)
+	for rows.Next() {
+		checkpoint, err := scanCheckpointV2[S](rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// scanCheckpointV2 reads a workflow_checkpoints_v2 row selected as run_id,
+// step_id, state, frontier, rng_seed, recorded_ios, idempotency_key,
+// timestamp and label. sql.ErrNoRows is returned unwrapped.
+func scanCheckpointV2[S any](row rowScanner) (CheckpointV2[S], error) {
 	var (
 		stateJSON       string
 		frontierJSON    string
 		recordedIOsJSON string
 		timestampStr    string
 		checkpoint      CheckpointV2[S]
+		zero            CheckpointV2[S]
 	)
 
-	err := s.db.QueryRowContext(ctx, query, runID, stepID).Scan(
+	err := row.Scan(
 		&checkpoint.RunID,
 		&checkpoint.StepID,
 		&stateJSON,
@@ -531,75 +889,93 @@ func (s *SQLiteStore[S]) LoadCheckpointV2(ctx context.Context, runID string, ste
 		&timestampStr,
 		&checkpoint.Label,
 	)
-
 	if errors.Is(err, sql.ErrNoRows) {
-		var zero CheckpointV2[S]
-		return zero, ErrNotFound
+		return zero, err
 	}
 	if err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to load checkpoint: %w", err)
 	}
 
 	// Parse timestamp
 	checkpoint.Timestamp, err = time.Parse(time.RFC3339Nano, timestampStr)
 	if err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to parse timestamp: %w", err)
 	}
 
 	// Deserialize JSON fields
 	if err := json.Unmarshal([]byte(stateJSON), &checkpoint.State); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal state: %w", err)
 	}
 
 	if err := json.Unmarshal([]byte(frontierJSON), &checkpoint.Frontier); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal frontier: %w", err)
 	}
 
 	if err := json.Unmarshal([]byte(recordedIOsJSON), &checkpoint.RecordedIOs); err != nil {
-		var zero CheckpointV2[S]
 		return zero, fmt.Errorf("failed to unmarshal recorded IOs: %w", err)
 	}
 
 	return checkpoint, nil
 }
 
-// CheckIdempotency verifies if an idempotency key has been used.
-//
-// Returns true if the key exists in the idempotency_keys table.
-// Returns false if the key doesn't exist (safe to use).
-// Returns error only on database access failures.
+// SaveFork records the lineage of a forked run (implements ForkStore).
 //
-// This uses a unique constraint on the key for race-safe duplicate detection.
-func (s *SQLiteStore[S]) CheckIdempotency(ctx context.Context, key string) (bool, error) {
+// Forks are stored in the workflow_forks table, keyed by the forked run ID.
+func (s *SQLiteStore[S]) SaveFork(ctx context.Context, fork ForkRecord) error {
 	s.mu.RLock()
 	if s.closed {
 		s.mu.RUnlock()
-		return false, fmt.Errorf("store is closed")
+		return fmt.Errorf("store is closed")
 	}
 	s.mu.RUnlock()
 
 	query := `
-		SELECT COUNT(*) FROM idempotency_keys WHERE key_value = ?
+		INSERT INTO workflow_forks (run_id, parent_run_id, parent_step, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(run_id) DO UPDATE SET
+			parent_run_id = excluded.parent_run_id,
+			parent_step = excluded.parent_step,
+			created_at = excluded.created_at
 	`
 
-	var count int
-	err := s.db.QueryRowContext(ctx, query, key).Scan(&count)
+	_, err := s.db.ExecContext(ctx, query, fork.RunID, fork.ParentRunID, fork.ParentStep, fork.CreatedAt.Format(time.RFC3339Nano))
 	if err != nil {
-		return false, fmt.Errorf("failed to check idempotency: %w", err)
+		return fmt.Errorf("failed to save fork: %w", err)
 	}
 
-	return count > 0, nil
+	return nil
 }
 
-// PendingEvents retrieves events from the outbox that haven't been emitted yet.
+// LoadFork returns the lineage of a forked run (implements ForkStore).
 //
-// Returns events where emitted_at IS NULL, ordered by created_at.
-// Limited to the specified number of events for batching.
-func (s *SQLiteStore[S]) PendingEvents(ctx context.Context, limit int) ([]emit.Event, error) {
+// Returns ErrNotFound if the run is not a fork.
+func (s *SQLiteStore[S]) LoadFork(ctx context.Context, runID string) (ForkRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return ForkRecord{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT run_id, parent_run_id, parent_step, created_at
+		FROM workflow_forks
+		WHERE run_id = ?
+	`
+
+	fork, err := scanFork(s.db.QueryRowContext(ctx, query, runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ForkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return ForkRecord{}, fmt.Errorf("failed to load fork: %w", err)
+	}
+
+	return fork, nil
+}
+
+// ListForks returns the runs forked from parentRunID (implements ForkStore).
+func (s *SQLiteStore[S]) ListForks(ctx context.Context, parentRunID string) ([]ForkRecord, error) {
 	s.mu.RLock()
 	if s.closed {
 		s.mu.RUnlock()
@@ -608,51 +984,53 @@ func (s *SQLiteStore[S]) PendingEvents(ctx context.Context, limit int) ([]emit.E
 	s.mu.RUnlock()
 
 	query := `
-		SELECT id, run_id, event_data
-		FROM events_outbox
-		WHERE emitted_at IS NULL
-		ORDER BY created_at ASC
-		LIMIT ?
+		SELECT run_id, parent_run_id, parent_step, created_at
+		FROM workflow_forks
+		WHERE parent_run_id = ?
+		ORDER BY parent_step ASC, created_at ASC, run_id ASC
 	`
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	rows, err := s.db.QueryContext(ctx, query, parentRunID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pending events: %w", err)
+		return nil, fmt.Errorf("failed to list forks: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var events []emit.Event
+	forks := make([]ForkRecord, 0)
 	for rows.Next() {
-		var (
-			id        string
-			runID     string
-			eventJSON string
-		)
-
-		if err := rows.Scan(&id, &runID, &eventJSON); err != nil {
-			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		fork, err := scanFork(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fork: %w", err)
 		}
+		forks = append(forks, fork)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list forks: %w", err)
+	}
 
-		var event emit.Event
-		if err := json.Unmarshal([]byte(eventJSON), &event); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
-		}
+	return forks, nil
+}
 
-		events = append(events, event)
+// scanFork reads a workflow_forks row whose created_at is stored as RFC 3339 text.
+func scanFork(row rowScanner) (ForkRecord, error) {
+	var fork ForkRecord
+	var createdAt string
+	if err := row.Scan(&fork.RunID, &fork.ParentRunID, &fork.ParentStep, &createdAt); err != nil {
+		return ForkRecord{}, err
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating event rows: %w", err)
+	var err error
+	fork.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return ForkRecord{}, fmt.Errorf("failed to parse created_at: %w", err)
 	}
-
-	return events, nil
+	return fork, nil
 }
 
-// MarkEventsEmitted marks events as successfully emitted to prevent re-delivery.
+// StartRun records that owner is executing a run (implements RunTracker).
 //
-// Updates the emitted_at timestamp for the specified event IDs.
-// This ensures the events won't be returned by PendingEvents again.
-func (s *SQLiteStore[S]) MarkEventsEmitted(ctx context.Context, eventIDs []string) error {
+// Runs are stored in the workflow_runs table, keyed by run ID.
+func (s *SQLiteStore[S]) StartRun(ctx context.Context, runID, owner string, leaseUntil time.Time) error {
 	s.mu.RLock()
 	if s.closed {
 		s.mu.RUnlock()
@@ -660,13 +1038,573 @@ func (s *SQLiteStore[S]) MarkEventsEmitted(ctx context.Context, eventIDs []strin
 	}
 	s.mu.RUnlock()
 
-	if len(eventIDs) == 0 {
-		return nil // No-op for empty list
+	query := `
+		INSERT INTO workflow_runs (run_id, status, owner, lease_expires_at, error, started_at, updated_at)
+		VALUES (?, ?, ?, ?, '', ?, ?)
+		ON CONFLICT(run_id) DO UPDATE SET
+			status = excluded.status,
+			owner = excluded.owner,
+			lease_expires_at = excluded.lease_expires_at,
+			error = '',
+			updated_at = excluded.updated_at
+	`
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err := s.db.ExecContext(ctx, query, runID, RunRunning, owner, leaseMillis(leaseUntil), now, now); err != nil {
+		return fmt.Errorf("failed to start run: %w", err)
 	}
 
-	// Build IN clause with placeholders
-	placeholders := ""
-	args := make([]interface{}, len(eventIDs))
+	return nil
+}
+
+// RenewLease extends owner's lease on a running run (implements RunTracker).
+func (s *SQLiteStore[S]) RenewLease(ctx context.Context, runID, owner string, leaseUntil time.Time) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_runs
+		SET lease_expires_at = ?, updated_at = ?
+		WHERE run_id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := s.db.ExecContext(ctx, query, leaseMillis(leaseUntil), time.Now().UTC().Format(time.RFC3339Nano), runID, RunRunning, owner)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	renewed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	if renewed == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// FinishRun records the final status of a run (implements RunTracker).
+func (s *SQLiteStore[S]) FinishRun(ctx context.Context, runID, owner string, status RunState, errMsg string) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_runs
+		SET status = ?, lease_expires_at = 0, error = ?, updated_at = ?
+		WHERE run_id = ? AND owner = ?
+	`
+
+	res, err := s.db.ExecContext(ctx, query, status, errMsg, time.Now().UTC().Format(time.RFC3339Nano), runID, owner)
+	if err != nil {
+		return fmt.Errorf("failed to finish run: %w", err)
+	}
+	finished, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to finish run: %w", err)
+	}
+	if finished == 0 {
+		// Tell a run held by another owner from one that was never started
+		if _, err := s.LoadRun(ctx, runID); err != nil {
+			return err
+		}
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// ClaimExpiredRuns transfers abandoned runs to owner (implements RunTracker).
+//
+// Runs are claimed in the order their leases expired, by a single UPDATE so
+// that processes sharing the database never claim the same run.
+func (s *SQLiteStore[S]) ClaimExpiredRuns(ctx context.Context, owner string, leaseUntil time.Time, limit int) ([]RunRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	query := `
+		UPDATE workflow_runs
+		SET owner = ?, lease_expires_at = ?, updated_at = ?
+		WHERE run_id IN (
+			SELECT run_id
+			FROM workflow_runs
+			WHERE status = ? AND lease_expires_at < ?
+			ORDER BY lease_expires_at ASC, run_id ASC
+			LIMIT ?
+		)
+		RETURNING run_id, status, owner, lease_expires_at, error, started_at, updated_at
+	`
+
+	now := time.Now()
+	rows, err := s.db.QueryContext(ctx, query, owner, leaseMillis(leaseUntil), now.UTC().Format(time.RFC3339Nano), RunRunning, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim expired runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	claimed := make([]RunRecord, 0)
+	for rows.Next() {
+		record, err := scanRunRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		claimed = append(claimed, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim expired runs: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// LoadRun returns the status record of a run (implements RunTracker).
+//
+// Returns ErrNotFound if the run was never started.
+func (s *SQLiteStore[S]) LoadRun(ctx context.Context, runID string) (RunRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return RunRecord{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT run_id, status, owner, lease_expires_at, error, started_at, updated_at
+		FROM workflow_runs
+		WHERE run_id = ?
+	`
+
+	record, err := scanRunRecord(s.db.QueryRowContext(ctx, query, runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return RunRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return RunRecord{}, fmt.Errorf("failed to load run: %w", err)
+	}
+
+	return record, nil
+}
+
+// ListRunRecords returns the status records of runs (implements RunTracker).
+func (s *SQLiteStore[S]) ListRunRecords(ctx context.Context, status RunState) ([]RunRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT run_id, status, owner, lease_expires_at, error, started_at, updated_at
+		FROM workflow_runs
+		WHERE ? = '' OR status = ?
+		ORDER BY started_at ASC, run_id ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := make([]RunRecord, 0)
+	for rows.Next() {
+		record, err := scanRunRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	return records, nil
+}
+
+// scanRunRecord reads a workflow_runs row whose timestamps are stored as
+// RFC 3339 text.
+func scanRunRecord(row rowScanner) (RunRecord, error) {
+	var record RunRecord
+	var leaseExpiresAt int64
+	var startedAt, updatedAt string
+	if err := row.Scan(&record.RunID, &record.Status, &record.Owner, &leaseExpiresAt, &record.Error, &startedAt, &updatedAt); err != nil {
+		return RunRecord{}, err
+	}
+	record.LeaseExpiresAt = leaseTime(leaseExpiresAt)
+
+	var err error
+	if record.StartedAt, err = time.Parse(time.RFC3339Nano, startedAt); err != nil {
+		return RunRecord{}, fmt.Errorf("failed to parse started_at: %w", err)
+	}
+	if record.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+		return RunRecord{}, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	return record, nil
+}
+
+// PushWork queues a work item (implements WorkQueue).
+//
+// Work items are stored in the workflow_work table, keyed by their ID and
+// leased in the order of their seq column.
+func (s *SQLiteStore[S]) PushWork(ctx context.Context, work WorkRecord) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_work (id, run_id, node_id, item, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING
+	`
+
+	_, err := s.db.ExecContext(ctx, query, work.ID, work.RunID, work.NodeID, string(work.Item), WorkPending, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to push work: %w", err)
+	}
+
+	return nil
+}
+
+// LeaseWork hands owner the next available work item (implements WorkQueue).
+//
+// The item is leased by a single UPDATE, so that processes sharing the
+// database never lease the same item.
+func (s *SQLiteStore[S]) LeaseWork(ctx context.Context, owner string, leaseUntil time.Time) (WorkRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return WorkRecord{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_work
+		SET status = ?, owner = ?, lease_expires_at = ?
+		WHERE seq = (
+			SELECT seq
+			FROM workflow_work
+			WHERE status = ? OR (status = ? AND lease_expires_at < ?)
+			ORDER BY seq ASC
+			LIMIT 1
+		)
+		RETURNING id, run_id, node_id, item, status, owner, lease_expires_at, result, created_at
+	`
+
+	row := s.db.QueryRowContext(ctx, query, WorkLeased, owner, leaseMillis(leaseUntil), WorkPending, WorkLeased, time.Now().UnixMilli())
+	work, err := scanWork(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return WorkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to lease work: %w", err)
+	}
+
+	return work, nil
+}
+
+// RenewWork extends owner's lease on a work item (implements WorkQueue).
+func (s *SQLiteStore[S]) RenewWork(ctx context.Context, id, owner string, leaseUntil time.Time) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_work
+		SET lease_expires_at = ?
+		WHERE id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := s.db.ExecContext(ctx, query, leaseMillis(leaseUntil), id, WorkLeased, owner)
+	if err != nil {
+		return fmt.Errorf("failed to renew work lease: %w", err)
+	}
+	renewed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to renew work lease: %w", err)
+	}
+	if renewed == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// CompleteWork commits the result of a work item (implements WorkQueue).
+func (s *SQLiteStore[S]) CompleteWork(ctx context.Context, id, owner string, result []byte) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		UPDATE workflow_work
+		SET status = ?, lease_expires_at = 0, result = ?
+		WHERE id = ? AND status = ? AND owner = ?
+	`
+
+	res, err := s.db.ExecContext(ctx, query, WorkDone, string(result), id, WorkLeased, owner)
+	if err != nil {
+		return fmt.Errorf("failed to complete work: %w", err)
+	}
+	completed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to complete work: %w", err)
+	}
+	if completed == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// LoadWork returns a queued work item (implements WorkQueue).
+func (s *SQLiteStore[S]) LoadWork(ctx context.Context, id string) (WorkRecord, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return WorkRecord{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT id, run_id, node_id, item, status, owner, lease_expires_at, result, created_at
+		FROM workflow_work
+		WHERE id = ?
+	`
+
+	work, err := scanWork(s.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return WorkRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to load work: %w", err)
+	}
+
+	return work, nil
+}
+
+// DeleteWork removes the work items of a run (implements WorkQueue).
+func (s *SQLiteStore[S]) DeleteWork(ctx context.Context, runID string) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM workflow_work WHERE run_id = ?", runID); err != nil {
+		return fmt.Errorf("failed to delete work: %w", err)
+	}
+
+	return nil
+}
+
+// LoadCachedResult returns a cached node result (implements ResultCache).
+//
+// Cached results are stored in the workflow_cache table. An expired result
+// is deleted when it is looked up.
+func (s *SQLiteStore[S]) LoadCachedResult(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	var value string
+	var expiresAt int64
+	err := s.db.QueryRowContext(ctx, "SELECT value, expires_at FROM workflow_cache WHERE cache_key = ?", key).Scan(&value, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cached result: %w", err)
+	}
+
+	if expiresAt != 0 && expiresAt <= leaseMillis(time.Now()) {
+		_, err := s.db.ExecContext(ctx, "DELETE FROM workflow_cache WHERE cache_key = ? AND expires_at = ?", key, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete expired result: %w", err)
+		}
+		return nil, ErrNotFound
+	}
+	return []byte(value), nil
+}
+
+// SaveCachedResult caches a node result (implements ResultCache).
+func (s *SQLiteStore[S]) SaveCachedResult(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		INSERT INTO workflow_cache (cache_key, value, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(cache_key) DO UPDATE SET
+			value = excluded.value,
+			expires_at = excluded.expires_at,
+			created_at = excluded.created_at
+	`
+
+	_, err := s.db.ExecContext(ctx, query, key, string(value), leaseMillis(expiresAt), time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to save cached result: %w", err)
+	}
+
+	return nil
+}
+
+// scanWork reads a workflow_work row whose created_at is stored as RFC 3339
+// text.
+func scanWork(row rowScanner) (WorkRecord, error) {
+	var work WorkRecord
+	var item string
+	var result sql.NullString
+	var leaseExpiresAt int64
+	var createdAt string
+	if err := row.Scan(&work.ID, &work.RunID, &work.NodeID, &item, &work.Status, &work.Owner, &leaseExpiresAt, &result, &createdAt); err != nil {
+		return WorkRecord{}, err
+	}
+	work.Item = []byte(item)
+	if result.Valid {
+		work.Result = []byte(result.String)
+	}
+	work.LeaseExpiresAt = leaseTime(leaseExpiresAt)
+
+	var err error
+	work.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return WorkRecord{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return work, nil
+}
+
+// CheckIdempotency verifies if an idempotency key has been used.
+//
+// Returns true if the key exists in the idempotency_keys table.
+// Returns false if the key doesn't exist (safe to use).
+// Returns error only on database access failures.
+//
+// This uses a unique constraint on the key for race-safe duplicate detection.
+func (s *SQLiteStore[S]) CheckIdempotency(ctx context.Context, key string) (bool, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return false, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT COUNT(*) FROM idempotency_keys WHERE key_value = ?
+	`
+
+	var count int
+	err := s.db.QueryRowContext(ctx, query, key).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// PendingEvents retrieves events from the outbox that haven't been emitted yet.
+//
+// Returns events where emitted_at IS NULL, ordered by created_at.
+// Limited to the specified number of events for batching.
+func (s *SQLiteStore[S]) PendingEvents(ctx context.Context, limit int) ([]emit.Event, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	query := `
+		SELECT id, run_id, event_data
+		FROM events_outbox
+		WHERE emitted_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []emit.Event
+	for rows.Next() {
+		var (
+			id        string
+			runID     string
+			eventJSON string
+		)
+
+		if err := rows.Scan(&id, &runID, &eventJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		}
+
+		var event emit.Event
+		if err := json.Unmarshal([]byte(eventJSON), &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkEventsEmitted marks events as successfully emitted to prevent re-delivery.
+//
+// Updates the emitted_at timestamp for the specified event IDs.
+// This ensures the events won't be returned by PendingEvents again.
+func (s *SQLiteStore[S]) MarkEventsEmitted(ctx context.Context, eventIDs []string) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	if len(eventIDs) == 0 {
+		return nil // No-op for empty list
+	}
+
+	// Build IN clause with placeholders
+	placeholders := ""
+	args := make([]interface{}, len(eventIDs))
 	for i, id := range eventIDs {
 		if i > 0 {
 			placeholders += ", "
@@ -690,6 +1628,171 @@ func (s *SQLiteStore[S]) MarkEventsEmitted(ctx context.Context, eventIDs []strin
 	return nil
 }
 
+// Prune deletes the history policy does not retain in one transaction
+// (implements Pruner).
+//
+// Ages are measured from the created_at column of each row, and a completed
+// run's age from the updated_at of its status record, the creation of its
+// latest checkpoint or the creation of its last step (see
+// RetentionPolicy.CompletedRunTTL).
+func (s *SQLiteStore[S]) Prune(ctx context.Context, policy RetentionPolicy) (PruneResult, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return PruneResult{}, fmt.Errorf("store is closed")
+	}
+	s.mu.RUnlock()
+
+	var result PruneResult
+	now := time.Now()
+	cutoff := func(ttl time.Duration) string {
+		return now.Add(-ttl).UTC().Format(time.DateTime)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // No-op once committed
+
+	deleteRows := func(count *int, query string, args ...interface{}) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		*count += int(n)
+		return err
+	}
+
+	if policy.CompletedRunTTL > 0 {
+		// A run's status record tells whether it completed; without one its
+		// latest checkpoint does, and a run saved without checkpoints
+		// finished with its last step
+		rows, err := tx.QueryContext(ctx, `
+			SELECT run_id
+			FROM workflow_checkpoints_v2 latest
+			WHERE step_id = (SELECT MAX(step_id) FROM workflow_checkpoints_v2 WHERE run_id = latest.run_id)
+				AND frontier IN ('[]', 'null')
+				AND created_at < ?
+				AND run_id NOT IN (SELECT run_id FROM workflow_runs)
+			UNION
+			SELECT run_id
+			FROM workflow_steps
+			WHERE run_id NOT IN (SELECT run_id FROM workflow_runs)
+				AND run_id NOT IN (SELECT run_id FROM workflow_checkpoints_v2)
+			GROUP BY run_id
+			HAVING MAX(created_at) < ?
+		`, cutoff(policy.CompletedRunTTL), cutoff(policy.CompletedRunTTL))
+		if err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+		var runIDs []string
+		for rows.Next() {
+			var runID string
+			if err := rows.Scan(&runID); err != nil {
+				_ = rows.Close()
+				return result, fmt.Errorf("failed to scan run ID: %w", err)
+			}
+			runIDs = append(runIDs, runID)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+
+		// Status records keep RFC 3339 times, which are compared once parsed
+		rows, err = tx.QueryContext(ctx, "SELECT run_id, updated_at FROM workflow_runs WHERE status = ?", RunCompleted)
+		if err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+		for rows.Next() {
+			var runID, updatedAt string
+			if err := rows.Scan(&runID, &updatedAt); err != nil {
+				_ = rows.Close()
+				return result, fmt.Errorf("failed to scan run record: %w", err)
+			}
+			completedAt, err := time.Parse(time.RFC3339Nano, updatedAt)
+			if err != nil {
+				_ = rows.Close()
+				return result, fmt.Errorf("failed to parse updated_at: %w", err)
+			}
+			if completedAt.Before(now.Add(-policy.CompletedRunTTL)) {
+				runIDs = append(runIDs, runID)
+			}
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("failed to find completed runs: %w", err)
+		}
+
+		var records int
+		for _, runID := range runIDs {
+			if err := deleteRows(&result.Steps, "DELETE FROM workflow_steps WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete steps of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&result.Checkpoints, "DELETE FROM workflow_checkpoints_v2 WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete checkpoints of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&records, "DELETE FROM workflow_forks WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete fork record of run %s: %w", runID, err)
+			}
+			if err := deleteRows(&records, "DELETE FROM workflow_runs WHERE run_id = ?", runID); err != nil {
+				return result, fmt.Errorf("failed to delete run record of run %s: %w", runID, err)
+			}
+		}
+		result.Runs = len(runIDs)
+	}
+
+	if policy.KeepLastSteps > 0 {
+		query := `
+			DELETE FROM workflow_steps WHERE id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY run_id ORDER BY step DESC) AS position
+					FROM workflow_steps
+				) WHERE position > ?
+			)
+		`
+		if err := deleteRows(&result.Steps, query, policy.KeepLastSteps); err != nil {
+			return result, fmt.Errorf("failed to prune steps: %w", err)
+		}
+	}
+
+	if policy.LabeledCheckpointsOnly {
+		query := `
+			DELETE FROM workflow_checkpoints_v2
+			WHERE COALESCE(label, '') = ''
+				AND step_id < (
+					SELECT MAX(step_id) FROM workflow_checkpoints_v2 latest
+					WHERE latest.run_id = workflow_checkpoints_v2.run_id
+				)
+		`
+		if err := deleteRows(&result.Checkpoints, query); err != nil {
+			return result, fmt.Errorf("failed to compact checkpoints: %w", err)
+		}
+	}
+
+	if policy.IdempotencyKeyTTL > 0 {
+		query := "DELETE FROM idempotency_keys WHERE created_at < ?"
+		if err := deleteRows(&result.IdempotencyKeys, query, cutoff(policy.IdempotencyKeyTTL)); err != nil {
+			return result, fmt.Errorf("failed to expire idempotency keys: %w", err)
+		}
+	}
+
+	if policy.EmittedEventTTL > 0 {
+		query := "DELETE FROM events_outbox WHERE emitted_at IS NOT NULL AND emitted_at < ?"
+		if err := deleteRows(&result.Events, query, cutoff(policy.EmittedEventTTL)); err != nil {
+			return result, fmt.Errorf("failed to purge emitted events: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PruneResult{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
 // Close closes the database connection.
 //
 // After Close, all operations will return an error.
@@ -105,6 +105,113 @@ func TestSQLiteStore_SaveLoadStep(t *testing.T) {
 	}
 }
 
+// TestSQLiteStore_ListSteps verifies a run's step history is listed in step order.
+func TestSQLiteStore_ListSteps(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	defer func() { _ = store.Close() }()
+
+	if _, err := store.ListSteps(ctx, "run-001", Page{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown run, got %v", err)
+	}
+
+	_ = store.SaveStep(ctx, "run-001", 2, "node-b", TestState{Value: "second", Counter: 2})
+	_ = store.SaveStep(ctx, "run-001", 1, "node-a", TestState{Value: "first", Counter: 1})
+
+	steps, err := store.ListSteps(ctx, "run-001", Page{})
+	if err != nil {
+		t.Fatalf("ListSteps failed: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+	if steps[0].NodeID != "node-a" || steps[1].NodeID != "node-b" || steps[1].State.Counter != 2 {
+		t.Errorf("unexpected steps: %+v", steps)
+	}
+}
+
+// TestSQLiteStore_Forks verifies fork lineage is saved and listed by parent.
+func TestSQLiteStore_Forks(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	defer func() { _ = store.Close() }()
+
+	if _, err := store.LoadFork(ctx, "run-001"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a run that is not a fork, got %v", err)
+	}
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = store.SaveFork(ctx, ForkRecord{RunID: "fork-b", ParentRunID: "run-001", ParentStep: 3, CreatedAt: created})
+	_ = store.SaveFork(ctx, ForkRecord{RunID: "fork-a", ParentRunID: "run-001", ParentStep: 0, CreatedAt: created})
+	if err := store.SaveFork(ctx, ForkRecord{RunID: "fork-a", ParentRunID: "run-001", ParentStep: 1, CreatedAt: created}); err != nil {
+		t.Fatalf("SaveFork over an existing fork failed: %v", err)
+	}
+
+	fork, err := store.LoadFork(ctx, "fork-a")
+	if err != nil {
+		t.Fatalf("LoadFork failed: %v", err)
+	}
+	if fork.ParentRunID != "run-001" || fork.ParentStep != 1 || !fork.CreatedAt.Equal(created) {
+		t.Errorf("unexpected fork: %+v", fork)
+	}
+
+	forks, err := store.ListForks(ctx, "run-001")
+	if err != nil {
+		t.Fatalf("ListForks failed: %v", err)
+	}
+	if len(forks) != 2 || forks[0].RunID != "fork-a" || forks[1].RunID != "fork-b" {
+		t.Errorf("expected forks ordered by parent step, got %+v", forks)
+	}
+	if forks, err := store.ListForks(ctx, "run-002"); err != nil || len(forks) != 0 {
+		t.Errorf("ListForks of a run without forks = %+v, %v; want empty", forks, err)
+	}
+}
+
+// TestSQLiteStore_Prune verifies retention policies delete old history.
+func TestSQLiteStore_Prune(t *testing.T) {
+	newStore := func(t *testing.T) pruneTestStore {
+		store := newTestSQLiteStore(t)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+	age := func(st pruneTestStore, runIDs ...string) {
+		db := st.(*SQLiteStore[TestState]).db
+		for _, runID := range runIDs {
+			_, _ = db.Exec("UPDATE workflow_steps SET created_at = datetime('now', '-2 hours') WHERE run_id = ?", runID)
+			_, _ = db.Exec("UPDATE workflow_checkpoints_v2 SET created_at = datetime('now', '-2 hours') WHERE run_id = ?", runID)
+			_, _ = db.Exec("UPDATE idempotency_keys SET created_at = datetime('now', '-2 hours') WHERE key_value LIKE ?", runID+"-%")
+			_, _ = db.Exec("UPDATE workflow_runs SET updated_at = ? WHERE run_id = ?", time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339Nano), runID)
+		}
+	}
+	runPruneTests(t, newStore, age)
+
+	t.Run("emitted event TTL keeps pending and recent events", func(t *testing.T) {
+		ctx := context.Background()
+		store := newTestSQLiteStore(t)
+		defer func() { _ = store.Close() }()
+		insert := `
+			INSERT INTO events_outbox (id, run_id, event_data, emitted_at)
+			VALUES (?, 'run-001', '{}', datetime('now', ?))
+		`
+		_, _ = store.db.ExecContext(ctx, insert, "evt-old", "-2 hours")
+		_, _ = store.db.ExecContext(ctx, insert, "evt-recent", "-1 minute")
+		_, _ = store.db.ExecContext(ctx, "INSERT INTO events_outbox (id, run_id, event_data) VALUES ('evt-pending', 'run-001', '{}')")
+
+		result, err := store.Prune(ctx, RetentionPolicy{EmittedEventTTL: time.Hour})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result != (PruneResult{Events: 1}) {
+			t.Errorf("Prune = %+v, want one event deleted", result)
+		}
+		var remaining int
+		_ = store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events_outbox").Scan(&remaining)
+		if remaining != 2 {
+			t.Errorf("expected 2 events left, got %d", remaining)
+		}
+	})
+}
+
 // TestSQLiteStore_CheckpointV2 verifies SaveCheckpointV2 and LoadCheckpointV2 (T058, T073).
 func TestSQLiteStore_CheckpointV2(t *testing.T) {
 	ctx := context.Background()
@@ -674,6 +781,8 @@ func TestSQLiteStore_ClosedStoreErrors(t *testing.T) {
 // TestSQLiteStore_InterfaceCompliance verifies SQLiteStore implements Store interface.
 func TestSQLiteStore_InterfaceCompliance(_ *testing.T) {
 	var _ Store[TestState] = (*SQLiteStore[TestState])(nil)
+	var _ ForkStore = (*SQLiteStore[TestState])(nil)
+	var _ Pruner = (*SQLiteStore[TestState])(nil)
 }
 
 // newTestSQLiteStore creates an in-memory SQLite store for testing.
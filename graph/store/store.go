@@ -12,6 +12,11 @@ import (
 // ErrNotFound is returned when a requested run ID or checkpoint ID does not exist.
 var ErrNotFound = errors.New("not found")
 
+// ErrLeaseLost is returned by RunTracker when a run is no longer held by the
+// owner renewing or finishing it, because its lease expired and another owner
+// claimed it or because it already finished.
+var ErrLeaseLost = errors.New("lease lost")
+
 // Store provides persistence for workflow state and checkpoints.
 //
 // It enables:
@@ -165,6 +170,98 @@ type Store[S any] interface {
 	MarkEventsEmitted(ctx context.Context, eventIDs []string) error
 }
 
+// StepLister is implemented by stores that can list a run's step history.
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type StepLister[S any] interface {
+	// ListSteps returns a page of the steps saved for a run, ordered by step
+	// number. The zero Page returns every step.
+	//
+	// Returns ErrNotFound if the run has no steps, and an empty slice for a
+	// page past the last step.
+	ListSteps(ctx context.Context, runID string, page Page) ([]StepRecord[S], error)
+}
+
+// HistoryStore is implemented by stores that can query the history of the
+// runs they hold: which runs exist, the node and state of each step, and the
+// checkpoints saved along the way.
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type HistoryStore[S any] interface {
+	StepLister[S]
+
+	// ListRuns returns the runs with saved steps or checkpoints that match
+	// filter, most recently updated first (ties ordered by run ID).
+	// Returns an empty slice if no run matches.
+	ListRuns(ctx context.Context, filter RunFilter) ([]RunSummary, error)
+
+	// GetStep returns the step saved for a run at the given step number.
+	//
+	// Returns ErrNotFound if the run has no such step.
+	GetStep(ctx context.Context, runID string, step int) (StepRecord[S], error)
+
+	// ListCheckpoints returns the CheckpointV2 saved for a run, ordered by
+	// step ID. A non-empty label returns only the checkpoints with that label.
+	// Returns an empty slice if the run has no matching checkpoints.
+	ListCheckpoints(ctx context.Context, runID string, label string) ([]CheckpointV2[S], error)
+}
+
+// Page selects a window of a listing: at most Limit items after skipping the
+// first Offset. A zero Limit means no limit, so the zero Page selects every
+// item. Negative values are treated as zero.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// bounds returns the slice bounds of the page within n items.
+func (p Page) bounds(n int) (start, end int) {
+	start = min(max(p.Offset, 0), n)
+	end = n
+	if p.Limit > 0 {
+		end = min(start+p.Limit, n)
+	}
+	return start, end
+}
+
+// RunFilter selects the runs returned by HistoryStore.ListRuns.
+type RunFilter struct {
+	// Prefix keeps only run IDs that start with it (compared byte for byte).
+	Prefix string
+
+	// UpdatedAfter and UpdatedBefore keep only runs last updated at or after
+	// UpdatedAfter and before UpdatedBefore. Zero values leave the window
+	// open. SQL stores keep timestamps to the second.
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// Page selects a window of the matching runs.
+	Page Page
+}
+
+// RunSummary describes a run held by a HistoryStore.
+type RunSummary struct {
+	// RunID identifies the run.
+	RunID string
+
+	// Steps is the number of steps saved with SaveStep. Runs executed in
+	// concurrent mode save checkpoints only.
+	Steps int
+
+	// Checkpoints is the number of CheckpointV2 saved for the run.
+	Checkpoints int
+
+	// LastStep is the highest step number of any saved step or checkpoint.
+	LastStep int
+
+	// StartedAt and UpdatedAt are when the first and the latest step or
+	// checkpoint were saved.
+	StartedAt time.Time
+	UpdatedAt time.Time
+}
+
 // StepRecord represents a single execution step in the workflow history.
 // Used internally by Store implementations to track step-by-step progression.
 type StepRecord[S any] struct {
@@ -176,6 +273,328 @@ type StepRecord[S any] struct {
 
 	// State is the workflow state after this step completed.
 	State S
+
+	// CreatedAt records when the step was saved.
+	CreatedAt time.Time
+}
+
+// ForkStore is implemented by stores that record the lineage of forked runs
+// (see Engine.Fork).
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type ForkStore interface {
+	// SaveFork records that a run was forked from a step of another run.
+	// Saving a record for the same RunID again replaces it.
+	SaveFork(ctx context.Context, fork ForkRecord) error
+
+	// LoadFork returns the lineage of a forked run.
+	//
+	// Returns ErrNotFound if the run is not a fork.
+	LoadFork(ctx context.Context, runID string) (ForkRecord, error)
+
+	// ListForks returns the runs forked from parentRunID, ordered by the step
+	// they were forked at and then by creation time. Returns an empty slice if
+	// the run has no forks.
+	ListForks(ctx context.Context, parentRunID string) ([]ForkRecord, error)
+}
+
+// CheckpointClaimer is implemented by stores that can claim a named
+// checkpoint atomically (see Engine.Resume, which claims the checkpoint of a
+// paused run so that the run is resumed once).
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type CheckpointClaimer interface {
+	// ClaimCheckpoint changes the step of the named checkpoint cpID from step
+	// to claimed, keeping its state, and reports whether it did. Of several
+	// callers claiming the same step, including callers in other processes
+	// sharing the store, one only succeeds.
+	//
+	// Returns false and no error if the checkpoint does not exist or is not
+	// at step.
+	ClaimCheckpoint(ctx context.Context, cpID string, step, claimed int) (bool, error)
+}
+
+// ForkRecord records where a forked run branched off its parent.
+type ForkRecord struct {
+	// RunID identifies the forked run.
+	RunID string `json:"run_id"`
+
+	// ParentRunID identifies the run it was forked from.
+	ParentRunID string `json:"parent_run_id"`
+
+	// ParentStep is the step of the parent run the fork continues from.
+	ParentStep int `json:"parent_step"`
+
+	// CreatedAt records when the fork was made.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RunTracker is implemented by stores that keep a status record of each run
+// and a lease on the runs being executed (see Engine.RecoverIncomplete).
+//
+// An owner holds a running run until its lease expires. Owners renew their
+// leases while they execute a run; a run whose lease expired was abandoned,
+// for example by a crashed process, and can be claimed by another owner.
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type RunTracker interface {
+	// StartRun records that owner is executing runID and holds its lease until
+	// leaseUntil. A run that is started again keeps its StartedAt.
+	StartRun(ctx context.Context, runID, owner string, leaseUntil time.Time) error
+
+	// RenewLease extends owner's lease on a running run until leaseUntil. A
+	// time in the past releases the lease so the run can be claimed at once.
+	//
+	// Returns ErrLeaseLost if the run is not running or another owner holds it.
+	RenewLease(ctx context.Context, runID, owner string, leaseUntil time.Time) error
+
+	// FinishRun records the final status of a run and clears its lease. errMsg
+	// describes the error of a RunFailed run. Finishing a run again replaces
+	// its status.
+	//
+	// Returns ErrLeaseLost if another owner holds the run, or ErrNotFound if
+	// the run was never started.
+	FinishRun(ctx context.Context, runID, owner string, status RunState, errMsg string) error
+
+	// ClaimExpiredRuns transfers up to limit running runs whose lease expired
+	// to owner, holding them until leaseUntil, and returns their records. A
+	// limit of 0 claims every expired run. Each run is claimed by one caller
+	// only, including callers in other processes sharing the store. Records
+	// are returned in no particular order.
+	ClaimExpiredRuns(ctx context.Context, owner string, leaseUntil time.Time, limit int) ([]RunRecord, error)
+
+	// LoadRun returns the status record of a run.
+	//
+	// Returns ErrNotFound if the run was never started.
+	LoadRun(ctx context.Context, runID string) (RunRecord, error)
+
+	// ListRunRecords returns the records of runs with status, or of all runs
+	// when status is empty, ordered by StartedAt and then RunID.
+	ListRunRecords(ctx context.Context, status RunState) ([]RunRecord, error)
+}
+
+// RunState is the status of a run recorded by RunTracker.
+type RunState string
+
+const (
+	// RunRunning marks a run that an owner is executing, or that was
+	// abandoned if its lease expired.
+	RunRunning RunState = "running"
+
+	// RunCompleted marks a run that reached a Stop route.
+	RunCompleted RunState = "completed"
+
+	// RunFailed marks a run that stopped with an error.
+	RunFailed RunState = "failed"
+
+	// RunInterrupted marks a run paused by an interrupt, waiting to be resumed.
+	RunInterrupted RunState = "interrupted"
+
+	// RunCancelled marks a run stopped by Engine.Cancel.
+	RunCancelled RunState = "cancelled"
+)
+
+// RunRecord is the status record RunTracker keeps for a run.
+type RunRecord struct {
+	// RunID identifies the run.
+	RunID string `json:"run_id"`
+
+	// Status is the current status of the run.
+	Status RunState `json:"status"`
+
+	// Owner identifies the process that executes, or last executed, the run.
+	Owner string `json:"owner"`
+
+	// LeaseExpiresAt is when Owner's lease on a running run expires. It is
+	// zero once the run finished.
+	LeaseExpiresAt time.Time `json:"lease_expires_at"`
+
+	// Error describes the error a failed run stopped with.
+	Error string `json:"error,omitempty"`
+
+	// StartedAt records when the run was first started.
+	StartedAt time.Time `json:"started_at"`
+
+	// UpdatedAt records when the record last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WorkQueue is implemented by stores that hold the work items of concurrent
+// runs for workers in other processes (see Options.WorkQueue and Engine.Work
+// in package graph).
+//
+// The process executing a run pushes each work item it schedules and waits
+// for its result; workers lease items, execute them and complete them with
+// their result. A worker holds an item until its lease expires, after which
+// another worker may lease it again. Items are identified by an idempotency
+// key, so an item is queued once and its result is committed once.
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type WorkQueue interface {
+	// PushWork queues a work item as WorkPending. Pushing an ID that is
+	// already queued, leased or done does nothing.
+	PushWork(ctx context.Context, work WorkRecord) error
+
+	// LeaseWork hands owner the longest-queued item that is pending or whose
+	// lease expired, marks it WorkLeased and holds it until leaseUntil. Each
+	// item is leased by one caller at a time, including callers in other
+	// processes sharing the store.
+	//
+	// Returns ErrNotFound if no item is available.
+	LeaseWork(ctx context.Context, owner string, leaseUntil time.Time) (WorkRecord, error)
+
+	// RenewWork extends owner's lease on an item until leaseUntil.
+	//
+	// Returns ErrLeaseLost if the item is done, deleted or leased by another
+	// owner.
+	RenewWork(ctx context.Context, id, owner string, leaseUntil time.Time) error
+
+	// CompleteWork commits the result of an item owner leased and marks it
+	// WorkDone.
+	//
+	// Returns ErrLeaseLost if the item is done, deleted or leased by another
+	// owner, so that the result of an item is committed once.
+	CompleteWork(ctx context.Context, id, owner string, result []byte) error
+
+	// LoadWork returns a work item with its status and, once done, result.
+	//
+	// Returns ErrNotFound if the item is not queued.
+	LoadWork(ctx context.Context, id string) (WorkRecord, error)
+
+	// DeleteWork removes the work items of a run in any status.
+	DeleteWork(ctx context.Context, runID string) error
+}
+
+// WorkState is the status of a work item in a WorkQueue.
+type WorkState string
+
+const (
+	// WorkPending marks an item waiting for a worker.
+	WorkPending WorkState = "pending"
+
+	// WorkLeased marks an item a worker is executing, or abandoned if its
+	// lease expired.
+	WorkLeased WorkState = "leased"
+
+	// WorkDone marks an item whose result was committed.
+	WorkDone WorkState = "done"
+)
+
+// WorkRecord is a work item held in a WorkQueue.
+type WorkRecord struct {
+	// ID is the idempotency key of the work item.
+	ID string `json:"id"`
+
+	// RunID identifies the run the item belongs to.
+	RunID string `json:"run_id"`
+
+	// NodeID is the node the item executes.
+	NodeID string `json:"node_id"`
+
+	// Item is the JSON-encoded work item.
+	Item []byte `json:"item"`
+
+	// Status is the current status of the item.
+	Status WorkState `json:"status"`
+
+	// Owner identifies the worker that leased the item last.
+	Owner string `json:"owner,omitempty"`
+
+	// LeaseExpiresAt is when Owner's lease on a leased item expires.
+	LeaseExpiresAt time.Time `json:"lease_expires_at"`
+
+	// Result is the JSON-encoded result of a done item.
+	Result []byte `json:"result,omitempty"`
+
+	// CreatedAt records when the item was pushed.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ResultCache is implemented by stores that cache node results across runs
+// (see CachePolicy in package graph). Values are opaque to the cache.
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type ResultCache interface {
+	// LoadCachedResult returns the value cached under key.
+	//
+	// Returns ErrNotFound if no value is cached or it expired. Expired values
+	// are deleted when they are looked up.
+	LoadCachedResult(ctx context.Context, key string) ([]byte, error)
+
+	// SaveCachedResult caches value under key until expiresAt, replacing any
+	// value cached before. A zero expiresAt never expires.
+	SaveCachedResult(ctx context.Context, key string, value []byte, expiresAt time.Time) error
+}
+
+// Pruner is implemented by stores that can delete history a RetentionPolicy
+// no longer retains (see Engine.Prune).
+//
+// MemStore, SQLiteStore and MySQLStore implement it. Callers should check for
+// it with a type assertion, since custom Store implementations may not.
+type Pruner interface {
+	// Prune deletes the records policy does not retain and reports how many
+	// were deleted. Records are deleted in one transaction where the store
+	// supports transactions.
+	Prune(ctx context.Context, policy RetentionPolicy) (PruneResult, error)
+}
+
+// RetentionPolicy selects the history Pruner.Prune deletes. Each rule is
+// disabled by its zero value, so the zero RetentionPolicy deletes nothing.
+type RetentionPolicy struct {
+	// KeepLastSteps keeps only the most recent N step records of each run.
+	// Engine.Fork falls back to step records between checkpoints, so pruned
+	// steps can only be forked from if a checkpoint was saved at them.
+	KeepLastSteps int
+
+	// CompletedRunTTL deletes the steps, checkpoints, fork and run records of
+	// runs that completed longer ago than this. A run with a RunTracker
+	// record is completed when its status is RunCompleted. Otherwise a run is
+	// completed when its latest checkpoint has an empty frontier, or, if it
+	// has no checkpoint, once its last step is older than the TTL.
+	//
+	// Interrupted runs, and failed runs with a status record or a checkpoint,
+	// are kept. A run that failed without either cannot be told apart from a
+	// completed one and is deleted too; track runs with RunTracker (run leases
+	// in package graph) to keep it.
+	CompletedRunTTL time.Duration
+
+	// IdempotencyKeyTTL deletes idempotency keys older than this. Once its key
+	// expires a checkpoint can be committed again, so the TTL should be longer
+	// than any run retries a step for.
+	IdempotencyKeyTTL time.Duration
+
+	// EmittedEventTTL deletes outbox events emitted longer ago than this.
+	// Pending events are never deleted.
+	EmittedEventTTL time.Duration
+
+	// LabeledCheckpointsOnly compacts checkpoint history to the checkpoints
+	// with a label, such as those saved on interrupts and forks. The latest
+	// checkpoint of each run is kept regardless so the run can be resumed.
+	LabeledCheckpointsOnly bool
+}
+
+// PruneResult counts the records deleted by Pruner.Prune.
+type PruneResult struct {
+	// Runs is the number of completed runs deleted by CompletedRunTTL. Their
+	// steps and checkpoints are included in Steps and Checkpoints.
+	Runs int `json:"runs"`
+
+	// Steps is the number of step records deleted.
+	Steps int `json:"steps"`
+
+	// Checkpoints is the number of CheckpointV2 records deleted.
+	Checkpoints int `json:"checkpoints"`
+
+	// IdempotencyKeys is the number of idempotency keys deleted.
+	IdempotencyKeys int `json:"idempotency_keys"`
+
+	// Events is the number of emitted outbox events deleted.
+	Events int `json:"events"`
 }
 
 // Checkpoint represents a named snapshot of workflow state.
@@ -228,7 +647,7 @@ type CheckpointV2[S any] struct {
 	// Computed from RunID to ensure consistent random values across replays.
 	RNGSeed int64 `json:"rng_seed"`
 
-	// RecordedIOs contains all captured external interactions up to this checkpoint.
+	// RecordedIOs contains the external interactions captured since the run's previous checkpoint.
 	// Must be JSON-serializable. Type is interface{} to avoid circular dependency.
 	// Expected to be []RecordedIO from graph package.
 	RecordedIOs interface{} `json:"recorded_ios"`
@@ -245,3 +664,27 @@ type CheckpointV2[S any] struct {
 	// Empty string for automatic checkpoints.
 	Label string `json:"label,omitempty"`
 }
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// leaseMillis encodes a lease expiry as the unix milliseconds SQL stores keep
+// in lease_expires_at, so leases compare as integers regardless of the
+// database's time zone. The zero time of a finished run is stored as 0.
+// Cached results keep their expiry the same way.
+func leaseMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// leaseTime decodes a lease_expires_at value written by leaseMillis.
+func leaseTime(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
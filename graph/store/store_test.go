@@ -4,7 +4,11 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dshills/langgraph-go/graph/emit"
 )
@@ -210,3 +214,163 @@ func TestStore_LoadCheckpoint_NotFound(t *testing.T) {
 		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
+
+// pruneTestStore is a store the retention tests can seed, prune and inspect.
+type pruneTestStore interface {
+	Store[TestState]
+	HistoryStore[TestState]
+	RunTracker
+	Pruner
+}
+
+// runPruneTests applies each retention rule to six freshly seeded runs:
+//   - "done" completed at step 4, with checkpoints at steps 0, 2, 3
+//     (labeled "interrupt") and 4
+//   - "paused" interrupted at step 2, with checkpoints at steps 0 and 2
+//   - "fresh" completed at step 1, with checkpoints at steps 0 and 1
+//   - "steps" ran 2 steps without checkpoints or a status record
+//   - "tracked" completed at step 2 with a RunCompleted status record
+//   - "failed" failed after step 2 with a RunFailed status record
+//
+// age backdates everything saved for the given runs by two hours; every run
+// but "fresh" is aged.
+func runPruneTests(t *testing.T, newStore func(*testing.T) pruneTestStore, age func(st pruneTestStore, runIDs ...string)) {
+	t.Helper()
+	ctx := context.Background()
+	const seeded = "done:4[0 2 3 4] failed:2[] fresh:1[0 1] paused:3[0 2] steps:2[] tracked:2[]"
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		want   PruneResult
+		runs   string
+	}{
+		{
+			name: "zero policy deletes nothing",
+			runs: seeded,
+		},
+		{
+			name:   "keep last steps",
+			policy: RetentionPolicy{KeepLastSteps: 2},
+			want:   PruneResult{Steps: 3},
+			runs:   "done:2[0 2 3 4] failed:2[] fresh:1[0 1] paused:2[0 2] steps:2[] tracked:2[]",
+		},
+		{
+			name:   "labeled checkpoints only keeps the latest checkpoint",
+			policy: RetentionPolicy{LabeledCheckpointsOnly: true},
+			want:   PruneResult{Checkpoints: 4},
+			runs:   "done:4[3 4] failed:2[] fresh:1[1] paused:3[2] steps:2[] tracked:2[]",
+		},
+		{
+			name:   "completed run TTL keeps recent and unfinished runs",
+			policy: RetentionPolicy{CompletedRunTTL: time.Hour},
+			want:   PruneResult{Runs: 3, Steps: 8, Checkpoints: 4},
+			runs:   "failed:2[] fresh:1[0 1] paused:3[0 2]",
+		},
+		{
+			name:   "idempotency key TTL",
+			policy: RetentionPolicy{IdempotencyKeyTTL: time.Hour},
+			want:   PruneResult{IdempotencyKeys: 6},
+			runs:   seeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newStore(t)
+			seedPruneRuns(t, st)
+			age(st, "done", "paused", "steps", "tracked", "failed")
+
+			result, err := st.Prune(ctx, tt.policy)
+			if err != nil {
+				t.Fatalf("Prune failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("Prune = %+v, want %+v", result, tt.want)
+			}
+			if runs := pruneSnapshot(t, st); runs != tt.runs {
+				t.Errorf("runs after Prune = %s, want %s", runs, tt.runs)
+			}
+
+			// Expired keys no longer block a checkpoint from being committed
+			expired := tt.policy.IdempotencyKeyTTL > 0
+			if used, _ := st.CheckIdempotency(ctx, "done-4"); used == expired {
+				t.Errorf("CheckIdempotency(done-4) = %v after Prune, want %v", used, !expired)
+			}
+			if used, _ := st.CheckIdempotency(ctx, "fresh-1"); !used {
+				t.Error("CheckIdempotency(fresh-1) = false after Prune, want the recent key kept")
+			}
+		})
+	}
+}
+
+// seedPruneRuns saves the runs described by runPruneTests.
+func seedPruneRuns(t *testing.T, st pruneTestStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	steps := map[string]int{"done": 4, "paused": 3, "fresh": 1, "steps": 2, "tracked": 2, "failed": 2}
+	for runID, last := range steps {
+		for step := 1; step <= last; step++ {
+			if err := st.SaveStep(ctx, runID, step, "node", TestState{Value: runID, Counter: step}); err != nil {
+				t.Fatalf("SaveStep failed: %v", err)
+			}
+		}
+	}
+
+	for runID, status := range map[string]RunState{"tracked": RunCompleted, "failed": RunFailed} {
+		if err := st.StartRun(ctx, runID, "owner", time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("StartRun failed: %v", err)
+		}
+		if err := st.FinishRun(ctx, runID, "owner", status, ""); err != nil {
+			t.Fatalf("FinishRun failed: %v", err)
+		}
+	}
+
+	pending := []interface{}{map[string]interface{}{"node_id": "next"}}
+	checkpoints := []CheckpointV2[TestState]{
+		{RunID: "done", StepID: 0, Frontier: pending},
+		{RunID: "done", StepID: 2, Frontier: pending},
+		{RunID: "done", StepID: 3, Frontier: pending, Label: "interrupt"},
+		{RunID: "done", StepID: 4, Frontier: []interface{}{}},
+		{RunID: "paused", StepID: 0, Frontier: pending},
+		{RunID: "paused", StepID: 2, Frontier: pending, Label: "interrupt"},
+		{RunID: "fresh", StepID: 0, Frontier: pending},
+		{RunID: "fresh", StepID: 1, Frontier: []interface{}{}},
+	}
+	for _, checkpoint := range checkpoints {
+		checkpoint.State = TestState{Value: checkpoint.RunID, Counter: checkpoint.StepID}
+		checkpoint.RecordedIOs = []interface{}{}
+		checkpoint.IdempotencyKey = fmt.Sprintf("%s-%d", checkpoint.RunID, checkpoint.StepID)
+		checkpoint.Timestamp = time.Now()
+		if err := st.SaveCheckpointV2(ctx, checkpoint); err != nil {
+			t.Fatalf("SaveCheckpointV2 failed: %v", err)
+		}
+	}
+}
+
+// pruneSnapshot summarizes each run as "run:<steps>[<checkpoint steps>]",
+// ordered by run ID.
+func pruneSnapshot(t *testing.T, st pruneTestStore) string {
+	t.Helper()
+	ctx := context.Background()
+
+	runs, err := st.ListRuns(ctx, RunFilter{})
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].RunID < runs[j].RunID })
+
+	summaries := make([]string, 0, len(runs))
+	for _, run := range runs {
+		checkpoints, err := st.ListCheckpoints(ctx, run.RunID, "")
+		if err != nil {
+			t.Fatalf("ListCheckpoints failed: %v", err)
+		}
+		stepIDs := make([]int, 0, len(checkpoints))
+		for _, checkpoint := range checkpoints {
+			stepIDs = append(stepIDs, checkpoint.StepID)
+		}
+		summaries = append(summaries, fmt.Sprintf("%s:%d%v", run.RunID, run.Steps, stepIDs))
+	}
+	return strings.Join(summaries, " ")
+}
@@ -0,0 +1,320 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// StreamMode selects which updates Engine.Stream delivers. Modes are bit flags
+// and can be combined, e.g. StreamDeltas | StreamCustom.
+type StreamMode uint8
+
+const (
+	// StreamDeltas delivers a node_end update carrying each node's delta.
+	StreamDeltas StreamMode = 1 << iota
+
+	// StreamState delivers a state update with the reduced state after each step.
+	StreamState
+
+	// StreamEvents delivers node_start, routing_decision and every other
+	// engine event (errors, join readiness, interrupts, checkpoints).
+	StreamEvents
+
+	// StreamCustom delivers values nodes publish with EmitCustom.
+	StreamCustom
+
+	// StreamAll delivers every update.
+	StreamAll = StreamDeltas | StreamState | StreamEvents | StreamCustom
+)
+
+// StreamUpdateKind identifies the type of a StreamUpdate.
+type StreamUpdateKind string
+
+const (
+	// UpdateNodeStart reports that a node began executing (StreamEvents).
+	UpdateNodeStart StreamUpdateKind = "node_start"
+
+	// UpdateNodeEnd reports that a node finished; Delta holds its result (StreamDeltas).
+	UpdateNodeEnd StreamUpdateKind = "node_end"
+
+	// UpdateState carries the reduced state after a step (StreamState).
+	UpdateState StreamUpdateKind = "state"
+
+	// UpdateRouting reports a routing decision; Meta holds the details (StreamEvents).
+	UpdateRouting StreamUpdateKind = "routing_decision"
+
+	// UpdateCustom carries a value published with EmitCustom (StreamCustom).
+	UpdateCustom StreamUpdateKind = "custom"
+
+	// UpdateEvent carries any other engine event; Msg and Meta hold it (StreamEvents).
+	UpdateEvent StreamUpdateKind = "event"
+
+	// UpdateDone is always the last update. State holds the final state and
+	// Err the error Run would have returned.
+	UpdateDone StreamUpdateKind = "done"
+)
+
+// StreamUpdate is a single update delivered by Engine.Stream.
+//
+// Only the fields relevant to Kind are set.
+type StreamUpdate[S any] struct {
+	// Kind identifies the update type.
+	Kind StreamUpdateKind
+
+	// RunID is the run the update belongs to.
+	RunID string
+
+	// NodeID is the node the update concerns (empty for run-level events).
+	NodeID string
+
+	// Step is the step number of the node execution.
+	Step int
+
+	// Delta is the node's partial state update (UpdateNodeEnd).
+	Delta S
+
+	// State is the reduced state after the step (UpdateState) or the final
+	// state of the run (UpdateDone).
+	State S
+
+	// Value is the value passed to EmitCustom (UpdateCustom).
+	Value interface{}
+
+	// Msg is the underlying event message (UpdateEvent).
+	Msg string
+
+	// Meta holds event metadata (UpdateRouting, UpdateEvent).
+	Meta map[string]interface{}
+
+	// Err is the run's terminal error, if any (UpdateDone).
+	Err error
+}
+
+// Stream executes the workflow like Run and delivers updates on the returned
+// channel while the run is in progress.
+//
+// modes selects the updates to deliver; with no modes every update is sent
+// (StreamAll). The channel always ends with an UpdateDone update holding the
+// final state and the error Run would have returned, and is then closed.
+//
+// Updates are delivered in the order each path of the run produces them. In
+// concurrent mode, updates from parallel branches interleave, and UpdateState
+// carries the state the node's successors observe (its input state with its
+// delta applied); the merged state arrives with UpdateDone.
+//
+// The run waits for the consumer, so callers must drain the channel or cancel
+// ctx. Events are still sent to the engine's emitter as usual.
+//
+// Parameters:
+//   - ctx: Context for cancellation; canceling it stops the run and the stream
+//   - runID: Unique identifier for this run; only one stream per run ID may be open
+//   - initial: Starting state for the workflow
+//   - modes: Updates to deliver (default StreamAll)
+//
+// Example:
+//
+//	for update := range engine.Stream(ctx, "run-001", initial, graph.StreamDeltas|graph.StreamCustom) {
+//	    switch update.Kind {
+//	    case graph.UpdateNodeEnd:
+//	        fmt.Printf("%s finished: %+v\n", update.NodeID, update.Delta)
+//	    case graph.UpdateCustom:
+//	        fmt.Print(update.Value)
+//	    case graph.UpdateDone:
+//	        if update.Err != nil {
+//	            log.Fatal(update.Err)
+//	        }
+//	    }
+//	}
+func (e *Engine[S]) Stream(ctx context.Context, runID string, initial S, modes ...StreamMode) <-chan StreamUpdate[S] {
+	mode := StreamMode(0)
+	for _, m := range modes {
+		mode |= m
+	}
+	if mode == 0 {
+		mode = StreamAll
+	}
+
+	sink := &streamSink[S]{
+		modes:   mode,
+		updates: make(chan StreamUpdate[S]),
+		done:    ctx.Done(),
+	}
+
+	go func() {
+		defer close(sink.updates)
+
+		final, err := e.runStream(ctx, runID, initial, sink)
+		sink.send(StreamUpdate[S]{Kind: UpdateDone, RunID: runID, State: final, Err: err})
+	}()
+
+	return sink.updates
+}
+
+// runStream runs the workflow with sink registered as runID's stream.
+func (e *Engine[S]) runStream(ctx context.Context, runID string, initial S, sink *streamSink[S]) (S, error) {
+	var zero S
+
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return zero, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+	if _, loaded := e.streams.LoadOrStore(runID, sink); loaded {
+		return zero, &EngineError{
+			Message: "run is already streaming: " + runID,
+			Code:    "STREAM_IN_USE",
+		}
+	}
+	defer e.streams.Delete(runID)
+
+	return e.Run(ctx, runID, initial)
+}
+
+// EmitCustom publishes value from inside a node. It is delivered to
+// Engine.Stream consumers as an UpdateCustom update and to the engine's
+// emitter as a "custom" event with the value under Meta["value"].
+//
+// EmitCustom reports whether ctx belongs to a running node; outside a node
+// it does nothing and returns false.
+//
+// Example:
+//
+//	func(ctx context.Context, s State) graph.NodeResult[State] {
+//	    for chunk := range tokens {
+//	        graph.EmitCustom(ctx, chunk)
+//	    }
+//	    ...
+//	}
+func EmitCustom(ctx context.Context, value interface{}) bool {
+	publish, ok := ctx.Value(customEmitterKey).(func(interface{}))
+	if !ok {
+		return false
+	}
+	publish(value)
+	return true
+}
+
+// customEmitterKey carries the function EmitCustom uses to publish a value
+// for the current node.
+const customEmitterKey contextKey = "langgraph.custom_emitter"
+
+// streamSink delivers one run's updates to its Stream consumer.
+type streamSink[S any] struct {
+	modes   StreamMode
+	updates chan StreamUpdate[S]
+	done    <-chan struct{}
+}
+
+// send delivers update unless the consumer's context has been canceled.
+func (s *streamSink[S]) send(update StreamUpdate[S]) {
+	select {
+	case s.updates <- update:
+	case <-s.done:
+	}
+}
+
+// event converts an engine event into an update if the sink's modes include it.
+func (s *streamSink[S]) event(event emit.Event) {
+	update := StreamUpdate[S]{
+		RunID:  event.RunID,
+		NodeID: event.NodeID,
+		Step:   event.Step,
+		Msg:    event.Msg,
+		Meta:   event.Meta,
+	}
+
+	var mode StreamMode
+	switch event.Msg {
+	case "node_start":
+		mode, update.Kind = StreamEvents, UpdateNodeStart
+	case "node_end":
+		// Subgraph nodes end with a delta of the child's state type
+		delta, ok := event.Meta["delta"].(S)
+		if !ok {
+			mode, update.Kind = StreamEvents, UpdateEvent
+			break
+		}
+		mode, update.Kind, update.Delta = StreamDeltas, UpdateNodeEnd, delta
+	case "routing_decision":
+		mode, update.Kind = StreamEvents, UpdateRouting
+	case "custom":
+		mode, update.Kind = StreamCustom, UpdateCustom
+		update.Value = event.Meta["value"]
+	default:
+		mode, update.Kind = StreamEvents, UpdateEvent
+	}
+
+	if s.modes&mode != 0 {
+		s.send(update)
+	}
+}
+
+// sinkFor returns the stream sink for runID, or nil if the run is not streaming.
+func (e *Engine[S]) sinkFor(runID string) *streamSink[S] {
+	sink, ok := e.streams.Load(runID)
+	if !ok {
+		return nil
+	}
+	return sink.(*streamSink[S])
+}
+
+// publish sends an event to the engine's emitter, to the run's stream and,
+// for nested runs, to the parent graph.
+func (e *Engine[S]) publish(event emit.Event) {
+	if e.emitter != nil {
+		e.emitter.Emit(event)
+	}
+	if sink := e.sinkFor(event.RunID); sink != nil {
+		sink.event(event)
+	}
+	if forward := e.parentFor(event.RunID); forward != nil {
+		forward(event)
+	}
+}
+
+// publishState delivers the reduced state after a step to the run's stream.
+//
+// The state is deep-copied so later reducer calls cannot mutate what the
+// consumer holds.
+func (e *Engine[S]) publishState(runID string, item WorkItem[S], state S) {
+	sink := e.sinkFor(runID)
+	if sink == nil || sink.modes&StreamState == 0 {
+		return
+	}
+	if copied, err := deepCopyState(state); err == nil {
+		state = copied
+	}
+	sink.send(StreamUpdate[S]{
+		Kind:   UpdateState,
+		RunID:  runID,
+		NodeID: item.NodeID,
+		Step:   item.StepID,
+		State:  state,
+	})
+}
+
+// nodeContext returns the context a work item's node runs with: the run
+// metadata keys (RunIDKey, StepIDKey, NodeIDKey, OrderKeyKey), the EmitCustom
+// and subgraph event publishers, the branch input of a node started by Sends
+// and, for the node that interrupted the run, the Resume input.
+func (e *Engine[S]) nodeContext(ctx context.Context, runID string, item WorkItem[S]) context.Context {
+	ctx = context.WithValue(ctx, RunIDKey, runID)
+	ctx = context.WithValue(ctx, StepIDKey, item.StepID)
+	ctx = context.WithValue(ctx, NodeIDKey, item.NodeID)
+	ctx = context.WithValue(ctx, OrderKeyKey, item.OrderKey)
+	ctx = context.WithValue(ctx, customEmitterKey, func(value interface{}) {
+		e.publish(emit.Event{
+			RunID:  runID,
+			Step:   item.StepID,
+			NodeID: item.NodeID,
+			Msg:    "custom",
+			Meta: map[string]interface{}{
+				"value": value,
+			},
+		})
+	})
+	ctx = context.WithValue(ctx, subgraphEventsKey, e.subgraphPublisher(runID, item))
+	ctx = withSendInput(ctx, item)
+	return withResumeInput(ctx, item)
+}
@@ -0,0 +1,239 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/model"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// newStreamTestEngine wires draft -> review: draft emits two custom chunks and
+// review stops the run.
+func newStreamTestEngine(t *testing.T, maxConcurrentNodes int) *Engine[JoinTestState] {
+	t.Helper()
+
+	engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+		MaxSteps:           20,
+		MaxConcurrentNodes: maxConcurrentNodes,
+	})
+	addJoinTestNode(t, engine, "draft", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		EmitCustom(ctx, "chunk-1")
+		EmitCustom(ctx, "chunk-2")
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"draft"}, Counter: 1}, Route: Goto("review")}
+	})
+	addJoinTestNode(t, engine, "review", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"review"}, Counter: 1}, Route: Stop()}
+	})
+	if err := engine.StartAt("draft"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+	return engine
+}
+
+// collectStream drains a stream and returns its updates.
+func collectStream(updates <-chan StreamUpdate[JoinTestState]) []StreamUpdate[JoinTestState] {
+	var all []StreamUpdate[JoinTestState]
+	for update := range updates {
+		all = append(all, update)
+	}
+	return all
+}
+
+// updateKinds returns the kinds of updates, annotated with their node ID.
+func updateKinds(updates []StreamUpdate[JoinTestState]) []string {
+	kinds := make([]string, 0, len(updates))
+	for _, u := range updates {
+		if u.NodeID == "" {
+			kinds = append(kinds, string(u.Kind))
+			continue
+		}
+		kinds = append(kinds, string(u.Kind)+":"+u.NodeID)
+	}
+	return kinds
+}
+
+// TestEngine_Stream verifies Stream delivers per-step updates while the run executes.
+func TestEngine_Stream(t *testing.T) {
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("all modes deliver every update in order", func(t *testing.T) {
+				engine := newStreamTestEngine(t, mode.maxConcurrentNodes)
+
+				updates := collectStream(engine.Stream(context.Background(), "stream-all", JoinTestState{}))
+
+				var got []string
+				for _, kind := range updateKinds(updates) {
+					// Checkpoint and other engine events vary by mode.
+					if kind != string(UpdateEvent) {
+						got = append(got, kind)
+					}
+				}
+				want := []string{
+					"node_start:draft", "custom:draft", "custom:draft", "node_end:draft", "state:draft", "routing_decision:draft",
+					"node_start:review", "node_end:review", "state:review", "routing_decision:review",
+					"done",
+				}
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatalf("update kinds =\n%v\nwant\n%v", got, want)
+				}
+
+				done := updates[len(updates)-1]
+				if done.Err != nil {
+					t.Fatalf("done Err = %v", done.Err)
+				}
+				if done.State.Counter != 2 {
+					t.Errorf("final Counter = %d, want 2", done.State.Counter)
+				}
+				for _, u := range updates {
+					switch u.Kind {
+					case UpdateNodeEnd:
+						if u.Delta.Counter != 1 {
+							t.Errorf("%s delta Counter = %d, want 1", u.NodeID, u.Delta.Counter)
+						}
+					case UpdateState:
+						if want := map[string]int{"draft": 1, "review": 2}[u.NodeID]; u.State.Counter != want {
+							t.Errorf("state after %s Counter = %d, want %d", u.NodeID, u.State.Counter, want)
+						}
+					}
+				}
+			})
+
+			t.Run("modes filter updates", func(t *testing.T) {
+				engine := newStreamTestEngine(t, mode.maxConcurrentNodes)
+
+				deltas := updateKinds(collectStream(engine.Stream(context.Background(), "stream-deltas", JoinTestState{}, StreamDeltas)))
+				if want := "[node_end:draft node_end:review done]"; fmt.Sprint(deltas) != want {
+					t.Errorf("StreamDeltas kinds = %v, want %s", deltas, want)
+				}
+
+				state := updateKinds(collectStream(engine.Stream(context.Background(), "stream-state", JoinTestState{}, StreamState)))
+				if want := "[state:draft state:review done]"; fmt.Sprint(state) != want {
+					t.Errorf("StreamState kinds = %v, want %s", state, want)
+				}
+
+				var values []interface{}
+				for _, u := range collectStream(engine.Stream(context.Background(), "stream-custom", JoinTestState{}, StreamCustom)) {
+					if u.Kind == UpdateCustom {
+						values = append(values, u.Value)
+					}
+				}
+				if fmt.Sprint(values) != "[chunk-1 chunk-2]" {
+					t.Errorf("custom values = %v, want [chunk-1 chunk-2]", values)
+				}
+			})
+
+			t.Run("done carries run error", func(t *testing.T) {
+				engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+				nodeErr := errors.New("boom")
+				addJoinTestNode(t, engine, "fail", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+					return NodeResult[JoinTestState]{Err: nodeErr}
+				})
+				if err := engine.StartAt("fail"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				updates := collectStream(engine.Stream(context.Background(), "stream-error", JoinTestState{}))
+				done := updates[len(updates)-1]
+				if done.Kind != UpdateDone || !errors.Is(done.Err, nodeErr) {
+					t.Fatalf("last update = %+v, want done with %v", done, nodeErr)
+				}
+			})
+		})
+	}
+}
+
+// TestEngine_StreamRunInUse verifies a run ID can only be streamed once at a time.
+func TestEngine_StreamRunInUse(t *testing.T) {
+	engine := newStreamTestEngine(t, 0)
+
+	// The first stream blocks on its first update until it is read.
+	first := engine.Stream(context.Background(), "stream-busy", JoinTestState{})
+	firstUpdate := <-first
+
+	second := collectStream(engine.Stream(context.Background(), "stream-busy", JoinTestState{}))
+	var engineErr *EngineError
+	if len(second) != 1 || !errors.As(second[0].Err, &engineErr) || engineErr.Code != "STREAM_IN_USE" {
+		t.Fatalf("second stream = %+v, want a single done update with STREAM_IN_USE", second)
+	}
+
+	rest := collectStream(first)
+	if firstUpdate.Kind != UpdateNodeStart || rest[len(rest)-1].Err != nil {
+		t.Errorf("first stream started with %s and ended with %v", firstUpdate.Kind, rest[len(rest)-1].Err)
+	}
+}
+
+// TestEngine_StreamChatTokens verifies LLM tokens reach the stream as they arrive.
+func TestEngine_StreamChatTokens(t *testing.T) {
+	llm := &model.MockChatModel{Responses: []model.ChatOut{{Text: "Paris is lovely"}}}
+
+	engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 20})
+	addJoinTestNode(t, engine, "answer", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		out, err := model.StreamChat(ctx, llm, []model.Message{{Role: model.RoleUser, Content: "Tell me about Paris"}}, nil, func(chunk model.ChatChunk) error {
+			EmitCustom(ctx, chunk.Text)
+			return nil
+		})
+		if err != nil {
+			return NodeResult[JoinTestState]{Err: err}
+		}
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{out.Text}}, Route: Stop()}
+	})
+	if err := engine.StartAt("answer"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+
+	var tokens []interface{}
+	var final JoinTestState
+	for update := range engine.Stream(context.Background(), "stream-tokens", JoinTestState{}, StreamCustom) {
+		switch update.Kind {
+		case UpdateCustom:
+			tokens = append(tokens, update.Value)
+		case UpdateDone:
+			if update.Err != nil {
+				t.Fatalf("run failed: %v", update.Err)
+			}
+			final = update.State
+		}
+	}
+
+	if fmt.Sprint(tokens) != "[Paris  is  lovely]" {
+		t.Errorf("tokens = %q, want one per word", tokens)
+	}
+	if len(final.Values) != 1 || final.Values[0] != "Paris is lovely" {
+		t.Errorf("final Values = %v, want the full answer", final.Values)
+	}
+}
+
+// TestNodeContext verifies nodes can read run metadata and EmitCustom outside a node is a no-op.
+func TestNodeContext(t *testing.T) {
+	t.Run("run metadata keys are set", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), &mockEmitter{}, Options{MaxSteps: 20})
+
+		var runID, nodeID interface{}
+		addJoinTestNode(t, engine, "only", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			runID, nodeID = ctx.Value(RunIDKey), ctx.Value(NodeIDKey)
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		if err := engine.StartAt("only"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if _, err := engine.Run(context.Background(), "ctx-keys", JoinTestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if runID != "ctx-keys" || nodeID != "only" {
+			t.Errorf("RunIDKey = %v, NodeIDKey = %v, want ctx-keys and only", runID, nodeID)
+		}
+	})
+
+	t.Run("EmitCustom outside a node", func(t *testing.T) {
+		if EmitCustom(context.Background(), "ignored") {
+			t.Error("EmitCustom returned true outside a node")
+		}
+	})
+}
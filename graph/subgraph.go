@@ -0,0 +1,174 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dshills/langgraph-go/graph/emit"
+)
+
+// SubgraphNode runs a child Engine as a single node of a parent graph.
+//
+// Create one with Subgraph. Each execution maps the parent state to the
+// child's state type, runs the child workflow to completion, and maps the
+// child's final state back to a delta for the parent. The node does not set a
+// route, so the parent's edges decide where execution goes next.
+//
+// Nesting is transparent to the rest of the engine:
+//   - Checkpoints: each execution runs the child under the run ID
+//     "<parent run ID>/<node ID>/<step>-<order key>" and persists its steps
+//     and checkpoints in its own store. An interrupted child is found again by
+//     its OrderKey, as sequential runs number steps anew on resume
+//   - Events: every child event is re-emitted by the parent with a namespaced
+//     node path such as "research/search/fetch" (see below)
+//   - Interrupts: if a child node interrupts, the subgraph node interrupts the
+//     parent with the same payload; resuming the parent resumes the child
+//
+// Forwarded events keep their message and metadata. RunID and Step are the
+// parent's, and Meta gains "subgraph_run_id" and "subgraph_step" with the
+// child's values. Give the child engine a nil emitter to avoid receiving its
+// events twice when parent and child share an emitter.
+//
+// A SubgraphNode can be added to several graphs, or several times to one
+// graph, and can execute many times in one run, in loops or as the target
+// of a Sends route: the child run ID includes the parent node ID and the
+// step and OrderKey of the work item, which a resumed item keeps.
+type SubgraphNode[S, T any] struct {
+	child  *Engine[T]
+	input  func(parent S) T
+	output func(parent S, child T) S
+}
+
+// Subgraph wraps child as a Node[S] for use in a parent graph.
+//
+// Parameters:
+//   - child: Engine holding the nested workflow (with its own reducer, store and start node)
+//   - input: Builds the child's initial state from the parent state
+//   - output: Builds the parent delta from the parent state and the child's final state
+//
+// Example:
+//
+//	// Reuse a "search -> summarize" flow in a research pipeline
+//	research := graph.Subgraph(searchFlow,
+//	    func(s Research) Search { return Search{Query: s.Topic} },
+//	    func(_ Research, out Search) Research { return Research{Notes: []string{out.Summary}} },
+//	)
+//	_ = pipeline.Add("research", research)
+func Subgraph[S, T any](child *Engine[T], input func(parent S) T, output func(parent S, child T) S) *SubgraphNode[S, T] {
+	return &SubgraphNode[S, T]{child: child, input: input, output: output}
+}
+
+// Run implements Node[S] by running the child workflow.
+func (n *SubgraphNode[S, T]) Run(ctx context.Context, state S) NodeResult[S] {
+	nodeID, _ := ctx.Value(NodeIDKey).(string)
+
+	if n.child == nil || n.input == nil || n.output == nil {
+		return NodeResult[S]{Err: &NodeError{
+			Message: "subgraph requires a child engine and input/output mappings",
+			Code:    "INVALID_SUBGRAPH",
+			NodeID:  nodeID,
+		}}
+	}
+
+	runID, _ := ctx.Value(RunIDKey).(string)
+	stepID, _ := ctx.Value(StepIDKey).(int)
+	orderKey, _ := ctx.Value(OrderKeyKey).(uint64)
+	pointerID := subgraphInterruptID(runID, nodeID, orderKey)
+	resumeInput, resuming := ResumeInput(ctx)
+	if resuming {
+		// Sequential runs number steps anew on resume, so the step of the
+		// interrupted child is looked up
+		if _, step, err := n.child.store.LoadCheckpoint(ctx, pointerID); err == nil {
+			stepID = step
+		}
+	}
+	childRunID := fmt.Sprintf("%s/%s/%d-%016x", runID, nodeID, stepID, orderKey)
+
+	// Re-emit child events through the parent with a namespaced node path
+	if forward, ok := ctx.Value(subgraphEventsKey).(func(emit.Event)); ok {
+		n.child.parents.Store(childRunID, forward)
+		defer n.child.parents.Delete(childRunID)
+	}
+
+	// Resume the child if it is the one that interrupted the parent
+	var final T
+	var err error
+	if resuming {
+		final, err = n.child.Resume(ctx, childRunID, resumeInput)
+	} else {
+		final, err = n.child.Run(ctx, childRunID, n.input(state))
+	}
+
+	var interrupt *InterruptError
+	if errors.As(err, &interrupt) {
+		var zero T
+		if err := n.child.store.SaveCheckpoint(ctx, pointerID, zero, stepID); err != nil {
+			return NodeResult[S]{Err: &NodeError{
+				Message: "failed to save subgraph interrupt: " + err.Error(),
+				Code:    "STORE_ERROR",
+				NodeID:  nodeID,
+				Cause:   err,
+			}}
+		}
+		return NodeResult[S]{Route: Interrupt(interrupt.Payload)}
+	}
+	if err != nil {
+		return NodeResult[S]{Err: &NodeError{
+			Message: "subgraph run failed: " + err.Error(),
+			Code:    "SUBGRAPH_FAILED",
+			NodeID:  nodeID,
+			Cause:   err,
+		}}
+	}
+
+	return NodeResult[S]{Delta: n.output(state, final)}
+}
+
+// subgraphInterruptID returns the named checkpoint, in the child's store,
+// whose step is the step of the child run that interrupted the execution of
+// nodeID with orderKey in the parent run.
+func subgraphInterruptID(runID, nodeID string, orderKey uint64) string {
+	return fmt.Sprintf("__subgraph_interrupt__:%s/%s/%016x", runID, nodeID, orderKey)
+}
+
+// subgraphEventsKey carries the function a SubgraphNode uses to re-emit its
+// child's events through the parent engine.
+const subgraphEventsKey contextKey = "langgraph.subgraph_events"
+
+// subgraphPublisher returns the function that re-emits a child run's events
+// as events of item in the parent run.
+func (e *Engine[S]) subgraphPublisher(runID string, item WorkItem[S]) func(emit.Event) {
+	return func(event emit.Event) {
+		path := item.NodeID
+		if event.NodeID != "" {
+			path += "/" + event.NodeID
+		}
+
+		meta := make(map[string]interface{}, len(event.Meta)+2)
+		for k, v := range event.Meta {
+			meta[k] = v
+		}
+		meta["subgraph_run_id"] = event.RunID
+		meta["subgraph_step"] = event.Step
+
+		e.publish(emit.Event{
+			RunID:  runID,
+			Step:   item.StepID,
+			NodeID: path,
+			Msg:    event.Msg,
+			Meta:   meta,
+		})
+	}
+}
+
+// parentFor returns the function re-emitting runID's events in a parent
+// graph, or nil if the run is not nested.
+func (e *Engine[S]) parentFor(runID string) func(emit.Event) {
+	forward, ok := e.parents.Load(runID)
+	if !ok {
+		return nil
+	}
+	return forward.(func(emit.Event))
+}
@@ -0,0 +1,307 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// ResearchState is the parent state for subgraph tests.
+type ResearchState struct {
+	Topic string
+	Notes []string
+}
+
+func researchReducer(prev, delta ResearchState) ResearchState {
+	if delta.Topic != "" {
+		prev.Topic = delta.Topic
+	}
+	prev.Notes = append(prev.Notes, delta.Notes...)
+	return prev
+}
+
+// newSearchFlow builds a child "search -> summarize" workflow over JoinTestState.
+// The search node records each query it receives in Values.
+func newSearchFlow(t *testing.T, childStore store.Store[JoinTestState]) *Engine[JoinTestState] {
+	t.Helper()
+
+	child := New(joinTestReducer, childStore, nil, Options{MaxSteps: 20})
+	addJoinTestNode(t, child, "search", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"found:" + s.Values[0]}}, Route: Goto("summarize")}
+	})
+	addJoinTestNode(t, child, "summarize", func(_ context.Context, s JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"summary of " + s.Values[0]}}, Route: Stop()}
+	})
+	if err := child.StartAt("search"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+	return child
+}
+
+// researchSubgraph maps the topic into the child and the summary back as a note.
+func researchSubgraph(child *Engine[JoinTestState]) *SubgraphNode[ResearchState, JoinTestState] {
+	return Subgraph(child,
+		func(s ResearchState) JoinTestState { return JoinTestState{Values: []string{s.Topic}} },
+		func(_ ResearchState, out JoinTestState) ResearchState {
+			return ResearchState{Notes: []string{out.Values[len(out.Values)-1]}}
+		},
+	)
+}
+
+// connectToStop routes from to a terminal "done" node, since subgraph nodes
+// leave routing to the parent's edges.
+func connectToStop[S any](t *testing.T, engine *Engine[S], from string) {
+	t.Helper()
+
+	if err := engine.Add("done", NodeFunc[S](func(_ context.Context, _ S) NodeResult[S] {
+		return NodeResult[S]{Route: Stop()}
+	})); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := engine.Connect(from, "done", nil); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+}
+
+// childRunIDs returns the distinct child run IDs in the events forwarded by
+// subgraph nodes to emitter, by subgraph node ID.
+func childRunIDs(emitter *mockEmitter) map[string][]string {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+
+	runIDs := map[string][]string{}
+	for _, event := range emitter.events {
+		childRunID, ok := event.Meta["subgraph_run_id"].(string)
+		if !ok {
+			continue
+		}
+		nodeID, _, _ := strings.Cut(event.NodeID, "/")
+		if !slices.Contains(runIDs[nodeID], childRunID) {
+			runIDs[nodeID] = append(runIDs[nodeID], childRunID)
+		}
+	}
+	return runIDs
+}
+
+// TestSubgraph verifies an Engine can run as a node of another graph.
+func TestSubgraph(t *testing.T) {
+	for _, mode := range interruptModes {
+		mode := mode
+		t.Run(mode.name, func(t *testing.T) {
+			t.Run("maps state and continues parent routing", func(t *testing.T) {
+				childStore := store.NewMemStore[JoinTestState]()
+				research := researchSubgraph(newSearchFlow(t, childStore))
+
+				emitter := &mockEmitter{}
+				parent := New(researchReducer, store.NewMemStore[ResearchState](), emitter, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+				if err := parent.Add("research", research); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+				if err := parent.Add("again", research); err != nil {
+					t.Fatalf("Add failed: %v", err)
+				}
+				_ = parent.Connect("research", "again", nil)
+				connectToStop(t, parent, "again")
+				if err := parent.StartAt("research"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				final, err := parent.Run(context.Background(), "pipeline", ResearchState{Topic: "go"})
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if len(final.Notes) != 2 || final.Notes[0] != "summary of go" || final.Notes[1] != "summary of go" {
+					t.Errorf("Notes = %v, want two summaries of go", final.Notes)
+				}
+
+				// Each use of the subgraph persists under its own namespaced run ID.
+				runIDs := childRunIDs(emitter)
+				for _, nodeID := range []string{"research", "again"} {
+					if len(runIDs[nodeID]) != 1 || !strings.HasPrefix(runIDs[nodeID][0], "pipeline/"+nodeID+"/") {
+						t.Fatalf("%s child runs = %v, want one under pipeline/%s/", nodeID, runIDs[nodeID], nodeID)
+					}
+					runID := runIDs[nodeID][0]
+					state, _, err := childStore.LoadLatest(context.Background(), runID)
+					if err != nil {
+						t.Fatalf("LoadLatest(%s) failed: %v", runID, err)
+					}
+					if len(state.Values) != 3 {
+						t.Errorf("%s child state Values = %v, want 3 entries", runID, state.Values)
+					}
+				}
+			})
+
+			t.Run("each execution runs its own child", func(t *testing.T) {
+				emitter := &mockEmitter{}
+				parent := New(researchReducer, store.NewMemStore[ResearchState](), emitter, Options{
+					MaxSteps:           20,
+					MaxConcurrentNodes: mode.maxConcurrentNodes,
+				})
+				// split sends six branches to research; the loop node runs
+				// research twice more, once on each pass
+				split := NodeFunc[ResearchState](func(_ context.Context, s ResearchState) NodeResult[ResearchState] {
+					if s.Topic == "loop" {
+						return NodeResult[ResearchState]{Route: Goto("loop")}
+					}
+					return NodeResult[ResearchState]{Route: SendEach("research", []int{1, 2, 3, 4, 5, 6})}
+				})
+				loop := NodeFunc[ResearchState](func(_ context.Context, _ ResearchState) NodeResult[ResearchState] {
+					return NodeResult[ResearchState]{Route: Goto("research")}
+				})
+				research := researchSubgraph(newSearchFlow(t, store.NewMemStore[JoinTestState]()))
+				for id, node := range map[string]Node[ResearchState]{"split": split, "loop": loop, "research": research} {
+					if err := parent.Add(id, node); err != nil {
+						t.Fatalf("Add failed: %v", err)
+					}
+				}
+				_ = parent.Connect("research", "loop", func(s ResearchState) bool { return s.Topic == "loop" && len(s.Notes) < 2 })
+				connectToStop(t, parent, "research")
+				if err := parent.StartAt("split"); err != nil {
+					t.Fatalf("StartAt failed: %v", err)
+				}
+
+				for topic, executions := range map[string]int{"fanout": 6, "loop": 2} {
+					final, err := parent.Run(context.Background(), topic, ResearchState{Topic: topic})
+					if err != nil {
+						t.Fatalf("Run failed: %v", err)
+					}
+					if len(final.Notes) != executions {
+						t.Errorf("Notes = %v, want %d summaries", final.Notes, executions)
+					}
+				}
+				if runIDs := childRunIDs(emitter)["research"]; len(runIDs) != 8 {
+					t.Errorf("child runs = %v, want one per execution of research", runIDs)
+				}
+			})
+		})
+	}
+
+	t.Run("events carry namespaced node paths", func(t *testing.T) {
+		// leaf graph: fetch
+		leaf := New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+		addJoinTestNode(t, leaf, "fetch", func(ctx context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			EmitCustom(ctx, "fetched")
+			return NodeResult[JoinTestState]{Route: Stop()}
+		})
+		_ = leaf.StartAt("fetch")
+
+		// middle graph: search runs the leaf graph
+		middle := New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+		if err := middle.Add("search", Subgraph(leaf,
+			func(s JoinTestState) JoinTestState { return s },
+			func(_ JoinTestState, out JoinTestState) JoinTestState { return out },
+		)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		connectToStop(t, middle, "search")
+		_ = middle.StartAt("search")
+
+		emitter := &mockEmitter{}
+		parent := New(researchReducer, store.NewMemStore[ResearchState](), emitter, Options{MaxSteps: 20})
+		if err := parent.Add("research", Subgraph(middle,
+			func(ResearchState) JoinTestState { return JoinTestState{} },
+			func(ResearchState, JoinTestState) ResearchState { return ResearchState{} },
+		)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		connectToStop(t, parent, "research")
+		_ = parent.StartAt("research")
+
+		var custom []string
+		for update := range parent.Stream(context.Background(), "nested", ResearchState{}, StreamCustom) {
+			if update.Kind == UpdateCustom {
+				custom = append(custom, update.NodeID)
+			}
+			if update.Kind == UpdateDone && update.Err != nil {
+				t.Fatalf("run failed: %v", update.Err)
+			}
+		}
+		if len(custom) != 1 || custom[0] != "research/search/fetch" {
+			t.Errorf("custom updates from %v, want [research/search/fetch]", custom)
+		}
+
+		var starts []string
+		for _, event := range emitter.events {
+			if event.Msg == "node_start" {
+				starts = append(starts, event.NodeID)
+			}
+			if strings.Contains(event.NodeID, "/") && event.RunID != "nested" {
+				t.Errorf("forwarded event has RunID %q, want the parent's", event.RunID)
+			}
+		}
+		want := "research research/search research/search/fetch research/done done"
+		if strings.Join(starts, " ") != want {
+			t.Errorf("node_start paths = %v, want %s", starts, want)
+		}
+	})
+
+	t.Run("interrupts pass through and resume the child", func(t *testing.T) {
+		var approveRuns atomic.Int32
+		child := New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+		addJoinTestNode(t, child, "approve", approvalNode(&approveRuns))
+		addJoinTestNode(t, child, "send", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Values: []string{"sent"}}, Route: Stop()}
+		})
+		_ = child.StartAt("approve")
+
+		parent := New(researchReducer, store.NewMemStore[ResearchState](), &mockEmitter{}, Options{MaxSteps: 20})
+		if err := parent.Add("review", Subgraph(child,
+			func(ResearchState) JoinTestState { return JoinTestState{} },
+			func(_ ResearchState, out JoinTestState) ResearchState { return ResearchState{Notes: out.Values} },
+		)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		connectToStop(t, parent, "review")
+		_ = parent.StartAt("review")
+
+		_, err := parent.Run(context.Background(), "nested-approval", ResearchState{})
+		var interrupt *InterruptError
+		if !errors.As(err, &interrupt) {
+			t.Fatalf("expected InterruptError, got %v", err)
+		}
+		if interrupt.NodeID != "review" || interrupt.Payload != "approve draft?" {
+			t.Errorf("interrupt = %+v, want node review with the child's payload", interrupt)
+		}
+
+		final, err := parent.Resume(context.Background(), "nested-approval", "yes")
+		if err != nil {
+			t.Fatalf("Resume failed: %v", err)
+		}
+		if strings.Join(final.Notes, ",") != "approved:yes,sent" {
+			t.Errorf("Notes = %v, want [approved:yes sent]", final.Notes)
+		}
+		if got := approveRuns.Load(); got != 2 {
+			t.Errorf("approve ran %d times, want 2", got)
+		}
+	})
+
+	t.Run("child errors fail the subgraph node", func(t *testing.T) {
+		childErr := errors.New("search backend down")
+		child := New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+		addJoinTestNode(t, child, "search", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Err: childErr}
+		})
+		_ = child.StartAt("search")
+
+		parent := New(researchReducer, store.NewMemStore[ResearchState](), &mockEmitter{}, Options{MaxSteps: 20})
+		_ = parent.Add("research", researchSubgraph(child))
+		_ = parent.StartAt("research")
+
+		_, err := parent.Run(context.Background(), "nested-error", ResearchState{Topic: "go"})
+		var nodeErr *NodeError
+		if !errors.As(err, &nodeErr) || nodeErr.Code != "SUBGRAPH_FAILED" || nodeErr.NodeID != "research" {
+			t.Fatalf("expected SUBGRAPH_FAILED from research, got %v", err)
+		}
+		if !errors.Is(err, childErr) {
+			t.Errorf("error does not wrap the child error: %v", err)
+		}
+	})
+}
@@ -3,10 +3,14 @@ package tool
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/dshills/langgraph-go/graph"
+	"github.com/dshills/langgraph-go/graph/store"
 )
 
 // T178: HTTP tool tests
@@ -291,3 +295,66 @@ func TestHTTPTool_DefaultMethod(t *testing.T) {
 		t.Fatalf("Call() error = %v, want nil", err)
 	}
 }
+
+// httpNode calls an HTTP tool from a node with recordable I/O.
+type httpNode struct {
+	tool Tool
+	url  string
+}
+
+func (n httpNode) Run(ctx context.Context, _ string) graph.NodeResult[string] {
+	result, err := n.tool.Call(ctx, map[string]interface{}{"url": n.url})
+	if err != nil {
+		return graph.NodeResult[string]{Err: err}
+	}
+	return graph.NodeResult[string]{
+		Delta: fmt.Sprintf("%v %s %v", result["status_code"], result["body"], result["headers"].(map[string]interface{})["X-Test"]),
+		Route: graph.Stop(),
+	}
+}
+
+func (n httpNode) Effects() graph.SideEffectPolicy {
+	return graph.SideEffectPolicy{Recordable: true}
+}
+
+// TestHTTPTool_Replay verifies an HTTPTool wrapped in RecordedTool is replayed
+// without repeating the request.
+func TestHTTPTool_Replay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Test", "recorded")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	st := store.NewMemStore[string]()
+	run := func(opts graph.Options) (string, error) {
+		reducer := func(prev, delta string) string { return prev + delta }
+		engine := graph.New(reducer, st, nil, opts)
+		if err := engine.Add("fetch", httpNode{tool: NewRecordedTool(NewHTTPTool()), url: server.URL}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("fetch"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine.Run(context.Background(), "http-run", "")
+	}
+
+	recorded, err := run(graph.Options{})
+	if err != nil {
+		t.Fatalf("record Run failed: %v", err)
+	}
+
+	replayed, err := run(graph.Options{ReplayMode: true, StrictReplay: true})
+	if err != nil {
+		t.Fatalf("replay Run failed: %v", err)
+	}
+
+	if recorded != "200 hello recorded" || replayed != recorded {
+		t.Errorf("replayed %q, recorded %q, want both %q", replayed, recorded, "200 hello recorded")
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1", requests)
+	}
+}
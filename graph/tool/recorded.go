@@ -0,0 +1,41 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/dshills/langgraph-go/graph"
+)
+
+// RecordedTool wraps a Tool so that its calls are recorded and replayed
+// through graph.Recorded.
+//
+// Tools do not record their calls themselves; wrap HTTPTool, MockTool or your
+// own Tool implementations to replay a run without calling them again. Calls
+// are recorded under the key "tool.<name>" with their input as the request.
+// Replayed outputs are decoded from JSON, so numbers come back as float64 and
+// nested objects as map[string]interface{}.
+//
+// Example usage:
+//
+//	search := tool.NewRecordedTool(&SearchTool{})
+//	result, err := search.Call(ctx, map[string]interface{}{"query": "golang"})
+type RecordedTool struct {
+	tool Tool
+}
+
+// NewRecordedTool returns t with its calls recorded.
+func NewRecordedTool(t Tool) *RecordedTool {
+	return &RecordedTool{tool: t}
+}
+
+// Name returns the wrapped tool's name.
+func (r *RecordedTool) Name() string {
+	return r.tool.Name()
+}
+
+// Call calls the wrapped tool through graph.Recorded.
+func (r *RecordedTool) Call(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	return graph.Recorded(ctx, "tool."+r.tool.Name(), input, func() (map[string]interface{}, error) {
+		return r.tool.Call(ctx, input)
+	})
+}
@@ -0,0 +1,64 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph"
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// recordedToolNode calls a tool from a node with recordable I/O.
+type recordedToolNode struct {
+	tool Tool
+}
+
+func (n recordedToolNode) Run(ctx context.Context, _ string) graph.NodeResult[string] {
+	result, err := n.tool.Call(ctx, map[string]interface{}{"city": "Paris"})
+	if err != nil {
+		return graph.NodeResult[string]{Err: err}
+	}
+	return graph.NodeResult[string]{Delta: fmt.Sprint(result["forecast"]), Route: graph.Stop()}
+}
+
+func (n recordedToolNode) Effects() graph.SideEffectPolicy {
+	return graph.SideEffectPolicy{Recordable: true}
+}
+
+func TestRecordedTool_Replay(t *testing.T) {
+	st := store.NewMemStore[string]()
+	run := func(mock *MockTool, opts graph.Options) (string, error) {
+		reducer := func(prev, delta string) string { return prev + delta }
+		engine := graph.New(reducer, st, nil, opts)
+		if err := engine.Add("weather", recordedToolNode{tool: NewRecordedTool(mock)}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := engine.StartAt("weather"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		return engine.Run(context.Background(), "tool-run", "")
+	}
+
+	live := &MockTool{ToolName: "weather", Responses: []map[string]interface{}{{"forecast": "sunny"}}}
+	recorded, err := run(live, graph.Options{})
+	if err != nil {
+		t.Fatalf("record Run failed: %v", err)
+	}
+
+	replayMock := &MockTool{ToolName: "weather", Responses: []map[string]interface{}{{"forecast": "rain"}}}
+	replayed, err := run(replayMock, graph.Options{ReplayMode: true, StrictReplay: true})
+	if err != nil {
+		t.Fatalf("replay Run failed: %v", err)
+	}
+
+	if recorded != "sunny" || replayed != recorded {
+		t.Errorf("replayed %q, recorded %q, want both %q", replayed, recorded, "sunny")
+	}
+	if live.CallCount() != 1 || replayMock.CallCount() != 0 {
+		t.Errorf("tool called %d times live and %d in replay, want 1 and 0", live.CallCount(), replayMock.CallCount())
+	}
+	if NewRecordedTool(live).Name() != "weather" {
+		t.Errorf("Name() = %q, want the wrapped tool's name", NewRecordedTool(live).Name())
+	}
+}
@@ -0,0 +1,448 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// EdgeKind classifies the edges of a Topology.
+type EdgeKind string
+
+const (
+	// EdgeAlways is an unconditional edge added with Connect.
+	EdgeAlways EdgeKind = "always"
+
+	// EdgeConditional is an edge added with Connect and a predicate.
+	EdgeConditional EdgeKind = "conditional"
+
+	// EdgeRoute is a Goto destination a node declares with Routes().
+	EdgeRoute EdgeKind = "route"
+
+	// EdgeFanOut is a Many destination a node declares with FanOut().
+	EdgeFanOut EdgeKind = "fan_out"
+
+	// EdgeError is an error edge added with OnError.
+	EdgeError EdgeKind = "error"
+)
+
+// TopologyNode is a node of a Topology.
+type TopologyNode struct {
+	// ID is the node ID.
+	ID string
+
+	// Join lists the predecessors the node waits for if it is a join node
+	// (see Engine.Join).
+	Join []string
+
+	// Declared reports whether the node declares its routes (see Node).
+	Declared bool
+
+	// Visits is how many times the node ran in the overlaid run (see
+	// Engine.RunTopology). Zero without an overlay.
+	Visits int
+}
+
+// TopologyEdge is an edge of a Topology.
+type TopologyEdge struct {
+	// From is the source node ID.
+	From string
+
+	// To is the destination node ID.
+	To string
+
+	// Kind tells how the transition is defined.
+	Kind EdgeKind
+
+	// Label is the edge label given with ConnectLabeled, if any.
+	Label string
+
+	// Traversed reports whether the overlaid run took the edge (see
+	// Engine.RunTopology).
+	Traversed bool
+}
+
+// Topology is a snapshot of a graph's structure for inspection and rendering.
+//
+// Nodes are sorted by ID. Edges list Connect edges in the order they were
+// added, then OnError edges, followed by declared routes and fan-outs in node
+// order. Predicates cannot be inspected, so conditional edges carry their
+// label instead.
+type Topology struct {
+	// Start is the start node ID (empty if StartAt was not called).
+	Start string
+
+	// Nodes are the graph's nodes, sorted by ID.
+	Nodes []TopologyNode
+
+	// Edges are the graph's transitions.
+	Edges []TopologyEdge
+
+	// RunID is the run overlaid with RunTopology (empty without an overlay).
+	RunID string
+}
+
+// Topology returns a snapshot of the graph's nodes, edges and joins.
+//
+// Example:
+//
+//	fmt.Println(engine.Topology().Mermaid())
+func (e *Engine[S]) Topology() Topology {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return Topology{}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	topology := Topology{Start: e.startNode}
+
+	ids := make([]string, 0, len(e.nodes))
+	for id := range e.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, edge := range e.edges {
+		kind := EdgeAlways
+		if edge.When != nil {
+			kind = EdgeConditional
+		}
+		topology.Edges = append(topology.Edges, TopologyEdge{From: edge.From, To: edge.To, Kind: kind, Label: edge.Label})
+	}
+	for _, edge := range e.errorEdges {
+		topology.Edges = append(topology.Edges, TopologyEdge{From: edge.From, To: edge.To, Kind: EdgeError})
+	}
+
+	for _, id := range ids {
+		routes, fanOut, declared := declaredRoutes(e.nodes[id])
+		topology.Nodes = append(topology.Nodes, TopologyNode{
+			ID:       id,
+			Join:     append([]string(nil), e.joins[id]...),
+			Declared: declared,
+		})
+		for _, to := range routes {
+			topology.Edges = append(topology.Edges, TopologyEdge{From: id, To: to, Kind: EdgeRoute})
+		}
+		for _, to := range fanOut {
+			topology.Edges = append(topology.Edges, TopologyEdge{From: id, To: to, Kind: EdgeFanOut})
+		}
+	}
+
+	return topology
+}
+
+// RunTopology returns the graph's Topology overlaid with the path a run took,
+// read from the step history in the engine's store.
+//
+// Each node's Visits counts its saved steps, and an edge is marked Traversed
+// when the run followed it from one step to a later one. Step history is saved
+// in sequential mode (MaxConcurrentNodes = 0); concurrent runs have none.
+//
+// Returns an EngineError with code HISTORY_UNSUPPORTED if the store does not
+// implement store.StepLister, or the store's ErrNotFound if the run has no
+// steps.
+//
+// Example:
+//
+//	topology, err := engine.RunTopology(ctx, "run-001")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("run-001.dot", []byte(topology.DOT()), 0o644)
+func (e *Engine[S]) RunTopology(ctx context.Context, runID string) (Topology, error) {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return Topology{}, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+
+	lister, ok := e.store.(store.StepLister[S])
+	if !ok {
+		return Topology{}, &EngineError{
+			Message: "store does not implement store.StepLister",
+			Code:    "HISTORY_UNSUPPORTED",
+		}
+	}
+
+	steps, err := lister.ListSteps(ctx, runID, store.Page{})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return Topology{}, err
+		}
+		return Topology{}, &EngineError{
+			Message: "failed to list steps: " + err.Error(),
+			Code:    "STORE_ERROR",
+		}
+	}
+
+	path := make([]string, len(steps))
+	for i, step := range steps {
+		path[i] = step.NodeID
+	}
+	return e.Topology().withPath(runID, path), nil
+}
+
+// withPath returns a copy of t overlaid with the nodes a run executed, in
+// step order.
+//
+// Step history does not record which node routed to which, so each step is
+// attributed to the nearest earlier step with an edge into it, and a join
+// node to the latest earlier run of each of its predecessors. Only the edges
+// between those pairs are marked Traversed, so a branch that was not taken
+// stays unmarked even if its target later ran through another route.
+func (t Topology) withPath(runID string, path []string) Topology {
+	visits := make(map[string]int)
+	for _, id := range path {
+		visits[id]++
+	}
+
+	joins := make(map[string][]string)
+	for _, node := range t.Nodes {
+		joins[node.ID] = node.Join
+	}
+	hasEdge := make(map[[2]string]bool)
+	for _, edge := range t.Edges {
+		hasEdge[[2]string{edge.From, edge.To}] = true
+	}
+
+	taken := make(map[[2]string]bool)
+	for i := 1; i < len(path); i++ {
+		to := path[i]
+		waiting := make(map[string]bool)
+		for _, pred := range joins[to] {
+			waiting[pred] = true
+		}
+		for j := i - 1; j >= 0; j-- {
+			transition := [2]string{path[j], to}
+			if !hasEdge[transition] {
+				continue
+			}
+			if len(waiting) == 0 {
+				taken[transition] = true
+				break
+			}
+			if waiting[path[j]] {
+				taken[transition] = true
+				delete(waiting, path[j])
+				if len(waiting) == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	overlay := Topology{Start: t.Start, RunID: runID}
+	for _, node := range t.Nodes {
+		node.Visits = visits[node.ID]
+		overlay.Nodes = append(overlay.Nodes, node)
+	}
+	for _, edge := range t.Edges {
+		edge.Traversed = taken[[2]string{edge.From, edge.To}]
+		overlay.Edges = append(overlay.Edges, edge)
+	}
+	return overlay
+}
+
+// nodeIDs returns the IDs of every node the topology refers to, including
+// edge endpoints that were never added, in a stable order.
+func (t Topology) nodeIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, node := range t.Nodes {
+		add(node.ID)
+	}
+	for _, edge := range t.Edges {
+		add(edge.From)
+		add(edge.To)
+	}
+	return ids
+}
+
+// Mermaid renders the topology as a Mermaid flowchart.
+//
+// Unconditional edges are solid arrows, conditional edges are labeled solid
+// arrows ("when" if they have no label), declared routes are dotted arrows,
+// declared fan-outs are thick arrows and error edges are dotted arrows
+// labeled "on error". Join nodes are drawn as hexagons.
+// With an overlay from RunTopology, visited nodes and traversed edges are
+// highlighted and nodes that ran more than once show their visit count.
+func (t Topology) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	ids := t.nodeIDs()
+	alias := make(map[string]string, len(ids))
+	for i, id := range ids {
+		alias[id] = "n" + strconv.Itoa(i)
+	}
+	nodes := make(map[string]TopologyNode, len(t.Nodes))
+	for _, node := range t.Nodes {
+		nodes[node.ID] = node
+	}
+
+	for _, id := range ids {
+		node := nodes[id]
+		label := mermaidText(nodeLabel(node, id))
+		if len(node.Join) > 0 {
+			fmt.Fprintf(&b, "    %s{{\"%s\"}}\n", alias[id], label)
+		} else {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", alias[id], label)
+		}
+	}
+
+	link := 0
+	var traversed []string
+	if t.Start != "" {
+		fmt.Fprintf(&b, "    __start__((start)) --> %s\n", alias[t.Start])
+		if t.RunID != "" && nodes[t.Start].Visits > 0 {
+			traversed = append(traversed, strconv.Itoa(link))
+		}
+		link++
+	}
+	for _, edge := range t.Edges {
+		from, to := alias[edge.From], alias[edge.To]
+		switch edge.Kind {
+		case EdgeConditional:
+			label := edge.Label
+			if label == "" {
+				label = "when"
+			}
+			fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", from, mermaidText(label), to)
+		case EdgeRoute:
+			fmt.Fprintf(&b, "    %s -.-> %s\n", from, to)
+		case EdgeFanOut:
+			fmt.Fprintf(&b, "    %s ==> %s\n", from, to)
+		case EdgeError:
+			fmt.Fprintf(&b, "    %s -.->|\"on error\"| %s\n", from, to)
+		default:
+			if edge.Label != "" {
+				fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", from, mermaidText(edge.Label), to)
+			} else {
+				fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+			}
+		}
+		if edge.Traversed {
+			traversed = append(traversed, strconv.Itoa(link))
+		}
+		link++
+	}
+
+	if t.RunID != "" {
+		var visited []string
+		for _, id := range ids {
+			if nodes[id].Visits > 0 {
+				visited = append(visited, alias[id])
+			}
+		}
+		b.WriteString("    classDef visited fill:#d4edda,stroke:#28a745,stroke-width:2px\n")
+		if len(visited) > 0 {
+			fmt.Fprintf(&b, "    class %s visited\n", strings.Join(visited, ","))
+		}
+		if len(traversed) > 0 {
+			fmt.Fprintf(&b, "    linkStyle %s stroke:#28a745,stroke-width:3px\n", strings.Join(traversed, ","))
+		}
+	}
+
+	return b.String()
+}
+
+// DOT renders the topology in the Graphviz DOT language.
+//
+// Unconditional edges are solid, conditional edges are labeled ("when" if
+// they have no label), declared routes are dashed, declared fan-outs are
+// bold and error edges are red, dashed and labeled "on error". Join nodes
+// are drawn as hexagons. With an overlay from RunTopology, visited nodes are
+// filled, traversed edges are colored and nodes that ran more than once show
+// their visit count.
+func (t Topology) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph workflow {\n")
+	b.WriteString("    rankdir=TB;\n")
+	b.WriteString("    node [shape=box];\n")
+
+	nodes := make(map[string]TopologyNode, len(t.Nodes))
+	for _, node := range t.Nodes {
+		nodes[node.ID] = node
+	}
+
+	for _, id := range t.nodeIDs() {
+		node := nodes[id]
+		attrs := []string{"label=" + dotQuote(nodeLabel(node, id))}
+		if len(node.Join) > 0 {
+			attrs = append(attrs, "shape=hexagon")
+		}
+		if node.Visits > 0 {
+			attrs = append(attrs, "style=filled", `fillcolor="#d4edda"`, `color="#28a745"`)
+		}
+		fmt.Fprintf(&b, "    %s [%s];\n", dotQuote(id), strings.Join(attrs, ", "))
+	}
+
+	if t.Start != "" {
+		b.WriteString("    __start__ [shape=circle, label=\"start\"];\n")
+		fmt.Fprintf(&b, "    __start__ -> %s;\n", dotQuote(t.Start))
+	}
+	for _, edge := range t.Edges {
+		var attrs []string
+		label := edge.Label
+		switch edge.Kind {
+		case EdgeConditional:
+			if label == "" {
+				label = "when"
+			}
+		case EdgeRoute:
+			attrs = append(attrs, "style=dashed")
+		case EdgeFanOut:
+			attrs = append(attrs, "style=bold")
+		case EdgeError:
+			label = "on error"
+			attrs = append(attrs, "style=dashed", `color="#dc3545"`)
+		}
+		if label != "" {
+			attrs = append([]string{"label=" + dotQuote(label)}, attrs...)
+		}
+		if edge.Traversed {
+			attrs = append(attrs, `color="#28a745"`, "penwidth=2")
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "    %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+			continue
+		}
+		fmt.Fprintf(&b, "    %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// nodeLabel returns the text drawn for a node: its ID, with the visit count
+// if it ran more than once.
+func nodeLabel(node TopologyNode, id string) string {
+	if node.Visits > 1 {
+		return fmt.Sprintf("%s ×%d", id, node.Visits)
+	}
+	return id
+}
+
+// mermaidText escapes double quotes for a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// dotQuote returns s as a quoted DOT identifier.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// newTopologyTestEngine wires a small review workflow:
+//
+//	fanout ==> {draft, research} -> review (join) -[score > "0.8"]-> publish
+//	review -.-> draft (declared route back for rework)
+func newTopologyTestEngine(t *testing.T, st store.Store[JoinTestState]) *Engine[JoinTestState] {
+	t.Helper()
+
+	engine := New(joinTestReducer, st, nil, Options{MaxSteps: 20})
+	stop := NodeFunc[JoinTestState](func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Route: Stop()}
+	})
+	// pass leaves routing to the outgoing edges
+	pass := NodeFunc[JoinTestState](func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}}
+	})
+	_ = engine.Add("fanout", DeclareFanOut(func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Route: Many([]string{"draft", "research"})}
+	}, "draft", "research"))
+	_ = engine.Add("draft", pass)
+	_ = engine.Add("research", pass)
+	_ = engine.Add("review", DeclareRoutes(pass, "draft"))
+	_ = engine.Add("publish", stop)
+	_ = engine.Connect("draft", "review", nil)
+	_ = engine.Connect("research", "review", nil)
+	_ = engine.ConnectLabeled("review", "publish", `score > "0.8"`, func(JoinTestState) bool { return true })
+	_ = engine.Join("review", "draft", "research")
+	if err := engine.StartAt("fanout"); err != nil {
+		t.Fatalf("StartAt failed: %v", err)
+	}
+	return engine
+}
+
+// TestEngine_Topology verifies the graph structure is exposed and rendered.
+func TestEngine_Topology(t *testing.T) {
+	engine := newTopologyTestEngine(t, store.NewMemStore[JoinTestState]())
+	topology := engine.Topology()
+
+	t.Run("nodes and edges", func(t *testing.T) {
+		var nodes []string
+		for _, node := range topology.Nodes {
+			nodes = append(nodes, fmt.Sprintf("%s:%v:%v", node.ID, node.Join, node.Declared))
+		}
+		want := "[draft:[]:false fanout:[]:true publish:[]:false research:[]:false review:[draft research]:true]"
+		if fmt.Sprint(nodes) != want {
+			t.Errorf("nodes = %v, want %s", nodes, want)
+		}
+
+		var edges []string
+		for _, edge := range topology.Edges {
+			edges = append(edges, fmt.Sprintf("%s->%s:%s", edge.From, edge.To, edge.Kind))
+		}
+		want = "[draft->review:always research->review:always review->publish:conditional fanout->draft:fan_out fanout->research:fan_out review->draft:route]"
+		if fmt.Sprint(edges) != want {
+			t.Errorf("edges = %v, want %s", edges, want)
+		}
+		if topology.Start != "fanout" || topology.Edges[2].Label != `score > "0.8"` {
+			t.Errorf("Start = %q, label = %q", topology.Start, topology.Edges[2].Label)
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		want := `flowchart TD
+    n0["draft"]
+    n1["fanout"]
+    n2["publish"]
+    n3["research"]
+    n4{{"review"}}
+    __start__((start)) --> n1
+    n0 --> n4
+    n3 --> n4
+    n4 -->|"score > #quot;0.8#quot;"| n2
+    n1 ==> n0
+    n1 ==> n3
+    n4 -.-> n0
+`
+		if got := topology.Mermaid(); got != want {
+			t.Errorf("Mermaid() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		want := `digraph workflow {
+    rankdir=TB;
+    node [shape=box];
+    "draft" [label="draft"];
+    "fanout" [label="fanout"];
+    "publish" [label="publish"];
+    "research" [label="research"];
+    "review" [label="review", shape=hexagon];
+    __start__ [shape=circle, label="start"];
+    __start__ -> "fanout";
+    "draft" -> "review";
+    "research" -> "review";
+    "review" -> "publish" [label="score > \"0.8\""];
+    "fanout" -> "draft" [style=bold];
+    "fanout" -> "research" [style=bold];
+    "review" -> "draft" [style=dashed];
+}
+`
+		if got := topology.DOT(); got != want {
+			t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+// TestEngine_RunTopology verifies a run's path is overlaid from step history.
+func TestEngine_RunTopology(t *testing.T) {
+	t.Run("highlights the path taken", func(t *testing.T) {
+		engine := newTopologyTestEngine(t, store.NewMemStore[JoinTestState]())
+		if _, err := engine.Run(context.Background(), "topology-run", JoinTestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		topology, err := engine.RunTopology(context.Background(), "topology-run")
+		if err != nil {
+			t.Fatalf("RunTopology failed: %v", err)
+		}
+
+		visits := make(map[string]int)
+		for _, node := range topology.Nodes {
+			visits[node.ID] = node.Visits
+		}
+		if fmt.Sprint(visits) != "map[draft:1 fanout:1 publish:1 research:1 review:1]" {
+			t.Errorf("visits = %v", visits)
+		}
+		for _, edge := range topology.Edges {
+			want := edge.From != "review" || edge.To != "draft"
+			if edge.Traversed != want {
+				t.Errorf("%s->%s Traversed = %v, want %v", edge.From, edge.To, edge.Traversed, want)
+			}
+		}
+
+		mermaid := topology.Mermaid()
+		for _, line := range []string{"class n0,n1,n2,n3,n4 visited", "linkStyle 0,1,2,3,4,5 stroke:#28a745"} {
+			if !strings.Contains(mermaid, line) {
+				t.Errorf("Mermaid() missing %q:\n%s", line, mermaid)
+			}
+		}
+		if dot := topology.DOT(); !strings.Contains(dot, `"review" -> "draft" [style=dashed];`) ||
+			!strings.Contains(dot, `"fanout" -> "draft" [style=bold, color="#28a745", penwidth=2];`) {
+			t.Errorf("DOT() overlay unexpected:\n%s", dot)
+		}
+	})
+
+	t.Run("skipped conditional edge", func(t *testing.T) {
+		engine := New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+		pass := NodeFunc[JoinTestState](func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}}
+		})
+		_ = engine.Add("a", pass)
+		_ = engine.Add("b", pass)
+		_ = engine.Add("c", NodeFunc[JoinTestState](func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Stop()}
+		}))
+		// a -> c is declared first but never taken; c is reached through b
+		_ = engine.Connect("a", "c", func(JoinTestState) bool { return false })
+		_ = engine.Connect("a", "b", nil)
+		_ = engine.Connect("b", "c", nil)
+		if err := engine.StartAt("a"); err != nil {
+			t.Fatalf("StartAt failed: %v", err)
+		}
+		if _, err := engine.Run(context.Background(), "skipped-run", JoinTestState{}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		topology, err := engine.RunTopology(context.Background(), "skipped-run")
+		if err != nil {
+			t.Fatalf("RunTopology failed: %v", err)
+		}
+		var traversed []string
+		for _, edge := range topology.Edges {
+			traversed = append(traversed, fmt.Sprintf("%s->%s:%v", edge.From, edge.To, edge.Traversed))
+		}
+		if want := "[a->c:false a->b:true b->c:true]"; fmt.Sprint(traversed) != want {
+			t.Errorf("traversed = %v, want %s", traversed, want)
+		}
+	})
+
+	t.Run("store without step history", func(t *testing.T) {
+		// Embedding the interface hides MemStore's ListSteps
+		engine := newTopologyTestEngine(t, struct{ store.Store[JoinTestState] }{store.NewMemStore[JoinTestState]()})
+
+		_, err := engine.RunTopology(context.Background(), "any")
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "HISTORY_UNSUPPORTED" {
+			t.Fatalf("expected HISTORY_UNSUPPORTED, got %v", err)
+		}
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		engine := newTopologyTestEngine(t, store.NewMemStore[JoinTestState]())
+
+		if _, err := engine.RunTopology(context.Background(), "never-ran"); !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,388 @@
+// Package graph provides the core graph execution engine for LangGraph-Go.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationIssue is a single problem found by Engine.Validate.
+type ValidationIssue struct {
+	// Code is a machine-readable issue code such as "DANGLING_EDGE".
+	Code string
+
+	// NodeID is the node the issue concerns (empty for graph-level issues).
+	NodeID string
+
+	// Message is the human-readable description.
+	Message string
+}
+
+// String formats the issue as "CODE: message".
+func (i ValidationIssue) String() string {
+	return i.Code + ": " + i.Message
+}
+
+// ValidationReport is the result of checking a graph's topology.
+//
+// Errors describe graphs that will fail at runtime, for example an edge to a
+// node that was never added. Warnings describe graphs that may be wrong, such
+// as unreachable nodes or cycles with no way out.
+//
+// Issue codes:
+//   - NO_START_NODE (error): StartAt was never called
+//   - DANGLING_EDGE (error): an edge or error edge starts or ends at an unknown node
+//   - UNKNOWN_ROUTE (error): a node declares a route to an unknown node
+//   - INVALID_JOIN (error): a join node or predecessor is unknown
+//   - NO_OUTGOING_ROUTE (warning): a node has no edges and declares no routes
+//   - UNREACHABLE_NODE (warning): no path from the start node reaches the node
+//   - CYCLE_WITHOUT_EXIT (warning): no edge or declared route leaves a cycle
+type ValidationReport struct {
+	// Errors are issues that make the graph invalid.
+	Errors []ValidationIssue
+
+	// Warnings are suspicious but runnable topology.
+	Warnings []ValidationIssue
+
+	// Unreachable lists the node IDs no path from the start node reaches, in
+	// sorted order. It is only computed when the routes of every reachable
+	// node are known (see Engine.Validate).
+	Unreachable []string
+}
+
+// Valid reports whether the report has no errors.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// addError records an error issue.
+func (r *ValidationReport) addError(code, nodeID, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, ValidationIssue{Code: code, NodeID: nodeID, Message: fmt.Sprintf(format, args...)})
+}
+
+// addWarning records a warning issue.
+func (r *ValidationReport) addWarning(code, nodeID, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, ValidationIssue{Code: code, NodeID: nodeID, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the graph's topology without running it.
+//
+// Routes are taken from two sources: edges added with Connect, and the node
+// IDs a node declares by implementing Routes() []string or FanOut() []string
+// (see Node, DeclareRoutes and DeclareFanOut). Error edges added with
+// OnError make their handlers reachable but do not count as routes of their
+// node. A node without edges that does not declare its routes can only be
+// checked at runtime; it is reported as NO_OUTGOING_ROUTE, and if it is
+// reachable the unreachable-node check is skipped since it may route
+// anywhere.
+//
+// Validate does not modify the graph. Use Compile to validate and freeze it.
+//
+// Example:
+//
+//	report := engine.Validate()
+//	for _, issue := range report.Errors {
+//	    log.Printf("error: %s", issue)
+//	}
+//	for _, issue := range report.Warnings {
+//	    log.Printf("warning: %s", issue)
+//	}
+func (e *Engine[S]) Validate() *ValidationReport {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		report := &ValidationReport{}
+		report.addError("NIL_ENGINE", "", "engine is nil")
+		return report
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.validate()
+}
+
+// Compile validates the graph and, if it has no errors, freezes it.
+//
+// After a successful Compile, every graph-mutating method (Add, StartAt,
+// Connect, ConnectLabeled, Join, OnError and OnErrorState) returns an
+// EngineError with code GRAPH_COMPILED, so a graph shared between goroutines
+// cannot change while it runs. Compiling an already compiled graph is a no-op
+// that returns a fresh report.
+//
+// Returns the validation report and, if the report has errors, an EngineError
+// with code INVALID_GRAPH listing them. A graph that fails to compile is not
+// frozen.
+//
+// Example:
+//
+//	report, err := engine.Compile()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, issue := range report.Warnings {
+//	    log.Printf("warning: %s", issue)
+//	}
+func (e *Engine[S]) Compile() (*ValidationReport, error) {
+	// Prevent panic when called on nil Engine
+	if e == nil {
+		return nil, &EngineError{Message: "engine is nil", Code: "NIL_ENGINE"}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := e.validate()
+	if !report.Valid() {
+		issues := make([]string, len(report.Errors))
+		for i, issue := range report.Errors {
+			issues[i] = issue.String()
+		}
+		return report, &EngineError{
+			Message: fmt.Sprintf("graph has %d validation error(s): %s", len(issues), strings.Join(issues, "; ")),
+			Code:    "INVALID_GRAPH",
+		}
+	}
+
+	e.compiled = true
+	return report, nil
+}
+
+// declaredRoutes returns the Goto and Many destinations node declares with
+// Routes() and FanOut(), and whether it declares either.
+func declaredRoutes[S any](node Node[S]) (routes, fanOut []string, ok bool) {
+	if router, is := node.(interface{ Routes() []string }); is {
+		routes, ok = append([]string(nil), router.Routes()...), true
+	}
+	if fanner, is := node.(interface{ FanOut() []string }); is {
+		fanOut, ok = append([]string(nil), fanner.FanOut()...), true
+	}
+	return routes, fanOut, ok
+}
+
+// compiledError is returned by graph mutators once the graph is compiled.
+func compiledError(what string) error {
+	return &EngineError{
+		Message: "cannot " + what + " after Compile",
+		Code:    "GRAPH_COMPILED",
+	}
+}
+
+// validate builds the validation report. Callers must hold e.mu.
+func (e *Engine[S]) validate() *ValidationReport {
+	report := &ValidationReport{}
+
+	ids := make([]string, 0, len(e.nodes))
+	for id := range e.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	exists := func(id string) bool {
+		_, ok := e.nodes[id]
+		return ok
+	}
+
+	if e.startNode == "" {
+		report.addError("NO_START_NODE", "", "no start node set (call StartAt)")
+	}
+
+	// successors holds every known transition: edges and declared routes
+	successors := make(map[string]map[string]bool, len(ids))
+	link := func(from, to string) {
+		if successors[from] == nil {
+			successors[from] = make(map[string]bool)
+		}
+		successors[from][to] = true
+	}
+
+	hasEdges := make(map[string]bool)
+	for _, edge := range e.edges {
+		if !exists(edge.From) {
+			report.addError("DANGLING_EDGE", edge.From, "edge %s -> %s starts at unknown node %s", edge.From, edge.To, edge.From)
+		}
+		if !exists(edge.To) {
+			report.addError("DANGLING_EDGE", edge.From, "edge %s -> %s ends at unknown node %s", edge.From, edge.To, edge.To)
+		}
+		if exists(edge.From) && exists(edge.To) {
+			hasEdges[edge.From] = true
+			link(edge.From, edge.To)
+		}
+	}
+
+	for _, edge := range e.errorEdges {
+		if !exists(edge.From) {
+			report.addError("DANGLING_EDGE", edge.From, "error edge %s -> %s starts at unknown node %s", edge.From, edge.To, edge.From)
+		}
+		if !exists(edge.To) {
+			report.addError("DANGLING_EDGE", edge.From, "error edge %s -> %s ends at unknown node %s", edge.From, edge.To, edge.To)
+		}
+		if exists(edge.From) && exists(edge.To) {
+			link(edge.From, edge.To)
+		}
+	}
+
+	declared := make(map[string]bool)
+	for _, id := range ids {
+		routes, fanOut, ok := declaredRoutes(e.nodes[id])
+		if !ok {
+			continue
+		}
+		declared[id] = true
+		for _, to := range append(routes, fanOut...) {
+			if !exists(to) {
+				report.addError("UNKNOWN_ROUTE", id, "node %s declares a route to unknown node %s", id, to)
+				continue
+			}
+			link(id, to)
+		}
+	}
+
+	joinIDs := make([]string, 0, len(e.joins))
+	for id := range e.joins {
+		joinIDs = append(joinIDs, id)
+	}
+	sort.Strings(joinIDs)
+	for _, id := range joinIDs {
+		if !exists(id) {
+			report.addError("INVALID_JOIN", id, "join node %s does not exist", id)
+		}
+		for _, pred := range e.joins[id] {
+			if !exists(pred) {
+				report.addError("INVALID_JOIN", id, "join %s waits for unknown node %s", id, pred)
+			}
+		}
+	}
+
+	// Nodes with neither edges nor declared routes route only at runtime
+	dynamic := make(map[string]bool)
+	for _, id := range ids {
+		if !hasEdges[id] && !declared[id] {
+			dynamic[id] = true
+			report.addWarning("NO_OUTGOING_ROUTE", id,
+				"node %s has no outgoing edges and does not declare its routes; it must return Stop, Goto or Many", id)
+		}
+	}
+
+	if exists(e.startNode) {
+		e.checkReachability(report, ids, successors, dynamic)
+	}
+	checkCycles(report, ids, successors)
+
+	return report
+}
+
+// checkReachability records the nodes no path from the start node reaches.
+// It reports nothing if a reachable node routes dynamically, since that node
+// may route anywhere.
+func (e *Engine[S]) checkReachability(report *ValidationReport, ids []string, successors map[string]map[string]bool, dynamic map[string]bool) {
+	reached := map[string]bool{e.startNode: true}
+	queue := []string{e.startNode}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if dynamic[id] {
+			return
+		}
+		for to := range successors[id] {
+			if !reached[to] {
+				reached[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if !reached[id] {
+			report.Unreachable = append(report.Unreachable, id)
+			report.addWarning("UNREACHABLE_NODE", id, "node %s is not reachable from start node %s", id, e.startNode)
+		}
+	}
+}
+
+// checkCycles warns about cycles that no known transition leaves. Such a loop
+// only ends when one of its nodes returns Stop or MaxSteps is reached.
+func checkCycles(report *ValidationReport, ids []string, successors map[string]map[string]bool) {
+	for _, component := range stronglyConnected(ids, successors) {
+		members := make(map[string]bool, len(component))
+		for _, id := range component {
+			members[id] = true
+		}
+
+		// A single node is only a cycle if it routes to itself
+		if len(component) == 1 && !successors[component[0]][component[0]] {
+			continue
+		}
+
+		exits := false
+		for _, id := range component {
+			for to := range successors[id] {
+				if !members[to] {
+					exits = true
+				}
+			}
+		}
+		if !exits {
+			sort.Strings(component)
+			report.addWarning("CYCLE_WITHOUT_EXIT", component[0],
+				"cycle through %s has no edge or declared route leaving it", strings.Join(component, ", "))
+		}
+	}
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// using Tarjan's algorithm. Components are returned in a deterministic order
+// for a given ids slice.
+func stronglyConnected(ids []string, successors map[string]map[string]bool) [][]string {
+	index := make(map[string]int, len(ids))
+	lowlink := make(map[string]int, len(ids))
+	onStack := make(map[string]bool, len(ids))
+	var stack []string
+	var components [][]string
+	next := 0
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = next
+		lowlink[id] = next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		targets := make([]string, 0, len(successors[id]))
+		for to := range successors[id] {
+			targets = append(targets, to)
+		}
+		sort.Strings(targets)
+
+		for _, to := range targets {
+			if _, seen := index[to]; !seen {
+				visit(to)
+				lowlink[id] = min(lowlink[id], lowlink[to])
+			} else if onStack[to] {
+				lowlink[id] = min(lowlink[id], index[to])
+			}
+		}
+
+		if lowlink[id] == index[id] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, id := range ids {
+		if _, seen := index[id]; !seen {
+			visit(id)
+		}
+	}
+	return components
+}
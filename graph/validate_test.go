@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dshills/langgraph-go/graph/store"
+)
+
+// newValidateTestEngine returns an engine with no nodes.
+func newValidateTestEngine() *Engine[JoinTestState] {
+	return New(joinTestReducer, store.NewMemStore[JoinTestState](), nil, Options{MaxSteps: 20})
+}
+
+// routesTo returns a node declaring routes that stops when run.
+func routesTo(routes ...string) Node[JoinTestState] {
+	return DeclareRoutes(NodeFunc[JoinTestState](func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+		return NodeResult[JoinTestState]{Route: Stop()}
+	}), routes...)
+}
+
+// issueCodes returns "CODE:nodeID" for each issue.
+func issueCodes(issues []ValidationIssue) []string {
+	codes := make([]string, len(issues))
+	for i, issue := range issues {
+		codes[i] = issue.Code + ":" + issue.NodeID
+	}
+	return codes
+}
+
+// TestEngine_Validate verifies topology problems are reported before Run.
+func TestEngine_Validate(t *testing.T) {
+	t.Run("valid graph has no issues", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		_ = engine.Add("classify", routesTo("billing", "support"))
+		_ = engine.Add("billing", routesTo())
+		_ = engine.Add("support", routesTo())
+		_ = engine.StartAt("classify")
+
+		report := engine.Validate()
+		if !report.Valid() || len(report.Warnings) != 0 || len(report.Unreachable) != 0 {
+			t.Errorf("report = %+v, want no issues", report)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		_ = engine.Add("a", routesTo("missing-route"))
+		_ = engine.Add("b", routesTo())
+		_ = engine.Connect("a", "missing-target", nil)
+		_ = engine.Connect("ghost", "b", nil)
+		_ = engine.Join("b", "a", "missing-pred")
+
+		report := engine.Validate()
+		want := "[NO_START_NODE: DANGLING_EDGE:a DANGLING_EDGE:ghost UNKNOWN_ROUTE:a INVALID_JOIN:b]"
+		if got := fmt.Sprint(issueCodes(report.Errors)); got != want {
+			t.Errorf("errors = %v, want %s", got, want)
+		}
+		if report.Valid() {
+			t.Error("Valid() = true, want false")
+		}
+	})
+
+	t.Run("unreachable nodes", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		_ = engine.Add("start", routesTo("end"))
+		_ = engine.Add("end", routesTo())
+		_ = engine.Add("orphan", routesTo("end"))
+		_ = engine.StartAt("start")
+
+		report := engine.Validate()
+		if fmt.Sprint(report.Unreachable) != "[orphan]" {
+			t.Errorf("Unreachable = %v, want [orphan]", report.Unreachable)
+		}
+		if got := fmt.Sprint(issueCodes(report.Warnings)); got != "[UNREACHABLE_NODE:orphan]" {
+			t.Errorf("warnings = %v, want [UNREACHABLE_NODE:orphan]", got)
+		}
+	})
+
+	t.Run("undeclared routes skip reachability", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		addJoinTestNode(t, engine, "dynamic", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Route: Goto("end")}
+		})
+		_ = engine.Add("end", routesTo())
+		_ = engine.StartAt("dynamic")
+
+		report := engine.Validate()
+		if len(report.Unreachable) != 0 {
+			t.Errorf("Unreachable = %v, want none", report.Unreachable)
+		}
+		if got := fmt.Sprint(issueCodes(report.Warnings)); got != "[NO_OUTGOING_ROUTE:dynamic]" {
+			t.Errorf("warnings = %v, want [NO_OUTGOING_ROUTE:dynamic]", got)
+		}
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		_ = engine.Add("start", routesTo("loop-a"))
+		_ = engine.Add("loop-a", routesTo())
+		_ = engine.Add("loop-b", routesTo())
+		_ = engine.Add("retry", routesTo("retry", "end"))
+		_ = engine.Add("end", routesTo())
+		_ = engine.Connect("loop-a", "loop-b", nil)
+		_ = engine.Connect("loop-b", "loop-a", nil)
+		_ = engine.Connect("start", "retry", nil)
+		_ = engine.StartAt("start")
+
+		report := engine.Validate()
+		want := "[CYCLE_WITHOUT_EXIT:loop-a]"
+		if got := fmt.Sprint(issueCodes(report.Warnings)); got != want {
+			t.Errorf("warnings = %v, want %s", got, want)
+		}
+	})
+}
+
+// TestEngine_Compile verifies Compile rejects invalid graphs and freezes valid ones.
+func TestEngine_Compile(t *testing.T) {
+	t.Run("invalid graph is not frozen", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		_ = engine.Add("a", routesTo())
+		_ = engine.Connect("a", "missing", nil)
+		_ = engine.StartAt("a")
+
+		report, err := engine.Compile()
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) || engineErr.Code != "INVALID_GRAPH" {
+			t.Fatalf("expected INVALID_GRAPH, got %v", err)
+		}
+		if len(report.Errors) != 1 {
+			t.Errorf("report errors = %v, want 1", report.Errors)
+		}
+		if err := engine.Add("missing", routesTo()); err != nil {
+			t.Errorf("Add after failed Compile: %v", err)
+		}
+		if _, err := engine.Compile(); err != nil {
+			t.Errorf("Compile after fix: %v", err)
+		}
+	})
+
+	t.Run("compiled graph is frozen and runs", func(t *testing.T) {
+		engine := newValidateTestEngine()
+		addJoinTestNode(t, engine, "a", func(_ context.Context, _ JoinTestState) NodeResult[JoinTestState] {
+			return NodeResult[JoinTestState]{Delta: JoinTestState{Counter: 1}, Route: Stop()}
+		})
+		_ = engine.StartAt("a")
+		if _, err := engine.Compile(); err != nil {
+			t.Fatalf("Compile failed: %v", err)
+		}
+
+		mutations := map[string]error{
+			"Add":     engine.Add("b", routesTo()),
+			"StartAt": engine.StartAt("a"),
+			"Connect": engine.Connect("a", "a", nil),
+			"Join":    engine.Join("a", "a"),
+		}
+		for name, err := range mutations {
+			var engineErr *EngineError
+			if !errors.As(err, &engineErr) || engineErr.Code != "GRAPH_COMPILED" {
+				t.Errorf("%s after Compile = %v, want GRAPH_COMPILED", name, err)
+			}
+		}
+
+		final, err := engine.Run(context.Background(), "compiled", JoinTestState{})
+		if err != nil || final.Counter != 1 {
+			t.Errorf("Run = %+v, %v; want Counter 1", final, err)
+		}
+	})
+}